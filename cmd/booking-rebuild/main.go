@@ -0,0 +1,59 @@
+// Command booking-rebuild restores the bookings table from the booking event store.
+//
+//	go run ./cmd/booking-rebuild              # rebuild every booking with recorded history
+//	go run ./cmd/booking-rebuild -booking ID  # rebuild a single booking
+package main
+
+import (
+	"context"
+	"flag"
+	"os/signal"
+	"syscall"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	bookingrepo "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/booking/repository"
+	bookinguc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/config"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/database"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/logger"
+)
+
+func main() {
+	bookingID := flag.String("booking", "", "rebuild only this booking id")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg := config.Load()
+	log := logger.New()
+
+	db, err := database.NewGormPostgres(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("failed to connect to postgres", zap.Error(err))
+	}
+	if err := bookingrepo.AutoMigrate(db); err != nil {
+		log.Fatal("failed to run booking migrations", zap.Error(err))
+	}
+	repo := bookingrepo.NewGormRepository(db)
+
+	if *bookingID != "" {
+		id, err := uuid.Parse(*bookingID)
+		if err != nil {
+			log.Fatal("invalid booking id", zap.Error(err))
+		}
+		if err := bookinguc.RebuildBooking(ctx, repo, id); err != nil {
+			log.Fatal("failed to rebuild booking", zap.String("booking_id", id.String()), zap.Error(err))
+		}
+		log.Info("✅ Booking rebuilt from history", zap.String("booking_id", id.String()))
+		return
+	}
+
+	count, err := bookinguc.RebuildFromHistory(ctx, repo)
+	if err != nil {
+		log.Fatal("failed to rebuild bookings", zap.Int("rebuilt_bookings", count), zap.Error(err))
+	}
+	log.Info("✅ Bookings rebuilt from history", zap.Int("rebuilt_bookings", count))
+}
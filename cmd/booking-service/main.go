@@ -9,9 +9,11 @@ import (
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
+	bookingexchangerate "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/booking/exchangerate"
 	bookinghttp "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/booking/http"
 	bookingnotification "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/booking/notification"
 	bookingpayment "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/booking/payment"
+	bookingquote "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/booking/quotetoken"
 	bookingrepo "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/booking/repository"
 	bookingworker "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/booking/worker"
 	hotelrepo "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/hotel/repository"
@@ -21,6 +23,7 @@ import (
 	"github.com/ftryyln/hotel-booking-microservices/pkg/logger"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/server"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 func main() {
@@ -41,6 +44,14 @@ func main() {
 	if err := hotelrepo.AutoMigrate(db); err != nil {
 		log.Fatal("failed to run hotel migrations", zap.Error(err))
 	}
+	idempotency := database.NewIdempotencyStore(db)
+	if err := idempotency.AutoMigrate(); err != nil {
+		log.Fatal("failed to run idempotency migrations", zap.Error(err))
+	}
+	auditLog := database.NewAuditLog(db)
+	if err := auditLog.AutoMigrate(); err != nil {
+		log.Fatal("failed to run audit log migrations", zap.Error(err))
+	}
 
 	repoFactory := bookingrepo.NewGormFactory(db)
 	repo, err := repoFactory.CreateBookingRepository(bookingrepo.TypeGorm)
@@ -49,9 +60,21 @@ func main() {
 	}
 
 	hRepo := hotelrepo.NewGormRepository(db)
-	paymentClient := bookingpayment.NewHTTPGateway(cfg.PaymentServiceURL)
+	paymentClient := bookingpayment.NewHTTPGateway(cfg.PaymentServiceURL, cfg.JWTSecret)
 	notifier := bookingnotification.NewHTTPGateway(cfg.NotificationURL)
 	service := bookinguc.NewService(repo, hRepo, paymentClient, notifier)
+	service.SetHoldDuration(cfg.BookingHoldDuration)
+	service.SetNoShowPolicy(valueobject.NoShowPolicy{ChargeNights: cfg.NoShowChargeNights})
+	service.SetRetryPolicy(valueobject.RetryPolicy{
+		MaxAttempts: cfg.OutboxMaxAttempts,
+		BaseDelay:   cfg.OutboxRetryBaseDelay,
+		MaxDelay:    cfg.OutboxRetryMaxDelay,
+	})
+	service.SetAuditLog(auditLog)
+	if cfg.ExchangeRateURL != "" {
+		service.SetExchangeRateProvider(bookingexchangerate.NewHTTPProvider(cfg.ExchangeRateURL))
+	}
+	service.SetQuoteSigner(bookingquote.NewJWTSigner(cfg.QuoteSecret), cfg.QuoteTTL)
 	handler := bookinghttp.NewHandler(service)
 
 	r := chi.NewRouter()
@@ -59,8 +82,10 @@ func main() {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
+	r.Get("/availability", handler.SearchAvailability)
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.JWT(cfg.JWTSecret))
+		r.Use(middleware.Idempotency(idempotency, "booking", cfg.IdempotencyKeyTTL))
 		r.Mount("/", handler.Routes())
 	})
 
@@ -74,8 +99,50 @@ func main() {
 	}
 	defer scheduler.Stop()
 
+	holdExpiry := bookingworker.NewHoldExpiryScheduler(service, cfg.JWTSecret, log)
+	if err := holdExpiry.Start(); err != nil {
+		log.Fatal("failed to start hold expiry scheduler", zap.Error(err))
+	}
+	defer holdExpiry.Stop()
+
+	noShow := bookingworker.NewNoShowScheduler(service, cfg.JWTSecret, log)
+	if err := noShow.Start(); err != nil {
+		log.Fatal("failed to start no-show scheduler", zap.Error(err))
+	}
+	defer noShow.Stop()
+
+	waitlist := bookingworker.NewWaitlistScheduler(service, cfg.JWTSecret, log)
+	if err := waitlist.Start(); err != nil {
+		log.Fatal("failed to start waitlist scheduler", zap.Error(err))
+	}
+	defer waitlist.Stop()
+
+	outboxRelay := bookingworker.NewOutboxRelayScheduler(service, log)
+	if err := outboxRelay.Start(); err != nil {
+		log.Fatal("failed to start outbox relay scheduler", zap.Error(err))
+	}
+	defer outboxRelay.Stop()
+
+	exchangeRates := bookingworker.NewExchangeRateScheduler(service, cfg.ExchangeRateInterval, log)
+	if err := exchangeRates.Start(); err != nil {
+		log.Fatal("failed to start exchange rate scheduler", zap.Error(err))
+	}
+	defer exchangeRates.Stop()
+
+	inventoryHolds := bookingworker.NewInventoryHoldScheduler(service, log)
+	if err := inventoryHolds.Start(); err != nil {
+		log.Fatal("failed to start inventory hold scheduler", zap.Error(err))
+	}
+	defer inventoryHolds.Stop()
+
 	<-ctx.Done()
 	log.Info("Shutting down gracefully...")
 	scheduler.Stop()
+	holdExpiry.Stop()
+	noShow.Stop()
+	waitlist.Stop()
+	outboxRelay.Stop()
+	exchangeRates.Stop()
+	inventoryHolds.Stop()
 	_ = srv.Stop(context.Background())
 }
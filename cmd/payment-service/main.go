@@ -37,6 +37,14 @@ func main() {
 	if err := paymentrepo.AutoMigrate(db); err != nil {
 		log.Fatal("failed to run migrations", zap.Error(err))
 	}
+	idempotency := database.NewIdempotencyStore(db)
+	if err := idempotency.AutoMigrate(); err != nil {
+		log.Fatal("failed to run idempotency migrations", zap.Error(err))
+	}
+	auditLog := database.NewAuditLog(db)
+	if err := auditLog.AutoMigrate(); err != nil {
+		log.Fatal("failed to run audit log migrations", zap.Error(err))
+	}
 
 	repo := paymentrepo.NewGormRepository(db)
 	var provider paymentdomain.Provider
@@ -51,16 +59,25 @@ func main() {
 	} else {
 		provider = paymentprovider.NewXenditMockProvider(cfg.PaymentProviderKey)
 	}
-	statusClient := paymentbooking.NewHTTPStatusClient(cfg.BookingServiceURL)
+	statusClient := paymentbooking.NewHTTPStatusClient(cfg.BookingServiceURL, cfg.JWTSecret)
 	service := paymentuc.NewService(repo, provider, statusClient)
+	service.SetAuditLog(auditLog)
 	handler := paymenthttp.NewHandler(service)
 
+	idempotent := middleware.Idempotency(idempotency, "payment", cfg.IdempotencyKeyTTL)
 	api := chi.NewRouter()
-	api.Use(middleware.JWT(cfg.JWTSecret))
-	api.Post("/payments", handler.CreatePayment)
-	api.Get("/payments/{id}", handler.GetPayment)
-	api.Get("/payments/by-booking/{booking_id}", handler.GetByBooking)
-	api.Post("/payments/refund", handler.Refund)
+	api.Group(func(r chi.Router) {
+		r.Use(middleware.JWT(cfg.JWTSecret))
+		r.With(idempotent).Post("/payments", handler.CreatePayment)
+		r.Get("/payments/{id}", handler.GetPayment)
+		r.Get("/payments/by-booking/{booking_id}", handler.GetByBooking)
+	})
+	// Refunds and expiries are issued by admins and by the booking service's service tokens.
+	api.Group(func(r chi.Router) {
+		r.Use(middleware.JWT(cfg.JWTSecret, "admin", "service"))
+		r.With(idempotent).Post("/payments/refund", handler.Refund)
+		r.Post("/payments/{id}/expire", handler.Expire)
+	})
 
 	r := chi.NewRouter()
 	r.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) {
@@ -9,6 +9,7 @@ import (
 	"github.com/ftryyln/hotel-booking-microservices/pkg/domain"
 	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 const (
@@ -17,20 +18,51 @@ const (
 	StatusCancelled      = "cancelled"
 	StatusCheckedIn      = "checked_in"
 	StatusCompleted      = "completed"
+	StatusNoShow         = "no_show"
 )
 
+// ReleasedStatuses lists statuses whose bookings no longer occupy inventory.
+func ReleasedStatuses() []string {
+	return []string{StatusCancelled, StatusNoShow}
+}
+
+// CancelReasonPaymentTimeout marks bookings whose payment hold expired unpaid.
+const CancelReasonPaymentTimeout = "payment_timeout"
+
+// ErrVersionConflict is returned by Save when the booking changed since it was loaded.
+var ErrVersionConflict = pkgErrors.New("conflict", "booking was modified by another request")
+
 // Booking aggregate.
+// RoomTypeID, Guests and TotalPrice summarise the active line items of a group booking.
 type Booking struct {
 	ID          uuid.UUID
 	UserID      uuid.UUID
 	RoomTypeID  uuid.UUID
+	RoomID      uuid.UUID // physical room, uuid.Nil until assigned
 	CheckIn     time.Time
 	CheckOut    time.Time
 	Status      string
 	Guests      int
-	TotalPrice  float64
+	TotalPrice  valueobject.Amount
 	TotalNights int
 	CreatedAt   time.Time
+	ExpiresAt   time.Time // end of the pending_payment hold, zero when none
+	Items       []LineItem
+	Version     int // incremented on every save; guards against lost updates
+
+	Guest           valueobject.GuestDetails
+	ArrivalTime     string // expected arrival as HH:MM, empty when unknown
+	SpecialRequests string
+	PromoCode       string      // promotion redeemed when booking, empty when none
+	Notes           []StaffNote // internal staff notes, never shown to the guest
+
+	// PaymentTiming is valueobject.PayAtHotel when the rate plans booked are paid on arrival. Other
+	// bookings, including those made before rate plans, are paid when booking.
+	PaymentTiming string
+
+	Currency       string                   // currency of the hotel, which every price of the booking is in
+	ExchangeRate   valueobject.ExchangeRate // snapshot taken when booking, from Currency into the display currency
+	ChargeCurrency string                   // currency the guest pays in: Currency or the display currency
 
 	// events stores domain events raised by this aggregate
 	events []domain.DomainEvent
@@ -46,16 +78,113 @@ func (b *Booking) Confirm() error {
 	return nil
 }
 
-// Cancel transitions booking to cancelled state.
-func (b *Booking) Cancel(reason string) error {
-	if b.Status == StatusConfirmed || b.Status == StatusCheckedIn || b.Status == StatusCompleted {
-		return pkgErrors.New("bad_request", "cannot cancel booking after confirmation")
+// Cancel transitions booking to cancelled state and returns the amount to refund.
+// Unpaid bookings and bookings paid at the hotel refund nothing; each active item of a confirmed
+// booking is refunded according to its policy in policies, keyed by item ID. The event reports
+// the policy of the primary item.
+func (b *Booking) Cancel(reason string, policies map[uuid.UUID]valueobject.CancellationPolicy, now time.Time) (valueobject.Amount, error) {
+	var refund valueobject.Amount
+	if b.Status == StatusCheckedIn || b.Status == StatusCompleted {
+		return refund, pkgErrors.New("bad_request", "cannot cancel booking after check-in")
 	}
 	if b.Status == StatusCancelled {
-		return pkgErrors.New("bad_request", "booking already cancelled")
+		return refund, pkgErrors.New("bad_request", "booking already cancelled")
+	}
+	active := b.ActiveItems()
+	if b.Status == StatusConfirmed && !b.PaysAtHotel() {
+		for _, item := range active {
+			paid := b.price(item.Price.Total)
+			refund = refund.Add(policies[item.ID].RefundFor(paid, b.TotalNights, b.CheckIn, now).Amount)
+		}
+	}
+	var code string
+	if len(active) > 0 {
+		code = policies[active[0].ID].Code
 	}
 	b.Status = StatusCancelled
-	b.RecordEvent(NewBookingCancelled(b.ID, reason))
+	b.RecordEvent(NewBookingCancelled(b.ID, reason, code, refund))
+	return refund, nil
+}
+
+// PaysAtHotel reports whether the guest pays the hotel on arrival rather than when booking.
+func (b Booking) PaysAtHotel() bool {
+	return b.PaymentTiming == valueobject.PayAtHotel
+}
+
+// Terms captures the guest-changeable parts of a booking.
+type Terms struct {
+	CheckIn    time.Time
+	CheckOut   time.Time
+	Guests     int
+	TotalPrice valueobject.Amount
+}
+
+// Terms returns the current stay, guest count and price.
+func (b Booking) Terms() Terms {
+	return Terms{CheckIn: b.CheckIn, CheckOut: b.CheckOut, Guests: b.Guests, TotalPrice: b.TotalPrice}
+}
+
+// HoldExpired reports whether an unpaid booking has outlived its payment hold.
+func (b Booking) HoldExpired(now time.Time) bool {
+	return b.Status == StatusPendingPayment && !b.ExpiresAt.IsZero() && !now.Before(b.ExpiresAt)
+}
+
+// ExpireHold cancels an unpaid booking whose hold has expired, releasing its inventory.
+func (b *Booking) ExpireHold(now time.Time) error {
+	if !b.HoldExpired(now) {
+		return pkgErrors.New("bad_request", "booking hold has not expired")
+	}
+	b.Status = StatusCancelled
+	b.RecordEvent(NewBookingCancelled(b.ID, CancelReasonPaymentTimeout, "", valueobject.Amount{}))
+	return nil
+}
+
+// Modify changes the stay and guest count of a confirmed single-room booking to an already repriced total.
+// Availability of the new stay must be checked by the caller.
+func (b *Booking) Modify(stay valueobject.DateRange, guests int, price PriceBreakdown) error {
+	if b.Status != StatusConfirmed {
+		return pkgErrors.New("bad_request", "only confirmed bookings can be modified")
+	}
+	if len(b.ActiveItems()) > 1 {
+		return pkgErrors.New("bad_request", "group bookings cannot be modified, cancel individual items instead")
+	}
+	if guests <= 0 {
+		return pkgErrors.New("bad_request", "guests must be positive")
+	}
+	if stay.Start.Equal(b.CheckIn) && stay.End.Equal(b.CheckOut) && guests == b.Guests {
+		return pkgErrors.New("bad_request", "modification does not change the booking")
+	}
+	old := b.Terms()
+	b.setTerms(Terms{CheckIn: stay.Start, CheckOut: stay.End, Guests: guests, TotalPrice: price.Total}, price)
+	b.RecordEvent(NewBookingModified(b.ID, old, b.Terms(), price))
+	return nil
+}
+
+// setTerms moves the booking and its active room to new terms.
+func (b *Booking) setTerms(t Terms, price PriceBreakdown) {
+	b.CheckIn = t.CheckIn
+	b.CheckOut = t.CheckOut
+	b.Guests = t.Guests
+	b.TotalPrice = t.TotalPrice
+	b.TotalNights = b.Stay().Nights()
+	for i := range b.Items {
+		if b.Items[i].Active() {
+			b.Items[i].Guests = t.Guests
+			b.Items[i].Price = price
+		}
+	}
+}
+
+// AssignRoom allocates a physical room of the booked room type.
+func (b *Booking) AssignRoom(roomID, roomTypeID uuid.UUID) error {
+	if b.Status != StatusConfirmed {
+		return pkgErrors.New("bad_request", "room can only be assigned to a confirmed booking")
+	}
+	if roomTypeID != b.RoomTypeID {
+		return pkgErrors.New("bad_request", "room does not belong to the booked room type")
+	}
+	b.RoomID = roomID
+	b.RecordEvent(NewBookingRoomAssigned(b.ID, roomID))
 	return nil
 }
 
@@ -64,6 +193,9 @@ func (b *Booking) GuestCheckIn() error {
 	if b.Status != StatusConfirmed {
 		return pkgErrors.New("bad_request", "booking must be confirmed before check-in")
 	}
+	if b.RoomID == uuid.Nil {
+		return pkgErrors.New("bad_request", "room must be assigned before check-in")
+	}
 	b.Status = StatusCheckedIn
 	b.RecordEvent(NewBookingCheckedIn(b.ID))
 	return nil
@@ -79,6 +211,46 @@ func (b *Booking) Complete() error {
 	return nil
 }
 
+// MarkNoShow records that the guest of a confirmed booking never arrived and returns the amount to refund.
+// Nothing was paid up front for a booking paid at the hotel, so it refunds nothing.
+// The booking must be past its check-in day, compared on dates so it matches the hotel calendar.
+func (b *Booking) MarkNoShow(policy valueobject.NoShowPolicy, now time.Time) (valueobject.Amount, error) {
+	var refund valueobject.Amount
+	if b.Status != StatusConfirmed {
+		return refund, pkgErrors.New("bad_request", "only confirmed bookings can be marked as no-show")
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	checkInDay := time.Date(b.CheckIn.Year(), b.CheckIn.Month(), b.CheckIn.Day(), 0, 0, 0, 0, now.Location())
+	if !checkInDay.Before(today) {
+		return refund, pkgErrors.New("bad_request", "check-in day has not passed yet")
+	}
+	charged := policy.Charge(b.price(b.TotalPrice), b.TotalNights).Amount
+	if !b.PaysAtHotel() {
+		refund = b.TotalPrice.Sub(charged)
+	}
+	b.Status = StatusNoShow
+	b.RecordEvent(NewBookingNoShow(b.ID, charged, refund))
+	return refund, nil
+}
+
+// CheckVersion enforces the version a caller last saw, such as an If-Match header. Zero accepts any version.
+func (b Booking) CheckVersion(expected int) error {
+	if expected != 0 && expected != b.Version {
+		return pkgErrors.New("precondition_failed", "booking has changed, reload it and retry")
+	}
+	return nil
+}
+
+// Stay returns the booked date range.
+func (b Booking) Stay() valueobject.DateRange {
+	return valueobject.DateRange{Start: b.CheckIn, End: b.CheckOut}
+}
+
+// HoldsInventory reports whether the booking still occupies a room.
+func (b Booking) HoldsInventory() bool {
+	return b.Status != StatusCancelled && b.Status != StatusNoShow
+}
+
 // Events returns the domain events raised by this aggregate.
 func (b *Booking) Events() []domain.DomainEvent {
 	return b.events
@@ -93,29 +265,76 @@ func (b *Booking) RecordEvent(event domain.DomainEvent) {
 	b.events = append(b.events, event)
 }
 
+// ListSchema is the allow-list of fields booking lists can be filtered and sorted by.
+var ListSchema = query.Schema{
+	Fields: map[string]query.Field{
+		"status":       {Type: query.String, Ops: []query.Op{query.OpEq}},
+		"user_id":      {Type: query.UUID, Ops: []query.Op{query.OpEq}},
+		"room_type_id": {Type: query.UUID, Ops: []query.Op{query.OpEq}},
+		"check_in":     {Type: query.Time, Ops: []query.Op{query.OpGte, query.OpLte}, Sortable: true},
+		"check_out":    {Type: query.Time, Ops: []query.Op{query.OpGte, query.OpLte}, Sortable: true},
+		"created_at":   {Type: query.Time, Ops: []query.Op{query.OpGte, query.OpLte}, Sortable: true},
+	},
+	DefaultSort: query.Sort{Field: "created_at", Desc: true},
+}
+
 // BookingReader handles queries (CQRS Read Side).
 type BookingReader interface {
 	FindByID(ctx context.Context, id uuid.UUID) (Booking, error)
-	List(ctx context.Context, opts query.Options) ([]Booking, error)
-	FindByUserID(ctx context.Context, userID uuid.UUID) ([]Booking, error)
+	// List returns a page of bookings filtered and sorted by fields of ListSchema.
+	List(ctx context.Context, opts query.Options) (query.Page[Booking], error)
+	// FindByUserID is List restricted to the bookings of one user.
+	FindByUserID(ctx context.Context, userID uuid.UUID, opts query.Options) (query.Page[Booking], error)
+	// FindOverlapping returns bookings still holding inventory of a room type that overlap the stay.
+	FindOverlapping(ctx context.Context, roomTypeID uuid.UUID, stay valueobject.DateRange) ([]Booking, error)
+	// FindByRoomID returns bookings still holding inventory assigned to a room that overlap the stay.
+	FindByRoomID(ctx context.Context, roomID uuid.UUID, stay valueobject.DateRange) ([]Booking, error)
+	// FindExpiredHolds locks up to limit pending bookings whose hold expired before now.
+	// Rows locked by another transaction are skipped, so it must run inside a transaction.
+	FindExpiredHolds(ctx context.Context, now time.Time, limit int) ([]Booking, error)
+	// FindMissedCheckIns locks up to limit confirmed bookings checking in before the given day.
+	// Like FindExpiredHolds it skips locked rows and must run inside a transaction.
+	FindMissedCheckIns(ctx context.Context, before time.Time, limit int) ([]Booking, error)
 }
 
 // BookingWriter handles commands (CQRS Write Side).
 type BookingWriter interface {
 	Create(ctx context.Context, b Booking) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
-	Save(ctx context.Context, b Booking) error
+	// Save persists b only if the stored version still matches b.Version, then advances b.Version.
+	// It returns ErrVersionConflict when another writer saved the booking first.
+	Save(ctx context.Context, b *Booking) error
+}
+
+// Transactional runs work atomically; repositories reusing the given context join the transaction.
+type Transactional interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 // Repository handles persistence (combines Read and Write).
 type Repository interface {
 	BookingReader
 	BookingWriter
+	Transactional
+	OutboxRepository
+	HistoryRepository
+	WaitlistRepository
+	PromotionRepository
+	ExchangeRateRepository
+	InventoryHoldRepository
 }
 
 // PaymentGateway used by booking service.
+// Payments are initiated for the booking's owner, who may then read and refund them.
 type PaymentGateway interface {
-	Initiate(ctx context.Context, bookingID uuid.UUID, amount float64) (PaymentResult, error)
+	// Initiate charges amount, itemised by lines, for the booking.
+	Initiate(ctx context.Context, bookingID, userID uuid.UUID, amount valueobject.Money, lines []PriceLine) (PaymentResult, error)
+	// InitiateSupplement charges an additional amount on top of the booking's original payment.
+	InitiateSupplement(ctx context.Context, bookingID, userID uuid.UUID, amount valueobject.Money) (PaymentResult, error)
+	// Refund returns part of what the booking's payments collected, its supplements included.
+	Refund(ctx context.Context, bookingID uuid.UUID, amount valueobject.Money, reason string) error
+	// Expire voids the booking's pending payment. It returns a conflict error when the payment was already settled.
+	Expire(ctx context.Context, bookingID uuid.UUID) error
 }
 
 // NotificationGateway for events.
@@ -0,0 +1,86 @@
+package booking
+
+import (
+	"context"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// PriceCurrency returns the currency the booking's prices are in. Bookings made before hotels had a
+// currency were priced in the default one.
+func (b Booking) PriceCurrency() string {
+	if b.Currency == "" {
+		return valueobject.DefaultCurrency
+	}
+	return b.Currency
+}
+
+// DisplayRate returns the rate snapshot prices are shown at, which leaves them unchanged when the
+// guest did not ask for another currency.
+func (b Booking) DisplayRate() valueobject.ExchangeRate {
+	if b.ExchangeRate.Base == "" {
+		return valueobject.IdentityRate(b.PriceCurrency())
+	}
+	return b.ExchangeRate
+}
+
+// ChargeRate returns the rate amounts are charged and refunded at: the snapshot when the guest pays
+// in the display currency, none when they pay in the hotel's currency.
+func (b Booking) ChargeRate() valueobject.ExchangeRate {
+	if rate := b.DisplayRate(); b.ChargeCurrency == rate.Quote {
+		return rate
+	}
+	return valueobject.IdentityRate(b.PriceCurrency())
+}
+
+// price returns an amount of the booking's currency as money.
+func (b Booking) price(amount valueobject.Amount) valueobject.Money {
+	return valueobject.Money{Amount: amount, Currency: b.PriceCurrency()}
+}
+
+// Charge converts an amount of the booking's currency into the money charged or refunded for it.
+// The rate snapshot taken when booking is used, so a refund returns what was paid for the same amount.
+func (b Booking) Charge(amount valueobject.Amount) valueobject.Money {
+	price := b.price(amount)
+	charged, err := price.Convert(b.ChargeRate())
+	if err != nil {
+		// The snapshot always converts from the booking's own currency.
+		return price
+	}
+	return charged
+}
+
+// ChargeLines itemises the charged total in the charge currency.
+func (b Booking) ChargeLines() []PriceLine {
+	return ConvertLines(b.PriceLines(), b.TotalPrice, b.ChargeRate())
+}
+
+// ConvertLines converts the lines of total at rate. Each line is rounded on its own, so the rounding
+// difference is put on the first line to keep the lines adding up to the converted total.
+func ConvertLines(lines []PriceLine, total valueobject.Amount, rate valueobject.ExchangeRate) []PriceLine {
+	out := make([]PriceLine, 0, len(lines))
+	var sum valueobject.Amount
+	for _, l := range lines {
+		l.Amount = rate.Apply(l.Amount)
+		if !l.Included {
+			sum = sum.Add(l.Amount)
+		}
+		out = append(out, l)
+	}
+	if len(out) > 0 && !rate.IsIdentity() {
+		out[0].Amount = out[0].Amount.Add(rate.Apply(total).Sub(sum))
+	}
+	return out
+}
+
+// ExchangeRateRepository stores the latest rate of every currency pair.
+type ExchangeRateRepository interface {
+	// SaveExchangeRates replaces the stored rates of the given pairs.
+	SaveExchangeRates(ctx context.Context, rates []valueobject.ExchangeRate) error
+	ListExchangeRates(ctx context.Context) (valueobject.ExchangeRates, error)
+}
+
+// ExchangeRateProvider fetches the latest rates from an external source.
+type ExchangeRateProvider interface {
+	LatestRates(ctx context.Context) ([]valueobject.ExchangeRate, error)
+}
@@ -1,39 +1,79 @@
 package booking
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 
 	"github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // Event type constants
 const (
-	EventTypeBookingCreated   = "booking.created"
-	EventTypeBookingConfirmed = "booking.confirmed"
-	EventTypeBookingCancelled = "booking.cancelled"
-	EventTypeBookingCheckedIn = "booking.checked_in"
-	EventTypeBookingCompleted = "booking.completed"
+	EventTypeBookingCreated       = "booking.created"
+	EventTypeBookingConfirmed     = "booking.confirmed"
+	EventTypeBookingCancelled     = "booking.cancelled"
+	EventTypeBookingModified      = "booking.modified"
+	EventTypeBookingRoomAssigned  = "booking.room_assigned"
+	EventTypeBookingCheckedIn     = "booking.checked_in"
+	EventTypeBookingCompleted     = "booking.completed"
+	EventTypeBookingNoShow        = "booking.no_show"
+	EventTypeBookingItemCancelled = "booking.item_cancelled"
 )
 
 // BookingCreated event is raised when a new booking is created.
+// It carries the full initial state so the booking can be rebuilt from its history.
 type BookingCreated struct {
 	domain.BaseEvent
-	BookingID  uuid.UUID
-	UserID     uuid.UUID
-	RoomTypeID uuid.UUID
-	TotalPrice float64
-	Guests     int
+	BookingID   uuid.UUID
+	UserID      uuid.UUID
+	RoomTypeID  uuid.UUID
+	TotalPrice  valueobject.Amount
+	Guests      int
+	CheckIn     time.Time
+	CheckOut    time.Time
+	TotalNights int
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	Items       []LineItem
+
+	Guest           valueobject.GuestDetails
+	ArrivalTime     string
+	SpecialRequests string
+	PromoCode       string
+	PaymentTiming   string
+
+	Currency       string
+	ExchangeRate   valueobject.ExchangeRate
+	ChargeCurrency string
 }
 
-// NewBookingCreated creates a new BookingCreated event.
-func NewBookingCreated(bookingID, userID, roomTypeID uuid.UUID, totalPrice float64, guests int) BookingCreated {
+// NewBookingCreated creates a new BookingCreated event from the freshly created booking.
+func NewBookingCreated(b Booking) BookingCreated {
 	return BookingCreated{
-		BaseEvent:  domain.NewBaseEvent(bookingID, EventTypeBookingCreated),
-		BookingID:  bookingID,
-		UserID:     userID,
-		RoomTypeID: roomTypeID,
-		TotalPrice: totalPrice,
-		Guests:     guests,
+		BaseEvent:   domain.NewBaseEvent(b.ID, EventTypeBookingCreated),
+		BookingID:   b.ID,
+		UserID:      b.UserID,
+		RoomTypeID:  b.RoomTypeID,
+		TotalPrice:  b.TotalPrice,
+		Guests:      b.Guests,
+		CheckIn:     b.CheckIn,
+		CheckOut:    b.CheckOut,
+		TotalNights: b.TotalNights,
+		CreatedAt:   b.CreatedAt,
+		ExpiresAt:   b.ExpiresAt,
+		Items:       b.Items,
+
+		Guest:           b.Guest,
+		ArrivalTime:     b.ArrivalTime,
+		SpecialRequests: b.SpecialRequests,
+		PromoCode:       b.PromoCode,
+		PaymentTiming:   b.PaymentTiming,
+
+		Currency:       b.Currency,
+		ExchangeRate:   b.ExchangeRate,
+		ChargeCurrency: b.ChargeCurrency,
 	}
 }
 
@@ -54,16 +94,58 @@ func NewBookingConfirmed(bookingID uuid.UUID) BookingConfirmed {
 // BookingCancelled event is raised when a booking is cancelled.
 type BookingCancelled struct {
 	domain.BaseEvent
-	BookingID uuid.UUID
-	Reason    string
+	BookingID    uuid.UUID
+	Reason       string
+	Policy       string // cancellation policy applied
+	RefundAmount valueobject.Amount
 }
 
 // NewBookingCancelled creates a new BookingCancelled event.
-func NewBookingCancelled(bookingID uuid.UUID, reason string) BookingCancelled {
+func NewBookingCancelled(bookingID uuid.UUID, reason, policy string, refundAmount valueobject.Amount) BookingCancelled {
 	return BookingCancelled{
-		BaseEvent: domain.NewBaseEvent(bookingID, EventTypeBookingCancelled),
+		BaseEvent:    domain.NewBaseEvent(bookingID, EventTypeBookingCancelled),
+		BookingID:    bookingID,
+		Reason:       reason,
+		Policy:       policy,
+		RefundAmount: refundAmount,
+	}
+}
+
+// BookingModified event is raised when the dates or guest count of a booking change.
+type BookingModified struct {
+	domain.BaseEvent
+	BookingID  uuid.UUID
+	Old        Terms
+	New        Terms
+	Price      PriceBreakdown // breakdown of the new price
+	PriceDelta valueobject.Amount
+}
+
+// NewBookingModified creates a new BookingModified event.
+func NewBookingModified(bookingID uuid.UUID, old, updated Terms, price PriceBreakdown) BookingModified {
+	return BookingModified{
+		BaseEvent:  domain.NewBaseEvent(bookingID, EventTypeBookingModified),
+		BookingID:  bookingID,
+		Old:        old,
+		New:        updated,
+		Price:      price,
+		PriceDelta: updated.TotalPrice.Sub(old.TotalPrice),
+	}
+}
+
+// BookingRoomAssigned event is raised when a physical room is allocated to a booking.
+type BookingRoomAssigned struct {
+	domain.BaseEvent
+	BookingID uuid.UUID
+	RoomID    uuid.UUID
+}
+
+// NewBookingRoomAssigned creates a new BookingRoomAssigned event.
+func NewBookingRoomAssigned(bookingID, roomID uuid.UUID) BookingRoomAssigned {
+	return BookingRoomAssigned{
+		BaseEvent: domain.NewBaseEvent(bookingID, EventTypeBookingRoomAssigned),
 		BookingID: bookingID,
-		Reason:    reason,
+		RoomID:    roomID,
 	}
 }
 
@@ -94,3 +176,43 @@ func NewBookingCompleted(bookingID uuid.UUID) BookingCompleted {
 		BookingID: bookingID,
 	}
 }
+
+// BookingNoShow event is raised when the guest of a confirmed booking never arrived.
+type BookingNoShow struct {
+	domain.BaseEvent
+	BookingID     uuid.UUID
+	ChargedAmount valueobject.Amount
+	RefundAmount  valueobject.Amount
+}
+
+// NewBookingNoShow creates a new BookingNoShow event.
+func NewBookingNoShow(bookingID uuid.UUID, charged, refund valueobject.Amount) BookingNoShow {
+	return BookingNoShow{
+		BaseEvent:     domain.NewBaseEvent(bookingID, EventTypeBookingNoShow),
+		BookingID:     bookingID,
+		ChargedAmount: charged,
+		RefundAmount:  refund,
+	}
+}
+
+// BookingItemCancelled event is raised when a single room of a group booking is cancelled.
+type BookingItemCancelled struct {
+	domain.BaseEvent
+	BookingID    uuid.UUID
+	ItemID       uuid.UUID
+	RoomTypeID   uuid.UUID
+	Policy       string
+	RefundAmount valueobject.Amount
+}
+
+// NewBookingItemCancelled creates a new BookingItemCancelled event.
+func NewBookingItemCancelled(bookingID, itemID, roomTypeID uuid.UUID, policy string, refund valueobject.Amount) BookingItemCancelled {
+	return BookingItemCancelled{
+		BaseEvent:    domain.NewBaseEvent(bookingID, EventTypeBookingItemCancelled),
+		BookingID:    bookingID,
+		ItemID:       itemID,
+		RoomTypeID:   roomTypeID,
+		Policy:       policy,
+		RefundAmount: refund,
+	}
+}
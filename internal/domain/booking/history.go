@@ -0,0 +1,151 @@
+package booking
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// SystemActor is recorded for changes made by background jobs, which act without a user.
+const SystemActor = "system"
+
+// Actor identifies who caused a change to a booking.
+type Actor struct {
+	ID   string // user id, the calling service for service tokens, or SystemActor
+	Role string
+}
+
+// HistoryEntry is one event of a booking's append-only history.
+type HistoryEntry struct {
+	ID         uuid.UUID
+	BookingID  uuid.UUID
+	EventType  string
+	Payload    []byte // JSON encoded event
+	Actor      Actor
+	OccurredAt time.Time
+}
+
+// HistoryRepository is the append-only event store of bookings.
+type HistoryRepository interface {
+	// AppendHistory records events with the actor found on ctx; call it in the transaction writing the aggregate.
+	AppendHistory(ctx context.Context, events []domain.DomainEvent) error
+	// History returns the events of a booking, oldest first.
+	History(ctx context.Context, bookingID uuid.UUID) ([]HistoryEntry, error)
+	// HistoryBookingIDs returns every booking that has recorded history.
+	HistoryBookingIDs(ctx context.Context) ([]uuid.UUID, error)
+}
+
+// Replay rebuilds a booking by applying its history in order.
+// The history must start with the booking.created event.
+func Replay(entries []HistoryEntry) (Booking, error) {
+	var b Booking
+	for _, entry := range entries {
+		event, err := DecodeEvent(entry.EventType, entry.Payload)
+		if err != nil {
+			return Booking{}, err
+		}
+		if err := b.apply(event); err != nil {
+			return Booking{}, err
+		}
+	}
+	if b.ID == uuid.Nil {
+		return Booking{}, pkgErrors.New("not_found", "booking history not found")
+	}
+	return b, nil
+}
+
+// DecodeEvent turns a stored event payload back into its domain event.
+func DecodeEvent(eventType string, payload []byte) (domain.DomainEvent, error) {
+	switch eventType {
+	case EventTypeBookingCreated:
+		return decodeEvent[BookingCreated](payload)
+	case EventTypeBookingConfirmed:
+		return decodeEvent[BookingConfirmed](payload)
+	case EventTypeBookingCancelled:
+		return decodeEvent[BookingCancelled](payload)
+	case EventTypeBookingModified:
+		return decodeEvent[BookingModified](payload)
+	case EventTypeBookingRoomAssigned:
+		return decodeEvent[BookingRoomAssigned](payload)
+	case EventTypeBookingCheckedIn:
+		return decodeEvent[BookingCheckedIn](payload)
+	case EventTypeBookingCompleted:
+		return decodeEvent[BookingCompleted](payload)
+	case EventTypeBookingNoShow:
+		return decodeEvent[BookingNoShow](payload)
+	case EventTypeBookingItemCancelled:
+		return decodeEvent[BookingItemCancelled](payload)
+	default:
+		return nil, pkgErrors.New("bad_request", "unknown booking event type "+eventType)
+	}
+}
+
+func decodeEvent[T domain.DomainEvent](payload []byte) (domain.DomainEvent, error) {
+	var event T
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// apply replays the state change an event describes without re-running business rules.
+func (b *Booking) apply(event domain.DomainEvent) error {
+	if _, created := event.(BookingCreated); !created && b.ID == uuid.Nil {
+		return pkgErrors.New("bad_request", "booking history must start with booking.created")
+	}
+	switch e := event.(type) {
+	case BookingCreated:
+		*b = Booking{
+			ID:          e.BookingID,
+			UserID:      e.UserID,
+			RoomTypeID:  e.RoomTypeID,
+			CheckIn:     e.CheckIn,
+			CheckOut:    e.CheckOut,
+			Status:      StatusPendingPayment,
+			Guests:      e.Guests,
+			TotalPrice:  e.TotalPrice,
+			TotalNights: e.TotalNights,
+			CreatedAt:   e.CreatedAt,
+			ExpiresAt:   e.ExpiresAt,
+			Items:       e.Items,
+
+			Guest:           e.Guest,
+			ArrivalTime:     e.ArrivalTime,
+			SpecialRequests: e.SpecialRequests,
+			PromoCode:       e.PromoCode,
+			PaymentTiming:   e.PaymentTiming,
+
+			Currency:       e.Currency,
+			ExchangeRate:   e.ExchangeRate,
+			ChargeCurrency: e.ChargeCurrency,
+		}
+	case BookingConfirmed:
+		b.Status = StatusConfirmed
+	case BookingCancelled:
+		b.Status = StatusCancelled
+	case BookingModified:
+		b.setTerms(e.New, e.Price)
+	case BookingRoomAssigned:
+		b.RoomID = e.RoomID
+	case BookingCheckedIn:
+		b.Status = StatusCheckedIn
+	case BookingCompleted:
+		b.Status = StatusCompleted
+	case BookingNoShow:
+		b.Status = StatusNoShow
+	case BookingItemCancelled:
+		items := b.LineItems()
+		for i := range items {
+			if items[i].ID == e.ItemID {
+				items[i].Status = ItemStatusCancelled
+			}
+		}
+		b.SetItems(items)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+const (
+	HoldActive   = "active"
+	HoldReleased = "released" // given up by the guest
+	HoldConsumed = "consumed" // turned into a booking
+	HoldExpired  = "expired"
+)
+
+// How long an inventory hold lasts when the guest does not say, and at most.
+const (
+	DefaultInventoryHold = 10 * time.Minute
+	MaxInventoryHold     = 30 * time.Minute
+)
+
+// InventoryHold keeps rooms of a room type aside for a stay while a guest fills in the checkout
+// form, so nobody else can book them until the hold expires. A hold is not a booking: it is
+// never charged, and it frees its rooms when it expires or is turned into a booking.
+type InventoryHold struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	SessionID  string // checkout session of the user, empty when none
+	RoomTypeID uuid.UUID
+	CheckIn    time.Time
+	CheckOut   time.Time
+	Rooms      int
+	Status     string
+	BookingID  uuid.UUID // booking made from the hold, uuid.Nil until consumed
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+}
+
+// Stay returns the dates the hold keeps rooms for.
+func (h InventoryHold) Stay() valueobject.DateRange {
+	return valueobject.DateRange{Start: h.CheckIn, End: h.CheckOut}
+}
+
+// Active reports whether the hold still keeps its rooms aside at now.
+func (h InventoryHold) Active(now time.Time) bool {
+	return h.Status == HoldActive && now.Before(h.ExpiresAt)
+}
+
+// Release gives the rooms up before the hold expires.
+func (h *InventoryHold) Release(now time.Time) error {
+	if !h.Active(now) {
+		return pkgErrors.New("conflict", "hold is no longer active")
+	}
+	h.Status = HoldReleased
+	return nil
+}
+
+// Consume turns the hold into the booking that takes over its rooms.
+func (h *InventoryHold) Consume(bookingID uuid.UUID, now time.Time) error {
+	if !h.Active(now) {
+		return pkgErrors.New("conflict", "hold has expired, hold the rooms again")
+	}
+	h.Status = HoldConsumed
+	h.BookingID = bookingID
+	return nil
+}
+
+// InventoryHoldRepository stores inventory holds. Holds past their expiry no longer count as active,
+// whether or not ExpireInventoryHolds has marked them yet.
+type InventoryHoldRepository interface {
+	CreateInventoryHold(ctx context.Context, h InventoryHold) error
+	SaveInventoryHold(ctx context.Context, h InventoryHold) error
+	FindInventoryHold(ctx context.Context, id uuid.UUID) (InventoryHold, error)
+	// FindActiveHolds returns the holds active at now keeping rooms of the room type on a night of the stay.
+	FindActiveHolds(ctx context.Context, roomTypeID uuid.UUID, stay valueobject.DateRange, now time.Time) ([]InventoryHold, error)
+	// FindUserHolds returns the holds of the user active at now.
+	FindUserHolds(ctx context.Context, userID uuid.UUID, now time.Time) ([]InventoryHold, error)
+	// ExpireInventoryHolds marks the active holds that ran out by now as expired and returns how many.
+	ExpireInventoryHolds(ctx context.Context, now time.Time) (int, error)
+}
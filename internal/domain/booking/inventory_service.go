@@ -0,0 +1,83 @@
+package booking
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// NightAvailability reports how many rooms remain free on a single night.
+type NightAvailability struct {
+	Date      time.Time
+	Available int
+}
+
+// InventoryService handles room availability calculations (pure domain logic).
+type InventoryService struct{}
+
+// NewInventoryService creates a new InventoryService.
+func NewInventoryService() *InventoryService {
+	return &InventoryService{}
+}
+
+// NightlyAvailability returns the free rooms of a room type for each night of the stay.
+// A group booking consumes one room per active line item of that room type, and an active hold the
+// rooms it keeps aside. Nights are compared as calendar dates, so a stay checking out at noon
+// frees its room for a guest checking in that afternoon.
+func (s *InventoryService) NightlyAvailability(roomTypeID uuid.UUID, totalRooms int, stay valueobject.DateRange, bookings []Booking, holds []InventoryHold) []NightAvailability {
+	var active []Booking
+	for _, b := range bookings {
+		if b.RoomsHeld(roomTypeID) > 0 {
+			active = append(active, b)
+		}
+	}
+	var held []InventoryHold
+	for _, h := range holds {
+		if h.RoomTypeID == roomTypeID && h.Status == HoldActive {
+			held = append(held, h)
+		}
+	}
+
+	nights := stay.NightDates()
+	out := make([]NightAvailability, 0, len(nights))
+	for _, night := range nights {
+		occupied := 0
+		for _, b := range active {
+			if b.Stay().Contains(night) {
+				occupied += b.RoomsHeld(roomTypeID)
+			}
+		}
+		for _, h := range held {
+			if h.Stay().Contains(night) {
+				occupied += h.Rooms
+			}
+		}
+		available := totalRooms - occupied
+		if available < 0 {
+			available = 0
+		}
+		out = append(out, NightAvailability{Date: night, Available: available})
+	}
+	return out
+}
+
+// RoomsLeft returns how many rooms of the room type stay free on every night of the requested stay.
+func (s *InventoryService) RoomsLeft(roomTypeID uuid.UUID, totalRooms int, stay valueobject.DateRange, bookings []Booking, holds []InventoryHold) int {
+	return MinAvailable(s.NightlyAvailability(roomTypeID, totalRooms, stay, bookings, holds))
+}
+
+// MinAvailable returns the availability of the busiest night.
+func MinAvailable(nights []NightAvailability) int {
+	if len(nights) == 0 {
+		return 0
+	}
+	min := nights[0].Available
+	for _, n := range nights[1:] {
+		if n.Available < min {
+			min = n.Available
+		}
+	}
+	return min
+}
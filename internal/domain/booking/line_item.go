@@ -0,0 +1,210 @@
+package booking
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+const (
+	ItemStatusActive    = "active"
+	ItemStatusCancelled = "cancelled"
+)
+
+// PriceBreakdown itemises how the price of a line item was reached.
+type PriceBreakdown struct {
+	Base           valueobject.Amount // sum of the nightly rates
+	GuestSurcharge valueobject.Amount
+	Discount       valueobject.Amount // long-stay discount, already subtracted from Total
+	Promo          valueobject.Amount // promo code discount, already subtracted from Total
+	Charges        []Charge           // taxes and fees; exclusive ones are already added to Total
+	Total          valueobject.Amount
+	Nights         []NightPrice // per-night breakdown the totals add up from
+}
+
+// Charge is a tax or fee levied on a line item. An inclusive charge is part of the room price
+// and only itemised.
+type Charge struct {
+	Kind      string // tax or fee
+	Name      string
+	Amount    valueobject.Amount
+	Inclusive bool
+}
+
+// LineItem is a single room booked as part of a booking.
+type LineItem struct {
+	ID         uuid.UUID
+	RoomTypeID uuid.UUID
+	RatePlanID uuid.UUID // uuid.Nil when booked at the room type's own terms
+	// CancellationPolicy is the policy code of the rate plan when booked; empty uses the room type's.
+	CancellationPolicy string
+	Guests             int
+	Price              PriceBreakdown
+	Status             string
+}
+
+// Active reports whether the item still belongs to the stay.
+func (i LineItem) Active() bool {
+	return i.Status != ItemStatusCancelled
+}
+
+// SetItems replaces the line items and derives the booking totals from the active ones.
+// The first active item becomes the primary room type used for room assignment.
+func (b *Booking) SetItems(items []LineItem) {
+	b.Items = items
+	b.Guests = 0
+	b.TotalPrice = valueobject.Amount{}
+	primary := uuid.Nil
+	for _, item := range items {
+		if !item.Active() {
+			continue
+		}
+		if primary == uuid.Nil {
+			primary = item.RoomTypeID
+		}
+		b.Guests += item.Guests
+		b.TotalPrice = b.TotalPrice.Add(item.Price.Total)
+	}
+	if primary != uuid.Nil {
+		b.RoomTypeID = primary
+	}
+}
+
+// LineItems returns the booked rooms. Bookings made before line items existed are
+// reported as a single item carrying the booking's own room type, guests and price.
+func (b Booking) LineItems() []LineItem {
+	if len(b.Items) > 0 {
+		return b.Items
+	}
+	return []LineItem{{
+		ID:         b.ID,
+		RoomTypeID: b.RoomTypeID,
+		Guests:     b.Guests,
+		Price:      PriceBreakdown{Base: b.TotalPrice, Total: b.TotalPrice},
+		Status:     ItemStatusActive,
+	}}
+}
+
+// ActiveItems returns the line items that have not been cancelled.
+func (b Booking) ActiveItems() []LineItem {
+	var out []LineItem
+	for _, item := range b.LineItems() {
+		if item.Active() {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// RoomsHeld returns how many rooms of the room type the booking occupies.
+func (b Booking) RoomsHeld(roomTypeID uuid.UUID) int {
+	if !b.HoldsInventory() {
+		return 0
+	}
+	held := 0
+	for _, item := range b.ActiveItems() {
+		if item.RoomTypeID == roomTypeID {
+			held++
+		}
+	}
+	return held
+}
+
+// CancelItem cancels one room of a confirmed group booking and returns the amount to refund, which is
+// nothing when the guest pays at the hotel.
+// The last remaining item cannot be cancelled on its own; the whole booking must be cancelled instead.
+func (b *Booking) CancelItem(itemID uuid.UUID, policy valueobject.CancellationPolicy, now time.Time) (valueobject.Amount, error) {
+	var none valueobject.Amount
+	if b.Status != StatusConfirmed {
+		return none, pkgErrors.New("bad_request", "items can only be cancelled on confirmed bookings")
+	}
+	items := b.LineItems()
+	idx := -1
+	for i, item := range items {
+		if item.ID == itemID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return none, pkgErrors.New("not_found", "booking item not found")
+	}
+	if !items[idx].Active() {
+		return none, pkgErrors.New("bad_request", "booking item already cancelled")
+	}
+	if len(b.ActiveItems()) == 1 {
+		return none, pkgErrors.New("bad_request", "cannot cancel the last item, cancel the booking instead")
+	}
+
+	item := items[idx]
+	var refund valueobject.Amount
+	if !b.PaysAtHotel() {
+		refund = policy.RefundFor(b.price(item.Price.Total), b.TotalNights, b.CheckIn, now).Amount
+	}
+	items[idx].Status = ItemStatusCancelled
+	b.SetItems(items)
+	b.RecordEvent(NewBookingItemCancelled(b.ID, item.ID, item.RoomTypeID, policy.Code, refund))
+	return refund, nil
+}
+
+// Price line types.
+const (
+	LineRoom      = "room"
+	LineSurcharge = "surcharge"
+	LineDiscount  = "discount"
+	LinePromo     = "promo"
+	LineTax       = "tax"
+	LineFee       = "fee"
+)
+
+// PriceLine is one line of the itemised price of a booking. Discounts are negative. Included lines
+// are already part of the room price; the other lines add up to the total price.
+type PriceLine struct {
+	Type     string
+	Name     string
+	Amount   valueobject.Amount
+	Included bool
+}
+
+// PriceLines itemises the price of the active rooms: room nights, surcharges, discounts, then each
+// tax and fee summed over the rooms.
+func (b Booking) PriceLines() []PriceLine {
+	var room, surcharge, discount, promo valueobject.Amount
+	var charges []PriceLine
+	for _, item := range b.ActiveItems() {
+		p := item.Price
+		room = room.Add(p.Base)
+		surcharge = surcharge.Add(p.GuestSurcharge)
+		discount = discount.Add(p.Discount)
+		promo = promo.Add(p.Promo)
+		for _, c := range p.Charges {
+			charges = addCharge(charges, c)
+		}
+	}
+
+	lines := []PriceLine{{Type: LineRoom, Name: "Room nights", Amount: room}}
+	if !surcharge.IsZero() {
+		lines = append(lines, PriceLine{Type: LineSurcharge, Name: "Extra guests", Amount: surcharge})
+	}
+	if !discount.IsZero() {
+		lines = append(lines, PriceLine{Type: LineDiscount, Name: "Length of stay discount", Amount: discount.Neg()})
+	}
+	if !promo.IsZero() {
+		lines = append(lines, PriceLine{Type: LinePromo, Name: b.PromoCode, Amount: promo.Neg()})
+	}
+	return append(lines, charges...)
+}
+
+// addCharge adds c to the line of the same charge, or appends a new line for it.
+func addCharge(lines []PriceLine, c Charge) []PriceLine {
+	for i, l := range lines {
+		if l.Type == c.Kind && l.Name == c.Name && l.Included == c.Inclusive {
+			lines[i].Amount = l.Amount.Add(c.Amount)
+			return lines
+		}
+	}
+	return append(lines, PriceLine{Type: c.Kind, Name: c.Name, Amount: c.Amount, Included: c.Inclusive})
+}
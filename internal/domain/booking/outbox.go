@@ -0,0 +1,77 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+const (
+	OutboxPending   = "pending"
+	OutboxDelivered = "delivered"
+	OutboxDead      = "dead"
+)
+
+// OutboxMessage is a domain event persisted with the aggregate write and delivered later by the relay.
+type OutboxMessage struct {
+	ID            uuid.UUID
+	AggregateID   uuid.UUID
+	EventType     string
+	Payload       []byte // JSON encoded event
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	DeliveredAt   time.Time
+}
+
+// MarkDelivered records a successful delivery.
+func (m *OutboxMessage) MarkDelivered(now time.Time) {
+	m.Status = OutboxDelivered
+	m.Attempts++
+	m.LastError = ""
+	m.DeliveredAt = now
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt with backoff.
+// Once the policy runs out of attempts the message is dead-lettered and only a replay revives it.
+func (m *OutboxMessage) MarkFailed(reason string, now time.Time, policy valueobject.RetryPolicy) {
+	m.Attempts++
+	m.LastError = reason
+	if policy.Exhausted(m.Attempts) {
+		m.Status = OutboxDead
+		return
+	}
+	m.NextAttemptAt = now.Add(policy.Backoff(m.Attempts))
+}
+
+// Replay puts a stuck or dead-lettered message back in line for immediate delivery with a fresh retry budget.
+func (m *OutboxMessage) Replay(now time.Time) error {
+	if m.Status == OutboxDelivered {
+		return pkgErrors.New("bad_request", "outbox message already delivered")
+	}
+	m.Status = OutboxPending
+	m.Attempts = 0
+	m.NextAttemptAt = now
+	return nil
+}
+
+// OutboxRepository stores domain events next to the aggregates that raised them.
+type OutboxRepository interface {
+	// AppendEvents stages events for delivery; call it in the transaction writing the aggregate.
+	AppendEvents(ctx context.Context, events []domain.DomainEvent) error
+	// ClaimOutbox locks up to limit pending messages due at now, skipping rows other relays hold.
+	// Only the oldest pending message of each aggregate is returned so events arrive in order.
+	ClaimOutbox(ctx context.Context, now time.Time, limit int) ([]OutboxMessage, error)
+	SaveOutbox(ctx context.Context, m OutboxMessage) error
+	// ListOutbox returns messages in the given status, or all messages when status is empty, oldest first.
+	ListOutbox(ctx context.Context, status string, opts query.Options) ([]OutboxMessage, error)
+	FindOutbox(ctx context.Context, id uuid.UUID) (OutboxMessage, error)
+}
@@ -1,6 +1,22 @@
 package booking
 
-// PricingService handles pricing calculations (pure domain logic).
+import (
+	"time"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// NightPrice is the price of one night of a line item.
+type NightPrice struct {
+	Date      time.Time
+	Rate      valueobject.Amount // nightly rate after season and day-of-week rules and the rate plan
+	Surcharge valueobject.Amount // occupancy surcharge
+	Discount  valueobject.Amount // length-of-stay discount, already subtracted from Total
+	Total     valueobject.Amount
+	Rules     []string // names of the rules applied to the night
+}
+
+// PricingService prices stays night by night from configurable pricing rules (pure domain logic).
 type PricingService struct{}
 
 // NewPricingService creates a new PricingService.
@@ -8,27 +24,141 @@ func NewPricingService() *PricingService {
 	return &PricingService{}
 }
 
-// CalculateTotalPrice calculates the base total price including extra guest surcharges.
-func (s *PricingService) CalculateTotalPrice(basePrice float64, nights int, guests int) float64 {
-	total := basePrice * float64(nights)
+// CalendarRule names the per-date calendar rate in the rules of a night.
+const CalendarRule = "calendar"
+
+// Quote prices a single room for the stay. Rules are given in precedence order, most specific first:
+// the first season, day-of-week and occupancy rule matching a night applies, as does the matching
+// length-of-stay rule with the most nights. Kinds without any rule fall back to the defaults.
+// rates holds the rates set for single dates, keyed by date as 2006-01-02; such a rate replaces
+// basePrice and is not adjusted by season or day-of-week rules. plan then adjusts the nightly rate
+// before surcharges and discounts are worked out; the zero plan leaves it alone. Every amount of a
+// night is rounded to the minor unit of currency, so the totals add up exactly.
+func (s *PricingService) Quote(currency string, basePrice valueobject.Amount, rates map[string]valueobject.Amount, stay valueobject.DateRange, guests int, rules []valueobject.PricingRule, plan valueobject.RatePlan) PriceBreakdown {
+	rules = withDefaults(rules)
+	nights := stay.Nights()
+	occupancy := firstOccupancy(rules, guests)
+	lengthOfStay := bestLengthOfStay(rules, nights)
+
+	var price PriceBreakdown
+	for _, date := range stay.NightDates() {
+		night := NightPrice{Date: date, Rate: basePrice.Round(currency)}
+		if rate, ok := rates[date.Format("2006-01-02")]; ok {
+			night.Rate = rate.Round(currency)
+			night.Rules = append(night.Rules, CalendarRule)
+		} else {
+			night.applyRates(rules, date, currency)
+		}
+		if plan.Modifies() {
+			night.Rate = plan.Adjust(night.Rate).Round(currency)
+			night.Rules = append(night.Rules, plan.Name)
+		}
+		if occupancy != nil {
+			night.Surcharge = night.Rate.Mul(guests - occupancy.Guests).Percent(occupancy.Percent).Round(currency)
+			night.Rules = append(night.Rules, occupancy.Name)
+		}
+		subtotal := night.Rate.Add(night.Surcharge)
+		if lengthOfStay != nil {
+			night.Discount = subtotal.Percent(-lengthOfStay.Percent).Round(currency)
+			night.Rules = append(night.Rules, lengthOfStay.Name)
+		}
+		night.Total = subtotal.Sub(night.Discount)
+
+		price.Base = price.Base.Add(night.Rate)
+		price.GuestSurcharge = price.GuestSurcharge.Add(night.Surcharge)
+		price.Discount = price.Discount.Add(night.Discount)
+		price.Total = price.Total.Add(night.Total)
+		price.Nights = append(price.Nights, night)
+	}
+	return price
+}
+
+// ApplyCharges levies the hotel's taxes and fees on a priced room, in the order of rules. Fees are
+// levied on the room price after discounts; exclusive taxes on that price plus the exclusive fees, the
+// way a tax is due on a service charge. Inclusive charges are worked out of the room price and leave
+// the total unchanged. Each charge is rounded to the minor unit of currency.
+func (s *PricingService) ApplyCharges(currency string, price PriceBreakdown, nights int, rules []valueobject.ChargeRule) PriceBreakdown {
+	room := price.Total
+	price.Charges = nil
+	var fees valueobject.Amount
+	for _, kind := range []string{valueobject.ChargeFee, valueobject.ChargeTax} {
+		for _, r := range rules {
+			if r.Kind != kind {
+				continue
+			}
+			base := room
+			if kind == valueobject.ChargeTax && !r.Inclusive {
+				base = base.Add(fees)
+			}
+			amount := r.Charge(base, nights).Round(currency)
+			charge := Charge{Kind: r.Kind, Name: r.Name, Amount: amount, Inclusive: r.Inclusive}
+			if !charge.Inclusive {
+				price.Total = price.Total.Add(charge.Amount)
+				if kind == valueobject.ChargeFee {
+					fees = fees.Add(charge.Amount)
+				}
+			}
+			price.Charges = append(price.Charges, charge)
+		}
+	}
+	return price
+}
+
+// applyRates adjusts the nightly rate by the first season and day-of-week rule covering date.
+func (n *NightPrice) applyRates(rules []valueobject.PricingRule, date time.Time, currency string) {
+	for _, kind := range []string{valueobject.PricingSeason, valueobject.PricingDayOfWeek} {
+		rule, ok := firstCovering(rules, kind, date)
+		if !ok {
+			continue
+		}
+		if !rule.Rate.IsZero() {
+			n.Rate = rule.Rate.Round(currency)
+		} else {
+			n.Rate = n.Rate.Add(n.Rate.Percent(rule.Percent)).Round(currency)
+		}
+		n.Rules = append(n.Rules, rule.Name)
+	}
+}
 
-	// Extra guest surcharge (domain rule: > 2 guests pay 20% extra per night)
-	if guests > 2 {
-		extraGuests := guests - 2
-		total += float64(extraGuests) * basePrice * 0.2 * float64(nights)
+// withDefaults appends the default rules of every kind rules do not configure.
+func withDefaults(rules []valueobject.PricingRule) []valueobject.PricingRule {
+	configured := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		configured[r.Kind] = true
 	}
+	out := append([]valueobject.PricingRule(nil), rules...)
+	for _, r := range valueobject.DefaultPricingRules() {
+		if !configured[r.Kind] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
 
-	return total
+func firstCovering(rules []valueobject.PricingRule, kind string, date time.Time) (valueobject.PricingRule, bool) {
+	for _, r := range rules {
+		if r.Kind == kind && r.CoversNight(date) {
+			return r, true
+		}
+	}
+	return valueobject.PricingRule{}, false
 }
 
-// ApplyDiscount applies discounts based on stay duration.
-func (s *PricingService) ApplyDiscount(totalPrice float64, nights int) float64 {
-	// Long stay discount (domain rule)
-	if nights >= 7 {
-		return totalPrice * 0.9 // 10% discount
+func firstOccupancy(rules []valueobject.PricingRule, guests int) *valueobject.PricingRule {
+	for i, r := range rules {
+		if r.Kind == valueobject.PricingOccupancy && guests > r.Guests {
+			return &rules[i]
+		}
 	}
-	if nights >= 3 {
-		return totalPrice * 0.95 // 5% discount
+	return nil
+}
+
+func bestLengthOfStay(rules []valueobject.PricingRule, nights int) *valueobject.PricingRule {
+	var best *valueobject.PricingRule
+	for i, r := range rules {
+		if r.Kind == valueobject.PricingLengthOfStay && nights >= r.MinNights && (best == nil || r.MinNights > best.MinNights) {
+			best = &rules[i]
+		}
 	}
-	return totalPrice
+	return best
 }
@@ -0,0 +1,191 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+const (
+	PromotionPercent = "percent" // Amount is a percentage off each eligible room
+	PromotionFixed   = "fixed"   // Amount is taken off the eligible rooms together
+)
+
+const (
+	RedemptionActive   = "active"
+	RedemptionReleased = "released" // the booking was cancelled or its hold expired
+)
+
+// Promotion is a discount code guests enter when booking.
+type Promotion struct {
+	ID             uuid.UUID
+	Code           string // upper case, unique
+	Kind           string
+	Amount         valueobject.Amount // percent, or the fixed amount
+	Currency       string             // of a fixed amount; empty for percent discounts, which apply in any currency
+	ValidFrom      time.Time          // bookings made before are refused, zero for no start
+	ValidUntil     time.Time          // bookings made after are refused, zero for no end
+	MinNights      int
+	MaxUses        int         // active redemptions allowed in total, 0 for unlimited
+	MaxUsesPerUser int         // active redemptions allowed per guest, 0 for unlimited
+	HotelIDs       []uuid.UUID // hotels the code applies to, empty for any
+	RoomTypeIDs    []uuid.UUID // room types the code applies to, empty for any
+	CreatedAt      time.Time
+}
+
+// NormalizePromoCode returns the form codes are stored and looked up under.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate normalizes the code and rejects promotions that could never apply.
+func (p *Promotion) Validate() error {
+	p.Code = NormalizePromoCode(p.Code)
+	if p.Code == "" || len(p.Code) > 32 {
+		return pkgErrors.New("bad_request", "code must be 1 to 32 characters")
+	}
+	switch p.Kind {
+	case PromotionPercent:
+		if !p.Amount.IsPositive() || p.Amount.Cmp(valueobject.AmountOf(100)) > 0 {
+			return pkgErrors.New("bad_request", "percent must be above 0 and at most 100")
+		}
+		p.Currency = ""
+	case PromotionFixed:
+		if !p.Amount.IsPositive() {
+			return pkgErrors.New("bad_request", "amount must be positive")
+		}
+		if p.Currency == "" {
+			p.Currency = valueobject.DefaultCurrency
+		}
+		cur, err := valueobject.ParseCurrency(p.Currency)
+		if err != nil {
+			return err
+		}
+		p.Currency = cur
+	default:
+		return pkgErrors.New("bad_request", "kind must be percent or fixed")
+	}
+	if !p.ValidFrom.IsZero() && !p.ValidUntil.IsZero() && !p.ValidFrom.Before(p.ValidUntil) {
+		return pkgErrors.New("bad_request", "valid_from must be before valid_until")
+	}
+	if p.MinNights < 0 || p.MaxUses < 0 || p.MaxUsesPerUser < 0 {
+		return pkgErrors.New("bad_request", "min_nights, max_uses and max_uses_per_user cannot be negative")
+	}
+	return nil
+}
+
+// Covers reports whether a room of the room type, in the hotel, is eligible for the discount.
+func (p Promotion) Covers(hotelID, roomTypeID uuid.UUID) bool {
+	return (len(p.HotelIDs) == 0 || containsID(p.HotelIDs, hotelID)) &&
+		(len(p.RoomTypeIDs) == 0 || containsID(p.RoomTypeIDs, roomTypeID))
+}
+
+// CheckRedeemable reports whether a booking of nights made at now may use the code, given how often
+// it is already in use in total and by the guest.
+func (p Promotion) CheckRedeemable(now time.Time, nights, used, usedByGuest int) error {
+	if !p.ValidFrom.IsZero() && now.Before(p.ValidFrom) {
+		return pkgErrors.New("unprocessable_entity", "promo code is not valid yet")
+	}
+	if !p.ValidUntil.IsZero() && now.After(p.ValidUntil) {
+		return pkgErrors.New("unprocessable_entity", "promo code has expired")
+	}
+	if nights < p.MinNights {
+		return pkgErrors.New("unprocessable_entity", fmt.Sprintf("promo code needs a stay of at least %d nights", p.MinNights))
+	}
+	if p.MaxUses > 0 && used >= p.MaxUses {
+		return pkgErrors.New("unprocessable_entity", "promo code has been used up")
+	}
+	if p.MaxUsesPerUser > 0 && usedByGuest >= p.MaxUsesPerUser {
+		return pkgErrors.New("unprocessable_entity", "promo code already used the maximum number of times")
+	}
+	return nil
+}
+
+// CheckCurrency rejects a fixed discount on a booking priced in another currency than its amount.
+func (p Promotion) CheckCurrency(currency string) error {
+	if p.Kind == PromotionFixed && p.Currency != currency {
+		return pkgErrors.New("bad_request", fmt.Sprintf("promo code only applies to bookings priced in %s", p.Currency))
+	}
+	return nil
+}
+
+// Apply takes the discount off the active items it covers and returns the amount taken off.
+// hotels maps the room type of every item to its hotel. A fixed discount is shared between the
+// covered items in proportion to their price and never exceeds it. Amounts are rounded to the minor
+// unit of currency, the last item taking the rounding difference so the shares add up to the discount.
+// A fixed discount in another currency takes nothing off.
+func (p Promotion) Apply(items []LineItem, hotels map[uuid.UUID]uuid.UUID, currency string) valueobject.Amount {
+	if p.CheckCurrency(currency) != nil {
+		return valueobject.Amount{}
+	}
+	var covered []int
+	var eligible valueobject.Amount
+	for i, item := range items {
+		if item.Active() && p.Covers(hotels[item.RoomTypeID], item.RoomTypeID) {
+			covered = append(covered, i)
+			eligible = eligible.Add(item.Price.Total)
+		}
+	}
+	if !eligible.IsPositive() {
+		return valueobject.Amount{}
+	}
+	off := eligible.Scale(p.Amount, valueobject.AmountOf(100)).Round(currency)
+	if p.Kind == PromotionFixed {
+		off = valueobject.MinAmount(p.Amount.Round(currency), eligible)
+	}
+	left := off
+	for n, i := range covered {
+		share := left
+		if n < len(covered)-1 {
+			share = off.Scale(items[i].Price.Total, eligible).Round(currency)
+		}
+		left = left.Sub(share)
+		items[i].Price.Promo = share
+		items[i].Price.Total = items[i].Price.Total.Sub(share)
+	}
+	return off
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Redemption records a booking using a promotion. Only active redemptions count towards its limits.
+type Redemption struct {
+	ID          uuid.UUID
+	PromotionID uuid.UUID
+	BookingID   uuid.UUID
+	UserID      uuid.UUID
+	Status      string
+	CreatedAt   time.Time
+}
+
+// PromotionRepository stores promotions and their redemptions.
+type PromotionRepository interface {
+	CreatePromotion(ctx context.Context, p Promotion) error
+	UpdatePromotion(ctx context.Context, p Promotion) error
+	DeletePromotion(ctx context.Context, id uuid.UUID) error
+	GetPromotion(ctx context.Context, id uuid.UUID) (Promotion, error)
+	// ListPromotions returns every promotion, newest first.
+	ListPromotions(ctx context.Context) ([]Promotion, error)
+	FindPromotionByCode(ctx context.Context, code string) (Promotion, error)
+	// LockPromotionByCode loads the promotion with the code and locks it until the transaction ends,
+	// so concurrent bookings redeeming it are counted one after another.
+	LockPromotionByCode(ctx context.Context, code string) (Promotion, error)
+	// CountRedemptions returns the active redemptions of the promotion in total and by the user.
+	CountRedemptions(ctx context.Context, promotionID, userID uuid.UUID) (total, byUser int, err error)
+	CreateRedemption(ctx context.Context, r Redemption) error
+	// ReleaseRedemptions releases the active redemptions of the booking; it is a no-op without any.
+	ReleaseRedemptions(ctx context.Context, bookingID uuid.UUID) error
+}
@@ -0,0 +1,49 @@
+package booking
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// Quote is the price of a stay promised to a guest until ExpiresAt. It carries the rooms priced
+// as booking them would, taxes and fees included, so a booking made with it keeps that price.
+type Quote struct {
+	UserID         uuid.UUID
+	CheckIn        time.Time
+	CheckOut       time.Time
+	Items          []LineItem // priced rooms, without ids
+	PromoCode      string
+	PaymentTiming  string
+	Currency       string
+	ExchangeRate   valueobject.ExchangeRate
+	ChargeCurrency string
+	ExpiresAt      time.Time
+}
+
+// Booking returns the booking the quote prices, without an id, for its totals and price lines.
+func (q Quote) Booking() Booking {
+	b := Booking{
+		UserID:         q.UserID,
+		CheckIn:        q.CheckIn,
+		CheckOut:       q.CheckOut,
+		TotalNights:    valueobject.DateRange{Start: q.CheckIn, End: q.CheckOut}.Nights(),
+		PromoCode:      q.PromoCode,
+		PaymentTiming:  q.PaymentTiming,
+		Currency:       q.Currency,
+		ExchangeRate:   q.ExchangeRate,
+		ChargeCurrency: q.ChargeCurrency,
+	}
+	b.SetItems(q.Items)
+	return b
+}
+
+// QuoteSigner turns quotes into tokens handed to the guest and back.
+type QuoteSigner interface {
+	Sign(q Quote) (string, error)
+	// Verify returns the quote of a token made by Sign. Tampered and expired tokens fail with a
+	// conflict.
+	Verify(token string) (Quote, error)
+}
@@ -0,0 +1,45 @@
+package booking
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// EventTypeSettlementDue marks outbox messages the relay settles with the payment service instead of
+// notifying.
+const EventTypeSettlementDue = "booking.settlement_due"
+
+// Settlement kinds.
+const (
+	SettleSupplement = "supplement"
+	SettleRefund     = "refund"
+)
+
+// SettlementDue asks the payment service to collect or pay back a change in the booking's price.
+// It is staged in the outbox with the write that changed the price, so the payment is only asked
+// for once that write has committed, and the relay retries it until it goes through. It is not part
+// of the booking's history: replaying history must not settle again.
+type SettlementDue struct {
+	domain.BaseEvent
+	BookingID uuid.UUID
+	UserID    uuid.UUID
+	Version   int // booking version that changed the price; keys the payment call
+	Kind      string
+	Amount    valueobject.Money
+	Reason    string
+}
+
+// NewSettlementDue creates a SettlementDue event for amount in the booking's charge currency.
+func NewSettlementDue(b Booking, kind string, amount valueobject.Money, reason string) SettlementDue {
+	return SettlementDue{
+		BaseEvent: domain.NewBaseEvent(b.ID, EventTypeSettlementDue),
+		BookingID: b.ID,
+		UserID:    b.UserID,
+		Version:   b.Version,
+		Kind:      kind,
+		Amount:    amount,
+		Reason:    reason,
+	}
+}
@@ -2,6 +2,7 @@ package booking
 
 import (
 	"github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // IsConfirmedSpec checks if booking is confirmed.
@@ -15,7 +16,7 @@ func (s IsConfirmedSpec) IsSatisfiedBy(b Booking) bool {
 type IsHighValueSpec struct{}
 
 func (s IsHighValueSpec) IsSatisfiedBy(b Booking) bool {
-	return b.TotalPrice > 10000000
+	return b.TotalPrice.Cmp(valueobject.AmountOf(10000000)) > 0
 }
 
 // IsLongStaySpec checks if booking is long stay (> 7 nights).
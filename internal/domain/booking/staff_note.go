@@ -0,0 +1,28 @@
+package booking
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// StaffNote is an internal remark left on a booking by hotel staff.
+type StaffNote struct {
+	ID        uuid.UUID
+	AuthorID  uuid.UUID // uuid.Nil when written by a service
+	Body      string
+	CreatedAt time.Time
+}
+
+// AddNote appends a staff note to the booking. Notes are append-only.
+func (b *Booking) AddNote(authorID uuid.UUID, body string, now time.Time) (StaffNote, error) {
+	text, err := valueobject.NormalizeStaffNote(body)
+	if err != nil {
+		return StaffNote{}, err
+	}
+	note := StaffNote{ID: uuid.New(), AuthorID: authorID, Body: text, CreatedAt: now}
+	b.Notes = append(b.Notes, note)
+	return note, nil
+}
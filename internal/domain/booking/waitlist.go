@@ -0,0 +1,123 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+const (
+	WaitlistWaiting = "waiting"
+	WaitlistOffered = "offered"
+	WaitlistLeft    = "left"
+	WaitlistExpired = "expired" // the stay began before a room freed up
+)
+
+// EventTypeWaitlistOffered is raised when a freed room is held for a waitlisted guest.
+const EventTypeWaitlistOffered = "waitlist.offered"
+
+// WaitlistEntry is a guest waiting for a sold-out room type to free up for their stay.
+type WaitlistEntry struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	RoomTypeID     uuid.UUID
+	CheckIn        time.Time
+	CheckOut       time.Time
+	Guests         int
+	Status         string
+	BookingID      uuid.UUID // hold offered to the guest, uuid.Nil until offered
+	OfferExpiresAt time.Time // end of the offered hold, zero until offered
+	CreatedAt      time.Time
+}
+
+// Stay returns the dates the guest is waiting for.
+func (e WaitlistEntry) Stay() valueobject.DateRange {
+	return valueobject.DateRange{Start: e.CheckIn, End: e.CheckOut}
+}
+
+// Offer records the pending booking holding a freed room for the guest.
+// The guest confirms it by paying before the hold expires, like any other booking.
+func (e *WaitlistEntry) Offer(hold Booking) error {
+	if e.Status != WaitlistWaiting {
+		return pkgErrors.New("conflict", "waitlist entry is no longer waiting")
+	}
+	e.Status = WaitlistOffered
+	e.BookingID = hold.ID
+	e.OfferExpiresAt = hold.ExpiresAt
+	return nil
+}
+
+// Leave takes the guest off the waitlist. Once a hold was offered the guest cancels the booking instead.
+func (e *WaitlistEntry) Leave() error {
+	switch e.Status {
+	case WaitlistWaiting:
+		e.Status = WaitlistLeft
+		return nil
+	case WaitlistOffered:
+		return pkgErrors.New("conflict", "a room was already offered, cancel the booking instead")
+	default:
+		return pkgErrors.New("conflict", "waitlist entry is no longer waiting")
+	}
+}
+
+// Expire closes an entry whose stay began while it was still waiting.
+func (e *WaitlistEntry) Expire(now time.Time) error {
+	if e.Status != WaitlistWaiting || !now.After(e.CheckIn) {
+		return pkgErrors.New("bad_request", "waitlist entry has not expired")
+	}
+	e.Status = WaitlistExpired
+	return nil
+}
+
+// WaitlistOfferedEvent tells the guest a room is held for them and until when.
+type WaitlistOfferedEvent struct {
+	domain.BaseEvent
+	EntryID       uuid.UUID
+	UserID        uuid.UUID
+	BookingID     uuid.UUID
+	RoomTypeID    uuid.UUID
+	CheckIn       time.Time
+	CheckOut      time.Time
+	HoldExpiresAt time.Time
+}
+
+// NewWaitlistOffered creates the event for an entry that was just offered a hold.
+func NewWaitlistOffered(e WaitlistEntry) WaitlistOfferedEvent {
+	return WaitlistOfferedEvent{
+		BaseEvent:     domain.NewBaseEvent(e.ID, EventTypeWaitlistOffered),
+		EntryID:       e.ID,
+		UserID:        e.UserID,
+		BookingID:     e.BookingID,
+		RoomTypeID:    e.RoomTypeID,
+		CheckIn:       e.CheckIn,
+		CheckOut:      e.CheckOut,
+		HoldExpiresAt: e.OfferExpiresAt,
+	}
+}
+
+// WaitlistSchema is the allow-list of fields waitlist lists can be filtered and sorted by.
+var WaitlistSchema = query.Schema{
+	Fields: map[string]query.Field{
+		"status":       {Type: query.String, Ops: []query.Op{query.OpEq}},
+		"user_id":      {Type: query.UUID, Ops: []query.Op{query.OpEq}},
+		"room_type_id": {Type: query.UUID, Ops: []query.Op{query.OpEq}},
+		"check_in":     {Type: query.Time, Ops: []query.Op{query.OpGte, query.OpLte}, Sortable: true},
+		"created_at":   {Type: query.Time, Ops: []query.Op{query.OpGte, query.OpLte}, Sortable: true},
+	},
+	DefaultSort: query.Sort{Field: "created_at"},
+}
+
+// WaitlistRepository stores waitlist entries.
+type WaitlistRepository interface {
+	CreateWaitlistEntry(ctx context.Context, e WaitlistEntry) error
+	SaveWaitlistEntry(ctx context.Context, e WaitlistEntry) error
+	FindWaitlistEntry(ctx context.Context, id uuid.UUID) (WaitlistEntry, error)
+	// ListWaitlist returns a page of entries filtered and sorted by fields of WaitlistSchema, oldest first by default.
+	ListWaitlist(ctx context.Context, opts query.Options) (query.Page[WaitlistEntry], error)
+}
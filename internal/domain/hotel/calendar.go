@@ -0,0 +1,135 @@
+package hotel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// MaxCalendarDays bounds how many dates a single calendar read or update may span.
+const MaxCalendarDays = 366
+
+// CalendarDay holds the settings of a room type for a single date. Dates without a stored day use
+// the room type's BasePrice and carry no restrictions.
+type CalendarDay struct {
+	RoomTypeID        uuid.UUID
+	Date              time.Time          // midnight UTC
+	Price             valueobject.Amount // nightly rate for the date, overriding BasePrice when positive
+	ClosedToArrival   bool               // stays may not check in on the date
+	ClosedToDeparture bool               // stays may not check out on the date
+	MinStay           int                // minimum nights of stays arriving on the date, 0 for none
+	MaxStay           int                // maximum nights of stays arriving on the date, 0 for none
+}
+
+// CalendarDate normalizes t to the midnight UTC key calendar days are stored under.
+func CalendarDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// CalendarUpdate changes the settings of a range of dates. Nil fields are left as they are.
+type CalendarUpdate struct {
+	Price             *valueobject.Amount // 0 removes the override
+	ClosedToArrival   *bool
+	ClosedToDeparture *bool
+	MinStay           *int // 0 removes the minimum
+	MaxStay           *int // 0 removes the maximum
+}
+
+// Validate rejects negative values and updates that change nothing.
+func (u CalendarUpdate) Validate() error {
+	if u.Price == nil && u.ClosedToArrival == nil && u.ClosedToDeparture == nil && u.MinStay == nil && u.MaxStay == nil {
+		return pkgErrors.New("bad_request", "nothing to update")
+	}
+	if u.Price != nil && u.Price.IsNegative() {
+		return pkgErrors.New("bad_request", "price cannot be negative")
+	}
+	if (u.MinStay != nil && *u.MinStay < 0) || (u.MaxStay != nil && *u.MaxStay < 0) {
+		return pkgErrors.New("bad_request", "min_stay and max_stay cannot be negative")
+	}
+	return nil
+}
+
+// Apply updates the day, refusing a maximum stay below the minimum.
+func (d *CalendarDay) Apply(u CalendarUpdate) error {
+	next := *d
+	if u.Price != nil {
+		next.Price = *u.Price
+	}
+	if u.ClosedToArrival != nil {
+		next.ClosedToArrival = *u.ClosedToArrival
+	}
+	if u.ClosedToDeparture != nil {
+		next.ClosedToDeparture = *u.ClosedToDeparture
+	}
+	if u.MinStay != nil {
+		next.MinStay = *u.MinStay
+	}
+	if u.MaxStay != nil {
+		next.MaxStay = *u.MaxStay
+	}
+	if next.MaxStay > 0 && next.MaxStay < next.MinStay {
+		return pkgErrors.New("bad_request", fmt.Sprintf("max_stay below min_stay on %s", next.Date.Format("2006-01-02")))
+	}
+	*d = next
+	return nil
+}
+
+// Calendar is the stored days of one room type.
+type Calendar []CalendarDay
+
+// Day returns the settings for date, falling back to an unrestricted day without a price override.
+func (c Calendar) Day(date time.Time) CalendarDay {
+	date = CalendarDate(date)
+	for _, d := range c {
+		if d.Date.Equal(date) {
+			return d
+		}
+	}
+	return CalendarDay{Date: date}
+}
+
+// Rates returns the nightly rates set for single dates, keyed by date as 2006-01-02.
+func (c Calendar) Rates() map[string]valueobject.Amount {
+	rates := make(map[string]valueobject.Amount)
+	for _, d := range c {
+		if !d.Price.IsZero() {
+			rates[d.Date.Format("2006-01-02")] = d.Price
+		}
+	}
+	return rates
+}
+
+// CheckStay reports whether a stay may be booked: it may not arrive on a date closed to arrival
+// nor leave on one closed to departure, and its length must respect the arrival date's limits.
+func (c Calendar) CheckStay(stay valueobject.DateRange) error {
+	arrival := c.Day(stay.Start)
+	on := arrival.Date.Format("2006-01-02")
+	if arrival.ClosedToArrival {
+		return pkgErrors.New("unprocessable_entity", "room type is closed to arrival on "+on)
+	}
+	if departure := c.Day(stay.End); departure.ClosedToDeparture {
+		return pkgErrors.New("unprocessable_entity", "room type is closed to departure on "+departure.Date.Format("2006-01-02"))
+	}
+	nights := stay.Nights()
+	if arrival.MinStay > 0 && nights < arrival.MinStay {
+		return pkgErrors.New("unprocessable_entity", fmt.Sprintf("stays arriving on %s need at least %d nights", on, arrival.MinStay))
+	}
+	if arrival.MaxStay > 0 && nights > arrival.MaxStay {
+		return pkgErrors.New("unprocessable_entity", fmt.Sprintf("stays arriving on %s may last at most %d nights", on, arrival.MaxStay))
+	}
+	return nil
+}
+
+// CalendarRepository stores calendar days.
+type CalendarRepository interface {
+	// ListCalendar returns the stored days of the room type from from through to, both included, by date.
+	ListCalendar(ctx context.Context, roomTypeID uuid.UUID, from, to time.Time) (Calendar, error)
+	// SaveCalendar inserts the days or replaces those already stored for the same date.
+	SaveCalendar(ctx context.Context, days []CalendarDay) error
+}
@@ -0,0 +1,37 @@
+package hotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// ChargeRule entity: a tax or fee the hotel adds to the price of every room.
+type ChargeRule struct {
+	ID        uuid.UUID
+	HotelID   uuid.UUID
+	Rule      valueobject.ChargeRule
+	CreatedAt time.Time
+}
+
+// ChargesOf returns the value objects of rules, keeping their order.
+func ChargesOf(rules []ChargeRule) []valueobject.ChargeRule {
+	out := make([]valueobject.ChargeRule, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, r.Rule)
+	}
+	return out
+}
+
+// ChargeRuleRepository stores charge rules.
+type ChargeRuleRepository interface {
+	CreateChargeRule(ctx context.Context, r ChargeRule) error
+	UpdateChargeRule(ctx context.Context, r ChargeRule) error
+	DeleteChargeRule(ctx context.Context, id uuid.UUID) error
+	GetChargeRule(ctx context.Context, id uuid.UUID) (ChargeRule, error)
+	// ListChargeRules returns every rule of the hotel, oldest first, the order they are itemised in.
+	ListChargeRules(ctx context.Context, hotelID uuid.UUID) ([]ChargeRule, error)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // Hotel entity.
@@ -15,6 +16,7 @@ type Hotel struct {
 	Name        string
 	Description string
 	Address     string
+	Currency    string // ISO 4217 code every price of the hotel is in
 	CreatedAt   time.Time
 }
 
@@ -24,8 +26,10 @@ type RoomType struct {
 	HotelID   uuid.UUID
 	Name      string
 	Capacity  int
-	BasePrice float64
+	BasePrice valueobject.Amount
 	Amenities string
+	// CancellationPolicy is a valueobject cancellation policy code.
+	CancellationPolicy string
 }
 
 // Room entity.
@@ -36,10 +40,20 @@ type Room struct {
 	Status     string
 }
 
+// ListSchema is the allow-list of fields hotel lists can be filtered and sorted by.
+var ListSchema = query.Schema{
+	Fields: map[string]query.Field{
+		"name":       {Type: query.String, Ops: []query.Op{query.OpEq, query.OpContains}, Sortable: true},
+		"created_at": {Type: query.Time, Ops: []query.Op{query.OpGte, query.OpLte}, Sortable: true},
+	},
+	DefaultSort: query.Sort{Field: "name"},
+}
+
 // Repository contract.
 type Repository interface {
 	CreateHotel(ctx context.Context, h Hotel) error
-	ListHotels(ctx context.Context, opts query.Options) ([]Hotel, error)
+	// ListHotels returns a page of hotels filtered and sorted by fields of ListSchema.
+	ListHotels(ctx context.Context, opts query.Options) (query.Page[Hotel], error)
 	GetHotel(ctx context.Context, id uuid.UUID) (Hotel, error)
 	UpdateHotel(ctx context.Context, id uuid.UUID, h Hotel) error
 	DeleteHotel(ctx context.Context, id uuid.UUID) error
@@ -52,4 +66,13 @@ type Repository interface {
 	DeleteRoom(ctx context.Context, id uuid.UUID) error
 	GetRoomType(ctx context.Context, id uuid.UUID) (RoomType, error)
 	ListRooms(ctx context.Context, opts query.Options) ([]Room, error)
+	// GetRoomTypeForUpdate loads a room type and locks it until the surrounding transaction ends.
+	GetRoomTypeForUpdate(ctx context.Context, id uuid.UUID) (RoomType, error)
+	// CountBookableRooms counts rooms of a type that can be sold to guests.
+	CountBookableRooms(ctx context.Context, roomTypeID uuid.UUID) (int, error)
+	ListRoomsByType(ctx context.Context, roomTypeID uuid.UUID) ([]Room, error)
+	PricingRuleRepository
+	CalendarRepository
+	ChargeRuleRepository
+	RatePlanRepository
 }
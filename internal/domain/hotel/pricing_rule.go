@@ -0,0 +1,46 @@
+package hotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// PricingRule entity: a pricing rule configured for a hotel, or for one of its room types.
+type PricingRule struct {
+	ID         uuid.UUID
+	HotelID    uuid.UUID
+	RoomTypeID uuid.UUID // uuid.Nil applies the rule to every room type of the hotel
+	Rule       valueobject.PricingRule
+	CreatedAt  time.Time
+}
+
+// RulesFor returns the rules that price the room type in precedence order: its own rules before
+// the hotel-wide ones, each newest first. rules must be listed newest first.
+func RulesFor(rules []PricingRule, roomTypeID uuid.UUID) []valueobject.PricingRule {
+	out := make([]valueobject.PricingRule, 0, len(rules))
+	for _, r := range rules {
+		if r.RoomTypeID == roomTypeID {
+			out = append(out, r.Rule)
+		}
+	}
+	for _, r := range rules {
+		if r.RoomTypeID == uuid.Nil {
+			out = append(out, r.Rule)
+		}
+	}
+	return out
+}
+
+// PricingRuleRepository stores pricing rules.
+type PricingRuleRepository interface {
+	CreatePricingRule(ctx context.Context, r PricingRule) error
+	UpdatePricingRule(ctx context.Context, r PricingRule) error
+	DeletePricingRule(ctx context.Context, id uuid.UUID) error
+	GetPricingRule(ctx context.Context, id uuid.UUID) (PricingRule, error)
+	// ListPricingRules returns every rule of the hotel and its room types, newest first.
+	ListPricingRules(ctx context.Context, hotelID uuid.UUID) ([]PricingRule, error)
+}
@@ -0,0 +1,28 @@
+package hotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// RatePlan entity: terms a room type is sold under, priced from the room type's rates.
+type RatePlan struct {
+	ID         uuid.UUID
+	RoomTypeID uuid.UUID
+	Plan       valueobject.RatePlan
+	CreatedAt  time.Time
+}
+
+// RatePlanRepository stores rate plans.
+type RatePlanRepository interface {
+	CreateRatePlan(ctx context.Context, p RatePlan) error
+	UpdateRatePlan(ctx context.Context, p RatePlan) error
+	DeleteRatePlan(ctx context.Context, id uuid.UUID) error
+	GetRatePlan(ctx context.Context, id uuid.UUID) (RatePlan, error)
+	// ListRatePlans returns every plan of the room type, oldest first.
+	ListRatePlans(ctx context.Context, roomTypeID uuid.UUID) ([]RatePlan, error)
+}
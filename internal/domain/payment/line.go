@@ -0,0 +1,55 @@
+package payment
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// Line is one line of the itemised amount of a payment: room nights, a surcharge, a discount, a tax
+// or a fee. Discounts are negative. Included lines are already part of another line's amount.
+type Line struct {
+	Type     string
+	Name     string
+	Amount   valueobject.Amount
+	Included bool
+}
+
+// CheckLines rejects lines that do not add up to amount exactly. Payments without lines are not itemised.
+func CheckLines(amount valueobject.Amount, lines []Line) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	var sum valueobject.Amount
+	for _, l := range lines {
+		if !l.Included {
+			sum = sum.Add(l.Amount)
+		}
+	}
+	if sum.Cmp(amount) != 0 {
+		return pkgErrors.New("bad_request", "lines do not add up to amount")
+	}
+	return nil
+}
+
+// Describe builds the description the provider shows the payer, listing the lines of the amount in
+// the minor units of currency.
+func Describe(bookingID uuid.UUID, currency string, lines []Line) string {
+	desc := fmt.Sprintf("Booking %s", bookingID)
+	if len(lines) == 0 {
+		return desc
+	}
+	parts := make([]string, 0, len(lines))
+	for _, l := range lines {
+		part := l.Name + " " + l.Amount.StringFixed(valueobject.CurrencyDigits(currency))
+		if l.Included {
+			part += " (included)"
+		}
+		parts = append(parts, part)
+	}
+	return desc + ": " + strings.Join(parts, "; ")
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 const (
@@ -13,15 +15,25 @@ const (
 	StatusFailed  = "failed"
 )
 
+const (
+	// KindBooking is the single payment that settles a booking and drives its status.
+	KindBooking = "booking"
+	// KindSupplement charges a later price increase, e.g. after a booking modification.
+	KindSupplement = "supplement"
+)
+
 // Payment aggregates payment state.
 type Payment struct {
 	ID         uuid.UUID
 	BookingID  uuid.UUID
-	Amount     float64
+	UserID     uuid.UUID // owner of the booking; only they and admins may see or refund the payment
+	Kind       string
+	Amount     valueobject.Amount
 	Currency   string
 	Status     string
 	Provider   string
 	PaymentURL string
+	Description string // shown to the payer, itemising the amount when lines were given
 	WebhookPayload  string
 	WebhookSignature string
 	CreatedAt  time.Time
@@ -31,14 +43,23 @@ type Payment struct {
 type Provider interface {
 	Initiate(ctx context.Context, payment Payment) (Payment, error)
 	VerifySignature(ctx context.Context, payload, signature string) bool
-	Refund(ctx context.Context, payment Payment, reason string) (string, error)
+	Refund(ctx context.Context, payment Payment, amount valueobject.Amount, reason string) (string, error)
 }
 
 // Repository persists payments.
 type Repository interface {
+	RefundRepository
+
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 	Create(ctx context.Context, p Payment) error
 	FindByID(ctx context.Context, id uuid.UUID) (Payment, error)
+	// FindByIDForUpdate loads a payment and locks it until the surrounding transaction ends.
+	FindByIDForUpdate(ctx context.Context, id uuid.UUID) (Payment, error)
+	// FindByBookingID returns the booking's KindBooking payment.
 	FindByBookingID(ctx context.Context, bookingID uuid.UUID) (Payment, error)
+	// ListByBookingForUpdate returns every payment of a booking, supplements included, oldest
+	// first, and locks them until the surrounding transaction ends.
+	ListByBookingForUpdate(ctx context.Context, bookingID uuid.UUID) ([]Payment, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, status, paymentURL, rawPayload, signature string) error
 }
 
@@ -0,0 +1,45 @@
+package payment
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+const (
+	RefundPending   = "pending" // recorded, waiting for the provider to pay it out
+	RefundSucceeded = "succeeded"
+	RefundFailed    = "failed" // rejected by the provider; its amount can be refunded again
+)
+
+// Refund is money paid back from a payment. A payment may be refunded several times, but never
+// for more than it collected.
+type Refund struct {
+	ID        uuid.UUID
+	PaymentID uuid.UUID
+	Amount    valueobject.Amount
+	Reason    string
+	Status    string
+	Reference string // the provider's reference once paid out
+	CreatedAt time.Time
+}
+
+// Refundable returns how much of the payment can still be refunded once refunded was paid back.
+// Only paid payments collected anything to refund.
+func (p Payment) Refundable(refunded valueobject.Amount) valueobject.Amount {
+	if p.Status != StatusPaid || refunded.Cmp(p.Amount) >= 0 {
+		return valueobject.Amount{}
+	}
+	return p.Amount.Sub(refunded)
+}
+
+// RefundRepository records refunds.
+type RefundRepository interface {
+	CreateRefund(ctx context.Context, r Refund) error
+	SaveRefund(ctx context.Context, r Refund) error
+	// RefundedAmount returns what the refunds of a payment that did not fail add up to.
+	RefundedAmount(ctx context.Context, paymentID uuid.UUID) (valueobject.Amount, error)
+}
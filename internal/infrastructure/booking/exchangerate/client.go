@@ -0,0 +1,78 @@
+package exchangerate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// HTTPProvider fetches the latest rates from a JSON endpoint quoting every currency against one base,
+// answering like {"base":"USD","date":"2024-05-31","rates":{"IDR":16250.5,"SGD":1.35}}.
+type HTTPProvider struct {
+	url    string
+	source string
+	client *http.Client
+}
+
+func NewHTTPProvider(rawURL string) domain.ExchangeRateProvider {
+	source := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		source = u.Host
+	}
+	return &HTTPProvider{url: rawURL, source: source, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// LatestRates returns the published rates, as of the date they were published or now when the
+// provider does not say.
+func (p *HTTPProvider) LatestRates(ctx context.Context) ([]valueobject.ExchangeRate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("exchange rate lookup failed: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Base  string             `json:"base"`
+		Date  string             `json:"date"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	asOf, err := time.Parse("2006-01-02", body.Date)
+	if err != nil {
+		asOf = time.Now()
+	}
+
+	quotes := make([]string, 0, len(body.Rates))
+	for quote := range body.Rates {
+		// Some providers quote the base against itself.
+		if quote != body.Base {
+			quotes = append(quotes, quote)
+		}
+	}
+	sort.Strings(quotes)
+	rates := make([]valueobject.ExchangeRate, 0, len(quotes))
+	for _, quote := range quotes {
+		rate, err := valueobject.NewExchangeRate(body.Base, quote, body.Rates[quote], asOf, p.source)
+		if err != nil {
+			return nil, fmt.Errorf("exchange rate %s/%s: %w", body.Base, quote, err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
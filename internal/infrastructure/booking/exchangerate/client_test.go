@@ -0,0 +1,48 @@
+package exchangerate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProviderLatestRates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"base":"USD","date":"2024-05-31","rates":{"USD":1,"SGD":1.35,"IDR":16250.5}}`))
+	}))
+	defer srv.Close()
+
+	rates, err := NewHTTPProvider(srv.URL).LatestRates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	require.Equal(t, "IDR", rates[0].Quote)
+	require.Equal(t, 16250.5, rates[0].Rate)
+	require.Equal(t, "USD", rates[1].Base)
+	require.Equal(t, "SGD", rates[1].Quote)
+	require.Equal(t, time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC), rates[1].AsOf)
+	require.NotEmpty(t, rates[1].Source)
+}
+
+func TestHTTPProviderRejectsBadRates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"base":"USD","rates":{"IDR":0}}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewHTTPProvider(srv.URL).LatestRates(context.Background())
+	require.Error(t, err)
+}
+
+func TestHTTPProviderError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, err := NewHTTPProvider(srv.URL).LatestRates(context.Background())
+	require.Error(t, err)
+}
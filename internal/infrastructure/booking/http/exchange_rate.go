@@ -0,0 +1,77 @@
+package bookinghttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/utils"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// @Summary Upload exchange rates (admin)
+// @Description Stores the rates of several currencies against one base currency, replacing the stored rates of the same pairs. Bookings keep the rate they were made at.
+// @Tags ExchangeRates
+// @Accept json
+// @Produce json
+// @Param request body dto.ExchangeRatesRequest true "Rates payload"
+// @Success 200 {array} dto.ExchangeRateResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /exchange-rates [put]
+func (h *Handler) uploadExchangeRates(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	var req dto.ExchangeRatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	rates, err := assembler.FromExchangeRatesRequest(req, time.Now())
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	if err := h.service.UploadExchangeRates(r.Context(), rates); err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resources := exchangeRateResources(rates)
+	utils.RespondWithCount(w, http.StatusOK, "exchange rates uploaded", resources, len(resources))
+}
+
+// @Summary List exchange rates (admin)
+// @Tags ExchangeRates
+// @Produce json
+// @Success 200 {array} dto.ExchangeRateResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /exchange-rates [get]
+func (h *Handler) listExchangeRates(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	rates, err := h.service.ListExchangeRates(r.Context())
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resources := exchangeRateResources(rates)
+	utils.RespondWithCount(w, http.StatusOK, "exchange rates listed", resources, len(resources))
+}
+
+func exchangeRateResources(rates []valueobject.ExchangeRate) []utils.Resource {
+	resources := make([]utils.Resource, 0, len(rates))
+	for _, rate := range rates {
+		resp := assembler.ToExchangeRateResponse(rate)
+		resources = append(resources, utils.NewResource(resp.Base+"-"+resp.Quote, "exchange_rate", "/api/v1/exchange-rates", resp))
+	}
+	return resources
+}
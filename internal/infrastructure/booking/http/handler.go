@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -13,8 +14,10 @@ import (
 	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
 	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/utils"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // Handler exposes booking endpoints.
@@ -26,25 +29,60 @@ func NewHandler(service *booking.Service) *Handler {
 	return &Handler{service: service}
 }
 
+// SearchAvailability is public, so it is mounted outside the authenticated Routes.
+func (h *Handler) SearchAvailability(w http.ResponseWriter, r *http.Request) {
+	h.searchAvailability(w, r)
+}
+
 func (h *Handler) Routes() http.Handler {
 	r := chi.NewRouter()
 	r.Get("/bookings", h.listBookings)
 	r.Post("/bookings", h.createBooking)
+	r.Post("/quotes", h.createQuote)
 	r.Get("/bookings/{id}", h.getBooking)
 	r.Get("/bookings/{id}/status", h.getStatus)
 	r.Post("/bookings/{id}/cancel", h.cancelBooking)
+	r.Post("/bookings/{id}/items/{itemID}/cancel", h.cancelBookingItem)
+	r.Post("/bookings/{id}/modify", h.modifyBooking)
 	r.Post("/bookings/{id}/status", h.updateStatus)
 	r.Post("/bookings/{id}/checkpoint", h.checkpoint)
+	r.Get("/bookings/{id}/notes", h.listNotes)
+	r.Post("/bookings/{id}/notes", h.addNote)
+	r.Get("/bookings/{id}/history", h.getHistory)
+	r.Get("/bookings/outbox", h.listOutbox)
+	r.Get("/bookings/outbox/{id}", h.getOutboxMessage)
+	r.Post("/bookings/outbox/{id}/replay", h.replayOutboxMessage)
+	r.Get("/waitlist", h.listWaitlist)
+	r.Post("/waitlist", h.joinWaitlist)
+	r.Delete("/waitlist/{id}", h.leaveWaitlist)
+	r.Post("/holds", h.createHold)
+	r.Get("/holds/{id}", h.getHold)
+	r.Delete("/holds/{id}", h.releaseHold)
+	r.Get("/promotions", h.listPromotions)
+	r.Post("/promotions", h.createPromotion)
+	r.Get("/promotions/{id}", h.getPromotion)
+	r.Put("/promotions/{id}", h.updatePromotion)
+	r.Delete("/promotions/{id}", h.deletePromotion)
+	r.Get("/exchange-rates", h.listExchangeRates)
+	r.Put("/exchange-rates", h.uploadExchangeRates)
 	return r
 }
 
 // @Summary Create booking
+// @Description Books for the caller. Admins may set user_id to book on behalf of a user.
+// @Description An optional promo_code is validated and its discount taken off the rooms it applies to.
+// @Description A rate_plan_id books a room under one of its room type's rate plans; rooms on pay_at_hotel plans are confirmed without a payment.
+// @Description A quote_token from POST /quotes keeps the quoted price while it is valid; an expired, tampered or mismatched token answers 409.
+// @Description A hold_id from POST /holds books the held rooms for the hold's stay even when the rest sold out; an expired hold answers 409.
 // @Tags Bookings
 // @Accept json
 // @Produce json
 // @Param request body dto.BookingRequest true "Booking payload"
 // @Success 201 {object} dto.BookingResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /bookings [post]
 func (h *Handler) createBooking(w http.ResponseWriter, r *http.Request) {
@@ -64,18 +102,60 @@ func (h *Handler) createBooking(w http.ResponseWriter, r *http.Request) {
 		writeError(w, pkgErrors.FromError(err))
 		return
 	}
+	setETag(w, bk.Version)
 	resp := assembler.ToResponse(bk, pay)
 	resource := utils.NewResource(resp.ID, "booking", "/api/v1/bookings/"+resp.ID, resp)
 	utils.Respond(w, http.StatusCreated, "booking created", resource)
 }
 
+// @Summary Search availability
+// @Description Each room type is quoted at its own terms and under each of its rate plans.
+// @Tags Bookings
+// @Produce json
+// @Param check_in query string true "check-in date (YYYY-MM-DD)"
+// @Param check_out query string true "check-out date (YYYY-MM-DD)"
+// @Param guests query int false "number of guests (default 1)"
+// @Param hotel_id query string false "restrict search to a hotel"
+// @Param limit query int false "pagination limit over room types (default 50)"
+// @Param offset query int false "pagination offset over room types"
+// @Success 200 {array} dto.AvailabilityResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /availability [get]
+func (h *Handler) searchAvailability(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	q, err := assembler.FromAvailabilityParams(params.Get("check_in"), params.Get("check_out"), params.Get("guests"), params.Get("hotel_id"))
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	opts, err := parseQueryOptions(r, query.Schema{})
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	list, err := h.service.SearchAvailability(r.Context(), q, opts)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	var resources []utils.Resource
+	for _, a := range list {
+		resp := assembler.ToAvailabilityResponse(a)
+		resources = append(resources, utils.NewResource(resp.RoomTypeID, "availability", "/api/v1/room-types/"+resp.RoomTypeID, resp))
+	}
+	utils.RespondWithCount(w, http.StatusOK, "availability listed", resources, len(resources))
+}
+
 // @Summary Cancel booking
 // @Tags Bookings
 // @Produce json
 // @Param id path string true "Booking ID"
-// @Success 200 {object} dto.BookingResponse
+// @Param If-Match header string false "ETag of the booking version being changed"
+// @Success 200 {object} dto.BookingCancellationResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /bookings/{id}/cancel [post]
 func (h *Handler) cancelBooking(w http.ResponseWriter, r *http.Request) {
@@ -84,20 +164,107 @@ func (h *Handler) cancelBooking(w http.ResponseWriter, r *http.Request) {
 		writeError(w, pkgErrors.New("bad_request", "invalid id"))
 		return
 	}
-	if err := h.service.CancelBooking(r.Context(), bookingID); err != nil {
+	version, err := ifMatch(r)
+	if err != nil {
 		writeError(w, pkgErrors.FromError(err))
 		return
 	}
-	bk, err := h.service.GetBooking(r.Context(), bookingID)
+	res, err := h.service.CancelBooking(r.Context(), bookingID, version)
 	if err != nil {
 		writeError(w, pkgErrors.FromError(err))
 		return
 	}
-	resp := assembler.ToResponse(bk, domain.PaymentResult{})
+	setETag(w, res.Booking.Version)
+	resp := assembler.ToCancellationResponse(res)
 	resource := utils.NewResource(resp.ID, "booking", "/api/v1/bookings/"+resp.ID, resp)
 	utils.Respond(w, http.StatusOK, "booking cancelled", resource)
 }
 
+// @Summary Cancel one room of a group booking
+// @Tags Bookings
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param itemID path string true "Booking item ID"
+// @Param If-Match header string false "ETag of the booking version being changed"
+// @Success 200 {object} dto.BookingCancellationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/{id}/items/{itemID}/cancel [post]
+func (h *Handler) cancelBookingItem(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	itemID, err := uuid.Parse(chi.URLParam(r, "itemID"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid item id"))
+		return
+	}
+	version, err := ifMatch(r)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	res, err := h.service.CancelBookingItem(r.Context(), bookingID, itemID, version)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	setETag(w, res.Booking.Version)
+	resp := assembler.ToCancellationResponse(res)
+	resource := utils.NewResource(resp.ID, "booking", "/api/v1/bookings/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "booking item cancelled", resource)
+}
+
+// @Summary Modify booking
+// @Tags Bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param request body dto.ModifyBookingRequest true "New dates and/or guest count"
+// @Param If-Match header string false "ETag of the booking version being changed"
+// @Success 200 {object} dto.BookingModificationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/{id}/modify [post]
+func (h *Handler) modifyBooking(w http.ResponseWriter, r *http.Request) {
+	bookingID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	var req dto.ModifyBookingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	cmd, err := assembler.FromModifyRequest(req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	if cmd.ExpectedVersion, err = ifMatch(r); err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	res, err := h.service.ModifyBooking(r.Context(), bookingID, cmd)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	setETag(w, res.Booking.Version)
+	resp := assembler.ToModificationResponse(res)
+	resource := utils.NewResource(resp.ID, "booking", "/api/v1/bookings/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "booking modified", resource)
+}
+
 // @Summary Get booking
 // @Tags Bookings
 // @Produce json
@@ -118,6 +285,7 @@ func (h *Handler) getBooking(w http.ResponseWriter, r *http.Request) {
 		writeError(w, pkgErrors.FromError(err))
 		return
 	}
+	setETag(w, bk.Version)
 	resp := assembler.ToResponse(bk, domain.PaymentResult{})
 	resource := utils.NewResource(resp.ID, "booking", "/api/v1/bookings/"+resp.ID, resp)
 	utils.Respond(w, http.StatusOK, "booking retrieved", resource)
@@ -147,29 +315,42 @@ func (h *Handler) getStatus(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary List bookings
+// @Description Customers only see their own bookings; admins see all of them.
 // @Tags Bookings
 // @Produce json
 // @Param limit query int false "pagination limit (default 50)"
 // @Param offset query int false "pagination offset"
+// @Param cursor query string false "next_cursor of the previous page; replaces offset"
+// @Param sort query string false "check_in, check_out or created_at; prefix with - for descending (default -created_at)"
+// @Param status query string false "filter by status"
+// @Param user_id query string false "filter by user"
+// @Param room_type_id query string false "filter by room type"
+// @Param check_in[gte] query string false "check-in on or after (YYYY-MM-DD)"
+// @Param check_in[lte] query string false "check-in on or before (YYYY-MM-DD)"
 // @Success 200 {array} dto.BookingResponse
+// @Failure 400 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /bookings [get]
 func (h *Handler) listBookings(w http.ResponseWriter, r *http.Request) {
-	opts := parseQueryOptions(r)
-	list, err := h.service.ListBookings(r.Context(), opts)
+	opts, err := parseQueryOptions(r, domain.ListSchema)
 	if err != nil {
 		writeError(w, pkgErrors.FromError(err))
 		return
 	}
-	resp := make([]dto.BookingResponse, 0, len(list))
-	for _, b := range list {
+	page, err := h.service.ListBookings(r.Context(), opts)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := make([]dto.BookingResponse, 0, len(page.Items))
+	for _, b := range page.Items {
 		resp = append(resp, assembler.ToResponse(b, domain.PaymentResult{}))
 	}
 	var resources []utils.Resource
 	for _, b := range resp {
 		resources = append(resources, utils.NewResource(b.ID, "booking", "/api/v1/bookings/"+b.ID, b))
 	}
-	utils.RespondWithCount(w, http.StatusOK, "bookings listed", resources, len(resources))
+	utils.RespondWithPage(w, http.StatusOK, "bookings listed", resources, len(resources), page.Total, page.NextCursor)
 }
 
 // @Summary Booking checkpoint
@@ -178,8 +359,11 @@ func (h *Handler) listBookings(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "Booking ID"
 // @Param request body dto.CheckpointRequest true "Checkpoint payload"
+// @Param If-Match header string false "ETag of the booking version being changed"
 // @Success 200 {object} dto.BookingResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /bookings/{id}/checkpoint [post]
 func (h *Handler) checkpoint(w http.ResponseWriter, r *http.Request) {
@@ -193,7 +377,16 @@ func (h *Handler) checkpoint(w http.ResponseWriter, r *http.Request) {
 		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
 		return
 	}
-	if err := h.service.Checkpoint(r.Context(), bookingID, req.Action); err != nil {
+	cmd, err := assembler.FromCheckpointRequest(req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	if cmd.ExpectedVersion, err = ifMatch(r); err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	if err := h.service.Checkpoint(r.Context(), bookingID, cmd); err != nil {
 		writeError(w, pkgErrors.FromError(err))
 		return
 	}
@@ -202,6 +395,7 @@ func (h *Handler) checkpoint(w http.ResponseWriter, r *http.Request) {
 		writeError(w, pkgErrors.FromError(err))
 		return
 	}
+	setETag(w, bk.Version)
 	resp := assembler.ToResponse(bk, domain.PaymentResult{})
 	resource := utils.NewResource(resp.ID, "booking", "/api/v1/bookings/"+resp.ID, resp)
 	utils.Respond(w, http.StatusOK, "status updated", resource)
@@ -213,12 +407,20 @@ func (h *Handler) checkpoint(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path string true "Booking ID"
 // @Param request body map[string]string true "Status payload"
+// @Param If-Match header string false "ETag of the booking version being changed"
 // @Success 200 {object} dto.BookingResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
 // @Router /bookings/{id}/status [post]
 // @Security BearerAuth
 func (h *Handler) updateStatus(w http.ResponseWriter, r *http.Request) {
+	// The payment service reports settled and failed payments here with its service token.
+	if _, ok := roleClaims(r, valueobject.RoleAdmin, valueobject.RoleService); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
 	bookingID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
 		writeError(w, pkgErrors.New("bad_request", "invalid id"))
@@ -231,7 +433,12 @@ func (h *Handler) updateStatus(w http.ResponseWriter, r *http.Request) {
 		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
 		return
 	}
-	if err := h.service.ApplyStatus(r.Context(), bookingID, payload.Status); err != nil {
+	version, err := ifMatch(r)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	if err := h.service.ApplyStatus(r.Context(), bookingID, payload.Status, version); err != nil {
 		writeError(w, pkgErrors.FromError(err))
 		return
 	}
@@ -240,17 +447,351 @@ func (h *Handler) updateStatus(w http.ResponseWriter, r *http.Request) {
 		writeError(w, pkgErrors.FromError(err))
 		return
 	}
+	setETag(w, bk.Version)
 	resp := assembler.ToResponse(bk, domain.PaymentResult{})
 	resource := utils.NewResource(resp.ID, "booking", "/api/v1/bookings/"+resp.ID, resp)
 	utils.Respond(w, http.StatusOK, "booking status updated", resource)
 }
 
+// @Summary Add staff note (admin)
+// @Tags Bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param request body dto.BookingNoteRequest true "Note payload"
+// @Success 201 {object} dto.BookingNoteResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/{id}/notes [post]
+func (h *Handler) addNote(w http.ResponseWriter, r *http.Request) {
+	claims, ok := staffClaims(r)
+	if !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	bookingID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	var req dto.BookingNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	// Service tokens carry no user id; their notes are recorded without an author.
+	authorID, _ := uuid.Parse(claims.UserID)
+	note, err := h.service.AddNote(r.Context(), bookingID, authorID, req.Body)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToNoteResponse(bookingID, note)
+	resource := utils.NewResource(resp.ID, "booking_note", "/api/v1/bookings/"+resp.BookingID+"/notes", resp)
+	utils.Respond(w, http.StatusCreated, "note added", resource)
+}
+
+// @Summary List staff notes (admin)
+// @Tags Bookings
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {array} dto.BookingNoteResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/{id}/notes [get]
+func (h *Handler) listNotes(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	bookingID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	notes, err := h.service.ListNotes(r.Context(), bookingID)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	var resources []utils.Resource
+	for _, n := range notes {
+		resp := assembler.ToNoteResponse(bookingID, n)
+		resources = append(resources, utils.NewResource(resp.ID, "booking_note", "/api/v1/bookings/"+resp.BookingID+"/notes", resp))
+	}
+	utils.RespondWithCount(w, http.StatusOK, "notes listed", resources, len(resources))
+}
+
+// @Summary Booking history (admin)
+// @Description Timeline of every change to a booking, with the actor who made it.
+// @Tags Bookings
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {array} dto.BookingHistoryEntryResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/{id}/history [get]
+func (h *Handler) getHistory(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	bookingID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	entries, err := h.service.History(r.Context(), bookingID)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	var resources []utils.Resource
+	for _, e := range entries {
+		resp := assembler.ToHistoryResponse(e)
+		resources = append(resources, utils.NewResource(resp.ID, "booking_event", "/api/v1/bookings/"+resp.BookingID+"/history", resp))
+	}
+	utils.RespondWithCount(w, http.StatusOK, "booking history listed", resources, len(resources))
+}
+
+// @Summary List outbox messages (admin)
+// @Tags Outbox
+// @Produce json
+// @Param status query string false "pending, delivered or dead"
+// @Param limit query int false "pagination limit (default 50)"
+// @Param offset query int false "pagination offset"
+// @Success 200 {array} dto.OutboxMessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/outbox [get]
+func (h *Handler) listOutbox(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	opts, err := parseQueryOptions(r, query.Schema{})
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	msgs, err := h.service.ListOutbox(r.Context(), r.URL.Query().Get("status"), opts)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	var resources []utils.Resource
+	for _, m := range msgs {
+		resp := assembler.ToOutboxResponse(m)
+		resources = append(resources, utils.NewResource(resp.ID, "outbox_message", "/api/v1/bookings/outbox/"+resp.ID, resp))
+	}
+	utils.RespondWithCount(w, http.StatusOK, "outbox messages listed", resources, len(resources))
+}
+
+// @Summary Get outbox message (admin)
+// @Tags Outbox
+// @Produce json
+// @Param id path string true "Outbox message ID"
+// @Success 200 {object} dto.OutboxMessageResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/outbox/{id} [get]
+func (h *Handler) getOutboxMessage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	msg, err := h.service.GetOutboxMessage(r.Context(), id)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToOutboxResponse(msg)
+	resource := utils.NewResource(resp.ID, "outbox_message", "/api/v1/bookings/outbox/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "outbox message retrieved", resource)
+}
+
+// @Summary Replay outbox message (admin)
+// @Description Queues a stuck or dead-lettered event for immediate redelivery with a fresh retry budget.
+// @Tags Outbox
+// @Produce json
+// @Param id path string true "Outbox message ID"
+// @Success 200 {object} dto.OutboxMessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/outbox/{id}/replay [post]
+func (h *Handler) replayOutboxMessage(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	msg, err := h.service.ReplayOutboxMessage(r.Context(), id)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToOutboxResponse(msg)
+	resource := utils.NewResource(resp.ID, "outbox_message", "/api/v1/bookings/outbox/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "outbox message queued for replay", resource)
+}
+
+// @Summary Join waitlist
+// @Description Waits for a sold-out room type to free up for the stay. When a room frees up the guest is
+// @Description notified and a pending booking holds it until its payment hold expires. Admins may set
+// @Description user_id to join on behalf of a user.
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param request body dto.WaitlistRequest true "Waitlist payload"
+// @Success 201 {object} dto.WaitlistResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /waitlist [post]
+func (h *Handler) joinWaitlist(w http.ResponseWriter, r *http.Request) {
+	var req dto.WaitlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	cmd, err := assembler.FromWaitlistRequest(req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	entry, err := h.service.JoinWaitlist(r.Context(), cmd)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToWaitlistResponse(entry)
+	resource := utils.NewResource(resp.ID, "waitlist_entry", "/api/v1/waitlist/"+resp.ID, resp)
+	utils.Respond(w, http.StatusCreated, "joined waitlist", resource)
+}
+
+// @Summary List waitlist entries
+// @Description Customers only see their own entries; admins see all of them.
+// @Tags Waitlist
+// @Produce json
+// @Param limit query int false "pagination limit (default 50)"
+// @Param offset query int false "pagination offset"
+// @Param cursor query string false "next_cursor of the previous page; replaces offset"
+// @Param sort query string false "check_in or created_at; prefix with - for descending (default created_at)"
+// @Param status query string false "waiting, offered, left or expired"
+// @Param user_id query string false "filter by user"
+// @Param room_type_id query string false "filter by room type"
+// @Success 200 {array} dto.WaitlistResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /waitlist [get]
+func (h *Handler) listWaitlist(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseQueryOptions(r, domain.WaitlistSchema)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	page, err := h.service.ListWaitlist(r.Context(), opts)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	var resources []utils.Resource
+	for _, e := range page.Items {
+		resp := assembler.ToWaitlistResponse(e)
+		resources = append(resources, utils.NewResource(resp.ID, "waitlist_entry", "/api/v1/waitlist/"+resp.ID, resp))
+	}
+	utils.RespondWithPage(w, http.StatusOK, "waitlist listed", resources, len(resources), page.Total, page.NextCursor)
+}
+
+// @Summary Leave waitlist
+// @Description Only waiting entries can be left; once a room was offered, cancel its booking instead.
+// @Tags Waitlist
+// @Produce json
+// @Param id path string true "Waitlist entry ID"
+// @Success 200 {object} dto.WaitlistResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /waitlist/{id} [delete]
+func (h *Handler) leaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	entry, err := h.service.LeaveWaitlist(r.Context(), id)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToWaitlistResponse(entry)
+	resource := utils.NewResource(resp.ID, "waitlist_entry", "/api/v1/waitlist/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "left waitlist", resource)
+}
+
+// staffClaims returns the caller's claims when they belong to hotel staff.
+func staffClaims(r *http.Request) (*middleware.Claims, bool) {
+	return roleClaims(r, valueobject.RoleAdmin)
+}
+
+// roleClaims returns the caller's claims when they carry one of roles.
+func roleClaims(r *http.Request, roles ...valueobject.Role) (*middleware.Claims, bool) {
+	claims, ok := r.Context().Value(middleware.AuthContextKey).(*middleware.Claims)
+	if !ok {
+		return nil, false
+	}
+	for _, role := range roles {
+		if claims.Role == string(role) {
+			return claims, true
+		}
+	}
+	return nil, false
+}
+
+// ifMatch reads the booking version a client expects from If-Match. A missing header or "*"
+// returns zero, which skips the check.
+func ifMatch(r *http.Request) (int, error) {
+	tag := strings.TrimPrefix(strings.TrimSpace(r.Header.Get("If-Match")), "W/")
+	if tag == "" || tag == "*" {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(strings.Trim(tag, `"`))
+	if err != nil || version < 1 {
+		return 0, pkgErrors.New("bad_request", "invalid If-Match header")
+	}
+	return version, nil
+}
+
+// setETag exposes the booking version so clients can send it back in If-Match.
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
 func writeError(w http.ResponseWriter, err pkgErrors.APIError) {
 	utils.Respond(w, pkgErrors.StatusCode(err), err.Message, err)
 }
 
-func parseQueryOptions(r *http.Request) query.Options {
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	return query.Options{Limit: limit, Offset: offset}
+// parseQueryOptions reads pagination, sort, cursor and the filters schema allows.
+func parseQueryOptions(r *http.Request, schema query.Schema) (query.Options, error) {
+	return query.Parse(r.URL.Query(), schema)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,7 +16,10 @@ import (
 	hdomain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
 	bookinghttp "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/booking/http"
 	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking"
+	pkgDomain "github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 func TestBookingHandlerListWithPagination(t *testing.T) {
@@ -31,10 +35,227 @@ func TestBookingHandlerListWithPagination(t *testing.T) {
 	r := chi.NewRouter()
 	r.Mount("/", h.Routes())
 
-	req := httptest.NewRequest(http.MethodGet, "/bookings?limit=1&offset=0", nil)
+	req := httptest.NewRequest(http.MethodGet, "/bookings?limit=1&offset=0&status=pending_payment", nil)
 	rec := httptest.NewRecorder()
 	r.ServeHTTP(rec, req)
 	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"total":1`)
+
+	req = httptest.NewRequest(http.MethodGet, "/bookings?sort=total_price", nil)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestBookingHandlerNotesAreStaffOnly(t *testing.T) {
+	id, owner := uuid.New(), uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{
+		id: {ID: id, UserID: owner, Status: domain.StatusConfirmed, CheckIn: time.Now(), CheckOut: time.Now().Add(24 * time.Hour)},
+	}}
+	svc := booking.NewService(repo, &hotelRepoStub{}, &paymentGatewayStub{}, &notificationGatewayStub{})
+	h := bookinghttp.NewHandler(svc)
+
+	serve := func(role, method, path, body string) *httptest.ResponseRecorder {
+		r := chi.NewRouter()
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				claims := &middleware.Claims{UserID: owner.String(), Role: role}
+				next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), middleware.AuthContextKey, claims)))
+			})
+		})
+		r.Mount("/", h.Routes())
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	path := "/bookings/" + id.String() + "/notes"
+	require.Equal(t, http.StatusForbidden, serve("customer", http.MethodPost, path, `{"body":"VIP"}`).Code)
+	require.Equal(t, http.StatusForbidden, serve("customer", http.MethodGet, path, "").Code)
+	require.Equal(t, http.StatusCreated, serve("admin", http.MethodPost, path, `{"body":"VIP, upgrade if possible"}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve("admin", http.MethodPost, path, `{"body":"  "}`).Code)
+
+	rec := serve("admin", http.MethodGet, path, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "VIP, upgrade if possible")
+
+	// The guest-facing booking never carries staff notes.
+	rec = serve("customer", http.MethodGet, "/bookings/"+id.String(), "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), "VIP")
+}
+
+func TestBookingHandlerETagAndIfMatch(t *testing.T) {
+	id := uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{
+		id: {ID: id, Status: domain.StatusPendingPayment, CheckIn: time.Now(), CheckOut: time.Now().Add(24 * time.Hour), Version: 2},
+	}}
+	svc := booking.NewService(repo, &hotelRepoStub{}, &paymentGatewayStub{}, &notificationGatewayStub{})
+	h := bookinghttp.NewHandler(svc)
+
+	role := "admin"
+	serve := func(method, path, ifMatch, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		claims := &middleware.Claims{UserID: uuid.New().String(), Role: role}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.AuthContextKey, claims))
+		rec := httptest.NewRecorder()
+		h.Routes().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "/bookings/"+id.String(), "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	path := "/bookings/" + id.String() + "/status"
+	require.Equal(t, http.StatusPreconditionFailed, serve(http.MethodPost, path, `"1"`, `{"status":"confirmed"}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPost, path, "abc", `{"status":"confirmed"}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPost, path, "", `{"status":"on_hold"}`).Code)
+	role = "customer"
+	require.Equal(t, http.StatusForbidden, serve(http.MethodPost, path, "", `{"status":"confirmed"}`).Code)
+	role = "admin"
+	require.Equal(t, domain.StatusPendingPayment, repo.store[id].Status)
+
+	// The payment service reports payment outcomes with its service token.
+	role = "service"
+	rec = serve(http.MethodPost, path, `W/"2"`, `{"status":"confirmed"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, `"3"`, rec.Header().Get("ETag"))
+	require.Equal(t, domain.StatusConfirmed, repo.store[id].Status)
+}
+
+func TestBookingHandlerOutboxIsStaffOnly(t *testing.T) {
+	svc := booking.NewService(&bookingRepoStub{}, &hotelRepoStub{}, &paymentGatewayStub{}, &notificationGatewayStub{})
+	h := bookinghttp.NewHandler(svc)
+
+	serve := func(role, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		claims := &middleware.Claims{UserID: uuid.New().String(), Role: role}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.AuthContextKey, claims))
+		rec := httptest.NewRecorder()
+		h.Routes().ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusForbidden, serve("customer", "/bookings/outbox").Code)
+	require.Equal(t, http.StatusForbidden, serve("service", "/bookings/outbox").Code)
+	require.Equal(t, http.StatusOK, serve("admin", "/bookings/outbox?status=dead").Code)
+	require.Equal(t, http.StatusBadRequest, serve("admin", "/bookings/outbox?status=stuck").Code)
+}
+
+func TestBookingHandlerWaitlistRoutes(t *testing.T) {
+	svc := booking.NewService(&bookingRepoStub{}, &hotelRepoStub{}, &paymentGatewayStub{}, &notificationGatewayStub{})
+	h := bookinghttp.NewHandler(svc)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		claims := &middleware.Claims{UserID: uuid.New().String(), Role: "customer"}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.AuthContextKey, claims))
+		rec := httptest.NewRecorder()
+		h.Routes().ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusOK, serve(http.MethodGet, "/waitlist?status=waiting", "").Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/waitlist?sort=guests", "").Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/waitlist", `{"room_type_id":"nope","check_in":"2030-01-02","check_out":"2030-01-04"}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/waitlist", `{"room_type_id":"`+uuid.NewString()+`","check_in":"2030-01-04","check_out":"2030-01-02"}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodDelete, "/waitlist/nope", "").Code)
+}
+
+func TestBookingHandlerPromotionsAreStaffOnly(t *testing.T) {
+	svc := booking.NewService(&bookingRepoStub{}, &hotelRepoStub{}, &paymentGatewayStub{}, &notificationGatewayStub{})
+	h := bookinghttp.NewHandler(svc)
+
+	serve := func(role, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		claims := &middleware.Claims{UserID: uuid.New().String(), Role: role}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.AuthContextKey, claims))
+		rec := httptest.NewRecorder()
+		h.Routes().ServeHTTP(rec, req)
+		return rec
+	}
+
+	body := `{"code":"summer10","kind":"percent","amount":10,"room_type_ids":["` + uuid.NewString() + `"]}`
+	require.Equal(t, http.StatusForbidden, serve("customer", http.MethodGet, "/promotions", "").Code)
+	require.Equal(t, http.StatusForbidden, serve("customer", http.MethodPost, "/promotions", body).Code)
+	require.Equal(t, http.StatusForbidden, serve("customer", http.MethodDelete, "/promotions/"+uuid.NewString(), "").Code)
+
+	rec := serve("admin", http.MethodPost, "/promotions", body)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Contains(t, rec.Body.String(), `"code":"SUMMER10"`)
+	require.Equal(t, http.StatusBadRequest, serve("admin", http.MethodPost, "/promotions", `{"code":"X","kind":"bogo","amount":1}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve("admin", http.MethodPost, "/promotions", `{"code":"X","kind":"fixed","amount":5,"hotel_ids":["nope"]}`).Code)
+	require.Equal(t, http.StatusOK, serve("admin", http.MethodGet, "/promotions", "").Code)
+}
+
+func TestBookingHandlerExchangeRatesAreStaffOnly(t *testing.T) {
+	svc := booking.NewService(&bookingRepoStub{}, &hotelRepoStub{}, &paymentGatewayStub{}, &notificationGatewayStub{})
+	h := bookinghttp.NewHandler(svc)
+
+	serve := func(role, method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/exchange-rates", strings.NewReader(body))
+		claims := &middleware.Claims{UserID: uuid.New().String(), Role: role}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.AuthContextKey, claims))
+		rec := httptest.NewRecorder()
+		h.Routes().ServeHTTP(rec, req)
+		return rec
+	}
+
+	body := `{"base":"usd","rates":{"IDR":16250,"SGD":1.35}}`
+	require.Equal(t, http.StatusForbidden, serve("customer", http.MethodGet, "").Code)
+	require.Equal(t, http.StatusForbidden, serve("customer", http.MethodPut, body).Code)
+
+	rec := serve("admin", http.MethodPut, body)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"id":"USD-IDR"`)
+	require.Equal(t, http.StatusBadRequest, serve("admin", http.MethodPut, `{"base":"USD","rates":{"IDR":-1}}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve("admin", http.MethodPut, `{"base":"USD","rates":{}}`).Code)
+	require.Equal(t, http.StatusOK, serve("admin", http.MethodGet, "").Code)
+}
+
+func TestBookingHandlerQuotes(t *testing.T) {
+	svc := booking.NewService(&bookingRepoStub{}, &hotelRepoStub{}, &paymentGatewayStub{}, &notificationGatewayStub{})
+	h := bookinghttp.NewHandler(svc)
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/quotes", strings.NewReader(body))
+		claims := &middleware.Claims{UserID: uuid.New().String(), Role: "customer"}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.AuthContextKey, claims))
+		rec := httptest.NewRecorder()
+		h.Routes().ServeHTTP(rec, req)
+		return rec
+	}
+
+	body := `{"room_type_id":"` + uuid.NewString() + `","check_in":"2030-01-02","check_out":"2030-01-04"}`
+	require.Equal(t, http.StatusUnprocessableEntity, serve(body).Code)
+	require.Equal(t, http.StatusBadRequest, serve(`{"room_type_id":"nope","check_in":"2030-01-02","check_out":"2030-01-04"}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve(`{`).Code)
+}
+
+func TestBookingHandlerHolds(t *testing.T) {
+	svc := booking.NewService(&bookingRepoStub{}, &hotelRepoStub{}, &paymentGatewayStub{}, &notificationGatewayStub{})
+	h := bookinghttp.NewHandler(svc)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		claims := &middleware.Claims{UserID: uuid.New().String(), Role: "customer"}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.AuthContextKey, claims))
+		rec := httptest.NewRecorder()
+		h.Routes().ServeHTTP(rec, req)
+		return rec
+	}
+
+	stay := `"room_type_id":"` + uuid.NewString() + `","check_in":"2030-01-02","check_out":"2030-01-04"`
+	// The stub room type has no bookable rooms, so nothing can be held.
+	require.Equal(t, http.StatusConflict, serve(http.MethodPost, "/holds", `{`+stay+`}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/holds", `{`+stay+`,"minutes":45}`).Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/holds", `{`).Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/holds/nope", "").Code)
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodDelete, "/holds/nope", "").Code)
 }
 
 // stubs for booking handler test
@@ -46,35 +267,119 @@ func (b *bookingRepoStub) Create(ctx context.Context, bk domain.Booking) error {
 func (b *bookingRepoStub) FindByID(ctx context.Context, id uuid.UUID) (domain.Booking, error) {
 	return b.store[id], nil
 }
-func (b *bookingRepoStub) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Booking, error) {
+func (b *bookingRepoStub) FindByUserID(ctx context.Context, userID uuid.UUID, _ query.Options) (query.Page[domain.Booking], error) {
 	var out []domain.Booking
 	for _, v := range b.store {
 		if v.UserID == userID {
 			out = append(out, v)
 		}
 	}
-	return out, nil
+	return query.Page[domain.Booking]{Items: out, Total: int64(len(out))}, nil
 }
-func (b *bookingRepoStub) List(ctx context.Context, opts query.Options) ([]domain.Booking, error) {
+func (b *bookingRepoStub) List(ctx context.Context, opts query.Options) (query.Page[domain.Booking], error) {
 	var out []domain.Booking
 	for _, v := range b.store {
 		out = append(out, v)
 	}
-	return out, nil
+	return query.Page[domain.Booking]{Items: out, Total: int64(len(out))}, nil
 }
 func (b *bookingRepoStub) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
 	return nil
 }
-func (b *bookingRepoStub) Save(ctx context.Context, bk domain.Booking) error {
-	b.store[bk.ID] = bk
+func (b *bookingRepoStub) Save(ctx context.Context, bk *domain.Booking) error {
+	bk.Version++
+	b.store[bk.ID] = *bk
 	return nil
 }
+func (b *bookingRepoStub) FindOverlapping(context.Context, uuid.UUID, valueobject.DateRange) ([]domain.Booking, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindByRoomID(context.Context, uuid.UUID, valueobject.DateRange) ([]domain.Booking, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindExpiredHolds(context.Context, time.Time, int) ([]domain.Booking, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindMissedCheckIns(context.Context, time.Time, int) ([]domain.Booking, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+func (b *bookingRepoStub) AppendHistory(context.Context, []pkgDomain.DomainEvent) error { return nil }
+func (b *bookingRepoStub) History(context.Context, uuid.UUID) ([]domain.HistoryEntry, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) HistoryBookingIDs(context.Context) ([]uuid.UUID, error)      { return nil, nil }
+func (b *bookingRepoStub) AppendEvents(context.Context, []pkgDomain.DomainEvent) error { return nil }
+func (b *bookingRepoStub) ClaimOutbox(context.Context, time.Time, int) ([]domain.OutboxMessage, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) SaveOutbox(context.Context, domain.OutboxMessage) error { return nil }
+func (b *bookingRepoStub) ListOutbox(context.Context, string, query.Options) ([]domain.OutboxMessage, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindOutbox(context.Context, uuid.UUID) (domain.OutboxMessage, error) {
+	return domain.OutboxMessage{}, nil
+}
+func (b *bookingRepoStub) CreateWaitlistEntry(context.Context, domain.WaitlistEntry) error {
+	return nil
+}
+func (b *bookingRepoStub) SaveWaitlistEntry(context.Context, domain.WaitlistEntry) error { return nil }
+func (b *bookingRepoStub) FindWaitlistEntry(context.Context, uuid.UUID) (domain.WaitlistEntry, error) {
+	return domain.WaitlistEntry{}, nil
+}
+func (b *bookingRepoStub) ListWaitlist(context.Context, query.Options) (query.Page[domain.WaitlistEntry], error) {
+	return query.Page[domain.WaitlistEntry]{}, nil
+}
+func (b *bookingRepoStub) CreatePromotion(context.Context, domain.Promotion) error { return nil }
+func (b *bookingRepoStub) UpdatePromotion(context.Context, domain.Promotion) error { return nil }
+func (b *bookingRepoStub) DeletePromotion(context.Context, uuid.UUID) error        { return nil }
+func (b *bookingRepoStub) GetPromotion(context.Context, uuid.UUID) (domain.Promotion, error) {
+	return domain.Promotion{}, nil
+}
+func (b *bookingRepoStub) ListPromotions(context.Context) ([]domain.Promotion, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindPromotionByCode(context.Context, string) (domain.Promotion, error) {
+	return domain.Promotion{}, nil
+}
+func (b *bookingRepoStub) LockPromotionByCode(context.Context, string) (domain.Promotion, error) {
+	return domain.Promotion{}, nil
+}
+func (b *bookingRepoStub) CountRedemptions(context.Context, uuid.UUID, uuid.UUID) (int, int, error) {
+	return 0, 0, nil
+}
+func (b *bookingRepoStub) CreateRedemption(context.Context, domain.Redemption) error { return nil }
+func (b *bookingRepoStub) ReleaseRedemptions(context.Context, uuid.UUID) error       { return nil }
+func (b *bookingRepoStub) SaveExchangeRates(context.Context, []valueobject.ExchangeRate) error {
+	return nil
+}
+func (b *bookingRepoStub) ListExchangeRates(context.Context) (valueobject.ExchangeRates, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) CreateInventoryHold(context.Context, domain.InventoryHold) error {
+	return nil
+}
+func (b *bookingRepoStub) SaveInventoryHold(context.Context, domain.InventoryHold) error { return nil }
+func (b *bookingRepoStub) FindInventoryHold(context.Context, uuid.UUID) (domain.InventoryHold, error) {
+	return domain.InventoryHold{}, nil
+}
+func (b *bookingRepoStub) FindActiveHolds(context.Context, uuid.UUID, valueobject.DateRange, time.Time) ([]domain.InventoryHold, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindUserHolds(context.Context, uuid.UUID, time.Time) ([]domain.InventoryHold, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) ExpireInventoryHolds(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
 
 type hotelRepoStub struct{}
 
 func (h *hotelRepoStub) CreateHotel(context.Context, hdomain.Hotel) error { return nil }
-func (h *hotelRepoStub) ListHotels(ctx context.Context, opts query.Options) ([]hdomain.Hotel, error) {
-	return nil, nil
+func (h *hotelRepoStub) ListHotels(ctx context.Context, opts query.Options) (query.Page[hdomain.Hotel], error) {
+	return query.Page[hdomain.Hotel]{}, nil
 }
 func (h *hotelRepoStub) CreateRoomType(context.Context, hdomain.RoomType) error { return nil }
 func (h *hotelRepoStub) ListRoomTypes(context.Context, uuid.UUID) ([]hdomain.RoomType, error) {
@@ -90,6 +395,13 @@ func (h *hotelRepoStub) GetRoomType(context.Context, uuid.UUID) (hdomain.RoomTyp
 func (h *hotelRepoStub) ListRooms(context.Context, query.Options) ([]hdomain.Room, error) {
 	return nil, nil
 }
+func (h *hotelRepoStub) GetRoomTypeForUpdate(context.Context, uuid.UUID) (hdomain.RoomType, error) {
+	return hdomain.RoomType{}, nil
+}
+func (h *hotelRepoStub) CountBookableRooms(context.Context, uuid.UUID) (int, error) { return 0, nil }
+func (h *hotelRepoStub) ListRoomsByType(context.Context, uuid.UUID) ([]hdomain.Room, error) {
+	return nil, nil
+}
 func (h *hotelRepoStub) GetHotel(context.Context, uuid.UUID) (hdomain.Hotel, error) {
 	return hdomain.Hotel{}, nil
 }
@@ -98,12 +410,43 @@ func (h *hotelRepoStub) DeleteHotel(context.Context, uuid.UUID) error
 func (h *hotelRepoStub) GetRoom(context.Context, uuid.UUID) (hdomain.Room, error) {
 	return hdomain.Room{}, nil
 }
-func (h *hotelRepoStub) UpdateRoom(context.Context, uuid.UUID, hdomain.Room) error { return nil }
-func (h *hotelRepoStub) DeleteRoom(context.Context, uuid.UUID) error               { return nil }
+func (h *hotelRepoStub) UpdateRoom(context.Context, uuid.UUID, hdomain.Room) error    { return nil }
+func (h *hotelRepoStub) DeleteRoom(context.Context, uuid.UUID) error                  { return nil }
+func (h *hotelRepoStub) CreatePricingRule(context.Context, hdomain.PricingRule) error { return nil }
+func (h *hotelRepoStub) UpdatePricingRule(context.Context, hdomain.PricingRule) error { return nil }
+func (h *hotelRepoStub) DeletePricingRule(context.Context, uuid.UUID) error           { return nil }
+func (h *hotelRepoStub) GetPricingRule(context.Context, uuid.UUID) (hdomain.PricingRule, error) {
+	return hdomain.PricingRule{}, nil
+}
+func (h *hotelRepoStub) ListPricingRules(context.Context, uuid.UUID) ([]hdomain.PricingRule, error) {
+	return nil, nil
+}
+func (h *hotelRepoStub) ListCalendar(context.Context, uuid.UUID, time.Time, time.Time) (hdomain.Calendar, error) {
+	return nil, nil
+}
+func (h *hotelRepoStub) SaveCalendar(context.Context, []hdomain.CalendarDay) error  { return nil }
+func (h *hotelRepoStub) CreateChargeRule(context.Context, hdomain.ChargeRule) error { return nil }
+func (h *hotelRepoStub) UpdateChargeRule(context.Context, hdomain.ChargeRule) error { return nil }
+func (h *hotelRepoStub) DeleteChargeRule(context.Context, uuid.UUID) error          { return nil }
+func (h *hotelRepoStub) GetChargeRule(context.Context, uuid.UUID) (hdomain.ChargeRule, error) {
+	return hdomain.ChargeRule{}, nil
+}
+func (h *hotelRepoStub) ListChargeRules(context.Context, uuid.UUID) ([]hdomain.ChargeRule, error) {
+	return nil, nil
+}
+func (h *hotelRepoStub) CreateRatePlan(context.Context, hdomain.RatePlan) error { return nil }
+func (h *hotelRepoStub) UpdateRatePlan(context.Context, hdomain.RatePlan) error { return nil }
+func (h *hotelRepoStub) DeleteRatePlan(context.Context, uuid.UUID) error        { return nil }
+func (h *hotelRepoStub) GetRatePlan(context.Context, uuid.UUID) (hdomain.RatePlan, error) {
+	return hdomain.RatePlan{}, nil
+}
+func (h *hotelRepoStub) ListRatePlans(context.Context, uuid.UUID) ([]hdomain.RatePlan, error) {
+	return nil, nil
+}
 
 type paymentGatewayStub struct{}
 
-func (p *paymentGatewayStub) Initiate(context.Context, uuid.UUID, float64) (domain.PaymentResult, error) {
+func (p *paymentGatewayStub) Initiate(context.Context, uuid.UUID, uuid.UUID, valueobject.Money, []domain.PriceLine) (domain.PaymentResult, error) {
 	return domain.PaymentResult{
 		ID:         uuid.New(),
 		Status:     "pending",
@@ -112,6 +455,18 @@ func (p *paymentGatewayStub) Initiate(context.Context, uuid.UUID, float64) (doma
 	}, nil
 }
 
+func (p *paymentGatewayStub) InitiateSupplement(ctx context.Context, bookingID, userID uuid.UUID, amount valueobject.Money) (domain.PaymentResult, error) {
+	return p.Initiate(ctx, bookingID, userID, amount, nil)
+}
+
+func (p *paymentGatewayStub) Refund(context.Context, uuid.UUID, valueobject.Money, string) error {
+	return nil
+}
+
+func (p *paymentGatewayStub) Expire(context.Context, uuid.UUID) error {
+	return nil
+}
+
 type notificationGatewayStub struct{}
 
 func (n *notificationGatewayStub) Notify(context.Context, string, any) error { return nil }
@@ -0,0 +1,104 @@
+package bookinghttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/utils"
+)
+
+// @Summary Hold rooms
+// @Description Keeps rooms of a room type aside for the stay while the guest checks out, for minutes (default 10, at most 30).
+// @Description Nobody else can book the held rooms meanwhile; passing the hold's id as hold_id to POST /bookings books them even when the rest sold out.
+// @Description A new hold with the same session_id replaces the session's earlier hold. Holds expire on their own. Admins may set user_id to hold on behalf of a user.
+// @Tags Holds
+// @Accept json
+// @Produce json
+// @Param request body dto.HoldRequest true "Hold payload"
+// @Success 201 {object} dto.HoldResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /holds [post]
+func (h *Handler) createHold(w http.ResponseWriter, r *http.Request) {
+	var req dto.HoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	cmd, err := assembler.FromHoldRequest(req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	hold, err := h.service.HoldInventory(r.Context(), cmd)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToHoldResponse(hold)
+	resource := utils.NewResource(resp.ID, "hold", "/api/v1/holds/"+resp.ID, resp)
+	utils.Respond(w, http.StatusCreated, "rooms held", resource)
+}
+
+// @Summary Get hold
+// @Description Customers only see their own holds; admins see all of them.
+// @Tags Holds
+// @Produce json
+// @Param id path string true "Hold ID"
+// @Success 200 {object} dto.HoldResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /holds/{id} [get]
+func (h *Handler) getHold(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	hold, err := h.service.GetInventoryHold(r.Context(), id)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToHoldResponse(hold)
+	resource := utils.NewResource(resp.ID, "hold", "/api/v1/holds/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "hold retrieved", resource)
+}
+
+// @Summary Release hold
+// @Description Gives the held rooms up before the hold expires. Only active holds can be released.
+// @Tags Holds
+// @Produce json
+// @Param id path string true "Hold ID"
+// @Success 200 {object} dto.HoldResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /holds/{id} [delete]
+func (h *Handler) releaseHold(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	hold, err := h.service.ReleaseInventoryHold(r.Context(), id)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToHoldResponse(hold)
+	resource := utils.NewResource(resp.ID, "hold", "/api/v1/holds/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "hold released", resource)
+}
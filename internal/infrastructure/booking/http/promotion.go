@@ -0,0 +1,179 @@
+package bookinghttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/utils"
+)
+
+// @Summary Create promotion (admin)
+// @Description Adds a promo code worth a percentage or a fixed amount off the rooms it applies to.
+// @Tags Promotions
+// @Accept json
+// @Produce json
+// @Param request body dto.PromotionRequest true "Promotion payload"
+// @Success 201 {object} dto.PromotionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /promotions [post]
+func (h *Handler) createPromotion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	var req dto.PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	promo, err := assembler.FromPromotionRequest(req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	promo, err = h.service.CreatePromotion(r.Context(), promo)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToPromotionResponse(promo)
+	resource := utils.NewResource(resp.ID, "promotion", "/api/v1/promotions/"+resp.ID, resp)
+	utils.Respond(w, http.StatusCreated, "promotion created", resource)
+}
+
+// @Summary List promotions (admin)
+// @Tags Promotions
+// @Produce json
+// @Success 200 {array} dto.PromotionResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /promotions [get]
+func (h *Handler) listPromotions(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	promotions, err := h.service.ListPromotions(r.Context())
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	var resources []utils.Resource
+	for _, p := range promotions {
+		resp := assembler.ToPromotionResponse(p)
+		resources = append(resources, utils.NewResource(resp.ID, "promotion", "/api/v1/promotions/"+resp.ID, resp))
+	}
+	utils.RespondWithCount(w, http.StatusOK, "promotions listed", resources, len(resources))
+}
+
+// @Summary Get promotion (admin)
+// @Tags Promotions
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} dto.PromotionResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /promotions/{id} [get]
+func (h *Handler) getPromotion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	promo, err := h.service.GetPromotion(r.Context(), id)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToPromotionResponse(promo)
+	resource := utils.NewResource(resp.ID, "promotion", "/api/v1/promotions/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "promotion retrieved", resource)
+}
+
+// @Summary Update promotion (admin)
+// @Description Replaces a promo code. Bookings that already redeemed it keep their discount.
+// @Tags Promotions
+// @Accept json
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Param request body dto.PromotionRequest true "Promotion payload"
+// @Success 200 {object} dto.PromotionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /promotions/{id} [put]
+func (h *Handler) updatePromotion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	var req dto.PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	promo, err := assembler.FromPromotionRequest(req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	promo, err = h.service.UpdatePromotion(r.Context(), id, promo)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToPromotionResponse(promo)
+	resource := utils.NewResource(resp.ID, "promotion", "/api/v1/promotions/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "promotion updated", resource)
+}
+
+// @Summary Delete promotion (admin)
+// @Tags Promotions
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /promotions/{id} [delete]
+func (h *Handler) deletePromotion(w http.ResponseWriter, r *http.Request) {
+	if _, ok := staffClaims(r); !ok {
+		writeError(w, pkgErrors.New("forbidden", "insufficient role"))
+		return
+	}
+	idParam := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	if err := h.service.DeletePromotion(r.Context(), id); err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	utils.Respond(w, http.StatusOK, "promotion deleted", dto.SuccessResponse{
+		ID:      idParam,
+		Message: "promotion deleted",
+	})
+}
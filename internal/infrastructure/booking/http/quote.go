@@ -0,0 +1,44 @@
+package bookinghttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/utils"
+)
+
+// @Summary Quote booking
+// @Description Prices a booking request exactly as POST /bookings would, without holding any rooms, and itemises the price.
+// @Description Passing the returned token as quote_token to POST /bookings with the same rooms, dates, promo code and currencies keeps the quoted price until expires_at; an expired, tampered or mismatched token answers 409.
+// @Tags Bookings
+// @Accept json
+// @Produce json
+// @Param request body dto.BookingRequest true "Booking payload; guest details are ignored"
+// @Success 200 {object} dto.QuoteResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /quotes [post]
+func (h *Handler) createQuote(w http.ResponseWriter, r *http.Request) {
+	var input dto.BookingRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	cmd, err := assembler.FromRequest(input)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	quote, token, err := h.service.CreateQuote(r.Context(), cmd)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	utils.Respond(w, http.StatusOK, "quote created", assembler.ToQuoteResponse(quote, token))
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -12,28 +13,51 @@ import (
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
+// initiateAttempts bounds how often a payment initiation is sent when the payment service is unreachable.
+// Retrying is only safe because every attempt carries the same Idempotency-Key.
+const initiateAttempts = 3
+
 // HTTPGateway calls payment service over HTTP.
 type HTTPGateway struct {
 	baseURL string
+	secret  string
 	client  *http.Client
 }
 
-func NewHTTPGateway(baseURL string) domain.PaymentGateway {
-	return &HTTPGateway{baseURL: baseURL, client: &http.Client{Timeout: 5 * time.Second}}
+// NewHTTPGateway builds a gateway to the payment service at baseURL. Secret signs the service token
+// sent with the calls the booking service makes on its own account: supplements, refunds and expiries.
+func NewHTTPGateway(baseURL, secret string) domain.PaymentGateway {
+	return &HTTPGateway{baseURL: baseURL, secret: secret, client: &http.Client{Timeout: 5 * time.Second}}
 }
 
-func (g *HTTPGateway) Initiate(ctx context.Context, bookingID uuid.UUID, amount float64) (domain.PaymentResult, error) {
-	payload := map[string]any{"booking_id": bookingID.String(), "amount": amount, "currency": "IDR"}
-	body, _ := json.Marshal(payload)
-	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/payments", g.baseURL), bytes.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-	if token, ok := ctx.Value(middleware.AuthTokenKey).(string); ok && token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
+func (g *HTTPGateway) Initiate(ctx context.Context, bookingID, userID uuid.UUID, amount valueobject.Money, lines []domain.PriceLine) (domain.PaymentResult, error) {
+	payload := map[string]any{"booking_id": bookingID.String(), "user_id": userID.String(), "amount": amount.Amount, "currency": amount.Currency}
+	if len(lines) > 0 {
+		items := make([]dto.PriceLine, 0, len(lines))
+		for _, l := range lines {
+			items = append(items, dto.PriceLine(l))
+		}
+		payload["lines"] = items
 	}
-	resp, err := g.client.Do(req)
+	return g.initiate(ctx, payload)
+}
+
+// InitiateSupplement creates an additional payment for the same booking.
+func (g *HTTPGateway) InitiateSupplement(ctx context.Context, bookingID, userID uuid.UUID, amount valueobject.Money) (domain.PaymentResult, error) {
+	ctx, err := g.asService(ctx)
+	if err != nil {
+		return domain.PaymentResult{}, err
+	}
+	return g.initiate(ctx, map[string]any{"booking_id": bookingID.String(), "user_id": userID.String(), "amount": amount.Amount, "currency": amount.Currency, "kind": "supplement"})
+}
+
+func (g *HTTPGateway) initiate(ctx context.Context, payload map[string]any) (domain.PaymentResult, error) {
+	resp, err := g.doIdempotent(ctx, http.MethodPost, "/payments", payload)
 	if err != nil {
 		return domain.PaymentResult{}, err
 	}
@@ -41,16 +65,10 @@ func (g *HTTPGateway) Initiate(ctx context.Context, bookingID uuid.UUID, amount
 	if resp.StatusCode >= 300 {
 		return domain.PaymentResult{}, fmt.Errorf("payment initiation failed: %d", resp.StatusCode)
 	}
-	// unwrap gateway envelope -> resource -> attributes
-	var envelope struct {
-		Data struct {
-			Attributes dto.PaymentResponse `json:"attributes"`
-		} `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+	var result dto.PaymentResponse
+	if err := decodeAttributes(resp, &result); err != nil {
 		return domain.PaymentResult{}, err
 	}
-	result := envelope.Data.Attributes
 	paymentID, _ := uuid.Parse(result.ID)
 	return domain.PaymentResult{
 		ID:         paymentID,
@@ -59,3 +77,140 @@ func (g *HTTPGateway) Initiate(ctx context.Context, bookingID uuid.UUID, amount
 		PaymentURL: result.PaymentURL,
 	}, nil
 }
+
+// Refund requests a refund of amount for the booking. The payment service takes it from the booking
+// payment first and its paid supplements after, and rejects it when amount is more than they
+// collected or not in the currency they were made in.
+func (g *HTTPGateway) Refund(ctx context.Context, bookingID uuid.UUID, amount valueobject.Money, reason string) error {
+	ctx, err := g.asService(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := g.doIdempotent(ctx, http.MethodPost, "/payments/refund", dto.RefundRequest{BookingID: bookingID.String(), Amount: amount.Amount, Currency: amount.Currency, Reason: reason})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("payment refund failed: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Expire voids the booking's pending payment; a booking without a payment has nothing to expire.
+func (g *HTTPGateway) Expire(ctx context.Context, bookingID uuid.UUID) error {
+	ctx, err := g.asService(ctx)
+	if err != nil {
+		return err
+	}
+	pay, err := g.bookingPayment(ctx, bookingID)
+	if pkgErrors.FromError(err).Code == "not_found" {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := g.do(ctx, http.MethodPost, "/payments/"+pay.ID+"/expire", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusConflict {
+		return pkgErrors.New("conflict", "payment already settled")
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("payment expiry failed: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// bookingPayment fetches the original payment of a booking.
+func (g *HTTPGateway) bookingPayment(ctx context.Context, bookingID uuid.UUID) (dto.PaymentResponse, error) {
+	resp, err := g.do(ctx, http.MethodGet, "/payments/by-booking/"+bookingID.String(), nil)
+	if err != nil {
+		return dto.PaymentResponse{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return dto.PaymentResponse{}, pkgErrors.New("not_found", "payment not found")
+	}
+	if resp.StatusCode >= 300 {
+		return dto.PaymentResponse{}, fmt.Errorf("payment lookup failed: %d", resp.StatusCode)
+	}
+	var pay dto.PaymentResponse
+	if err := decodeAttributes(resp, &pay); err != nil {
+		return dto.PaymentResponse{}, err
+	}
+	return pay, nil
+}
+
+// asService swaps the caller's token on ctx for a booking-service token. A settlement the outbox
+// relay retries then reaches the payment service as the same caller as the first attempt, which
+// keeps both in one Idempotency-Key scope.
+func (g *HTTPGateway) asService(ctx context.Context) (context.Context, error) {
+	return middleware.WithServiceToken(ctx, g.secret, "booking-service")
+}
+
+// doIdempotent sends a request carrying the Idempotency-Key found on ctx and retries it on
+// transport and server errors. Without a key it sends the request once.
+func (g *HTTPGateway) doIdempotent(ctx context.Context, method, path string, payload any) (*http.Response, error) {
+	if _, ok := middleware.IdempotencyKeyFrom(ctx); !ok {
+		return g.do(ctx, method, path, payload)
+	}
+	var (
+		resp *http.Response
+		err  error
+	)
+	for attempt := 0; attempt < initiateAttempts; attempt++ {
+		resp, err = g.do(ctx, method, path, payload)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			return resp, nil
+		}
+		if ctx.Err() != nil || attempt == initiateAttempts-1 {
+			break
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+	}
+	return resp, err
+}
+
+func (g *HTTPGateway) do(ctx context.Context, method, path string, payload any) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		raw, _ := json.Marshal(payload)
+		body = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token, ok := ctx.Value(middleware.AuthTokenKey).(string); ok && token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if key, ok := middleware.IdempotencyKeyFrom(ctx); ok {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	return g.client.Do(req)
+}
+
+// decodeAttributes unwraps gateway envelope -> resource -> attributes.
+func decodeAttributes(resp *http.Response, out any) error {
+	var envelope struct {
+		Data struct {
+			Attributes json.RawMessage `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return err
+	}
+	if len(envelope.Data.Attributes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data.Attributes, out)
+}
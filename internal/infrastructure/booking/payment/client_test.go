@@ -2,14 +2,26 @@ package payment
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
+func idr(amount float64) valueobject.Money {
+	return valueobject.Money{Amount: valueobject.AmountOf(amount), Currency: "IDR"}
+}
+
 func TestHTTPGatewayInitiateSuccess(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -17,8 +29,8 @@ func TestHTTPGatewayInitiateSuccess(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	gw := NewHTTPGateway(srv.URL)
-	res, err := gw.Initiate(context.Background(), uuid.New(), 1000)
+	gw := NewHTTPGateway(srv.URL, "secret")
+	res, err := gw.Initiate(context.Background(), uuid.New(), uuid.New(), idr(1000), nil)
 	require.NoError(t, err)
 	require.Equal(t, "pending", res.Status)
 }
@@ -29,8 +41,8 @@ func TestHTTPGatewayInitiateError(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	gw := NewHTTPGateway(srv.URL)
-	_, err := gw.Initiate(context.Background(), uuid.New(), 1000)
+	gw := NewHTTPGateway(srv.URL, "secret")
+	_, err := gw.Initiate(context.Background(), uuid.New(), uuid.New(), idr(1000), nil)
 	require.Error(t, err)
 }
 
@@ -40,7 +52,141 @@ func TestHTTPGatewayInitiateBadJSON(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	gw := NewHTTPGateway(srv.URL)
-	_, err := gw.Initiate(context.Background(), uuid.New(), 1000)
+	gw := NewHTTPGateway(srv.URL, "secret")
+	_, err := gw.Initiate(context.Background(), uuid.New(), uuid.New(), idr(1000), nil)
+	require.Error(t, err)
+}
+
+func TestHTTPGatewayInitiateSupplementSendsKind(t *testing.T) {
+	var payload map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"data":{"attributes":{"id":"` + uuid.New().String() + `","status":"pending"}}}`))
+	}))
+	defer srv.Close()
+
+	gw := NewHTTPGateway(srv.URL, "secret")
+	res, err := gw.InitiateSupplement(context.Background(), uuid.New(), uuid.New(), idr(250))
+	require.NoError(t, err)
+	require.Equal(t, "pending", res.Status)
+	require.Equal(t, "supplement", payload["kind"])
+	require.Equal(t, float64(250), payload["amount"])
+}
+
+func TestHTTPGatewayInitiateSendsLines(t *testing.T) {
+	var payload struct {
+		Amount   valueobject.Amount `json:"amount"`
+		Currency string             `json:"currency"`
+		Lines    []dto.PriceLine    `json:"lines"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"data":{"attributes":{"id":"` + uuid.New().String() + `","status":"pending"}}}`))
+	}))
+	defer srv.Close()
+
+	gw := NewHTTPGateway(srv.URL, "secret")
+	_, err := gw.Initiate(context.Background(), uuid.New(), uuid.New(), valueobject.Money{Amount: valueobject.AmountOf(68.75), Currency: "USD"}, []domain.PriceLine{
+		{Type: domain.LineRoom, Name: "Room nights", Amount: valueobject.AmountOf(62.5)},
+		{Type: domain.LineTax, Name: "PB1", Amount: valueobject.AmountOf(6.25)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(68.75), payload.Amount)
+	require.Equal(t, "USD", payload.Currency)
+	require.Equal(t, []dto.PriceLine{
+		{Type: "room", Name: "Room nights", Amount: valueobject.AmountOf(62.5)},
+		{Type: "tax", Name: "PB1", Amount: valueobject.AmountOf(6.25)},
+	}, payload.Lines)
+}
+
+func TestHTTPGatewayRefundByBooking(t *testing.T) {
+	bookingID := uuid.New()
+	var (
+		refund        map[string]any
+		auth, idemKey string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/payments/refund" {
+			http.NotFound(w, r)
+			return
+		}
+		auth, idemKey = r.Header.Get("Authorization"), r.Header.Get(middleware.IdempotencyKeyHeader)
+		_ = json.NewDecoder(r.Body).Decode(&refund)
+		w.Write([]byte(`{"data":{"attributes":{"id":"` + uuid.New().String() + `","status":"refunded"}}}`))
+	}))
+	defer srv.Close()
+
+	// Refunds are sent as the booking service, not as the user who triggered them.
+	ctx := context.WithValue(context.Background(), middleware.AuthTokenKey, "user-token")
+	ctx = middleware.WithIdempotencyKey(ctx, "booking:refund:v2")
+	gw := NewHTTPGateway(srv.URL, "secret")
+	require.NoError(t, gw.Refund(ctx, bookingID, idr(100), "booking_modified"))
+	require.True(t, strings.HasPrefix(auth, "Bearer "))
+	require.NotEqual(t, "Bearer user-token", auth)
+	require.Equal(t, "booking:refund:v2", idemKey)
+	require.Equal(t, bookingID.String(), refund["booking_id"])
+	require.NotContains(t, refund, "payment_id")
+	require.Equal(t, float64(100), refund["amount"])
+	require.Equal(t, "IDR", refund["currency"])
+}
+
+func TestHTTPGatewayRefundError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "refund exceeds payment amount", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	gw := NewHTTPGateway(srv.URL, "secret")
+	require.Error(t, gw.Refund(context.Background(), uuid.New(), idr(100), "booking_modified"))
+}
+
+func TestHTTPGatewayExpire(t *testing.T) {
+	bookingID := uuid.New()
+	paymentID := uuid.New().String()
+	settled := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/payments/by-booking/" + bookingID.String():
+			w.Write([]byte(`{"data":{"attributes":{"id":"` + paymentID + `","status":"pending"}}}`))
+		case "/payments/" + paymentID + "/expire":
+			if settled {
+				http.Error(w, "settled", http.StatusConflict)
+				return
+			}
+			w.Write([]byte(`{"data":{"attributes":{"id":"` + paymentID + `","status":"failed"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	gw := NewHTTPGateway(srv.URL, "secret")
+	require.NoError(t, gw.Expire(context.Background(), bookingID))
+	// Bookings without a payment have nothing to expire.
+	require.NoError(t, gw.Expire(context.Background(), uuid.New()))
+
+	settled = true
+	err := gw.Expire(context.Background(), bookingID)
 	require.Error(t, err)
+	require.Equal(t, "conflict", pkgErrors.FromError(err).Code)
+}
+
+func TestHTTPGatewayInitiateRetriesWithIdempotencyKey(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(middleware.IdempotencyKeyHeader))
+		if len(keys) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":{"attributes":{"id":"` + uuid.New().String() + `","status":"pending"}}}`))
+	}))
+	defer srv.Close()
+
+	gw := NewHTTPGateway(srv.URL, "secret")
+	ctx := middleware.WithIdempotencyKey(context.Background(), "booking:1:payment:v1")
+	res, err := gw.Initiate(ctx, uuid.New(), uuid.New(), idr(1000), nil)
+	require.NoError(t, err)
+	require.Equal(t, "pending", res.Status)
+	require.Equal(t, []string{"booking:1:payment:v1", "booking:1:payment:v1"}, keys)
 }
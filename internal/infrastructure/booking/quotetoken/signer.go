@@ -0,0 +1,53 @@
+package quotetoken
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// audience marks quote tokens, so a quote token and a bearer token never pass for one another.
+const audience = "quote"
+
+// JWTSigner signs quotes as HS256 tokens that expire with the quote.
+type JWTSigner struct {
+	secret []byte
+}
+
+func NewJWTSigner(secret string) domain.QuoteSigner {
+	return &JWTSigner{secret: []byte(secret)}
+}
+
+type claims struct {
+	Quote domain.Quote `json:"quote"`
+	jwt.RegisteredClaims
+}
+
+func (s *JWTSigner) Sign(q domain.Quote) (string, error) {
+	c := claims{
+		Quote: q,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   q.UserID.String(),
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(q.ExpiresAt),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(s.secret)
+}
+
+func (s *JWTSigner) Verify(token string) (domain.Quote, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(audience))
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return domain.Quote{}, pkgErrors.New("conflict", "quote expired, request a new quote")
+	case err != nil:
+		return domain.Quote{}, pkgErrors.New("conflict", "invalid quote token")
+	}
+	return c.Quote, nil
+}
@@ -0,0 +1,85 @@
+package quotetoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+func sampleQuote(expires time.Time) domain.Quote {
+	return domain.Quote{
+		UserID:   uuid.New(),
+		CheckIn:  time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		CheckOut: time.Date(2030, 1, 3, 0, 0, 0, 0, time.UTC),
+		Items: []domain.LineItem{{
+			RoomTypeID: uuid.New(),
+			Guests:     2,
+			Price: domain.PriceBreakdown{
+				Base:    valueobject.AmountOf(200),
+				Charges: []domain.Charge{{Kind: "tax", Name: "VAT", Amount: valueobject.AmountOf(22.005)}},
+				Total:   valueobject.AmountOf(222.005),
+			},
+			Status: domain.ItemStatusActive,
+		}},
+		Currency:  "USD",
+		ExpiresAt: expires.Truncate(time.Second),
+	}
+}
+
+func TestJWTSignerRoundTrip(t *testing.T) {
+	signer := NewJWTSigner("secret")
+	q := sampleQuote(time.Now().Add(time.Minute))
+
+	token, err := signer.Sign(q)
+	require.NoError(t, err)
+	got, err := signer.Verify(token)
+	require.NoError(t, err)
+	require.Equal(t, q.UserID, got.UserID)
+	require.True(t, q.CheckIn.Equal(got.CheckIn))
+	require.Equal(t, q.Items[0].Price.Total, got.Items[0].Price.Total)
+	require.Equal(t, q.Items[0].Price.Charges, got.Items[0].Price.Charges)
+	require.Equal(t, q.Booking().TotalPrice, got.Booking().TotalPrice)
+}
+
+func TestJWTSignerRejectsExpiredAndTamperedTokens(t *testing.T) {
+	signer := NewJWTSigner("secret")
+
+	expired, err := signer.Sign(sampleQuote(time.Now().Add(-time.Minute)))
+	require.NoError(t, err)
+	_, err = signer.Verify(expired)
+	require.Equal(t, "conflict", pkgErrors.FromError(err).Code)
+	require.Contains(t, err.Error(), "expired")
+
+	token, err := signer.Sign(sampleQuote(time.Now().Add(time.Minute)))
+	require.NoError(t, err)
+	forged, err := NewJWTSigner("other").Sign(sampleQuote(time.Now().Add(time.Minute)))
+	require.NoError(t, err)
+	// The claims of one token under the signature of another.
+	tampered := forged[:strings.LastIndex(forged, ".")] + token[strings.LastIndex(token, "."):]
+	for _, bad := range []string{tampered, forged, "not-a-token"} {
+		_, err = signer.Verify(bad)
+		require.Equal(t, "conflict", pkgErrors.FromError(err).Code)
+		require.Equal(t, "invalid quote token", err.Error())
+	}
+}
+
+func TestJWTSignerRejectsOtherTokens(t *testing.T) {
+	signer := NewJWTSigner("secret")
+
+	// A token without the quote audience, like a bearer token, is not a quote even under the same key.
+	bearer, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Quote:            sampleQuote(time.Now().Add(time.Minute)),
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, err = signer.Verify(bearer)
+	require.Equal(t, "invalid quote token", err.Error())
+}
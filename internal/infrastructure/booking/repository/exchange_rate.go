@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// SaveExchangeRates upserts the rates, keeping one row per currency pair.
+func (r *GormRepository) SaveExchangeRates(ctx context.Context, rates []valueobject.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	models := make([]exchangeRateModel, 0, len(rates))
+	for _, rate := range rates {
+		models = append(models, exchangeRateModel{Base: rate.Base, Quote: rate.Quote, Rate: rate.Rate, AsOf: rate.AsOf, Source: rate.Source})
+	}
+	return r.conn(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base"}, {Name: "quote"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "as_of", "source"}),
+	}).Create(&models).Error
+}
+
+func (r *GormRepository) ListExchangeRates(ctx context.Context) (valueobject.ExchangeRates, error) {
+	var models []exchangeRateModel
+	if err := r.conn(ctx).Order("base, quote").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	rates := make(valueobject.ExchangeRates, 0, len(models))
+	for _, m := range models {
+		rates = append(rates, valueobject.ExchangeRate{Base: m.Base, Quote: m.Quote, Rate: m.Rate, AsOf: m.AsOf, Source: m.Source})
+	}
+	return rates, nil
+}
+
+type exchangeRateModel struct {
+	Base   string  `gorm:"primaryKey"`
+	Quote  string  `gorm:"primaryKey"`
+	Rate   float64 `gorm:"type:numeric"`
+	AsOf   time.Time
+	Source string
+}
+
+func (exchangeRateModel) TableName() string { return "exchange_rates" }
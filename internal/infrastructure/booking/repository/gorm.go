@@ -2,15 +2,19 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/database"
 	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // GormRepository persists bookings.
@@ -20,56 +24,82 @@ type GormRepository struct {
 
 func NewGormRepository(db *gorm.DB) *GormRepository { return &GormRepository{db: db} }
 
-// AutoMigrate ensures bookings table exists.
+// AutoMigrate ensures the booking tables, outbox, event store, waitlist, promotions, exchange rates and
+// inventory holds exist.
 func AutoMigrate(db *gorm.DB) error {
-	if db.Migrator().HasTable(&bookingModel{}) {
-		return nil
+	for _, model := range []any{&bookingModel{}, &bookingItemModel{}, &bookingNoteModel{}, &outboxModel{}, &historyModel{}, &waitlistModel{}, &promotionModel{}, &redemptionModel{}, &exchangeRateModel{}, &inventoryHoldModel{}} {
+		if db.Migrator().HasTable(model) {
+			continue
+		}
+		if err := db.AutoMigrate(model); err != nil {
+			return err
+		}
 	}
-	return db.AutoMigrate(&bookingModel{})
+	return nil
+}
+
+// WithinTransaction runs fn in a transaction shared with every repository using the same context.
+func (r *GormRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return database.WithinTransaction(ctx, r.db, fn)
+}
+
+func (r *GormRepository) conn(ctx context.Context) *gorm.DB {
+	return database.Conn(ctx, r.db)
 }
 
 func (r *GormRepository) Create(ctx context.Context, b domain.Booking) error {
-	return r.db.WithContext(ctx).Create(&bookingModel{
-		ID:          b.ID,
-		UserID:      b.UserID,
-		RoomTypeID:  b.RoomTypeID,
-		CheckIn:     b.CheckIn,
-		CheckOut:    b.CheckOut,
-		Status:      b.Status,
-		Guests:      b.Guests,
-		TotalPrice:  b.TotalPrice,
-		TotalNights: b.TotalNights,
-		CreatedAt:   b.CreatedAt,
-	}).Error
+	model := toModel(b)
+	if err := r.conn(ctx).Create(&model).Error; err != nil {
+		return err
+	}
+	items := toItemModels(b)
+	if len(items) == 0 {
+		return nil
+	}
+	return r.conn(ctx).Create(&items).Error
 }
 
 func (r *GormRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Booking, error) {
 	var model bookingModel
-	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+	if err := r.conn(ctx).First(&model, "id = ?", id).Error; err != nil {
 		return domain.Booking{}, translateErr(err)
 	}
-	return model.toDomain(), nil
+	bookings, err := r.hydrate(ctx, []bookingModel{model})
+	if err != nil {
+		return domain.Booking{}, err
+	}
+	return bookings[0], nil
 }
 
-func (r *GormRepository) List(ctx context.Context, opts query.Options) ([]domain.Booking, error) {
-	var models []bookingModel
-	qo := opts.Normalize(50)
-	tx := r.db.WithContext(ctx).Order("created_at DESC")
-	if qo.Limit > 0 {
-		tx = tx.Limit(qo.Limit).Offset(qo.Offset)
-	}
-	if err := tx.Find(&models).Error; err != nil {
-		return nil, err
+// bookingListing maps domain.ListSchema onto the bookings table.
+var bookingListing = database.Listing{
+	Columns: map[string]string{
+		"status":       "status",
+		"user_id":      "user_id",
+		"room_type_id": "room_type_id",
+		"check_in":     "check_in",
+		"check_out":    "check_out",
+		"created_at":   "created_at",
+	},
+	DefaultSort: domain.ListSchema.DefaultSort,
+	DefaultSize: 50,
+}
+
+func (r *GormRepository) List(ctx context.Context, opts query.Options) (query.Page[domain.Booking], error) {
+	page, err := database.Paginate[bookingModel](r.conn(ctx), bookingListing, opts)
+	if err != nil {
+		return query.Page[domain.Booking]{}, err
 	}
-	bookings := make([]domain.Booking, 0, len(models))
-	for _, m := range models {
-		bookings = append(bookings, m.toDomain())
+	bookings, err := r.hydrate(ctx, page.Items)
+	if err != nil {
+		return query.Page[domain.Booking]{}, err
 	}
-	return bookings, nil
+	return query.Page[domain.Booking]{Items: bookings, NextCursor: page.NextCursor, Total: page.Total}, nil
 }
 
 func (r *GormRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
-	res := r.db.WithContext(ctx).Model(&bookingModel{}).Where("id = ?", id).Update("status", status)
+	res := r.conn(ctx).Model(&bookingModel{}).Where("id = ?", id).
+		Updates(map[string]any{"status": status, "version": gorm.Expr("version + 1")})
 	if err := res.Error; err != nil {
 		return err
 	}
@@ -79,51 +109,217 @@ func (r *GormRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status
 	return nil
 }
 
-func (r *GormRepository) Save(ctx context.Context, b domain.Booking) error {
-	return r.db.WithContext(ctx).Save(&bookingModel{
-		ID:          b.ID,
-		UserID:      b.UserID,
-		RoomTypeID:  b.RoomTypeID,
-		CheckIn:     b.CheckIn,
-		CheckOut:    b.CheckOut,
-		Status:      b.Status,
-		Guests:      b.Guests,
-		TotalPrice:  b.TotalPrice,
-		TotalNights: b.TotalNights,
-		CreatedAt:   b.CreatedAt,
-	}).Error
+// Save updates the booking row only while its version is unchanged, so concurrent writers cannot
+// silently overwrite each other.
+func (r *GormRepository) Save(ctx context.Context, b *domain.Booking) error {
+	model := toModel(*b)
+	model.Version = b.Version + 1
+	res := r.conn(ctx).Model(&model).Where("version = ?", b.Version).Select("*").Updates(&model)
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrVersionConflict
+	}
+	b.Version = model.Version
+	for _, item := range toItemModels(*b) {
+		if err := r.conn(ctx).Save(&item).Error; err != nil {
+			return err
+		}
+	}
+	// Notes are append-only, so only new ones need writing.
+	notes := toNoteModels(*b)
+	if len(notes) == 0 {
+		return nil
+	}
+	return r.conn(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&notes).Error
+}
+
+// FindByUserID lists the user's bookings like List. A user_id filter in opts cannot widen the list
+// to other users; it is replaced by userID.
+func (r *GormRepository) FindByUserID(ctx context.Context, userID uuid.UUID, opts query.Options) (query.Page[domain.Booking], error) {
+	filters := []query.Filter{{Field: "user_id", Op: query.OpEq, Value: userID}}
+	for _, f := range opts.Filters {
+		if f.Field != "user_id" {
+			filters = append(filters, f)
+		}
+	}
+	opts.Filters = filters
+	return r.List(ctx, opts)
+}
+
+func (r *GormRepository) FindOverlapping(ctx context.Context, roomTypeID uuid.UUID, stay valueobject.DateRange) ([]domain.Booking, error) {
+	var models []bookingModel
+	err := r.conn(ctx).
+		Where("status NOT IN ?", domain.ReleasedStatuses()).
+		Where("(room_type_id = ? OR id IN (?))", roomTypeID, r.conn(ctx).Model(&bookingItemModel{}).
+			Select("booking_id").
+			Where("room_type_id = ? AND status = ?", roomTypeID, domain.ItemStatusActive)).
+		Where("check_in < ? AND check_out > ?", stay.End, stay.Start).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.hydrate(ctx, models)
+}
+
+func (r *GormRepository) FindByRoomID(ctx context.Context, roomID uuid.UUID, stay valueobject.DateRange) ([]domain.Booking, error) {
+	var models []bookingModel
+	err := r.conn(ctx).
+		Where("room_id = ? AND status NOT IN ?", roomID, domain.ReleasedStatuses()).
+		Where("check_in < ? AND check_out > ?", stay.End, stay.Start).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.hydrate(ctx, models)
+}
+
+// FindExpiredHolds claims expired pending bookings with SKIP LOCKED so concurrent workers never pick the same row.
+func (r *GormRepository) FindExpiredHolds(ctx context.Context, now time.Time, limit int) ([]domain.Booking, error) {
+	var models []bookingModel
+	err := r.conn(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND expires_at <= ?", domain.StatusPendingPayment, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.hydrate(ctx, models)
 }
 
-func (r *GormRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Booking, error) {
+// FindMissedCheckIns claims confirmed bookings whose check-in day is over, skipping rows other workers hold.
+func (r *GormRepository) FindMissedCheckIns(ctx context.Context, before time.Time, limit int) ([]domain.Booking, error) {
 	var models []bookingModel
-	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&models).Error; err != nil {
+	err := r.conn(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND check_in < ?", domain.StatusConfirmed, before).
+		Order("check_in").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
 		return nil, err
 	}
+	return r.hydrate(ctx, models)
+}
+
+// hydrate loads the line items and staff notes of the given bookings and maps them to the domain.
+func (r *GormRepository) hydrate(ctx context.Context, models []bookingModel) ([]domain.Booking, error) {
 	bookings := make([]domain.Booking, 0, len(models))
+	if len(models) == 0 {
+		return bookings, nil
+	}
+	ids := make([]uuid.UUID, 0, len(models))
 	for _, m := range models {
-		bookings = append(bookings, m.toDomain())
+		ids = append(ids, m.ID)
+	}
+	var items []bookingItemModel
+	if err := r.conn(ctx).Where("booking_id IN ?", ids).Order("position").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	var notes []bookingNoteModel
+	if err := r.conn(ctx).Where("booking_id IN ?", ids).Order("created_at").Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	itemsByBooking := make(map[uuid.UUID][]domain.LineItem, len(models))
+	for _, item := range items {
+		itemsByBooking[item.BookingID] = append(itemsByBooking[item.BookingID], item.toDomain())
+	}
+	notesByBooking := make(map[uuid.UUID][]domain.StaffNote)
+	for _, note := range notes {
+		notesByBooking[note.BookingID] = append(notesByBooking[note.BookingID], note.toDomain())
+	}
+	for _, m := range models {
+		b := m.toDomain()
+		b.Items = itemsByBooking[m.ID]
+		b.Notes = notesByBooking[m.ID]
+		bookings = append(bookings, b)
 	}
 	return bookings, nil
 }
 
-
 type bookingModel struct {
-	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
-	UserID      uuid.UUID `gorm:"type:uuid;index"`
-	RoomTypeID  uuid.UUID `gorm:"type:uuid;index"`
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	UserID      uuid.UUID  `gorm:"type:uuid;index"`
+	RoomTypeID  uuid.UUID  `gorm:"type:uuid;index"`
+	RoomID      *uuid.UUID `gorm:"type:uuid;index"`
 	CheckIn     time.Time
 	CheckOut    time.Time
 	Status      string `gorm:"index"`
 	Guests      int
-	TotalPrice  float64 `gorm:"type:numeric"`
+	TotalPrice  valueobject.Amount `gorm:"type:numeric"`
 	TotalNights int
-	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime"`
+	ExpiresAt   *time.Time `gorm:"index"`
+	Version     int        `gorm:"not null;default:1"`
+
+	GuestName        string
+	GuestPhone       string
+	GuestNationality string
+	AdditionalGuests []string `gorm:"type:text;serializer:json"`
+	ArrivalTime      string
+	SpecialRequests  string
+	PromoCode        string
+	PaymentTiming    string
+
+	Currency       string `gorm:"not null;default:IDR"`
+	ChargeCurrency string
+	RateCurrency   string  // display currency of the rate snapshot, empty when prices are shown as is
+	ExchangeRate   float64 `gorm:"type:numeric"`
+	RateAsOf       *time.Time
+	RateSource     string
 }
 
 func (bookingModel) TableName() string { return "bookings" }
 
+func toModel(b domain.Booking) bookingModel {
+	m := bookingModel{
+		ID:          b.ID,
+		UserID:      b.UserID,
+		RoomTypeID:  b.RoomTypeID,
+		CheckIn:     b.CheckIn,
+		CheckOut:    b.CheckOut,
+		Status:      b.Status,
+		Guests:      b.Guests,
+		TotalPrice:  b.TotalPrice,
+		TotalNights: b.TotalNights,
+		CreatedAt:   b.CreatedAt,
+		Version:     b.Version,
+
+		GuestName:        b.Guest.Name,
+		GuestPhone:       b.Guest.Phone,
+		GuestNationality: b.Guest.Nationality,
+		AdditionalGuests: b.Guest.AdditionalGuests,
+		ArrivalTime:      b.ArrivalTime,
+		SpecialRequests:  b.SpecialRequests,
+		PromoCode:        b.PromoCode,
+		PaymentTiming:    b.PaymentTiming,
+
+		Currency:       b.PriceCurrency(),
+		ChargeCurrency: b.ChargeCurrency,
+	}
+	if rate := b.ExchangeRate; rate.Base != "" && !rate.IsIdentity() {
+		asOf := rate.AsOf
+		m.RateCurrency = rate.Quote
+		m.ExchangeRate = rate.Rate
+		m.RateAsOf = &asOf
+		m.RateSource = rate.Source
+	}
+	if b.RoomID != uuid.Nil {
+		roomID := b.RoomID
+		m.RoomID = &roomID
+	}
+	if !b.ExpiresAt.IsZero() {
+		expiresAt := b.ExpiresAt
+		m.ExpiresAt = &expiresAt
+	}
+	return m
+}
+
 func (m bookingModel) toDomain() domain.Booking {
-	return domain.Booking{
+	b := domain.Booking{
 		ID:          m.ID,
 		UserID:      m.UserID,
 		RoomTypeID:  m.RoomTypeID,
@@ -134,7 +330,207 @@ func (m bookingModel) toDomain() domain.Booking {
 		TotalPrice:  m.TotalPrice,
 		TotalNights: m.TotalNights,
 		CreatedAt:   m.CreatedAt,
+		Version:     m.Version,
+
+		Guest: valueobject.GuestDetails{
+			Name:             m.GuestName,
+			Phone:            m.GuestPhone,
+			Nationality:      m.GuestNationality,
+			AdditionalGuests: m.AdditionalGuests,
+		},
+		ArrivalTime:     m.ArrivalTime,
+		SpecialRequests: m.SpecialRequests,
+		PromoCode:       m.PromoCode,
+		PaymentTiming:   m.PaymentTiming,
+
+		Currency:       m.Currency,
+		ChargeCurrency: m.ChargeCurrency,
+	}
+	if m.RateCurrency != "" {
+		b.ExchangeRate = valueobject.ExchangeRate{Base: m.Currency, Quote: m.RateCurrency, Rate: m.ExchangeRate, Source: m.RateSource}
+		if m.RateAsOf != nil {
+			b.ExchangeRate.AsOf = *m.RateAsOf
+		}
+	}
+	if m.RoomID != nil {
+		b.RoomID = *m.RoomID
+	}
+	if m.ExpiresAt != nil {
+		b.ExpiresAt = *m.ExpiresAt
+	}
+	return b
+}
+
+type bookingItemModel struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	BookingID          uuid.UUID  `gorm:"type:uuid;index"`
+	RoomTypeID         uuid.UUID  `gorm:"type:uuid;index"`
+	RatePlanID         *uuid.UUID `gorm:"type:uuid"`
+	CancellationPolicy string     // of the rate plan, empty for the room type's
+	Position           int
+	Guests             int
+	BasePrice          valueobject.Amount `gorm:"type:numeric"`
+	GuestSurcharge     valueobject.Amount `gorm:"type:numeric"`
+	Discount           valueobject.Amount `gorm:"type:numeric"`
+	Promo              valueobject.Amount `gorm:"type:numeric"`
+	Total              valueobject.Amount `gorm:"type:numeric"`
+	Nights             string             `gorm:"type:text"` // JSON encoded nightPrice list
+	Charges            string             `gorm:"type:text"` // JSON encoded itemCharge list
+	Status             string             `gorm:"not null;default:active"`
+}
+
+// nightPrice is the stored form of a domain.NightPrice. Amounts are written as exact JSON numbers;
+// those written as floats before are read back rounded to four decimals.
+type nightPrice struct {
+	Date      time.Time          `json:"date"`
+	Rate      valueobject.Amount `json:"rate"`
+	Surcharge valueobject.Amount `json:"surcharge"`
+	Discount  valueobject.Amount `json:"discount"`
+	Total     valueobject.Amount `json:"total"`
+	Rules     []string           `json:"rules,omitempty"`
+}
+
+// itemCharge is the stored form of a domain.Charge.
+type itemCharge struct {
+	Kind      string             `json:"kind"`
+	Name      string             `json:"name"`
+	Amount    valueobject.Amount `json:"amount"`
+	Inclusive bool               `json:"inclusive,omitempty"`
+}
+
+func (bookingItemModel) TableName() string { return "booking_items" }
+
+func toItemModels(b domain.Booking) []bookingItemModel {
+	items := make([]bookingItemModel, 0, len(b.Items))
+	for i, item := range b.Items {
+		m := bookingItemModel{
+			ID:                 item.ID,
+			BookingID:          b.ID,
+			RoomTypeID:         item.RoomTypeID,
+			CancellationPolicy: item.CancellationPolicy,
+			Position:           i,
+			Guests:             item.Guests,
+			BasePrice:          item.Price.Base,
+			GuestSurcharge:     item.Price.GuestSurcharge,
+			Discount:           item.Price.Discount,
+			Promo:              item.Price.Promo,
+			Total:              item.Price.Total,
+			Nights:             encodeNights(item.Price.Nights),
+			Charges:            encodeCharges(item.Price.Charges),
+			Status:             item.Status,
+		}
+		if item.RatePlanID != uuid.Nil {
+			planID := item.RatePlanID
+			m.RatePlanID = &planID
+		}
+		items = append(items, m)
+	}
+	return items
+}
+
+func encodeNights(nights []domain.NightPrice) string {
+	if len(nights) == 0 {
+		return ""
+	}
+	stored := make([]nightPrice, 0, len(nights))
+	for _, n := range nights {
+		stored = append(stored, nightPrice(n))
+	}
+	// Plain numbers, times and strings always marshal.
+	raw, _ := json.Marshal(stored)
+	return string(raw)
+}
+
+// decodeNights reads the nights back. Items priced before the breakdown was stored have none.
+func decodeNights(raw string) []domain.NightPrice {
+	var stored []nightPrice
+	if raw == "" || json.Unmarshal([]byte(raw), &stored) != nil {
+		return nil
+	}
+	nights := make([]domain.NightPrice, 0, len(stored))
+	for _, n := range stored {
+		nights = append(nights, domain.NightPrice(n))
+	}
+	return nights
+}
+
+func encodeCharges(charges []domain.Charge) string {
+	if len(charges) == 0 {
+		return ""
+	}
+	stored := make([]itemCharge, 0, len(charges))
+	for _, c := range charges {
+		stored = append(stored, itemCharge(c))
+	}
+	raw, _ := json.Marshal(stored)
+	return string(raw)
+}
+
+// decodeCharges reads the charges back. Items priced before taxes and fees were itemised have none.
+func decodeCharges(raw string) []domain.Charge {
+	var stored []itemCharge
+	if raw == "" || json.Unmarshal([]byte(raw), &stored) != nil {
+		return nil
+	}
+	charges := make([]domain.Charge, 0, len(stored))
+	for _, c := range stored {
+		charges = append(charges, domain.Charge(c))
+	}
+	return charges
+}
+
+func (m bookingItemModel) toDomain() domain.LineItem {
+	item := domain.LineItem{
+		ID:                 m.ID,
+		RoomTypeID:         m.RoomTypeID,
+		CancellationPolicy: m.CancellationPolicy,
+		Guests:             m.Guests,
+		Price: domain.PriceBreakdown{
+			Base:           m.BasePrice,
+			GuestSurcharge: m.GuestSurcharge,
+			Discount:       m.Discount,
+			Promo:          m.Promo,
+			Charges:        decodeCharges(m.Charges),
+			Total:          m.Total,
+			Nights:         decodeNights(m.Nights),
+		},
+		Status: m.Status,
+	}
+	if m.RatePlanID != nil {
+		item.RatePlanID = *m.RatePlanID
+	}
+	return item
+}
+
+type bookingNoteModel struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	BookingID uuid.UUID  `gorm:"type:uuid;index"`
+	AuthorID  *uuid.UUID `gorm:"type:uuid"`
+	Body      string     `gorm:"type:text"`
+	CreatedAt time.Time
+}
+
+func (bookingNoteModel) TableName() string { return "booking_notes" }
+
+func toNoteModels(b domain.Booking) []bookingNoteModel {
+	notes := make([]bookingNoteModel, 0, len(b.Notes))
+	for _, n := range b.Notes {
+		m := bookingNoteModel{ID: n.ID, BookingID: b.ID, Body: n.Body, CreatedAt: n.CreatedAt}
+		if n.AuthorID != uuid.Nil {
+			authorID := n.AuthorID
+			m.AuthorID = &authorID
+		}
+		notes = append(notes, m)
+	}
+	return notes
+}
+
+func (m bookingNoteModel) toDomain() domain.StaffNote {
+	n := domain.StaffNote{ID: m.ID, Body: m.Body, CreatedAt: m.CreatedAt}
+	if m.AuthorID != nil {
+		n.AuthorID = *m.AuthorID
 	}
+	return n
 }
 
 func translateErr(err error) error {
@@ -12,6 +12,11 @@ import (
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
 	repo "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/booking/repository"
+	bookinguc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking"
+	pkgDomain "github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 func TestGormRepositoryCreate(t *testing.T) {
@@ -26,7 +31,7 @@ func TestGormRepositoryCreate(t *testing.T) {
 		CheckIn:     time.Now(),
 		CheckOut:    time.Now().Add(48 * time.Hour),
 		Status:      domain.StatusPendingPayment,
-		TotalPrice:  1000,
+		TotalPrice:  valueobject.AmountOf(1000),
 		TotalNights: 2,
 		Guests:      1,
 	}
@@ -38,6 +43,33 @@ func TestGormRepositoryCreate(t *testing.T) {
 	require.Equal(t, int64(1), count)
 }
 
+func TestGormRepositoryFindOverlapping(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	roomTypeID := uuid.New()
+	day := time.Date(2030, 5, 10, 0, 0, 0, 0, time.UTC)
+	seed := []domain.Booking{
+		{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: day, CheckOut: day.AddDate(0, 0, 2), Status: domain.StatusConfirmed},
+		{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: day, CheckOut: day.AddDate(0, 0, 2), Status: domain.StatusCancelled},
+		{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: day, CheckOut: day.AddDate(0, 0, 2), Status: domain.StatusNoShow},
+		{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: day.AddDate(0, 0, 2), CheckOut: day.AddDate(0, 0, 4), Status: domain.StatusPendingPayment},
+		{ID: uuid.New(), RoomTypeID: uuid.New(), CheckIn: day, CheckOut: day.AddDate(0, 0, 2), Status: domain.StatusConfirmed},
+	}
+	for _, b := range seed {
+		require.NoError(t, r.Create(ctx, b))
+	}
+
+	stay, err := valueobject.NewDateRange(day.AddDate(0, 0, 1), day.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	found, err := r.FindOverlapping(ctx, roomTypeID, stay)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, seed[0].ID, found[0].ID)
+}
+
 // repoTestBookingModel mirrors bookingModel table name for counting.
 type repoTestBookingModel struct {
 	ID uuid.UUID `gorm:"type:uuid;primaryKey"`
@@ -51,3 +83,433 @@ func newTestDB(t *testing.T) *gorm.DB {
 	require.NoError(t, err)
 	return db
 }
+
+func TestGormRepositoryFindExpiredHolds(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	expired := domain.Booking{ID: uuid.New(), Status: domain.StatusPendingPayment, CheckIn: now, CheckOut: now.Add(24 * time.Hour), ExpiresAt: now.Add(-time.Minute)}
+	active := domain.Booking{ID: uuid.New(), Status: domain.StatusPendingPayment, CheckIn: now, CheckOut: now.Add(24 * time.Hour), ExpiresAt: now.Add(time.Minute)}
+	paid := domain.Booking{ID: uuid.New(), Status: domain.StatusConfirmed, CheckIn: now, CheckOut: now.Add(24 * time.Hour), ExpiresAt: now.Add(-time.Minute)}
+	for _, b := range []domain.Booking{expired, active, paid} {
+		require.NoError(t, r.Create(ctx, b))
+	}
+
+	err := r.WithinTransaction(ctx, func(ctx context.Context) error {
+		found, err := r.FindExpiredHolds(ctx, now, 10)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		require.Equal(t, expired.ID, found[0].ID)
+		require.WithinDuration(t, expired.ExpiresAt, found[0].ExpiresAt, time.Second)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestGormRepositoryFindMissedCheckIns(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	day := time.Date(2001, 3, 10, 0, 0, 0, 0, time.UTC)
+	missed := domain.Booking{ID: uuid.New(), Status: domain.StatusConfirmed, CheckIn: day.AddDate(0, 0, -1), CheckOut: day.AddDate(0, 0, 1)}
+	arriving := domain.Booking{ID: uuid.New(), Status: domain.StatusConfirmed, CheckIn: day, CheckOut: day.AddDate(0, 0, 1)}
+	checkedIn := domain.Booking{ID: uuid.New(), Status: domain.StatusCheckedIn, CheckIn: day.AddDate(0, 0, -1), CheckOut: day.AddDate(0, 0, 1)}
+	for _, b := range []domain.Booking{missed, arriving, checkedIn} {
+		require.NoError(t, r.Create(ctx, b))
+	}
+
+	err := r.WithinTransaction(ctx, func(ctx context.Context) error {
+		found, err := r.FindMissedCheckIns(ctx, day, 10)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		require.Equal(t, missed.ID, found[0].ID)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestGormRepositoryGroupBookingItems(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	standard, suite := uuid.New(), uuid.New()
+	day := time.Date(2031, 7, 1, 0, 0, 0, 0, time.UTC)
+	bk := domain.Booking{ID: uuid.New(), Status: domain.StatusConfirmed, CheckIn: day, CheckOut: day.AddDate(0, 0, 2), TotalNights: 2, PromoCode: "SUMMER10", PaymentTiming: valueobject.PayNow}
+	bk.SetItems([]domain.LineItem{
+		{ID: uuid.New(), RoomTypeID: standard, RatePlanID: uuid.New(), CancellationPolicy: valueobject.PolicyNonRefundable, Guests: 2, Price: domain.PriceBreakdown{Base: valueobject.AmountOf(200), Promo: valueobject.AmountOf(20), Total: valueobject.AmountOf(198), Charges: []domain.Charge{
+			{Kind: "tax", Name: "PB1", Amount: valueobject.AmountOf(18)},
+			{Kind: "tax", Name: "VAT", Amount: valueobject.AmountOf(17.84), Inclusive: true},
+		}}, Status: domain.ItemStatusActive},
+		{ID: uuid.New(), RoomTypeID: suite, Guests: 3, Price: domain.PriceBreakdown{Base: valueobject.AmountOf(400), GuestSurcharge: valueobject.AmountOf(80), Total: valueobject.AmountOf(480), Nights: []domain.NightPrice{
+			{Date: day, Rate: valueobject.AmountOf(200), Surcharge: valueobject.AmountOf(40), Total: valueobject.AmountOf(240), Rules: []string{"extra guest"}},
+			{Date: day.AddDate(0, 0, 1), Rate: valueobject.AmountOf(200), Surcharge: valueobject.AmountOf(40), Total: valueobject.AmountOf(240), Rules: []string{"extra guest"}},
+		}}, Status: domain.ItemStatusActive},
+	})
+	require.NoError(t, r.Create(ctx, bk))
+
+	found, err := r.FindByID(ctx, bk.ID)
+	require.NoError(t, err)
+	require.Equal(t, bk.Items, found.Items)
+	require.Equal(t, "SUMMER10", found.PromoCode)
+	require.Equal(t, valueobject.PayNow, found.PaymentTiming)
+
+	stay, err := valueobject.NewDateRange(day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	overlapping, err := r.FindOverlapping(ctx, suite, stay)
+	require.NoError(t, err)
+	require.Len(t, overlapping, 1)
+	require.Equal(t, 1, overlapping[0].RoomsHeld(suite))
+
+	// A cancelled item stops counting against its room type.
+	found.Items[1].Status = domain.ItemStatusCancelled
+	found.SetItems(found.Items)
+	require.NoError(t, r.Save(ctx, &found))
+	overlapping, err = r.FindOverlapping(ctx, suite, stay)
+	require.NoError(t, err)
+	require.Empty(t, overlapping)
+}
+
+func TestGormRepositoryGuestDetailsAndNotes(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	guest, err := valueobject.NewGuestDetails("Jane Doe", "+6281234567890", "ID", []string{"John Doe"})
+	require.NoError(t, err)
+	day := time.Date(2031, 8, 1, 0, 0, 0, 0, time.UTC)
+	bk := domain.Booking{
+		ID: uuid.New(), Status: domain.StatusConfirmed, CheckIn: day, CheckOut: day.AddDate(0, 0, 1),
+		Guest: guest, ArrivalTime: "22:00", SpecialRequests: "high floor",
+	}
+	require.NoError(t, r.Create(ctx, bk))
+
+	found, err := r.FindByID(ctx, bk.ID)
+	require.NoError(t, err)
+	require.Equal(t, guest, found.Guest)
+	require.Equal(t, "22:00", found.ArrivalTime)
+	require.Equal(t, "high floor", found.SpecialRequests)
+
+	_, err = found.AddNote(uuid.New(), "VIP guest", time.Now())
+	require.NoError(t, err)
+	require.NoError(t, r.Save(ctx, &found))
+	_, err = found.AddNote(uuid.Nil, "airport pickup booked", time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.NoError(t, r.Save(ctx, &found))
+
+	found, err = r.FindByID(ctx, bk.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Notes, 2)
+	require.Equal(t, "VIP guest", found.Notes[0].Body)
+	require.Equal(t, uuid.Nil, found.Notes[1].AuthorID)
+}
+
+func TestGormRepositorySaveRejectsStaleVersion(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	day := time.Date(2031, 9, 1, 0, 0, 0, 0, time.UTC)
+	bk := domain.Booking{ID: uuid.New(), Status: domain.StatusPendingPayment, CheckIn: day, CheckOut: day.AddDate(0, 0, 1), Version: 1}
+	require.NoError(t, r.Create(ctx, bk))
+
+	// Two requests load the same version; the second save must not overwrite the first.
+	webhook, err := r.FindByID(ctx, bk.ID)
+	require.NoError(t, err)
+	admin, err := r.FindByID(ctx, bk.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, webhook.Confirm())
+	require.NoError(t, r.Save(ctx, &webhook))
+	require.Equal(t, 2, webhook.Version)
+
+	admin.Status = domain.StatusCancelled
+	require.ErrorIs(t, r.Save(ctx, &admin), domain.ErrVersionConflict)
+	require.Equal(t, 1, admin.Version)
+
+	found, err := r.FindByID(ctx, bk.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusConfirmed, found.Status)
+	require.Equal(t, 2, found.Version)
+
+	require.NoError(t, r.UpdateStatus(ctx, bk.ID, domain.StatusCompleted))
+	found, err = r.FindByID(ctx, bk.ID)
+	require.NoError(t, err)
+	require.Equal(t, 3, found.Version)
+}
+
+func TestGormRepositoryOutboxClaimsInAggregateOrder(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	first, second := uuid.New(), uuid.New()
+	require.NoError(t, r.AppendEvents(ctx, []pkgDomain.DomainEvent{
+		domain.NewBookingConfirmed(first),
+		domain.NewBookingCheckedIn(first),
+		domain.NewBookingConfirmed(second),
+	}))
+
+	claim := func() []domain.OutboxMessage {
+		msgs, err := r.ClaimOutbox(ctx, time.Now(), 10)
+		require.NoError(t, err)
+		var out []domain.OutboxMessage
+		for _, m := range msgs {
+			if m.AggregateID == first || m.AggregateID == second {
+				out = append(out, m)
+			}
+		}
+		return out
+	}
+
+	// Only the oldest pending event of each booking is claimable.
+	claimed := claim()
+	require.Len(t, claimed, 2)
+	require.Equal(t, first, claimed[0].AggregateID)
+	require.Equal(t, domain.EventTypeBookingConfirmed, claimed[0].EventType)
+	require.JSONEq(t, `{"BookingID":"`+first.String()+`"}`, string(claimed[0].Payload))
+
+	delivered := claimed[0]
+	delivered.MarkDelivered(time.Now())
+	require.NoError(t, r.SaveOutbox(ctx, delivered))
+	failed := claimed[1]
+	failed.MarkFailed("timeout", time.Now(), valueobject.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour})
+	require.NoError(t, r.SaveOutbox(ctx, failed))
+
+	claimed = claim()
+	require.Len(t, claimed, 1)
+	require.Equal(t, domain.EventTypeBookingCheckedIn, claimed[0].EventType)
+
+	found, err := r.FindOutbox(ctx, failed.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, found.Attempts)
+	require.Equal(t, "timeout", found.LastError)
+	require.True(t, found.NextAttemptAt.After(time.Now()))
+}
+
+func TestGormRepositoryHistoryRecordsActorAndRebuilds(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+
+	checkIn := time.Date(2093, 3, 1, 0, 0, 0, 0, time.UTC)
+	bk := domain.Booking{
+		ID: uuid.New(), UserID: uuid.New(), RoomTypeID: uuid.New(), CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2),
+		Status: domain.StatusPendingPayment, TotalPrice: valueobject.AmountOf(200), TotalNights: 2, Guests: 1, CreatedAt: time.Now(),
+	}
+	require.NoError(t, r.Create(context.Background(), bk))
+
+	userID := uuid.New()
+	asUser := context.WithValue(context.Background(), middleware.AuthContextKey, &middleware.Claims{UserID: userID.String(), Role: "customer"})
+	require.NoError(t, r.AppendHistory(asUser, []pkgDomain.DomainEvent{domain.NewBookingCreated(bk)}))
+	require.NoError(t, r.AppendHistory(context.Background(), []pkgDomain.DomainEvent{domain.NewBookingConfirmed(bk.ID)}))
+
+	history, err := r.History(context.Background(), bk.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, domain.Actor{ID: userID.String(), Role: "customer"}, history[0].Actor)
+	require.Equal(t, domain.Actor{ID: domain.SystemActor}, history[1].Actor)
+
+	require.NoError(t, db.Exec("DELETE FROM bookings WHERE id = ?", bk.ID).Error)
+	require.NoError(t, bookinguc.RebuildBooking(context.Background(), r, bk.ID))
+	rebuilt, err := r.FindByID(context.Background(), bk.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusConfirmed, rebuilt.Status)
+	require.Equal(t, valueobject.AmountOf(200), rebuilt.TotalPrice)
+}
+
+func TestGormRepositoryWaitlist(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	roomTypeID := uuid.New()
+	day := time.Date(2031, 10, 1, 0, 0, 0, 0, time.UTC)
+	first := domain.WaitlistEntry{ID: uuid.New(), UserID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: day, CheckOut: day.AddDate(0, 0, 2), Guests: 1, Status: domain.WaitlistWaiting, CreatedAt: day.Add(-2 * time.Hour)}
+	second := first
+	second.ID, second.UserID, second.CreatedAt = uuid.New(), uuid.New(), day.Add(-time.Hour)
+	require.NoError(t, r.CreateWaitlistEntry(ctx, second))
+	require.NoError(t, r.CreateWaitlistEntry(ctx, first))
+
+	waiting := query.Options{Filters: []query.Filter{
+		{Field: "room_type_id", Op: query.OpEq, Value: roomTypeID},
+		{Field: "status", Op: query.OpEq, Value: domain.WaitlistWaiting},
+	}}
+	page, err := r.ListWaitlist(ctx, waiting)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	require.Equal(t, first.ID, page.Items[0].ID, "oldest entry first")
+
+	hold := domain.Booking{ID: uuid.New(), ExpiresAt: day.Add(-30 * time.Minute)}
+	require.NoError(t, first.Offer(hold))
+	require.NoError(t, r.SaveWaitlistEntry(ctx, first))
+
+	found, err := r.FindWaitlistEntry(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.WaitlistOffered, found.Status)
+	require.Equal(t, hold.ID, found.BookingID)
+	require.True(t, hold.ExpiresAt.Equal(found.OfferExpiresAt))
+
+	page, err = r.ListWaitlist(ctx, waiting)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, second.ID, page.Items[0].ID)
+
+	_, err = r.FindWaitlistEntry(ctx, uuid.New())
+	require.Error(t, err)
+}
+
+func TestGormRepositoryPromotions(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	validUntil := time.Date(2031, 12, 31, 0, 0, 0, 0, time.UTC)
+	promo := domain.Promotion{
+		ID: uuid.New(), Code: "P" + uuid.NewString()[:8], Kind: domain.PromotionPercent, Amount: valueobject.AmountOf(15),
+		ValidUntil: validUntil, MaxUses: 5, RoomTypeIDs: []uuid.UUID{uuid.New()}, CreatedAt: time.Now(),
+	}
+	require.NoError(t, promo.Validate())
+	require.NoError(t, r.CreatePromotion(ctx, promo))
+
+	var locked domain.Promotion
+	err := r.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		locked, err = r.LockPromotionByCode(ctx, promo.Code)
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, promo.ID, locked.ID)
+	require.True(t, validUntil.Equal(locked.ValidUntil))
+	require.True(t, locked.ValidFrom.IsZero())
+	require.Equal(t, promo.RoomTypeIDs, locked.RoomTypeIDs)
+	require.Empty(t, locked.HotelIDs)
+
+	alice, bob := uuid.New(), uuid.New()
+	bookings := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for i, userID := range []uuid.UUID{alice, alice, bob} {
+		day := time.Date(2031, 11, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, r.Create(ctx, domain.Booking{ID: bookings[i], UserID: userID, Status: domain.StatusPendingPayment, CheckIn: day, CheckOut: day.AddDate(0, 0, 1)}))
+		require.NoError(t, r.CreateRedemption(ctx, domain.Redemption{ID: uuid.New(), PromotionID: promo.ID, BookingID: bookings[i], UserID: userID, Status: domain.RedemptionActive, CreatedAt: time.Now()}))
+	}
+	total, byAlice, err := r.CountRedemptions(ctx, promo.ID, alice)
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, 2, byAlice)
+
+	// Released redemptions no longer count, and releasing twice is harmless.
+	require.NoError(t, r.ReleaseRedemptions(ctx, bookings[0]))
+	require.NoError(t, r.ReleaseRedemptions(ctx, bookings[0]))
+	total, byAlice, err = r.CountRedemptions(ctx, promo.ID, alice)
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Equal(t, 1, byAlice)
+
+	promo.Kind, promo.Amount, promo.Currency = domain.PromotionFixed, valueobject.AmountOf(20), "USD"
+	require.NoError(t, r.UpdatePromotion(ctx, promo))
+	found, err := r.GetPromotion(ctx, promo.ID)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(20), found.Amount)
+	require.Equal(t, "USD", found.Currency)
+
+	require.NoError(t, r.DeletePromotion(ctx, promo.ID))
+	_, err = r.FindPromotionByCode(ctx, promo.Code)
+	require.Error(t, err)
+	require.Error(t, r.DeletePromotion(ctx, promo.ID))
+}
+
+func TestGormRepositoryExchangeRates(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	asOf := time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, r.SaveExchangeRates(ctx, []valueobject.ExchangeRate{
+		{Base: "USD", Quote: "IDR", Rate: 16000, AsOf: asOf, Source: "admin"},
+		{Base: "USD", Quote: "SGD", Rate: 1.35, AsOf: asOf, Source: "admin"},
+	}))
+	// Saving a pair again replaces its rate.
+	require.NoError(t, r.SaveExchangeRates(ctx, []valueobject.ExchangeRate{
+		{Base: "USD", Quote: "IDR", Rate: 16250, AsOf: asOf.AddDate(0, 0, 1), Source: "provider"},
+	}))
+	rates, err := r.ListExchangeRates(ctx)
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	rate, ok := rates.Find("USD", "IDR")
+	require.True(t, ok)
+	require.Equal(t, 16250.0, rate.Rate)
+	require.Equal(t, "provider", rate.Source)
+
+	// Bookings keep the rate snapshot they were made at.
+	bk := domain.Booking{ID: uuid.New(), Status: domain.StatusPendingPayment, Currency: "IDR", ChargeCurrency: "USD", TotalPrice: valueobject.AmountOf(1226500),
+		ExchangeRate: rate.Inverse()}
+	require.NoError(t, r.Create(ctx, bk))
+	found, err := r.FindByID(ctx, bk.ID)
+	require.NoError(t, err)
+	require.Equal(t, "IDR", found.Currency)
+	require.Equal(t, "USD", found.ChargeCurrency)
+	require.Equal(t, "USD", found.ExchangeRate.Quote)
+	require.InDelta(t, 1.0/16250, found.ExchangeRate.Rate, 1e-12)
+	require.True(t, found.ExchangeRate.AsOf.Equal(rate.AsOf))
+	require.Equal(t, found.Charge(found.TotalPrice), bk.Charge(bk.TotalPrice))
+}
+
+func TestGormRepositoryInventoryHolds(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	roomTypeID, userID := uuid.New(), uuid.New()
+	now := time.Date(2031, 11, 1, 12, 0, 0, 0, time.UTC)
+	day := time.Date(2031, 11, 10, 0, 0, 0, 0, time.UTC)
+	held := domain.InventoryHold{ID: uuid.New(), UserID: userID, SessionID: "checkout-1", RoomTypeID: roomTypeID, CheckIn: day, CheckOut: day.AddDate(0, 0, 2), Rooms: 2, Status: domain.HoldActive, ExpiresAt: now.Add(10 * time.Minute), CreatedAt: now}
+	lapsed := held
+	lapsed.ID, lapsed.ExpiresAt = uuid.New(), now.Add(-time.Minute)
+	elsewhere := held
+	elsewhere.ID, elsewhere.CheckIn, elsewhere.CheckOut = uuid.New(), day.AddDate(0, 0, 2), day.AddDate(0, 0, 3)
+	for _, h := range []domain.InventoryHold{held, lapsed, elsewhere} {
+		require.NoError(t, r.CreateInventoryHold(ctx, h))
+	}
+
+	// Only unexpired holds overlapping the stay keep rooms aside.
+	active, err := r.FindActiveHolds(ctx, roomTypeID, held.Stay(), now)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	require.Equal(t, held.ID, active[0].ID)
+	require.Equal(t, 2, active[0].Rooms)
+	mine, err := r.FindUserHolds(ctx, userID, now)
+	require.NoError(t, err)
+	require.Len(t, mine, 2)
+
+	bookingID := uuid.New()
+	require.NoError(t, held.Consume(bookingID, now))
+	require.NoError(t, r.SaveInventoryHold(ctx, held))
+	found, err := r.FindInventoryHold(ctx, held.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.HoldConsumed, found.Status)
+	require.Equal(t, bookingID, found.BookingID)
+	require.Equal(t, "checkout-1", found.SessionID)
+
+	expired, err := r.ExpireInventoryHolds(ctx, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, expired)
+	found, err = r.FindInventoryHold(ctx, lapsed.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.HoldExpired, found.Status)
+
+	_, err = r.FindInventoryHold(ctx, uuid.New())
+	require.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	pkgDomain "github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+)
+
+// AppendHistory records events in the append-only event store through the caller's connection.
+// The actor comes from the JWT claims on ctx; background jobs without claims are recorded as the system.
+func (r *GormRepository) AppendHistory(ctx context.Context, events []pkgDomain.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	actor := actorFrom(ctx)
+	models := make([]historyModel, 0, len(events))
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		models = append(models, historyModel{
+			ID:         uuid.New(),
+			BookingID:  event.AggregateID(),
+			EventType:  event.EventType(),
+			Payload:    string(payload),
+			ActorID:    actor.ID,
+			ActorRole:  actor.Role,
+			OccurredAt: event.OccurredAt(),
+		})
+	}
+	return r.conn(ctx).Create(&models).Error
+}
+
+func (r *GormRepository) History(ctx context.Context, bookingID uuid.UUID) ([]domain.HistoryEntry, error) {
+	var models []historyModel
+	if err := r.conn(ctx).Where("booking_id = ?", bookingID).Order("seq").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	entries := make([]domain.HistoryEntry, 0, len(models))
+	for _, m := range models {
+		entries = append(entries, m.toDomain())
+	}
+	return entries, nil
+}
+
+func (r *GormRepository) HistoryBookingIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.conn(ctx).Model(&historyModel{}).
+		Where("event_type = ?", domain.EventTypeBookingCreated).
+		Order("seq").
+		Pluck("booking_id", &ids).Error
+	return ids, err
+}
+
+// actorFrom identifies the caller from the JWT claims on ctx. Service tokens carry no user id,
+// so the calling service named in the token subject is recorded instead.
+func actorFrom(ctx context.Context) domain.Actor {
+	claims, ok := ctx.Value(middleware.AuthContextKey).(*middleware.Claims)
+	if !ok {
+		return domain.Actor{ID: domain.SystemActor}
+	}
+	id := claims.UserID
+	if id == "" {
+		id = claims.Subject
+	}
+	return domain.Actor{ID: id, Role: claims.Role}
+}
+
+type historyModel struct {
+	Seq        int64     `gorm:"primaryKey;autoIncrement"`
+	ID         uuid.UUID `gorm:"type:uuid;uniqueIndex"`
+	BookingID  uuid.UUID `gorm:"type:uuid;index"`
+	EventType  string
+	Payload    string `gorm:"type:text"`
+	ActorID    string
+	ActorRole  string
+	OccurredAt time.Time
+}
+
+func (historyModel) TableName() string { return "booking_events" }
+
+func (m historyModel) toDomain() domain.HistoryEntry {
+	return domain.HistoryEntry{
+		ID:         m.ID,
+		BookingID:  m.BookingID,
+		EventType:  m.EventType,
+		Payload:    []byte(m.Payload),
+		Actor:      domain.Actor{ID: m.ActorID, Role: m.ActorRole},
+		OccurredAt: m.OccurredAt,
+	}
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+func (r *GormRepository) CreateInventoryHold(ctx context.Context, h domain.InventoryHold) error {
+	model := toInventoryHoldModel(h)
+	return r.conn(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) SaveInventoryHold(ctx context.Context, h domain.InventoryHold) error {
+	model := toInventoryHoldModel(h)
+	res := r.conn(ctx).Model(&inventoryHoldModel{}).Where("id = ?", h.ID).Updates(map[string]any{
+		"status":     model.Status,
+		"booking_id": model.BookingID,
+	})
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "hold not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) FindInventoryHold(ctx context.Context, id uuid.UUID) (domain.InventoryHold, error) {
+	var model inventoryHoldModel
+	if err := r.conn(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.InventoryHold{}, pkgErrors.New("not_found", "hold not found")
+		}
+		return domain.InventoryHold{}, err
+	}
+	return model.toDomain(), nil
+}
+
+func (r *GormRepository) FindActiveHolds(ctx context.Context, roomTypeID uuid.UUID, stay valueobject.DateRange, now time.Time) ([]domain.InventoryHold, error) {
+	return r.findInventoryHolds(r.conn(ctx).
+		Where("room_type_id = ? AND status = ? AND expires_at > ?", roomTypeID, domain.HoldActive, now).
+		Where("check_in < ? AND check_out > ?", stay.End, stay.Start))
+}
+
+func (r *GormRepository) FindUserHolds(ctx context.Context, userID uuid.UUID, now time.Time) ([]domain.InventoryHold, error) {
+	return r.findInventoryHolds(r.conn(ctx).
+		Where("user_id = ? AND status = ? AND expires_at > ?", userID, domain.HoldActive, now).
+		Order("created_at"))
+}
+
+func (r *GormRepository) ExpireInventoryHolds(ctx context.Context, now time.Time) (int, error) {
+	res := r.conn(ctx).Model(&inventoryHoldModel{}).
+		Where("status = ? AND expires_at <= ?", domain.HoldActive, now).
+		Update("status", domain.HoldExpired)
+	return int(res.RowsAffected), res.Error
+}
+
+func (r *GormRepository) findInventoryHolds(db *gorm.DB) ([]domain.InventoryHold, error) {
+	var models []inventoryHoldModel
+	if err := db.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	holds := make([]domain.InventoryHold, 0, len(models))
+	for _, m := range models {
+		holds = append(holds, m.toDomain())
+	}
+	return holds, nil
+}
+
+type inventoryHoldModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;index"`
+	SessionID  string
+	RoomTypeID uuid.UUID `gorm:"type:uuid;index"`
+	CheckIn    time.Time
+	CheckOut   time.Time
+	Rooms      int
+	Status     string     `gorm:"not null;default:active;index"`
+	BookingID  *uuid.UUID `gorm:"type:uuid"`
+	ExpiresAt  time.Time  `gorm:"index"`
+	CreatedAt  time.Time
+}
+
+func (inventoryHoldModel) TableName() string { return "inventory_holds" }
+
+func toInventoryHoldModel(h domain.InventoryHold) inventoryHoldModel {
+	model := inventoryHoldModel{
+		ID:         h.ID,
+		UserID:     h.UserID,
+		SessionID:  h.SessionID,
+		RoomTypeID: h.RoomTypeID,
+		CheckIn:    h.CheckIn,
+		CheckOut:   h.CheckOut,
+		Rooms:      h.Rooms,
+		Status:     h.Status,
+		ExpiresAt:  h.ExpiresAt,
+		CreatedAt:  h.CreatedAt,
+	}
+	if h.BookingID != uuid.Nil {
+		model.BookingID = &h.BookingID
+	}
+	return model
+}
+
+func (m inventoryHoldModel) toDomain() domain.InventoryHold {
+	h := domain.InventoryHold{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		SessionID:  m.SessionID,
+		RoomTypeID: m.RoomTypeID,
+		CheckIn:    m.CheckIn,
+		CheckOut:   m.CheckOut,
+		Rooms:      m.Rooms,
+		Status:     m.Status,
+		ExpiresAt:  m.ExpiresAt,
+		CreatedAt:  m.CreatedAt,
+	}
+	if m.BookingID != nil {
+		h.BookingID = *m.BookingID
+	}
+	return h
+}
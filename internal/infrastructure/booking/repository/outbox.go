@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	pkgDomain "github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+)
+
+// AppendEvents writes events to the outbox through the caller's connection, so they commit or roll back with the aggregate.
+func (r *GormRepository) AppendEvents(ctx context.Context, events []pkgDomain.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	models := make([]outboxModel, 0, len(events))
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		models = append(models, outboxModel{
+			ID:            uuid.New(),
+			AggregateID:   event.AggregateID(),
+			EventType:     event.EventType(),
+			Payload:       string(payload),
+			Status:        domain.OutboxPending,
+			NextAttemptAt: event.OccurredAt(),
+			CreatedAt:     event.OccurredAt(),
+		})
+	}
+	return r.conn(ctx).Create(&models).Error
+}
+
+// ClaimOutbox claims due messages with SKIP LOCKED. A message waits while an older message of the
+// same aggregate is still pending, which keeps per-aggregate order across retries and replicas.
+func (r *GormRepository) ClaimOutbox(ctx context.Context, now time.Time, limit int) ([]domain.OutboxMessage, error) {
+	var models []outboxModel
+	err := r.conn(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", domain.OutboxPending, now).
+		Where("NOT EXISTS (?)", r.conn(ctx).Table("booking_outbox AS earlier").
+			Select("1").
+			Where("earlier.aggregate_id = booking_outbox.aggregate_id AND earlier.seq < booking_outbox.seq AND earlier.status = ?", domain.OutboxPending)).
+		Order("seq").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toOutboxMessages(models), nil
+}
+
+func (r *GormRepository) SaveOutbox(ctx context.Context, m domain.OutboxMessage) error {
+	updates := map[string]any{
+		"status":          m.Status,
+		"attempts":        m.Attempts,
+		"next_attempt_at": m.NextAttemptAt,
+		"last_error":      m.LastError,
+		"delivered_at":    nil,
+	}
+	if !m.DeliveredAt.IsZero() {
+		updates["delivered_at"] = m.DeliveredAt
+	}
+	res := r.conn(ctx).Model(&outboxModel{}).Where("id = ?", m.ID).Updates(updates)
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "outbox message not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) ListOutbox(ctx context.Context, status string, opts query.Options) ([]domain.OutboxMessage, error) {
+	var models []outboxModel
+	qo := opts.Normalize(50)
+	tx := r.conn(ctx).Order("seq")
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+	if qo.Limit > 0 {
+		tx = tx.Limit(qo.Limit).Offset(qo.Offset)
+	}
+	if err := tx.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return toOutboxMessages(models), nil
+}
+
+func (r *GormRepository) FindOutbox(ctx context.Context, id uuid.UUID) (domain.OutboxMessage, error) {
+	var model outboxModel
+	if err := r.conn(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.OutboxMessage{}, pkgErrors.New("not_found", "outbox message not found")
+		}
+		return domain.OutboxMessage{}, err
+	}
+	return model.toDomain(), nil
+}
+
+type outboxModel struct {
+	Seq           int64     `gorm:"primaryKey;autoIncrement"`
+	ID            uuid.UUID `gorm:"type:uuid;uniqueIndex"`
+	AggregateID   uuid.UUID `gorm:"type:uuid;index"`
+	EventType     string
+	Payload       string `gorm:"type:text"`
+	Status        string `gorm:"not null;default:pending"`
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string `gorm:"type:text"`
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time
+}
+
+func (outboxModel) TableName() string { return "booking_outbox" }
+
+func (m outboxModel) toDomain() domain.OutboxMessage {
+	msg := domain.OutboxMessage{
+		ID:            m.ID,
+		AggregateID:   m.AggregateID,
+		EventType:     m.EventType,
+		Payload:       []byte(m.Payload),
+		Status:        m.Status,
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     m.LastError,
+		CreatedAt:     m.CreatedAt,
+	}
+	if m.DeliveredAt != nil {
+		msg.DeliveredAt = *m.DeliveredAt
+	}
+	return msg
+}
+
+func toOutboxMessages(models []outboxModel) []domain.OutboxMessage {
+	out := make([]domain.OutboxMessage, 0, len(models))
+	for _, m := range models {
+		out = append(out, m.toDomain())
+	}
+	return out
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+func (r *GormRepository) CreatePromotion(ctx context.Context, p domain.Promotion) error {
+	model := toPromotionModel(p)
+	return r.conn(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) UpdatePromotion(ctx context.Context, p domain.Promotion) error {
+	model := toPromotionModel(p)
+	res := r.conn(ctx).Model(&model).Select("*").Omit("created_at").Updates(&model)
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "promotion not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) DeletePromotion(ctx context.Context, id uuid.UUID) error {
+	res := r.conn(ctx).Delete(&promotionModel{}, "id = ?", id)
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "promotion not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) GetPromotion(ctx context.Context, id uuid.UUID) (domain.Promotion, error) {
+	return r.findPromotion(r.conn(ctx), "id = ?", id)
+}
+
+func (r *GormRepository) FindPromotionByCode(ctx context.Context, code string) (domain.Promotion, error) {
+	return r.findPromotion(r.conn(ctx), "code = ?", domain.NormalizePromoCode(code))
+}
+
+func (r *GormRepository) LockPromotionByCode(ctx context.Context, code string) (domain.Promotion, error) {
+	db := r.conn(ctx).Clauses(clause.Locking{Strength: "UPDATE"})
+	return r.findPromotion(db, "code = ?", domain.NormalizePromoCode(code))
+}
+
+func (r *GormRepository) findPromotion(db *gorm.DB, cond string, arg any) (domain.Promotion, error) {
+	var model promotionModel
+	if err := db.First(&model, cond, arg).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Promotion{}, pkgErrors.New("not_found", "promotion not found")
+		}
+		return domain.Promotion{}, err
+	}
+	return model.toDomain(), nil
+}
+
+func (r *GormRepository) ListPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	var models []promotionModel
+	if err := r.conn(ctx).Order("created_at DESC").Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	promotions := make([]domain.Promotion, 0, len(models))
+	for _, m := range models {
+		promotions = append(promotions, m.toDomain())
+	}
+	return promotions, nil
+}
+
+func (r *GormRepository) CountRedemptions(ctx context.Context, promotionID, userID uuid.UUID) (int, int, error) {
+	var counts struct {
+		Total  int
+		ByUser int
+	}
+	err := r.conn(ctx).Model(&redemptionModel{}).
+		Select("COUNT(*) AS total, COALESCE(SUM(CASE WHEN user_id = ? THEN 1 ELSE 0 END), 0) AS by_user", userID).
+		Where("promotion_id = ? AND status = ?", promotionID, domain.RedemptionActive).
+		Scan(&counts).Error
+	return counts.Total, counts.ByUser, err
+}
+
+func (r *GormRepository) CreateRedemption(ctx context.Context, red domain.Redemption) error {
+	model := redemptionModel(red)
+	return r.conn(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) ReleaseRedemptions(ctx context.Context, bookingID uuid.UUID) error {
+	return r.conn(ctx).Model(&redemptionModel{}).
+		Where("booking_id = ? AND status = ?", bookingID, domain.RedemptionActive).
+		Update("status", domain.RedemptionReleased).Error
+}
+
+type promotionModel struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Code           string    `gorm:"uniqueIndex;not null"`
+	Kind           string
+	Amount         valueobject.Amount `gorm:"type:numeric"`
+	Currency       string             `gorm:"not null;default:''"`
+	ValidFrom      *time.Time
+	ValidUntil     *time.Time
+	MinNights      int
+	MaxUses        int
+	MaxUsesPerUser int
+	HotelIDs       []uuid.UUID `gorm:"type:text;serializer:json"`
+	RoomTypeIDs    []uuid.UUID `gorm:"type:text;serializer:json"`
+	CreatedAt      time.Time
+}
+
+func (promotionModel) TableName() string { return "promotions" }
+
+func toPromotionModel(p domain.Promotion) promotionModel {
+	m := promotionModel{
+		ID:             p.ID,
+		Code:           p.Code,
+		Kind:           p.Kind,
+		Amount:         p.Amount,
+		Currency:       p.Currency,
+		MinNights:      p.MinNights,
+		MaxUses:        p.MaxUses,
+		MaxUsesPerUser: p.MaxUsesPerUser,
+		HotelIDs:       p.HotelIDs,
+		RoomTypeIDs:    p.RoomTypeIDs,
+		CreatedAt:      p.CreatedAt,
+	}
+	if !p.ValidFrom.IsZero() {
+		m.ValidFrom = &p.ValidFrom
+	}
+	if !p.ValidUntil.IsZero() {
+		m.ValidUntil = &p.ValidUntil
+	}
+	return m
+}
+
+func (m promotionModel) toDomain() domain.Promotion {
+	p := domain.Promotion{
+		ID:             m.ID,
+		Code:           m.Code,
+		Kind:           m.Kind,
+		Amount:         m.Amount,
+		Currency:       m.Currency,
+		MinNights:      m.MinNights,
+		MaxUses:        m.MaxUses,
+		MaxUsesPerUser: m.MaxUsesPerUser,
+		HotelIDs:       m.HotelIDs,
+		RoomTypeIDs:    m.RoomTypeIDs,
+		CreatedAt:      m.CreatedAt,
+	}
+	if m.ValidFrom != nil {
+		p.ValidFrom = *m.ValidFrom
+	}
+	if m.ValidUntil != nil {
+		p.ValidUntil = *m.ValidUntil
+	}
+	return p
+}
+
+type redemptionModel struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	PromotionID uuid.UUID `gorm:"type:uuid;index"`
+	BookingID   uuid.UUID `gorm:"type:uuid;index"`
+	UserID      uuid.UUID `gorm:"type:uuid"`
+	Status      string    `gorm:"not null;default:active"`
+	CreatedAt   time.Time
+}
+
+func (redemptionModel) TableName() string { return "promotion_redemptions" }
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/database"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+)
+
+// waitlistListing maps domain.WaitlistSchema onto the waitlist table.
+var waitlistListing = database.Listing{
+	Columns: map[string]string{
+		"status":       "status",
+		"user_id":      "user_id",
+		"room_type_id": "room_type_id",
+		"check_in":     "check_in",
+		"created_at":   "created_at",
+	},
+	DefaultSort: domain.WaitlistSchema.DefaultSort,
+	DefaultSize: 50,
+}
+
+func (r *GormRepository) CreateWaitlistEntry(ctx context.Context, e domain.WaitlistEntry) error {
+	model := toWaitlistModel(e)
+	return r.conn(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) SaveWaitlistEntry(ctx context.Context, e domain.WaitlistEntry) error {
+	model := toWaitlistModel(e)
+	res := r.conn(ctx).Model(&waitlistModel{}).Where("id = ?", e.ID).Updates(map[string]any{
+		"status":           model.Status,
+		"booking_id":       model.BookingID,
+		"offer_expires_at": model.OfferExpiresAt,
+	})
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "waitlist entry not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) FindWaitlistEntry(ctx context.Context, id uuid.UUID) (domain.WaitlistEntry, error) {
+	var model waitlistModel
+	if err := r.conn(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.WaitlistEntry{}, pkgErrors.New("not_found", "waitlist entry not found")
+		}
+		return domain.WaitlistEntry{}, err
+	}
+	return model.toDomain(), nil
+}
+
+func (r *GormRepository) ListWaitlist(ctx context.Context, opts query.Options) (query.Page[domain.WaitlistEntry], error) {
+	page, err := database.Paginate[waitlistModel](r.conn(ctx), waitlistListing, opts)
+	if err != nil {
+		return query.Page[domain.WaitlistEntry]{}, err
+	}
+	entries := make([]domain.WaitlistEntry, 0, len(page.Items))
+	for _, m := range page.Items {
+		entries = append(entries, m.toDomain())
+	}
+	return query.Page[domain.WaitlistEntry]{Items: entries, NextCursor: page.NextCursor, Total: page.Total}, nil
+}
+
+type waitlistModel struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID         uuid.UUID `gorm:"type:uuid;index"`
+	RoomTypeID     uuid.UUID `gorm:"type:uuid;index"`
+	CheckIn        time.Time
+	CheckOut       time.Time
+	Guests         int
+	Status         string     `gorm:"not null;default:waiting"`
+	BookingID      *uuid.UUID `gorm:"type:uuid"`
+	OfferExpiresAt *time.Time
+	CreatedAt      time.Time
+}
+
+func (waitlistModel) TableName() string { return "booking_waitlist" }
+
+func toWaitlistModel(e domain.WaitlistEntry) waitlistModel {
+	model := waitlistModel{
+		ID:         e.ID,
+		UserID:     e.UserID,
+		RoomTypeID: e.RoomTypeID,
+		CheckIn:    e.CheckIn,
+		CheckOut:   e.CheckOut,
+		Guests:     e.Guests,
+		Status:     e.Status,
+		CreatedAt:  e.CreatedAt,
+	}
+	if e.BookingID != uuid.Nil {
+		model.BookingID = &e.BookingID
+	}
+	if !e.OfferExpiresAt.IsZero() {
+		model.OfferExpiresAt = &e.OfferExpiresAt
+	}
+	return model
+}
+
+func (m waitlistModel) toDomain() domain.WaitlistEntry {
+	e := domain.WaitlistEntry{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		RoomTypeID: m.RoomTypeID,
+		CheckIn:    m.CheckIn,
+		CheckOut:   m.CheckOut,
+		Guests:     m.Guests,
+		Status:     m.Status,
+		CreatedAt:  m.CreatedAt,
+	}
+	if m.BookingID != nil {
+		e.BookingID = *m.BookingID
+	}
+	if m.OfferExpiresAt != nil {
+		e.OfferExpiresAt = *m.OfferExpiresAt
+	}
+	return e
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	bookinguc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking"
+)
+
+// ExchangeRateScheduler refreshes the stored exchange rates from the configured provider.
+// Refreshing is an upsert of the same rates, so every replica may run it.
+type ExchangeRateScheduler struct {
+	cron     *cron.Cron
+	service  *bookinguc.Service
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// NewExchangeRateScheduler creates a new scheduler instance refreshing rates every interval.
+func NewExchangeRateScheduler(service *bookinguc.Service, interval time.Duration, logger *zap.Logger) *ExchangeRateScheduler {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &ExchangeRateScheduler{
+		cron:     cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger))),
+		service:  service,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Start refreshes the rates once and then schedules a refresh every interval.
+func (s *ExchangeRateScheduler) Start() error {
+	_, err := s.cron.AddFunc("@every "+s.interval.String(), func() {
+		if err := s.runRefresh(); err != nil {
+			s.logger.Error("❌ Exchange rate refresh failed", zap.Error(err))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.runRefresh(); err != nil {
+			s.logger.Error("❌ Exchange rate refresh failed", zap.Error(err))
+		}
+	}()
+	s.cron.Start()
+	s.logger.Info("✅ Exchange rate scheduler started", zap.Duration("interval", s.interval))
+	return nil
+}
+
+// Stop gracefully stops the cron scheduler.
+func (s *ExchangeRateScheduler) Stop() {
+	if s.cron != nil {
+		ctx := s.cron.Stop()
+		<-ctx.Done()
+		s.logger.Info("🛑 Exchange rate scheduler stopped")
+	}
+}
+
+// runRefresh executes the exchange rate refresh.
+func (s *ExchangeRateScheduler) runRefresh() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	count, err := s.service.RefreshExchangeRates(ctx)
+	if count > 0 {
+		s.logger.Info("✅ Refreshed exchange rates", zap.Int("rates", count))
+	}
+	return err
+}
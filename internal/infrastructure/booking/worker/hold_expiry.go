@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	bookinguc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+)
+
+// HoldExpiryScheduler cancels unpaid bookings whose payment hold has expired.
+// Several replicas may run it at once; the usecase claims bookings with SKIP LOCKED.
+type HoldExpiryScheduler struct {
+	cron      *cron.Cron
+	service   *bookinguc.Service
+	jwtSecret string
+	logger    *zap.Logger
+}
+
+// NewHoldExpiryScheduler creates a new scheduler instance.
+// jwtSecret signs the service token used to expire payments in the payment service.
+func NewHoldExpiryScheduler(service *bookinguc.Service, jwtSecret string, logger *zap.Logger) *HoldExpiryScheduler {
+	return &HoldExpiryScheduler{
+		cron:      cron.New(),
+		service:   service,
+		jwtSecret: jwtSecret,
+		logger:    logger,
+	}
+}
+
+// Start initializes and starts the cron scheduler.
+// Schedule: every minute, so holds are released shortly after the invoice expires.
+func (s *HoldExpiryScheduler) Start() error {
+	_, err := s.cron.AddFunc("@every 1m", func() {
+		if err := s.runHoldExpiry(); err != nil {
+			s.logger.Error("❌ Hold expiry failed", zap.Error(err))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	s.logger.Info("✅ Hold expiry scheduler started (runs every minute)")
+	return nil
+}
+
+// Stop gracefully stops the cron scheduler.
+func (s *HoldExpiryScheduler) Stop() {
+	if s.cron != nil {
+		ctx := s.cron.Stop()
+		<-ctx.Done()
+		s.logger.Info("🛑 Hold expiry scheduler stopped")
+	}
+}
+
+// runHoldExpiry executes the hold expiry logic.
+func (s *HoldExpiryScheduler) runHoldExpiry() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	ctx, err := middleware.WithServiceToken(ctx, s.jwtSecret, "booking-service")
+	if err != nil {
+		return err
+	}
+
+	count, err := s.service.ExpireHolds(ctx)
+	if count > 0 {
+		s.logger.Info("✅ Expired unpaid bookings", zap.Int("expired_bookings", count))
+	}
+	return err
+}
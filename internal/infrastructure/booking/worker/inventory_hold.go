@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	bookinguc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking"
+)
+
+// InventoryHoldScheduler marks inventory holds that ran out as expired. Expired holds stop keeping
+// rooms aside on their own, so the sweep only tidies their status; every replica may run it.
+type InventoryHoldScheduler struct {
+	cron    *cron.Cron
+	service *bookinguc.Service
+	logger  *zap.Logger
+}
+
+// NewInventoryHoldScheduler creates a new scheduler instance.
+func NewInventoryHoldScheduler(service *bookinguc.Service, logger *zap.Logger) *InventoryHoldScheduler {
+	return &InventoryHoldScheduler{
+		cron:    cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger))),
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Start initializes and starts the cron scheduler.
+// Schedule: every minute.
+func (s *InventoryHoldScheduler) Start() error {
+	_, err := s.cron.AddFunc("@every 1m", func() {
+		if err := s.runExpiry(); err != nil {
+			s.logger.Error("❌ Inventory hold expiry failed", zap.Error(err))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	s.logger.Info("✅ Inventory hold scheduler started (runs every minute)")
+	return nil
+}
+
+// Stop gracefully stops the cron scheduler.
+func (s *InventoryHoldScheduler) Stop() {
+	if s.cron != nil {
+		ctx := s.cron.Stop()
+		<-ctx.Done()
+		s.logger.Info("🛑 Inventory hold scheduler stopped")
+	}
+}
+
+// runExpiry expires the inventory holds that ran out.
+func (s *InventoryHoldScheduler) runExpiry() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	count, err := s.service.ExpireInventoryHolds(ctx)
+	if count > 0 {
+		s.logger.Info("✅ Expired inventory holds", zap.Int("expired_holds", count))
+	}
+	return err
+}
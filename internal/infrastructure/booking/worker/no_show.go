@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	bookinguc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+)
+
+// NoShowScheduler marks confirmed bookings as no_show once their check-in day has passed.
+// Like HoldExpiryScheduler it is safe to run on several replicas.
+type NoShowScheduler struct {
+	cron      *cron.Cron
+	service   *bookinguc.Service
+	jwtSecret string
+	logger    *zap.Logger
+}
+
+// NewNoShowScheduler creates a new scheduler instance.
+// jwtSecret signs the service token used to refund payments in the payment service.
+func NewNoShowScheduler(service *bookinguc.Service, jwtSecret string, logger *zap.Logger) *NoShowScheduler {
+	return &NoShowScheduler{
+		cron:      cron.New(),
+		service:   service,
+		jwtSecret: jwtSecret,
+		logger:    logger,
+	}
+}
+
+// Start initializes and starts the cron scheduler.
+// Schedule: daily at 01:00, after the previous check-in day has ended.
+func (s *NoShowScheduler) Start() error {
+	_, err := s.cron.AddFunc("0 1 * * *", func() {
+		if err := s.runNoShow(); err != nil {
+			s.logger.Error("❌ No-show detection failed", zap.Error(err))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	s.logger.Info("✅ No-show scheduler started (runs daily at 01:00)")
+	return nil
+}
+
+// Stop gracefully stops the cron scheduler.
+func (s *NoShowScheduler) Stop() {
+	if s.cron != nil {
+		ctx := s.cron.Stop()
+		<-ctx.Done()
+		s.logger.Info("🛑 No-show scheduler stopped")
+	}
+}
+
+// runNoShow executes the no-show detection logic.
+func (s *NoShowScheduler) runNoShow() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	ctx, err := middleware.WithServiceToken(ctx, s.jwtSecret, "booking-service")
+	if err != nil {
+		return err
+	}
+
+	count, err := s.service.MarkNoShows(ctx)
+	if count > 0 {
+		s.logger.Info("✅ Marked no-show bookings", zap.Int("no_show_bookings", count))
+	}
+	return err
+}
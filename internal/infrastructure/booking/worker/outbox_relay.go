@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	bookinguc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking"
+)
+
+// OutboxRelayScheduler delivers booking events from the outbox to the notification service.
+// Messages are claimed with SKIP LOCKED, so every replica may run it.
+type OutboxRelayScheduler struct {
+	cron    *cron.Cron
+	service *bookinguc.Service
+	logger  *zap.Logger
+}
+
+// NewOutboxRelayScheduler creates a new scheduler instance.
+func NewOutboxRelayScheduler(service *bookinguc.Service, logger *zap.Logger) *OutboxRelayScheduler {
+	return &OutboxRelayScheduler{
+		cron:    cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger))),
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Start initializes and starts the cron scheduler.
+// Schedule: every 5 seconds; a run still in progress makes the next tick skip.
+func (s *OutboxRelayScheduler) Start() error {
+	_, err := s.cron.AddFunc("@every 5s", func() {
+		if err := s.runRelay(); err != nil {
+			s.logger.Error("❌ Outbox relay failed", zap.Error(err))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	s.logger.Info("✅ Outbox relay scheduler started (runs every 5s)")
+	return nil
+}
+
+// Stop gracefully stops the cron scheduler.
+func (s *OutboxRelayScheduler) Stop() {
+	if s.cron != nil {
+		ctx := s.cron.Stop()
+		<-ctx.Done()
+		s.logger.Info("🛑 Outbox relay scheduler stopped")
+	}
+}
+
+// runRelay delivers due outbox messages.
+func (s *OutboxRelayScheduler) runRelay() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	count, err := s.service.RelayOutbox(ctx)
+	if count > 0 {
+		s.logger.Info("✅ Relayed outbox events", zap.Int("delivered_events", count))
+	}
+	return err
+}
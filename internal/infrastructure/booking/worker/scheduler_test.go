@@ -13,13 +13,15 @@ import (
 	bookinguc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking"
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
 	hdomain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	pkgDomain "github.com/ftryyln/hotel-booking-microservices/pkg/domain"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 func TestAutoCheckoutSchedulerCreation(t *testing.T) {
 	logger := zap.NewNop()
 	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
-	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: uuid.New(), BasePrice: 500000}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: uuid.New(), BasePrice: valueobject.AmountOf(500000)}}
 	payment := &paymentGatewayStub{}
 	notifier := &notificationGatewayStub{}
 	service := bookinguc.NewService(repo, hotelRepo, payment, notifier)
@@ -31,7 +33,7 @@ func TestAutoCheckoutSchedulerCreation(t *testing.T) {
 func TestAutoCheckoutSchedulerStartStop(t *testing.T) {
 	logger := zap.NewNop()
 	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
-	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: uuid.New(), BasePrice: 500000}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: uuid.New(), BasePrice: valueobject.AmountOf(500000)}}
 	payment := &paymentGatewayStub{}
 	notifier := &notificationGatewayStub{}
 	service := bookinguc.NewService(repo, hotelRepo, payment, notifier)
@@ -70,14 +72,14 @@ func (b *bookingRepoStub) FindByID(ctx context.Context, id uuid.UUID) (domain.Bo
 	return bk, nil
 }
 
-func (b *bookingRepoStub) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Booking, error) {
+func (b *bookingRepoStub) FindByUserID(ctx context.Context, userID uuid.UUID, _ query.Options) (query.Page[domain.Booking], error) {
 	var out []domain.Booking
 	for _, v := range b.store {
 		if v.UserID == userID {
 			out = append(out, v)
 		}
 	}
-	return out, nil
+	return query.Page[domain.Booking]{Items: out, Total: int64(len(out))}, nil
 }
 
 func (b *bookingRepoStub) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
@@ -89,18 +91,100 @@ func (b *bookingRepoStub) UpdateStatus(ctx context.Context, id uuid.UUID, status
 	return nil
 }
 
-func (b *bookingRepoStub) List(ctx context.Context, _ query.Options) ([]domain.Booking, error) {
+func (b *bookingRepoStub) List(ctx context.Context, _ query.Options) (query.Page[domain.Booking], error) {
 	var out []domain.Booking
 	for _, v := range b.store {
 		out = append(out, v)
 	}
-	return out, nil
+	return query.Page[domain.Booking]{Items: out, Total: int64(len(out))}, nil
 }
 
-func (b *bookingRepoStub) Save(ctx context.Context, bk domain.Booking) error {
-	b.store[bk.ID] = bk
+func (b *bookingRepoStub) Save(ctx context.Context, bk *domain.Booking) error {
+	bk.Version++
+	b.store[bk.ID] = *bk
+	return nil
+}
+func (b *bookingRepoStub) FindOverlapping(context.Context, uuid.UUID, valueobject.DateRange) ([]domain.Booking, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindByRoomID(context.Context, uuid.UUID, valueobject.DateRange) ([]domain.Booking, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindExpiredHolds(context.Context, time.Time, int) ([]domain.Booking, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindMissedCheckIns(context.Context, time.Time, int) ([]domain.Booking, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+func (b *bookingRepoStub) AppendHistory(context.Context, []pkgDomain.DomainEvent) error { return nil }
+func (b *bookingRepoStub) History(context.Context, uuid.UUID) ([]domain.HistoryEntry, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) HistoryBookingIDs(context.Context) ([]uuid.UUID, error) { return nil, nil }
+func (b *bookingRepoStub) AppendEvents(context.Context, []pkgDomain.DomainEvent) error { return nil }
+func (b *bookingRepoStub) ClaimOutbox(context.Context, time.Time, int) ([]domain.OutboxMessage, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) SaveOutbox(context.Context, domain.OutboxMessage) error { return nil }
+func (b *bookingRepoStub) ListOutbox(context.Context, string, query.Options) ([]domain.OutboxMessage, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindOutbox(context.Context, uuid.UUID) (domain.OutboxMessage, error) {
+	return domain.OutboxMessage{}, nil
+}
+func (b *bookingRepoStub) CreateWaitlistEntry(context.Context, domain.WaitlistEntry) error { return nil }
+func (b *bookingRepoStub) SaveWaitlistEntry(context.Context, domain.WaitlistEntry) error   { return nil }
+func (b *bookingRepoStub) FindWaitlistEntry(context.Context, uuid.UUID) (domain.WaitlistEntry, error) {
+	return domain.WaitlistEntry{}, nil
+}
+func (b *bookingRepoStub) ListWaitlist(context.Context, query.Options) (query.Page[domain.WaitlistEntry], error) {
+	return query.Page[domain.WaitlistEntry]{}, nil
+}
+func (b *bookingRepoStub) CreatePromotion(context.Context, domain.Promotion) error { return nil }
+func (b *bookingRepoStub) UpdatePromotion(context.Context, domain.Promotion) error { return nil }
+func (b *bookingRepoStub) DeletePromotion(context.Context, uuid.UUID) error        { return nil }
+func (b *bookingRepoStub) GetPromotion(context.Context, uuid.UUID) (domain.Promotion, error) {
+	return domain.Promotion{}, nil
+}
+func (b *bookingRepoStub) ListPromotions(context.Context) ([]domain.Promotion, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindPromotionByCode(context.Context, string) (domain.Promotion, error) {
+	return domain.Promotion{}, nil
+}
+func (b *bookingRepoStub) LockPromotionByCode(context.Context, string) (domain.Promotion, error) {
+	return domain.Promotion{}, nil
+}
+func (b *bookingRepoStub) CountRedemptions(context.Context, uuid.UUID, uuid.UUID) (int, int, error) {
+	return 0, 0, nil
+}
+func (b *bookingRepoStub) CreateRedemption(context.Context, domain.Redemption) error { return nil }
+func (b *bookingRepoStub) ReleaseRedemptions(context.Context, uuid.UUID) error       { return nil }
+func (b *bookingRepoStub) SaveExchangeRates(context.Context, []valueobject.ExchangeRate) error {
+	return nil
+}
+func (b *bookingRepoStub) ListExchangeRates(context.Context) (valueobject.ExchangeRates, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) CreateInventoryHold(context.Context, domain.InventoryHold) error {
 	return nil
 }
+func (b *bookingRepoStub) SaveInventoryHold(context.Context, domain.InventoryHold) error { return nil }
+func (b *bookingRepoStub) FindInventoryHold(context.Context, uuid.UUID) (domain.InventoryHold, error) {
+	return domain.InventoryHold{}, nil
+}
+func (b *bookingRepoStub) FindActiveHolds(context.Context, uuid.UUID, valueobject.DateRange, time.Time) ([]domain.InventoryHold, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) FindUserHolds(context.Context, uuid.UUID, time.Time) ([]domain.InventoryHold, error) {
+	return nil, nil
+}
+func (b *bookingRepoStub) ExpireInventoryHolds(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
 
 type hotelRepoStub struct {
 	roomType hdomain.RoomType
@@ -108,8 +192,8 @@ type hotelRepoStub struct {
 }
 
 func (h *hotelRepoStub) CreateHotel(context.Context, hdomain.Hotel) error { return nil }
-func (h *hotelRepoStub) ListHotels(context.Context, query.Options) ([]hdomain.Hotel, error) {
-	return nil, nil
+func (h *hotelRepoStub) ListHotels(context.Context, query.Options) (query.Page[hdomain.Hotel], error) {
+	return query.Page[hdomain.Hotel]{}, nil
 }
 func (h *hotelRepoStub) GetHotel(context.Context, uuid.UUID) (hdomain.Hotel, error) {
 	return hdomain.Hotel{}, nil
@@ -133,15 +217,53 @@ func (h *hotelRepoStub) GetRoomType(ctx context.Context, id uuid.UUID) (hdomain.
 func (h *hotelRepoStub) ListRooms(context.Context, query.Options) ([]hdomain.Room, error) {
 	return nil, nil
 }
+func (h *hotelRepoStub) GetRoomTypeForUpdate(context.Context, uuid.UUID) (hdomain.RoomType, error) {
+	return hdomain.RoomType{}, nil
+}
+func (h *hotelRepoStub) CountBookableRooms(context.Context, uuid.UUID) (int, error) { return 0, nil }
+func (h *hotelRepoStub) ListRoomsByType(context.Context, uuid.UUID) ([]hdomain.Room, error) {
+	return nil, nil
+}
 func (h *hotelRepoStub) GetRoom(context.Context, uuid.UUID) (hdomain.Room, error) {
 	return hdomain.Room{}, nil
 }
 func (h *hotelRepoStub) UpdateRoom(context.Context, uuid.UUID, hdomain.Room) error { return nil }
 func (h *hotelRepoStub) DeleteRoom(context.Context, uuid.UUID) error               { return nil }
+func (h *hotelRepoStub) CreatePricingRule(context.Context, hdomain.PricingRule) error { return nil }
+func (h *hotelRepoStub) UpdatePricingRule(context.Context, hdomain.PricingRule) error { return nil }
+func (h *hotelRepoStub) DeletePricingRule(context.Context, uuid.UUID) error         { return nil }
+func (h *hotelRepoStub) GetPricingRule(context.Context, uuid.UUID) (hdomain.PricingRule, error) {
+	return hdomain.PricingRule{}, nil
+}
+func (h *hotelRepoStub) ListPricingRules(context.Context, uuid.UUID) ([]hdomain.PricingRule, error) {
+	return nil, nil
+}
+func (h *hotelRepoStub) ListCalendar(context.Context, uuid.UUID, time.Time, time.Time) (hdomain.Calendar, error) {
+	return nil, nil
+}
+func (h *hotelRepoStub) SaveCalendar(context.Context, []hdomain.CalendarDay) error  { return nil }
+func (h *hotelRepoStub) CreateChargeRule(context.Context, hdomain.ChargeRule) error { return nil }
+func (h *hotelRepoStub) UpdateChargeRule(context.Context, hdomain.ChargeRule) error { return nil }
+func (h *hotelRepoStub) DeleteChargeRule(context.Context, uuid.UUID) error          { return nil }
+func (h *hotelRepoStub) GetChargeRule(context.Context, uuid.UUID) (hdomain.ChargeRule, error) {
+	return hdomain.ChargeRule{}, nil
+}
+func (h *hotelRepoStub) ListChargeRules(context.Context, uuid.UUID) ([]hdomain.ChargeRule, error) {
+	return nil, nil
+}
+func (h *hotelRepoStub) CreateRatePlan(context.Context, hdomain.RatePlan) error { return nil }
+func (h *hotelRepoStub) UpdateRatePlan(context.Context, hdomain.RatePlan) error { return nil }
+func (h *hotelRepoStub) DeleteRatePlan(context.Context, uuid.UUID) error        { return nil }
+func (h *hotelRepoStub) GetRatePlan(context.Context, uuid.UUID) (hdomain.RatePlan, error) {
+	return hdomain.RatePlan{}, nil
+}
+func (h *hotelRepoStub) ListRatePlans(context.Context, uuid.UUID) ([]hdomain.RatePlan, error) {
+	return nil, nil
+}
 
 type paymentGatewayStub struct{}
 
-func (p *paymentGatewayStub) Initiate(context.Context, uuid.UUID, float64) (domain.PaymentResult, error) {
+func (p *paymentGatewayStub) Initiate(context.Context, uuid.UUID, uuid.UUID, valueobject.Money, []domain.PriceLine) (domain.PaymentResult, error) {
 	return domain.PaymentResult{
 		ID:         uuid.New(),
 		Status:     "pending",
@@ -150,6 +272,18 @@ func (p *paymentGatewayStub) Initiate(context.Context, uuid.UUID, float64) (doma
 	}, nil
 }
 
+func (p *paymentGatewayStub) InitiateSupplement(ctx context.Context, bookingID, userID uuid.UUID, amount valueobject.Money) (domain.PaymentResult, error) {
+	return p.Initiate(ctx, bookingID, userID, amount, nil)
+}
+
+func (p *paymentGatewayStub) Refund(context.Context, uuid.UUID, valueobject.Money, string) error {
+	return nil
+}
+
+func (p *paymentGatewayStub) Expire(context.Context, uuid.UUID) error {
+	return nil
+}
+
 type notificationGatewayStub struct{}
 
 func (n *notificationGatewayStub) Notify(context.Context, string, any) error {
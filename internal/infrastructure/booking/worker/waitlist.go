@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	bookinguc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+)
+
+// WaitlistScheduler offers rooms freed by cancellations and expired holds to waitlisted guests.
+// Several replicas may run it at once; offers lock the room type, so a room is offered only once.
+type WaitlistScheduler struct {
+	cron      *cron.Cron
+	service   *bookinguc.Service
+	jwtSecret string
+	logger    *zap.Logger
+}
+
+// NewWaitlistScheduler creates a new scheduler instance.
+// jwtSecret signs the service token used to initiate payments for offered holds.
+func NewWaitlistScheduler(service *bookinguc.Service, jwtSecret string, logger *zap.Logger) *WaitlistScheduler {
+	return &WaitlistScheduler{
+		cron:      cron.New(),
+		service:   service,
+		jwtSecret: jwtSecret,
+		logger:    logger,
+	}
+}
+
+// Start initializes and starts the cron scheduler.
+// Schedule: every minute, right behind the hold expiry that frees most rooms.
+func (s *WaitlistScheduler) Start() error {
+	_, err := s.cron.AddFunc("@every 1m", func() {
+		if err := s.runOffers(); err != nil {
+			s.logger.Error("❌ Waitlist offers failed", zap.Error(err))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cron.Start()
+	s.logger.Info("✅ Waitlist scheduler started (runs every minute)")
+	return nil
+}
+
+// Stop gracefully stops the cron scheduler.
+func (s *WaitlistScheduler) Stop() {
+	if s.cron != nil {
+		ctx := s.cron.Stop()
+		<-ctx.Done()
+		s.logger.Info("🛑 Waitlist scheduler stopped")
+	}
+}
+
+// runOffers executes the waitlist offer logic.
+func (s *WaitlistScheduler) runOffers() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	ctx, err := middleware.WithServiceToken(ctx, s.jwtSecret, "booking-service")
+	if err != nil {
+		return err
+	}
+
+	count, err := s.service.OfferWaitlist(ctx)
+	if count > 0 {
+		s.logger.Info("✅ Offered rooms to waitlisted guests", zap.Int("offers", count))
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package hotelhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/hotel/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/utils"
+)
+
+// defaultCalendarDays is how far ahead the calendar is shown without an end_date.
+const defaultCalendarDays = 30
+
+// @Summary Get room type calendar
+// @Description Lists the nightly rate and stay restrictions of every date from start_date up to end_date.
+// @Tags Hotels
+// @Produce json
+// @Param id path string true "Room type ID"
+// @Param start_date query string false "first date, YYYY-MM-DD (default today)"
+// @Param end_date query string false "first date not listed, YYYY-MM-DD (default 30 days after start_date)"
+// @Success 200 {array} dto.CalendarDayResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /room-types/{id}/calendar [get]
+func (h *Handler) getCalendar(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	start := time.Now()
+	if raw := r.URL.Query().Get("start_date"); raw != "" {
+		if start, err = time.Parse("2006-01-02", raw); err != nil {
+			writeError(w, pkgErrors.New("bad_request", "invalid start_date"))
+			return
+		}
+	}
+	end := start.AddDate(0, 0, defaultCalendarDays)
+	if raw := r.URL.Query().Get("end_date"); raw != "" {
+		if end, err = time.Parse("2006-01-02", raw); err != nil {
+			writeError(w, pkgErrors.New("bad_request", "invalid end_date"))
+			return
+		}
+	}
+	rt, days, err := h.service.Calendar(r.Context(), id, start, end)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resource := utils.NewResource(idParam, "room_type_calendar", "/api/v1/room-types/"+idParam+"/calendar", assembler.CalendarResponses(rt, days))
+	utils.Respond(w, http.StatusOK, "calendar retrieved", resource)
+}
+
+// @Summary Update room type calendar
+// @Description Sets the nightly rate and stay restrictions for every date from start_date up to end_date, optionally only on some weekdays. Omitted fields keep their value.
+// @Tags Hotels
+// @Accept json
+// @Produce json
+// @Param id path string true "Room type ID"
+// @Param request body dto.CalendarUpdateRequest true "Calendar update"
+// @Success 200 {array} dto.CalendarDayResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /room-types/{id}/calendar [put]
+func (h *Handler) updateCalendar(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	var req dto.CalendarUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	rt, days, err := h.service.UpdateCalendar(r.Context(), id, req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resource := utils.NewResource(idParam, "room_type_calendar", "/api/v1/room-types/"+idParam+"/calendar", assembler.CalendarResponses(rt, days))
+	utils.Respond(w, http.StatusOK, "calendar updated", resource)
+}
@@ -0,0 +1,125 @@
+package hotelhttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/hotel/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/utils"
+)
+
+// @Summary Create charge rule
+// @Description Adds a tax or fee to every room of a hotel: a percentage or a flat amount per night or per stay, inclusive in or added to the room price.
+// @Tags Taxes and Fees
+// @Accept json
+// @Produce json
+// @Param request body dto.ChargeRuleRequest true "Charge rule payload"
+// @Success 201 {object} dto.ChargeRuleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /charge-rules [post]
+func (h *Handler) createChargeRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.ChargeRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	rule, err := h.service.CreateChargeRule(r.Context(), req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ChargeRuleResponse(rule)
+	resource := utils.NewResource(resp.ID, "charge_rule", "/api/v1/charge-rules/"+resp.ID, resp)
+	utils.Respond(w, http.StatusCreated, "charge rule created", resource)
+}
+
+// @Summary List charge rules
+// @Description Lists a hotel's taxes and fees in the order they are itemised.
+// @Tags Taxes and Fees
+// @Produce json
+// @Param hotel_id query string true "Hotel ID"
+// @Success 200 {array} dto.ChargeRuleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /charge-rules [get]
+func (h *Handler) listChargeRules(w http.ResponseWriter, r *http.Request) {
+	hotelID, err := uuid.Parse(r.URL.Query().Get("hotel_id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid hotel_id"))
+		return
+	}
+	rules, err := h.service.ListChargeRules(r.Context(), hotelID)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	var resources []utils.Resource
+	for _, rule := range assembler.ChargeRuleResponses(rules) {
+		resources = append(resources, utils.NewResource(rule.ID, "charge_rule", "/api/v1/charge-rules/"+rule.ID, rule))
+	}
+	utils.RespondWithCount(w, http.StatusOK, "charge rules listed", resources, len(resources))
+}
+
+// @Summary Update charge rule
+// @Tags Taxes and Fees
+// @Accept json
+// @Produce json
+// @Param id path string true "Charge rule ID"
+// @Param request body dto.ChargeRuleRequest true "Charge rule payload"
+// @Success 200 {object} dto.ChargeRuleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /charge-rules/{id} [put]
+func (h *Handler) updateChargeRule(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	var req dto.ChargeRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	rule, err := h.service.UpdateChargeRule(r.Context(), id, req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ChargeRuleResponse(rule)
+	resource := utils.NewResource(resp.ID, "charge_rule", "/api/v1/charge-rules/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "charge rule updated", resource)
+}
+
+// @Summary Delete charge rule
+// @Tags Taxes and Fees
+// @Produce json
+// @Param id path string true "Charge rule ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /charge-rules/{id} [delete]
+func (h *Handler) deleteChargeRule(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	if err := h.service.DeleteChargeRule(r.Context(), id); err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	utils.Respond(w, http.StatusOK, "charge rule deleted", dto.SuccessResponse{
+		ID:      idParam,
+		Message: "charge rule deleted",
+	})
+}
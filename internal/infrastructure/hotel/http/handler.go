@@ -3,11 +3,11 @@ package hotelhttp
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
 	hoteluc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/hotel"
 	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/hotel/assembler"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
@@ -32,17 +32,31 @@ func (h *Handler) Routes() http.Handler {
 	r.Get("/hotels", h.listHotels)
 	r.Get("/hotels/{id}", h.getHotel)
 	r.Get("/room-types", h.listRoomTypes)
+	r.Get("/room-types/{id}/calendar", h.getCalendar)
 	r.Get("/rooms", h.listRooms)
 	r.Get("/rooms/{id}", h.getRoom)
+	r.Get("/rate-plans", h.listRatePlans)
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.JWT(h.jwtSecret, "admin"))
 		r.Post("/hotels", h.createHotel)
 		r.Put("/hotels/{id}", h.updateHotel)
 		r.Delete("/hotels/{id}", h.deleteHotel)
 		r.Post("/room-types", h.createRoomType)
+		r.Put("/room-types/{id}/calendar", h.updateCalendar)
 		r.Post("/rooms", h.createRoom)
 		r.Put("/rooms/{id}", h.updateRoom)
 		r.Delete("/rooms/{id}", h.deleteRoom)
+		r.Get("/pricing-rules", h.listPricingRules)
+		r.Post("/pricing-rules", h.createPricingRule)
+		r.Put("/pricing-rules/{id}", h.updatePricingRule)
+		r.Delete("/pricing-rules/{id}", h.deletePricingRule)
+		r.Get("/charge-rules", h.listChargeRules)
+		r.Post("/charge-rules", h.createChargeRule)
+		r.Put("/charge-rules/{id}", h.updateChargeRule)
+		r.Delete("/charge-rules/{id}", h.deleteChargeRule)
+		r.Post("/rate-plans", h.createRatePlan)
+		r.Put("/rate-plans/{id}", h.updateRatePlan)
+		r.Delete("/rate-plans/{id}", h.deleteRatePlan)
 	})
 	return r
 }
@@ -82,22 +96,31 @@ func (h *Handler) createHotel(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param limit query int false "pagination limit (default 50)"
 // @Param offset query int false "pagination offset"
+// @Param cursor query string false "next_cursor of the previous page; replaces offset"
+// @Param sort query string false "name or created_at; prefix with - for descending (default name)"
+// @Param name query string false "exact hotel name"
+// @Param name[contains] query string false "case-insensitive part of the hotel name"
 // @Success 200 {array} dto.HotelResponse
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /hotels [get]
 func (h *Handler) listHotels(w http.ResponseWriter, r *http.Request) {
-	opts := parseQueryOptions(r)
-	resp, err := h.service.ListHotels(r.Context(), opts)
+	opts, err := parseQueryOptions(r, domain.ListSchema)
 	if err != nil {
 		writeError(w, pkgErrors.FromError(err))
 		return
 	}
-	dtoList := assembler.ToHotelList(resp)
+	page, err := h.service.ListHotels(r.Context(), opts)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	dtoList := assembler.ToHotelList(page.Items)
 	var resources []utils.Resource
 	for _, h := range dtoList {
 		resources = append(resources, utils.NewResource(h.ID, "hotel", "/api/v1/hotels/"+h.ID, h))
 	}
-	utils.RespondWithCount(w, http.StatusOK, "hotels listed", resources, len(resources))
+	utils.RespondWithPage(w, http.StatusOK, "hotels listed", resources, len(resources), page.Total, page.NextCursor)
 }
 
 // @Summary Get hotel detail
@@ -164,7 +187,11 @@ func (h *Handler) createRoomType(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {array} dto.RoomTypeResponse
 // @Router /room-types [get]
 func (h *Handler) listRoomTypes(w http.ResponseWriter, r *http.Request) {
-	opts := parseQueryOptions(r)
+	opts, err := parseQueryOptions(r, query.Schema{})
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
 	resp, err := h.service.ListRoomTypes(r.Context(), opts)
 	if err != nil {
 		writeError(w, pkgErrors.FromError(err))
@@ -216,7 +243,11 @@ func (h *Handler) createRoom(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {array} dto.RoomResponse
 // @Router /rooms [get]
 func (h *Handler) listRooms(w http.ResponseWriter, r *http.Request) {
-	opts := parseQueryOptions(r)
+	opts, err := parseQueryOptions(r, query.Schema{})
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
 	resp, err := h.service.ListRooms(r.Context(), opts)
 	if err != nil {
 		writeError(w, pkgErrors.FromError(err))
@@ -382,8 +413,7 @@ func writeError(w http.ResponseWriter, err pkgErrors.APIError) {
 	utils.Respond(w, pkgErrors.StatusCode(err), err.Message, err)
 }
 
-func parseQueryOptions(r *http.Request) query.Options {
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	return query.Options{Limit: limit, Offset: offset}
+// parseQueryOptions reads pagination, sort, cursor and the filters schema allows.
+func parseQueryOptions(r *http.Request, schema query.Schema) (query.Options, error) {
+	return query.Parse(r.URL.Query(), schema)
 }
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -34,8 +35,8 @@ func TestHotelHandlerListHotelsPagination(t *testing.T) {
 type hotelRepoStub struct{}
 
 func (h *hotelRepoStub) CreateHotel(ctx context.Context, hotel domain.Hotel) error { return nil }
-func (h *hotelRepoStub) ListHotels(ctx context.Context, opts query.Options) ([]domain.Hotel, error) {
-	return []domain.Hotel{{ID: uuid.New(), Name: "H", Address: "Addr"}}, nil
+func (h *hotelRepoStub) ListHotels(ctx context.Context, opts query.Options) (query.Page[domain.Hotel], error) {
+	return query.Page[domain.Hotel]{Items: []domain.Hotel{{ID: uuid.New(), Name: "H", Address: "Addr"}}, Total: 1}, nil
 }
 func (h *hotelRepoStub) CreateRoomType(context.Context, domain.RoomType) error { return nil }
 func (h *hotelRepoStub) ListRoomTypes(context.Context, uuid.UUID) ([]domain.RoomType, error) {
@@ -63,6 +64,44 @@ func (h *hotelRepoStub) GetRoom(ctx context.Context, id uuid.UUID) (domain.Room,
 }
 func (h *hotelRepoStub) UpdateRoom(context.Context, uuid.UUID, domain.Room) error { return nil }
 func (h *hotelRepoStub) DeleteRoom(context.Context, uuid.UUID) error              { return nil }
+func (h *hotelRepoStub) GetRoomTypeForUpdate(ctx context.Context, id uuid.UUID) (domain.RoomType, error) {
+	return h.GetRoomType(ctx, id)
+}
+func (h *hotelRepoStub) CountBookableRooms(context.Context, uuid.UUID) (int, error) { return 0, nil }
+func (h *hotelRepoStub) ListRoomsByType(context.Context, uuid.UUID) ([]domain.Room, error) {
+	return nil, nil
+}
+func (h *hotelRepoStub) CreatePricingRule(context.Context, domain.PricingRule) error { return nil }
+func (h *hotelRepoStub) UpdatePricingRule(context.Context, domain.PricingRule) error { return nil }
+func (h *hotelRepoStub) DeletePricingRule(context.Context, uuid.UUID) error          { return nil }
+func (h *hotelRepoStub) GetPricingRule(context.Context, uuid.UUID) (domain.PricingRule, error) {
+	return domain.PricingRule{}, nil
+}
+func (h *hotelRepoStub) ListPricingRules(context.Context, uuid.UUID) ([]domain.PricingRule, error) {
+	return nil, nil
+}
+func (h *hotelRepoStub) ListCalendar(context.Context, uuid.UUID, time.Time, time.Time) (domain.Calendar, error) {
+	return nil, nil
+}
+func (h *hotelRepoStub) SaveCalendar(context.Context, []domain.CalendarDay) error  { return nil }
+func (h *hotelRepoStub) CreateChargeRule(context.Context, domain.ChargeRule) error { return nil }
+func (h *hotelRepoStub) UpdateChargeRule(context.Context, domain.ChargeRule) error { return nil }
+func (h *hotelRepoStub) DeleteChargeRule(context.Context, uuid.UUID) error         { return nil }
+func (h *hotelRepoStub) GetChargeRule(context.Context, uuid.UUID) (domain.ChargeRule, error) {
+	return domain.ChargeRule{}, nil
+}
+func (h *hotelRepoStub) ListChargeRules(context.Context, uuid.UUID) ([]domain.ChargeRule, error) {
+	return nil, nil
+}
+func (h *hotelRepoStub) CreateRatePlan(context.Context, domain.RatePlan) error { return nil }
+func (h *hotelRepoStub) UpdateRatePlan(context.Context, domain.RatePlan) error { return nil }
+func (h *hotelRepoStub) DeleteRatePlan(context.Context, uuid.UUID) error       { return nil }
+func (h *hotelRepoStub) GetRatePlan(context.Context, uuid.UUID) (domain.RatePlan, error) {
+	return domain.RatePlan{}, nil
+}
+func (h *hotelRepoStub) ListRatePlans(context.Context, uuid.UUID) ([]domain.RatePlan, error) {
+	return nil, nil
+}
 
 func TestHotelHandlerUpdateHotel(t *testing.T) {
 	repo := &hotelRepoStub{}
@@ -161,3 +200,48 @@ func TestHotelHandlerUpdateHotelInvalidID(t *testing.T) {
 	// Update requires admin auth, so without JWT we expect 401 (not 400 for invalid ID)
 	require.Equal(t, http.StatusUnauthorized, rec.Code)
 }
+
+func TestHotelHandlerAdminPricingRoutes(t *testing.T) {
+	repo := &hotelRepoStub{}
+	svc := hotel.NewService(repo)
+	h := hotelhttp.NewHandler(svc, "secret")
+	r := chi.NewRouter()
+	r.Mount("/", h.Routes())
+
+	ruleID := uuid.New().String()
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, "/pricing-rules?hotel_id=" + uuid.New().String()},
+		{http.MethodPost, "/pricing-rules"},
+		{http.MethodPut, "/pricing-rules/" + ruleID},
+		{http.MethodDelete, "/pricing-rules/" + ruleID},
+		{http.MethodPut, "/room-types/" + uuid.New().String() + "/calendar"},
+		{http.MethodPost, "/rate-plans"},
+		{http.MethodPut, "/rate-plans/" + ruleID},
+		{http.MethodDelete, "/rate-plans/" + ruleID},
+	} {
+		req := httptest.NewRequest(route.method, route.path, strings.NewReader(`{}`))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusUnauthorized, rec.Code, route.method+" "+route.path)
+	}
+}
+
+func TestHotelHandlerGetCalendar(t *testing.T) {
+	repo := &hotelRepoStub{}
+	svc := hotel.NewService(repo)
+	h := hotelhttp.NewHandler(svc, "secret")
+	r := chi.NewRouter()
+	r.Mount("/", h.Routes())
+
+	path := "/room-types/" + uuid.New().String() + "/calendar"
+	req := httptest.NewRequest(http.MethodGet, path+"?start_date=2030-12-30&end_date=2031-01-02", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, 3, strings.Count(rec.Body.String(), `"closed_to_arrival"`))
+
+	req = httptest.NewRequest(http.MethodGet, path+"?start_date=30-12-2030", nil)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,133 @@
+package hotelhttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/hotel/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/utils"
+)
+
+// @Summary Create pricing rule
+// @Description Adds a season, day_of_week, occupancy or length_of_stay rule to a hotel, or to one room type when room_type_id is set.
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param request body dto.PricingRuleRequest true "Pricing rule payload"
+// @Success 201 {object} dto.PricingRuleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /pricing-rules [post]
+func (h *Handler) createPricingRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.PricingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	rule, err := h.service.CreatePricingRule(r.Context(), req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.PricingRuleResponse(rule)
+	resource := utils.NewResource(resp.ID, "pricing_rule", "/api/v1/pricing-rules/"+resp.ID, resp)
+	utils.Respond(w, http.StatusCreated, "pricing rule created", resource)
+}
+
+// @Summary List pricing rules
+// @Description Lists a hotel's rules newest first; with room_type_id only the rules pricing that room type.
+// @Tags Pricing
+// @Produce json
+// @Param hotel_id query string true "Hotel ID"
+// @Param room_type_id query string false "Room type ID"
+// @Success 200 {array} dto.PricingRuleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /pricing-rules [get]
+func (h *Handler) listPricingRules(w http.ResponseWriter, r *http.Request) {
+	hotelID, err := uuid.Parse(r.URL.Query().Get("hotel_id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid hotel_id"))
+		return
+	}
+	roomTypeID := uuid.Nil
+	if raw := r.URL.Query().Get("room_type_id"); raw != "" {
+		if roomTypeID, err = uuid.Parse(raw); err != nil {
+			writeError(w, pkgErrors.New("bad_request", "invalid room_type_id"))
+			return
+		}
+	}
+	rules, err := h.service.ListPricingRules(r.Context(), hotelID, roomTypeID)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	var resources []utils.Resource
+	for _, rule := range assembler.PricingRuleResponses(rules) {
+		resources = append(resources, utils.NewResource(rule.ID, "pricing_rule", "/api/v1/pricing-rules/"+rule.ID, rule))
+	}
+	utils.RespondWithCount(w, http.StatusOK, "pricing rules listed", resources, len(resources))
+}
+
+// @Summary Update pricing rule
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Pricing rule ID"
+// @Param request body dto.PricingRuleRequest true "Pricing rule payload"
+// @Success 200 {object} dto.PricingRuleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /pricing-rules/{id} [put]
+func (h *Handler) updatePricingRule(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	var req dto.PricingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	rule, err := h.service.UpdatePricingRule(r.Context(), id, req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.PricingRuleResponse(rule)
+	resource := utils.NewResource(resp.ID, "pricing_rule", "/api/v1/pricing-rules/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "pricing rule updated", resource)
+}
+
+// @Summary Delete pricing rule
+// @Tags Pricing
+// @Produce json
+// @Param id path string true "Pricing rule ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /pricing-rules/{id} [delete]
+func (h *Handler) deletePricingRule(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	if err := h.service.DeletePricingRule(r.Context(), id); err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	utils.Respond(w, http.StatusOK, "pricing rule deleted", dto.SuccessResponse{
+		ID:      idParam,
+		Message: "pricing rule deleted",
+	})
+}
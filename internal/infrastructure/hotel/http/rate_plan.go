@@ -0,0 +1,125 @@
+package hotelhttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/hotel/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/utils"
+)
+
+// @Summary Create rate plan
+// @Description Adds terms a room type is sold under: a price modifier, inclusions such as breakfast, a cancellation policy and whether guests pay now or at the hotel.
+// @Tags Rate Plans
+// @Accept json
+// @Produce json
+// @Param request body dto.RatePlanRequest true "Rate plan payload"
+// @Success 201 {object} dto.RatePlanResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /rate-plans [post]
+func (h *Handler) createRatePlan(w http.ResponseWriter, r *http.Request) {
+	var req dto.RatePlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	plan, err := h.service.CreateRatePlan(r.Context(), req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.RatePlanResponse(plan)
+	resource := utils.NewResource(resp.ID, "rate_plan", "/api/v1/rate-plans/"+resp.ID, resp)
+	utils.Respond(w, http.StatusCreated, "rate plan created", resource)
+}
+
+// @Summary List rate plans
+// @Description Lists the plans a room type can be booked under.
+// @Tags Rate Plans
+// @Produce json
+// @Param room_type_id query string true "Room type ID"
+// @Success 200 {array} dto.RatePlanResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /rate-plans [get]
+func (h *Handler) listRatePlans(w http.ResponseWriter, r *http.Request) {
+	roomTypeID, err := uuid.Parse(r.URL.Query().Get("room_type_id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid room_type_id"))
+		return
+	}
+	plans, err := h.service.ListRatePlans(r.Context(), roomTypeID)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	var resources []utils.Resource
+	for _, plan := range assembler.RatePlanResponses(plans) {
+		resources = append(resources, utils.NewResource(plan.ID, "rate_plan", "/api/v1/rate-plans/"+plan.ID, plan))
+	}
+	utils.RespondWithCount(w, http.StatusOK, "rate plans listed", resources, len(resources))
+}
+
+// @Summary Update rate plan
+// @Tags Rate Plans
+// @Accept json
+// @Produce json
+// @Param id path string true "Rate plan ID"
+// @Param request body dto.RatePlanRequest true "Rate plan payload"
+// @Success 200 {object} dto.RatePlanResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /rate-plans/{id} [put]
+func (h *Handler) updateRatePlan(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	var req dto.RatePlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid payload"))
+		return
+	}
+	plan, err := h.service.UpdateRatePlan(r.Context(), id, req)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.RatePlanResponse(plan)
+	resource := utils.NewResource(resp.ID, "rate_plan", "/api/v1/rate-plans/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "rate plan updated", resource)
+}
+
+// @Summary Delete rate plan
+// @Description Existing bookings keep the terms of the plan they were made on.
+// @Tags Rate Plans
+// @Produce json
+// @Param id path string true "Rate plan ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /rate-plans/{id} [delete]
+func (h *Handler) deleteRatePlan(w http.ResponseWriter, r *http.Request) {
+	idParam := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	if err := h.service.DeleteRatePlan(r.Context(), id); err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	utils.Respond(w, http.StatusOK, "rate plan deleted", dto.SuccessResponse{
+		ID:      idParam,
+		Message: "rate plan deleted",
+	})
+}
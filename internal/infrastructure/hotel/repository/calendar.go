@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+func (r *GormRepository) ListCalendar(ctx context.Context, roomTypeID uuid.UUID, from, to time.Time) (domain.Calendar, error) {
+	var models []calendarDayModel
+	err := r.conn(ctx).
+		Where("room_type_id = ? AND date BETWEEN ? AND ?", roomTypeID, domain.CalendarDate(from), domain.CalendarDate(to)).
+		Order("date").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	days := make(domain.Calendar, 0, len(models))
+	for _, m := range models {
+		days = append(days, m.toDomain())
+	}
+	return days, nil
+}
+
+func (r *GormRepository) SaveCalendar(ctx context.Context, days []domain.CalendarDay) error {
+	if len(days) == 0 {
+		return nil
+	}
+	models := make([]calendarDayModel, 0, len(days))
+	for _, d := range days {
+		models = append(models, calendarDayModel{
+			RoomTypeID:        d.RoomTypeID,
+			Date:              domain.CalendarDate(d.Date),
+			Price:             d.Price,
+			ClosedToArrival:   d.ClosedToArrival,
+			ClosedToDeparture: d.ClosedToDeparture,
+			MinStay:           d.MinStay,
+			MaxStay:           d.MaxStay,
+		})
+	}
+	return r.conn(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "room_type_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"price", "closed_to_arrival", "closed_to_departure", "min_stay", "max_stay"}),
+	}).Create(&models).Error
+}
+
+type calendarDayModel struct {
+	RoomTypeID        uuid.UUID          `gorm:"type:uuid;primaryKey"`
+	Date              time.Time          `gorm:"primaryKey"`
+	Price             valueobject.Amount `gorm:"type:numeric"`
+	ClosedToArrival   bool
+	ClosedToDeparture bool
+	MinStay           int
+	MaxStay           int
+}
+
+func (calendarDayModel) TableName() string { return "room_type_calendar" }
+
+func (m calendarDayModel) toDomain() domain.CalendarDay {
+	return domain.CalendarDay{
+		RoomTypeID:        m.RoomTypeID,
+		Date:              domain.CalendarDate(m.Date.UTC()),
+		Price:             m.Price,
+		ClosedToArrival:   m.ClosedToArrival,
+		ClosedToDeparture: m.ClosedToDeparture,
+		MinStay:           m.MinStay,
+		MaxStay:           m.MaxStay,
+	}
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+func (r *GormRepository) CreateChargeRule(ctx context.Context, rule domain.ChargeRule) error {
+	model := toChargeRuleModel(rule)
+	return r.conn(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) UpdateChargeRule(ctx context.Context, rule domain.ChargeRule) error {
+	model := toChargeRuleModel(rule)
+	result := r.conn(ctx).Model(&model).Select("*").Omit("created_at").Updates(&model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "charge rule not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) DeleteChargeRule(ctx context.Context, id uuid.UUID) error {
+	result := r.conn(ctx).Delete(&chargeRuleModel{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "charge rule not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) GetChargeRule(ctx context.Context, id uuid.UUID) (domain.ChargeRule, error) {
+	var model chargeRuleModel
+	if err := r.conn(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return domain.ChargeRule{}, translateErr(err)
+	}
+	return model.toDomain(), nil
+}
+
+func (r *GormRepository) ListChargeRules(ctx context.Context, hotelID uuid.UUID) ([]domain.ChargeRule, error) {
+	var models []chargeRuleModel
+	err := r.conn(ctx).Where("hotel_id = ?", hotelID).Order("created_at").Order("id").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]domain.ChargeRule, 0, len(models))
+	for _, m := range models {
+		rules = append(rules, m.toDomain())
+	}
+	return rules, nil
+}
+
+type chargeRuleModel struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	HotelID   uuid.UUID `gorm:"type:uuid;index"`
+	Kind      string
+	Name      string
+	Basis     string
+	Amount    valueobject.Amount `gorm:"type:numeric"`
+	Per       string
+	Inclusive bool
+	CreatedAt time.Time
+}
+
+func (chargeRuleModel) TableName() string { return "charge_rules" }
+
+func toChargeRuleModel(r domain.ChargeRule) chargeRuleModel {
+	return chargeRuleModel{
+		ID:        r.ID,
+		HotelID:   r.HotelID,
+		Kind:      r.Rule.Kind,
+		Name:      r.Rule.Name,
+		Basis:     r.Rule.Basis,
+		Amount:    r.Rule.Amount,
+		Per:       r.Rule.Per,
+		Inclusive: r.Rule.Inclusive,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (m chargeRuleModel) toDomain() domain.ChargeRule {
+	return domain.ChargeRule{
+		ID:      m.ID,
+		HotelID: m.HotelID,
+		Rule: valueobject.ChargeRule{
+			Kind:      m.Kind,
+			Name:      m.Name,
+			Basis:     m.Basis,
+			Amount:    m.Amount,
+			Per:       m.Per,
+			Inclusive: m.Inclusive,
+		},
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -7,10 +7,13 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/database"
 	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // GormRepository implements hotel repo.
@@ -22,50 +25,58 @@ func NewGormRepository(db *gorm.DB) *GormRepository { return &GormRepository{db:
 
 // AutoMigrate ensures hotel related tables exist.
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(&hotelModel{}, &roomTypeModel{}, &roomModel{})
+	return db.AutoMigrate(&hotelModel{}, &roomTypeModel{}, &roomModel{}, &pricingRuleModel{}, &calendarDayModel{}, &chargeRuleModel{}, &ratePlanModel{})
+}
+
+func (r *GormRepository) conn(ctx context.Context) *gorm.DB {
+	return database.Conn(ctx, r.db)
 }
 
 func (r *GormRepository) CreateHotel(ctx context.Context, h domain.Hotel) error {
-	return r.db.WithContext(ctx).Create(&hotelModel{
+	return r.conn(ctx).Create(&hotelModel{
 		ID:          h.ID,
 		Name:        h.Name,
 		Description: h.Description,
 		Address:     h.Address,
+		Currency:    h.Currency,
 		CreatedAt:   h.CreatedAt,
 	}).Error
 }
 
-func (r *GormRepository) ListHotels(ctx context.Context, opts query.Options) ([]domain.Hotel, error) {
-	var models []hotelModel
-	qo := opts.Normalize(50)
-	tx := r.db.WithContext(ctx)
-	if qo.Limit > 0 {
-		tx = tx.Limit(qo.Limit).Offset(qo.Offset)
-	}
-	if err := tx.Find(&models).Error; err != nil {
-		return nil, err
+// hotelListing maps domain.ListSchema onto the hotels table.
+var hotelListing = database.Listing{
+	Columns:     map[string]string{"name": "name", "created_at": "created_at"},
+	DefaultSort: domain.ListSchema.DefaultSort,
+	DefaultSize: 50,
+}
+
+func (r *GormRepository) ListHotels(ctx context.Context, opts query.Options) (query.Page[domain.Hotel], error) {
+	page, err := database.Paginate[hotelModel](r.conn(ctx), hotelListing, opts)
+	if err != nil {
+		return query.Page[domain.Hotel]{}, err
 	}
-	hotels := make([]domain.Hotel, 0, len(models))
-	for _, m := range models {
+	hotels := make([]domain.Hotel, 0, len(page.Items))
+	for _, m := range page.Items {
 		hotels = append(hotels, m.toDomain())
 	}
-	return hotels, nil
+	return query.Page[domain.Hotel]{Items: hotels, NextCursor: page.NextCursor, Total: page.Total}, nil
 }
 
 func (r *GormRepository) CreateRoomType(ctx context.Context, rt domain.RoomType) error {
-	return r.db.WithContext(ctx).Create(&roomTypeModel{
-		ID:        rt.ID,
-		HotelID:   rt.HotelID,
-		Name:      rt.Name,
-		Capacity:  rt.Capacity,
-		BasePrice: rt.BasePrice,
-		Amenities: rt.Amenities,
+	return r.conn(ctx).Create(&roomTypeModel{
+		ID:                 rt.ID,
+		HotelID:            rt.HotelID,
+		Name:               rt.Name,
+		Capacity:           rt.Capacity,
+		BasePrice:          rt.BasePrice,
+		Amenities:          rt.Amenities,
+		CancellationPolicy: rt.CancellationPolicy,
 	}).Error
 }
 
 func (r *GormRepository) ListRoomTypes(ctx context.Context, hotelID uuid.UUID) ([]domain.RoomType, error) {
 	var models []roomTypeModel
-	if err := r.db.WithContext(ctx).Where("hotel_id = ?", hotelID).Find(&models).Error; err != nil {
+	if err := r.conn(ctx).Where("hotel_id = ?", hotelID).Find(&models).Error; err != nil {
 		return nil, err
 	}
 	return toRoomTypes(models), nil
@@ -74,7 +85,7 @@ func (r *GormRepository) ListRoomTypes(ctx context.Context, hotelID uuid.UUID) (
 func (r *GormRepository) ListAllRoomTypes(ctx context.Context, opts query.Options) ([]domain.RoomType, error) {
 	var models []roomTypeModel
 	qo := opts.Normalize(50)
-	tx := r.db.WithContext(ctx)
+	tx := r.conn(ctx)
 	if qo.Limit > 0 {
 		tx = tx.Limit(qo.Limit).Offset(qo.Offset)
 	}
@@ -85,7 +96,7 @@ func (r *GormRepository) ListAllRoomTypes(ctx context.Context, opts query.Option
 }
 
 func (r *GormRepository) CreateRoom(ctx context.Context, room domain.Room) error {
-	return r.db.WithContext(ctx).Create(&roomModel{
+	return r.conn(ctx).Create(&roomModel{
 		ID:         room.ID,
 		RoomTypeID: room.RoomTypeID,
 		Number:     room.Number,
@@ -95,16 +106,38 @@ func (r *GormRepository) CreateRoom(ctx context.Context, room domain.Room) error
 
 func (r *GormRepository) GetRoomType(ctx context.Context, id uuid.UUID) (domain.RoomType, error) {
 	var model roomTypeModel
-	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+	if err := r.conn(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return domain.RoomType{}, translateErr(err)
+	}
+	return model.toDomain(), nil
+}
+
+func (r *GormRepository) GetRoomTypeForUpdate(ctx context.Context, id uuid.UUID) (domain.RoomType, error) {
+	var model roomTypeModel
+	err := r.conn(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&model, "id = ?", id).Error
+	if err != nil {
 		return domain.RoomType{}, translateErr(err)
 	}
 	return model.toDomain(), nil
 }
 
+func (r *GormRepository) CountBookableRooms(ctx context.Context, roomTypeID uuid.UUID) (int, error) {
+	var count int64
+	err := r.conn(ctx).Model(&roomModel{}).
+		Where("room_type_id = ? AND status IN ?", roomTypeID, valueobject.BookableRoomStatuses()).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
 func (r *GormRepository) ListRooms(ctx context.Context, opts query.Options) ([]domain.Room, error) {
 	var models []roomModel
 	qo := opts.Normalize(50)
-	tx := r.db.WithContext(ctx)
+	tx := r.conn(ctx)
 	if qo.Limit > 0 {
 		tx = tx.Limit(qo.Limit).Offset(qo.Offset)
 	}
@@ -118,16 +151,28 @@ func (r *GormRepository) ListRooms(ctx context.Context, opts query.Options) ([]d
 	return rooms, nil
 }
 
+func (r *GormRepository) ListRoomsByType(ctx context.Context, roomTypeID uuid.UUID) ([]domain.Room, error) {
+	var models []roomModel
+	if err := r.conn(ctx).Where("room_type_id = ?", roomTypeID).Order("number").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	rooms := make([]domain.Room, 0, len(models))
+	for _, m := range models {
+		rooms = append(rooms, m.toDomain())
+	}
+	return rooms, nil
+}
+
 func (r *GormRepository) GetRoom(ctx context.Context, id uuid.UUID) (domain.Room, error) {
 	var model roomModel
-	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+	if err := r.conn(ctx).First(&model, "id = ?", id).Error; err != nil {
 		return domain.Room{}, translateErr(err)
 	}
 	return model.toDomain(), nil
 }
 
 func (r *GormRepository) UpdateRoom(ctx context.Context, id uuid.UUID, room domain.Room) error {
-	result := r.db.WithContext(ctx).Model(&roomModel{}).
+	result := r.conn(ctx).Model(&roomModel{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
 			"number": room.Number,
@@ -143,7 +188,7 @@ func (r *GormRepository) UpdateRoom(ctx context.Context, id uuid.UUID, room doma
 }
 
 func (r *GormRepository) DeleteRoom(ctx context.Context, id uuid.UUID) error {
-	result := r.db.WithContext(ctx).Delete(&roomModel{}, "id = ?", id)
+	result := r.conn(ctx).Delete(&roomModel{}, "id = ?", id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -155,20 +200,24 @@ func (r *GormRepository) DeleteRoom(ctx context.Context, id uuid.UUID) error {
 
 func (r *GormRepository) GetHotel(ctx context.Context, id uuid.UUID) (domain.Hotel, error) {
 	var model hotelModel
-	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+	if err := r.conn(ctx).First(&model, "id = ?", id).Error; err != nil {
 		return domain.Hotel{}, translateErr(err)
 	}
 	return model.toDomain(), nil
 }
 
 func (r *GormRepository) UpdateHotel(ctx context.Context, id uuid.UUID, h domain.Hotel) error {
-	result := r.db.WithContext(ctx).Model(&hotelModel{}).
+	fields := map[string]interface{}{
+		"name":        h.Name,
+		"description": h.Description,
+		"address":     h.Address,
+	}
+	if h.Currency != "" {
+		fields["currency"] = h.Currency
+	}
+	result := r.conn(ctx).Model(&hotelModel{}).
 		Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"name":        h.Name,
-			"description": h.Description,
-			"address":     h.Address,
-		})
+		Updates(fields)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -179,7 +228,7 @@ func (r *GormRepository) UpdateHotel(ctx context.Context, id uuid.UUID, h domain
 }
 
 func (r *GormRepository) DeleteHotel(ctx context.Context, id uuid.UUID) error {
-	result := r.db.WithContext(ctx).Delete(&hotelModel{}, "id = ?", id)
+	result := r.conn(ctx).Delete(&hotelModel{}, "id = ?", id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -194,6 +243,7 @@ type hotelModel struct {
 	Name        string
 	Description string
 	Address     string
+	Currency    string         `gorm:"not null;default:IDR"`
 	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime"`
 	DeletedAt   gorm.DeletedAt `gorm:"index"` // Soft delete support
 }
@@ -206,29 +256,32 @@ func (m hotelModel) toDomain() domain.Hotel {
 		Name:        m.Name,
 		Description: m.Description,
 		Address:     m.Address,
+		Currency:    m.Currency,
 		CreatedAt:   m.CreatedAt,
 	}
 }
 
 type roomTypeModel struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
-	HotelID   uuid.UUID `gorm:"type:uuid;index"`
-	Name      string
-	Capacity  int
-	BasePrice float64 `gorm:"type:numeric"`
-	Amenities string
+	ID                 uuid.UUID `gorm:"type:uuid;primaryKey"`
+	HotelID            uuid.UUID `gorm:"type:uuid;index"`
+	Name               string
+	Capacity           int
+	BasePrice          valueobject.Amount `gorm:"type:numeric"`
+	Amenities          string
+	CancellationPolicy string `gorm:"not null;default:flexible"`
 }
 
 func (roomTypeModel) TableName() string { return "room_types" }
 
 func (m roomTypeModel) toDomain() domain.RoomType {
 	return domain.RoomType{
-		ID:        m.ID,
-		HotelID:   m.HotelID,
-		Name:      m.Name,
-		Capacity:  m.Capacity,
-		BasePrice: m.BasePrice,
-		Amenities: m.Amenities,
+		ID:                 m.ID,
+		HotelID:            m.HotelID,
+		Name:               m.Name,
+		Capacity:           m.Capacity,
+		BasePrice:          m.BasePrice,
+		Amenities:          m.Amenities,
+		CancellationPolicy: m.CancellationPolicy,
 	}
 }
 
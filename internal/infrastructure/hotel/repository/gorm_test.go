@@ -3,6 +3,7 @@ package repository_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	sqlite "github.com/glebarez/sqlite"
 	"github.com/google/uuid"
@@ -12,6 +13,7 @@ import (
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
 	repo "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/hotel/repository"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 func TestHotelGormRepository(t *testing.T) {
@@ -24,7 +26,172 @@ func TestHotelGormRepository(t *testing.T) {
 
 	hotels, err := r.ListHotels(context.Background(), query.Options{Limit: 10})
 	require.NoError(t, err)
-	require.Len(t, hotels, 1)
+	require.Len(t, hotels.Items, 1)
+	require.EqualValues(t, 1, hotels.Total)
+}
+
+func TestHotelGormRepositoryFiltersAndPagesByCursor(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	for _, name := range []string{"Cursor Inn A", "Cursor Inn B", "Cursor Inn C", "Other 100%"} {
+		require.NoError(t, r.CreateHotel(ctx, domain.Hotel{ID: uuid.New(), Name: name, Address: "Addr"}))
+	}
+
+	opts := query.Options{
+		Limit:   2,
+		Filters: []query.Filter{{Field: "name", Op: query.OpContains, Value: "cursor inn"}},
+		Sort:    query.Sort{Field: "name"},
+	}
+	first, err := r.ListHotels(ctx, opts)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, first.Total)
+	require.Len(t, first.Items, 2)
+	require.Equal(t, "Cursor Inn A", first.Items[0].Name)
+	require.NotEmpty(t, first.NextCursor)
+
+	cursor, err := query.DecodeCursor(first.NextCursor, domain.ListSchema)
+	require.NoError(t, err)
+	opts.After = &cursor
+	second, err := r.ListHotels(ctx, opts)
+	require.NoError(t, err)
+	require.Len(t, second.Items, 1)
+	require.Equal(t, "Cursor Inn C", second.Items[0].Name)
+	require.Empty(t, second.NextCursor)
+
+	// LIKE wildcards in the value are matched literally.
+	literal, err := r.ListHotels(ctx, query.Options{Filters: []query.Filter{{Field: "name", Op: query.OpContains, Value: "0%"}}})
+	require.NoError(t, err)
+	require.Len(t, literal.Items, 1)
+}
+
+func TestHotelGormRepositoryCountBookableRooms(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	roomTypeID := uuid.New()
+	rooms := []domain.Room{
+		{ID: uuid.New(), RoomTypeID: roomTypeID, Number: "101", Status: "available"},
+		{ID: uuid.New(), RoomTypeID: roomTypeID, Number: "102", Status: "maintenance"},
+		{ID: uuid.New(), RoomTypeID: roomTypeID, Number: "103", Status: "available"},
+	}
+	for _, room := range rooms {
+		require.NoError(t, r.CreateRoom(ctx, room))
+	}
+	require.NoError(t, r.DeleteRoom(ctx, rooms[2].ID))
+
+	count, err := r.CountBookableRooms(ctx, roomTypeID)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestHotelGormRepositoryPricingRules(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	hotelID, roomTypeID := uuid.New(), uuid.New()
+	start := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	season := domain.PricingRule{ID: uuid.New(), HotelID: hotelID, RoomTypeID: roomTypeID, CreatedAt: start, Rule: valueobject.PricingRule{
+		Kind: valueobject.PricingSeason, Name: "peak", StartDate: start, EndDate: start.AddDate(0, 0, 14), Rate: valueobject.AmountOf(900),
+	}}
+	weekend := domain.PricingRule{ID: uuid.New(), HotelID: hotelID, CreatedAt: start.Add(time.Hour), Rule: valueobject.PricingRule{
+		Kind: valueobject.PricingDayOfWeek, Name: "weekend", Weekdays: []time.Weekday{time.Friday, time.Saturday}, Percent: 15,
+	}}
+	require.NoError(t, r.CreatePricingRule(ctx, season))
+	require.NoError(t, r.CreatePricingRule(ctx, weekend))
+	require.NoError(t, r.CreatePricingRule(ctx, domain.PricingRule{ID: uuid.New(), HotelID: uuid.New(), CreatedAt: start, Rule: valueobject.PricingRule{
+		Kind: valueobject.PricingOccupancy, Name: "other hotel", Guests: 2, Percent: 10,
+	}}))
+
+	rules, err := r.ListPricingRules(ctx, hotelID)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, weekend.Rule, rules[0].Rule)
+	require.Equal(t, uuid.Nil, rules[0].RoomTypeID)
+	require.Equal(t, season.Rule, rules[1].Rule)
+	require.Equal(t, roomTypeID, rules[1].RoomTypeID)
+
+	weekend.Rule.Percent = 25
+	require.NoError(t, r.UpdatePricingRule(ctx, weekend))
+	found, err := r.GetPricingRule(ctx, weekend.ID)
+	require.NoError(t, err)
+	require.Equal(t, 25.0, found.Rule.Percent)
+
+	require.NoError(t, r.DeletePricingRule(ctx, season.ID))
+	_, err = r.GetPricingRule(ctx, season.ID)
+	require.Error(t, err)
+	require.Error(t, r.DeletePricingRule(ctx, season.ID))
+}
+
+func TestHotelGormRepositoryCalendar(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	roomTypeID := uuid.New()
+	day := time.Date(2030, 12, 30, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, r.SaveCalendar(ctx, []domain.CalendarDay{
+		{RoomTypeID: roomTypeID, Date: day, MinStay: 2},
+		{RoomTypeID: roomTypeID, Date: day.AddDate(0, 0, 1), Price: valueobject.AmountOf(400), ClosedToDeparture: true},
+		{RoomTypeID: uuid.New(), Date: day, Price: valueobject.AmountOf(999)},
+	}))
+	// Saving a stored date again replaces it.
+	require.NoError(t, r.SaveCalendar(ctx, []domain.CalendarDay{{RoomTypeID: roomTypeID, Date: day, MinStay: 3, MaxStay: 7}}))
+
+	calendar, err := r.ListCalendar(ctx, roomTypeID, day.Add(10*time.Hour), day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Equal(t, domain.Calendar{
+		{RoomTypeID: roomTypeID, Date: day, MinStay: 3, MaxStay: 7},
+		{RoomTypeID: roomTypeID, Date: day.AddDate(0, 0, 1), Price: valueobject.AmountOf(400), ClosedToDeparture: true},
+	}, calendar)
+
+	calendar, err = r.ListCalendar(ctx, roomTypeID, day.AddDate(0, 0, 2), day.AddDate(0, 0, 5))
+	require.NoError(t, err)
+	require.Empty(t, calendar)
+}
+
+func TestHotelGormRepositoryRatePlans(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	roomTypeID := uuid.New()
+	created := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	saver := domain.RatePlan{ID: uuid.New(), RoomTypeID: roomTypeID, CreatedAt: created, Plan: valueobject.RatePlan{
+		Name: "Saver", Percent: -15, CancellationPolicy: valueobject.PolicyNonRefundable, PaymentTiming: valueobject.PayNow,
+	}}
+	breakfast := domain.RatePlan{ID: uuid.New(), RoomTypeID: roomTypeID, CreatedAt: created.Add(time.Hour), Plan: valueobject.RatePlan{
+		Name: "Bed and breakfast", PerNight: valueobject.AmountOf(150000), Inclusions: []string{"breakfast", "parking"},
+		CancellationPolicy: valueobject.PolicyFlexible, PaymentTiming: valueobject.PayAtHotel,
+	}}
+	require.NoError(t, r.CreateRatePlan(ctx, saver))
+	require.NoError(t, r.CreateRatePlan(ctx, breakfast))
+	require.NoError(t, r.CreateRatePlan(ctx, domain.RatePlan{ID: uuid.New(), RoomTypeID: uuid.New(), CreatedAt: created, Plan: valueobject.RatePlan{Name: "Other"}}))
+
+	plans, err := r.ListRatePlans(ctx, roomTypeID)
+	require.NoError(t, err)
+	require.Len(t, plans, 2)
+	require.Equal(t, saver.Plan, plans[0].Plan)
+	require.Equal(t, breakfast.Plan, plans[1].Plan)
+
+	saver.Plan.Percent = -20
+	require.NoError(t, r.UpdateRatePlan(ctx, saver))
+	found, err := r.GetRatePlan(ctx, saver.ID)
+	require.NoError(t, err)
+	require.Equal(t, -20.0, found.Plan.Percent)
+
+	require.NoError(t, r.DeleteRatePlan(ctx, saver.ID))
+	_, err = r.GetRatePlan(ctx, saver.ID)
+	require.Error(t, err)
+	require.Error(t, r.DeleteRatePlan(ctx, saver.ID))
 }
 
 func newTestDB(t *testing.T) *gorm.DB {
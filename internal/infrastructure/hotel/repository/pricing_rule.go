@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+func (r *GormRepository) CreatePricingRule(ctx context.Context, rule domain.PricingRule) error {
+	model := toPricingRuleModel(rule)
+	return r.conn(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) UpdatePricingRule(ctx context.Context, rule domain.PricingRule) error {
+	model := toPricingRuleModel(rule)
+	result := r.conn(ctx).Model(&model).Select("*").Omit("created_at").Updates(&model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "pricing rule not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) DeletePricingRule(ctx context.Context, id uuid.UUID) error {
+	result := r.conn(ctx).Delete(&pricingRuleModel{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "pricing rule not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) GetPricingRule(ctx context.Context, id uuid.UUID) (domain.PricingRule, error) {
+	var model pricingRuleModel
+	if err := r.conn(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return domain.PricingRule{}, translateErr(err)
+	}
+	return model.toDomain(), nil
+}
+
+func (r *GormRepository) ListPricingRules(ctx context.Context, hotelID uuid.UUID) ([]domain.PricingRule, error) {
+	var models []pricingRuleModel
+	err := r.conn(ctx).Where("hotel_id = ?", hotelID).Order("created_at DESC").Order("id").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]domain.PricingRule, 0, len(models))
+	for _, m := range models {
+		rules = append(rules, m.toDomain())
+	}
+	return rules, nil
+}
+
+type pricingRuleModel struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	HotelID    uuid.UUID  `gorm:"type:uuid;index"`
+	RoomTypeID *uuid.UUID `gorm:"type:uuid;index"`
+	Kind       string
+	Name       string
+	StartDate  *time.Time
+	EndDate    *time.Time
+	Weekdays   string             // comma separated short weekday names
+	Rate       valueobject.Amount `gorm:"type:numeric"`
+	Percent    float64            `gorm:"type:numeric"`
+	Guests     int
+	MinNights  int
+	CreatedAt  time.Time
+}
+
+func (pricingRuleModel) TableName() string { return "pricing_rules" }
+
+func toPricingRuleModel(r domain.PricingRule) pricingRuleModel {
+	m := pricingRuleModel{
+		ID:        r.ID,
+		HotelID:   r.HotelID,
+		Kind:      r.Rule.Kind,
+		Name:      r.Rule.Name,
+		Weekdays:  strings.Join(valueobject.WeekdayNames(r.Rule.Weekdays), ","),
+		Rate:      r.Rule.Rate,
+		Percent:   r.Rule.Percent,
+		Guests:    r.Rule.Guests,
+		MinNights: r.Rule.MinNights,
+		CreatedAt: r.CreatedAt,
+	}
+	if r.RoomTypeID != uuid.Nil {
+		roomTypeID := r.RoomTypeID
+		m.RoomTypeID = &roomTypeID
+	}
+	if !r.Rule.StartDate.IsZero() {
+		start, end := r.Rule.StartDate, r.Rule.EndDate
+		m.StartDate, m.EndDate = &start, &end
+	}
+	return m
+}
+
+func (m pricingRuleModel) toDomain() domain.PricingRule {
+	rule := valueobject.PricingRule{
+		Kind:      m.Kind,
+		Name:      m.Name,
+		Rate:      m.Rate,
+		Percent:   m.Percent,
+		Guests:    m.Guests,
+		MinNights: m.MinNights,
+	}
+	if m.StartDate != nil && m.EndDate != nil {
+		rule.StartDate, rule.EndDate = m.StartDate.UTC(), m.EndDate.UTC()
+	}
+	if m.Weekdays != "" {
+		// Stored names were written by WeekdayNames and always parse.
+		rule.Weekdays, _ = valueobject.ParseWeekdays(strings.Split(m.Weekdays, ","))
+	}
+	r := domain.PricingRule{ID: m.ID, HotelID: m.HotelID, Rule: rule, CreatedAt: m.CreatedAt}
+	if m.RoomTypeID != nil {
+		r.RoomTypeID = *m.RoomTypeID
+	}
+	return r
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+func (r *GormRepository) CreateRatePlan(ctx context.Context, plan domain.RatePlan) error {
+	model := toRatePlanModel(plan)
+	return r.conn(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) UpdateRatePlan(ctx context.Context, plan domain.RatePlan) error {
+	model := toRatePlanModel(plan)
+	result := r.conn(ctx).Model(&model).Select("*").Omit("created_at").Updates(&model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "rate plan not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) DeleteRatePlan(ctx context.Context, id uuid.UUID) error {
+	result := r.conn(ctx).Delete(&ratePlanModel{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "rate plan not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) GetRatePlan(ctx context.Context, id uuid.UUID) (domain.RatePlan, error) {
+	var model ratePlanModel
+	if err := r.conn(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return domain.RatePlan{}, translateErr(err)
+	}
+	return model.toDomain(), nil
+}
+
+func (r *GormRepository) ListRatePlans(ctx context.Context, roomTypeID uuid.UUID) ([]domain.RatePlan, error) {
+	var models []ratePlanModel
+	err := r.conn(ctx).Where("room_type_id = ?", roomTypeID).Order("created_at").Order("id").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	plans := make([]domain.RatePlan, 0, len(models))
+	for _, m := range models {
+		plans = append(plans, m.toDomain())
+	}
+	return plans, nil
+}
+
+type ratePlanModel struct {
+	ID                 uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RoomTypeID         uuid.UUID `gorm:"type:uuid;index"`
+	Name               string
+	Percent            float64            `gorm:"type:numeric"`
+	PerNight           valueobject.Amount `gorm:"type:numeric"`
+	Inclusions         string             // comma separated
+	CancellationPolicy string
+	PaymentTiming      string
+	CreatedAt          time.Time
+}
+
+func (ratePlanModel) TableName() string { return "rate_plans" }
+
+func toRatePlanModel(p domain.RatePlan) ratePlanModel {
+	return ratePlanModel{
+		ID:                 p.ID,
+		RoomTypeID:         p.RoomTypeID,
+		Name:               p.Plan.Name,
+		Percent:            p.Plan.Percent,
+		PerNight:           p.Plan.PerNight,
+		Inclusions:         strings.Join(p.Plan.Inclusions, ","),
+		CancellationPolicy: p.Plan.CancellationPolicy,
+		PaymentTiming:      p.Plan.PaymentTiming,
+		CreatedAt:          p.CreatedAt,
+	}
+}
+
+func (m ratePlanModel) toDomain() domain.RatePlan {
+	plan := valueobject.RatePlan{
+		Name:               m.Name,
+		Percent:            m.Percent,
+		PerNight:           m.PerNight,
+		CancellationPolicy: m.CancellationPolicy,
+		PaymentTiming:      m.PaymentTiming,
+	}
+	if m.Inclusions != "" {
+		plan.Inclusions = strings.Split(m.Inclusions, ",")
+	}
+	return domain.RatePlan{
+		ID:         m.ID,
+		RoomTypeID: m.RoomTypeID,
+		Plan:       plan,
+		CreatedAt:  m.CreatedAt,
+	}
+}
@@ -11,24 +11,34 @@ import (
 	"github.com/google/uuid"
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
 )
 
 // HTTPStatusClient notifies booking service of payments.
 type HTTPStatusClient struct {
 	baseURL string
+	secret  string
 	client  *http.Client
 }
 
-func NewHTTPStatusClient(baseURL string) domain.BookingStatusUpdater {
-	return &HTTPStatusClient{baseURL: baseURL, client: &http.Client{Timeout: 5 * time.Second}}
+// NewHTTPStatusClient builds a client for the booking service at baseURL. Status changes are an
+// admin action there, so every update carries a payment-service token signed with secret.
+func NewHTTPStatusClient(baseURL, secret string) domain.BookingStatusUpdater {
+	return &HTTPStatusClient{baseURL: baseURL, secret: secret, client: &http.Client{Timeout: 5 * time.Second}}
 }
 
 func (c *HTTPStatusClient) Update(ctx context.Context, bookingID uuid.UUID, status string) error {
 	payload := map[string]string{"status": status}
 	body, _ := json.Marshal(payload)
 	url := fmt.Sprintf("%s/bookings/%s/status", c.baseURL, bookingID.String())
+	ctx, err := middleware.WithServiceToken(ctx, c.secret, "payment-service")
+	if err != nil {
+		return err
+	}
+	token, _ := ctx.Value(middleware.AuthTokenKey).(string)
 	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return err
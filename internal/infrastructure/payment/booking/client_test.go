@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -11,14 +12,18 @@ import (
 )
 
 func TestHTTPGatewayUpdateSuccess(t *testing.T) {
+	var auth string
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
 		w.WriteHeader(http.StatusOK)
 	}))
 	defer srv.Close()
 
-	gw := NewHTTPStatusClient(srv.URL)
+	gw := NewHTTPStatusClient(srv.URL, "secret")
 	err := gw.Update(context.Background(), uuid.New(), "confirmed")
 	require.NoError(t, err)
+	// The booking service only takes status changes from admins and services.
+	require.True(t, strings.HasPrefix(auth, "Bearer ey"))
 }
 
 func TestHTTPGatewayUpdateError(t *testing.T) {
@@ -27,7 +32,7 @@ func TestHTTPGatewayUpdateError(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	gw := NewHTTPStatusClient(srv.URL)
+	gw := NewHTTPStatusClient(srv.URL, "secret")
 	err := gw.Update(context.Background(), uuid.New(), "confirmed")
 	require.Error(t, err)
 }
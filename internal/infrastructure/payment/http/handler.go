@@ -25,6 +25,7 @@ func (h *Handler) CreatePayment(w http.ResponseWriter, r *http.Request) { h.crea
 func (h *Handler) GetPayment(w http.ResponseWriter, r *http.Request)    { h.getPayment(w, r) }
 func (h *Handler) HandleWebhook(w http.ResponseWriter, r *http.Request) { h.handleWebhook(w, r) }
 func (h *Handler) Refund(w http.ResponseWriter, r *http.Request)        { h.refund(w, r) }
+func (h *Handler) Expire(w http.ResponseWriter, r *http.Request)        { h.expire(w, r) }
 func (h *Handler) GetByBooking(w http.ResponseWriter, r *http.Request)  { h.getByBooking(w, r) }
 
 type webhookResponse struct {
@@ -44,16 +45,19 @@ func (h *Handler) Routes() http.Handler {
 	r.Get("/payments/by-booking/{booking_id}", h.getByBooking)
 	r.Post("/payments/webhook", h.handleWebhook)
 	r.Post("/payments/refund", h.refund)
+	r.Post("/payments/{id}/expire", h.expire)
 	return r
 }
 
 // @Summary Initiate payment
+// @Description The payment belongs to the caller. Admins may set user_id to pay on behalf of a user.
 // @Tags Payments
 // @Accept json
 // @Produce json
 // @Param request body dto.PaymentRequest true "Payment payload"
 // @Success 201 {object} dto.PaymentResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /payments [post]
 func (h *Handler) createPayment(w http.ResponseWriter, r *http.Request) {
@@ -115,12 +119,16 @@ func (h *Handler) handleWebhook(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Refund payment
+// @Description Refunds part or all of a paid payment. Refunds add up and may not exceed the amount paid; unpaid and fully refunded payments answer 409. With booking_id instead of payment_id the refund spans the booking payment and its paid supplements.
 // @Tags Payments
 // @Accept json
 // @Produce json
 // @Param request body dto.RefundRequest true "Refund payload"
 // @Success 200 {object} dto.RefundResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /payments/refund [post]
 func (h *Handler) refund(w http.ResponseWriter, r *http.Request) {
@@ -144,6 +152,32 @@ func (h *Handler) refund(w http.ResponseWriter, r *http.Request) {
 	utils.Respond(w, http.StatusOK, "refund created", resource)
 }
 
+// @Summary Expire pending payment (admin)
+// @Tags Payments
+// @Produce json
+// @Param id path string true "Payment ID"
+// @Success 200 {object} dto.PaymentResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /payments/{id}/expire [post]
+func (h *Handler) expire(w http.ResponseWriter, r *http.Request) {
+	paymentID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeError(w, pkgErrors.New("bad_request", "invalid id"))
+		return
+	}
+	pay, err := h.service.Expire(r.Context(), paymentID)
+	if err != nil {
+		writeError(w, pkgErrors.FromError(err))
+		return
+	}
+	resp := assembler.ToResponse(pay)
+	resource := utils.NewResource(resp.ID, "payment", "/api/v1/payments/"+resp.ID, resp)
+	utils.Respond(w, http.StatusOK, "payment expired", resource)
+}
+
 // @Summary Get payment by ID
 // @Tags Payments
 // @Produce json
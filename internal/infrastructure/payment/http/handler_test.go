@@ -14,6 +14,7 @@ import (
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
 	paymenthttp "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/payment/http"
 	paymentuc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/payment"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 func TestPaymentHandler_GetPayment(t *testing.T) {
@@ -74,6 +75,20 @@ func (p *paymentRepoStub) FindByBookingID(ctx context.Context, bookingID uuid.UU
 	}
 	return domain.Payment{}, errors.New("not found")
 }
+func (p *paymentRepoStub) ListByBookingForUpdate(context.Context, uuid.UUID) ([]domain.Payment, error) {
+	return nil, nil
+}
+func (p *paymentRepoStub) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+func (p *paymentRepoStub) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (domain.Payment, error) {
+	return p.FindByID(ctx, id)
+}
+func (p *paymentRepoStub) CreateRefund(context.Context, domain.Refund) error { return nil }
+func (p *paymentRepoStub) SaveRefund(context.Context, domain.Refund) error   { return nil }
+func (p *paymentRepoStub) RefundedAmount(context.Context, uuid.UUID) (valueobject.Amount, error) {
+	return valueobject.Amount{}, nil
+}
 func (p *paymentRepoStub) UpdateStatus(ctx context.Context, id uuid.UUID, status, url, payload, signature string) error {
 	pay := p.store[id]
 	pay.Status = status
@@ -91,7 +106,7 @@ func (p *providerStub) Initiate(ctx context.Context, pay domain.Payment) (domain
 func (p *providerStub) VerifySignature(ctx context.Context, payload, signature string) bool {
 	return true
 }
-func (p *providerStub) Refund(ctx context.Context, pay domain.Payment, reason string) (string, error) {
+func (p *providerStub) Refund(ctx context.Context, pay domain.Payment, amount valueobject.Amount, reason string) (string, error) {
 	return "ref", nil
 }
 
@@ -15,6 +15,7 @@ import (
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
 	paymenthttp "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/payment/http"
 	paymentuc "github.com/ftryyln/hotel-booking-microservices/internal/usecase/payment"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 func TestWebhookHandler_HeaderSignatureFallback(t *testing.T) {
@@ -64,6 +65,20 @@ func (p *paymentRepoStub2) FindByBookingID(_ context.Context, bookingID uuid.UUI
 	}
 	return domain.Payment{}, errors.New("not found")
 }
+func (p *paymentRepoStub2) ListByBookingForUpdate(context.Context, uuid.UUID) ([]domain.Payment, error) {
+	return nil, nil
+}
+func (p *paymentRepoStub2) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+func (p *paymentRepoStub2) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (domain.Payment, error) {
+	return p.FindByID(ctx, id)
+}
+func (p *paymentRepoStub2) CreateRefund(context.Context, domain.Refund) error { return nil }
+func (p *paymentRepoStub2) SaveRefund(context.Context, domain.Refund) error   { return nil }
+func (p *paymentRepoStub2) RefundedAmount(context.Context, uuid.UUID) (valueobject.Amount, error) {
+	return valueobject.Amount{}, nil
+}
 func (p *paymentRepoStub2) UpdateStatus(_ context.Context, id uuid.UUID, status, url, payload, signature string) error {
 	pay := p.store[id]
 	pay.Status = status
@@ -82,7 +97,7 @@ func (p *providerStub2) Initiate(_ context.Context, pay domain.Payment) (domain.
 func (p *providerStub2) VerifySignature(_ context.Context, payload, signature string) bool {
 	return signature == "header-token"
 }
-func (p *providerStub2) Refund(_ context.Context, pay domain.Payment, reason string) (string, error) {
+func (p *providerStub2) Refund(_ context.Context, pay domain.Payment, amount valueobject.Amount, reason string) (string, error) {
 	return "ref", nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // XenditProvider calls Xendit invoice API.
@@ -54,34 +55,39 @@ func NewXenditProvider(apiKey, callbackToken string, opt XenditOptions) *XenditP
 	}
 }
 
+// invoiceRequest carries the amount as an exact JSON number, already rounded to the currency's minor unit.
 type invoiceRequest struct {
-	ExternalID         string  `json:"external_id"`
-	Amount             float64 `json:"amount"`
-	PayerEmail         string  `json:"payer_email,omitempty"`
-	Description        string  `json:"description,omitempty"`
-	SuccessRedirect    string  `json:"success_redirect_url,omitempty"`
-	FailureRedirect    string  `json:"failure_redirect_url,omitempty"`
-	InvoiceDuration    int64   `json:"invoice_duration,omitempty"`
-	Currency           string  `json:"currency,omitempty"`
-	PaymentMethod      string  `json:"payment_method,omitempty"`
-	ShouldSendEmail    bool    `json:"should_send_email,omitempty"`
-	ShouldAuthenticate bool    `json:"should_authenticate,omitempty"`
+	ExternalID         string             `json:"external_id"`
+	Amount             valueobject.Amount `json:"amount"`
+	PayerEmail         string             `json:"payer_email,omitempty"`
+	Description        string             `json:"description,omitempty"`
+	SuccessRedirect    string             `json:"success_redirect_url,omitempty"`
+	FailureRedirect    string             `json:"failure_redirect_url,omitempty"`
+	InvoiceDuration    int64              `json:"invoice_duration,omitempty"`
+	Currency           string             `json:"currency,omitempty"`
+	PaymentMethod      string             `json:"payment_method,omitempty"`
+	ShouldSendEmail    bool               `json:"should_send_email,omitempty"`
+	ShouldAuthenticate bool               `json:"should_authenticate,omitempty"`
 }
 
 type invoiceResponse struct {
-	ID         string  `json:"id"`
-	InvoiceURL string  `json:"invoice_url"`
-	Status     string  `json:"status"`
-	Amount     float64 `json:"amount"`
-	Currency   string  `json:"currency"`
+	ID         string             `json:"id"`
+	InvoiceURL string             `json:"invoice_url"`
+	Status     string             `json:"status"`
+	Amount     valueobject.Amount `json:"amount"`
+	Currency   string             `json:"currency"`
 }
 
 // Initiate creates an invoice and returns updated payment info.
 func (p *XenditProvider) Initiate(ctx context.Context, payment domain.Payment) (domain.Payment, error) {
+	description := payment.Description
+	if description == "" {
+		description = fmt.Sprintf("Booking %s", payment.BookingID)
+	}
 	reqBody := invoiceRequest{
 		ExternalID:      payment.ID.String(),
 		Amount:          payment.Amount,
-		Description:     fmt.Sprintf("Booking %s", payment.BookingID),
+		Description:     description,
 		SuccessRedirect: p.successURL,
 		FailureRedirect: p.failureURL,
 		InvoiceDuration: int64(p.invoiceDuration.Seconds()),
@@ -127,7 +133,7 @@ func (p *XenditProvider) VerifySignature(_ context.Context, _ string, signature
 }
 
 // Refund requests a refund; here we just return a reference after notifying Xendit.
-func (p *XenditProvider) Refund(ctx context.Context, payment domain.Payment, reason string) (string, error) {
+func (p *XenditProvider) Refund(ctx context.Context, payment domain.Payment, amount valueobject.Amount, reason string) (string, error) {
 	// Xendit supports refunds via /credit_card_charges/{id}/refunds and others; for invoice we use a placeholder reference.
 	// Implementing full API requires charge_id. Here we return a deterministic reference and rely on downstream reconciliation.
 	return fmt.Sprintf("xendit-ref-%s", payment.ID.String()), nil
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // XenditMockProvider simulates payment provider.
@@ -32,6 +33,6 @@ func (p *XenditMockProvider) VerifySignature(ctx context.Context, payload, signa
 	return hmac.Equal([]byte(signature), []byte(hex.EncodeToString(h.Sum(nil))))
 }
 
-func (p *XenditMockProvider) Refund(ctx context.Context, payment domain.Payment, reason string) (string, error) {
+func (p *XenditMockProvider) Refund(ctx context.Context, payment domain.Payment, amount valueobject.Amount, reason string) (string, error) {
 	return fmt.Sprintf("rf_%s_%d", payment.ID.String(), time.Now().Unix()), nil
 }
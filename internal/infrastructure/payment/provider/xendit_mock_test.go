@@ -11,11 +11,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 func TestXenditMockProviderInitiate(t *testing.T) {
 	p := &XenditMockProvider{}
-	payment := domain.Payment{ID: uuid.New(), BookingID: uuid.New(), Currency: "IDR", Amount: 1000}
+	payment := domain.Payment{ID: uuid.New(), BookingID: uuid.New(), Currency: "IDR", Amount: valueobject.AmountOf(1000)}
 	res, err := p.Initiate(context.Background(), payment)
 	require.NoError(t, err)
 	require.Equal(t, payment.ID, res.ID)
@@ -33,7 +34,7 @@ func TestXenditMockProviderVerifySignature(t *testing.T) {
 
 func TestXenditMockProviderRefund(t *testing.T) {
 	p := NewXenditMockProvider("secret")
-	ref, err := p.Refund(context.Background(), domain.Payment{ID: uuid.New()}, "reason")
+	ref, err := p.Refund(context.Background(), domain.Payment{ID: uuid.New()}, valueobject.AmountOf(100), "reason")
 	require.NoError(t, err)
 	require.NotEmpty(t, ref)
 }
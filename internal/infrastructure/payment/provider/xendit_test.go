@@ -10,6 +10,7 @@ import (
 	"time"
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 	"github.com/google/uuid"
 )
 
@@ -30,7 +31,7 @@ func TestXenditProvider_Initiate(t *testing.T) {
 
 	opt := XenditOptions{BaseURL: ts.URL, SuccessURL: "https://success", FailureURL: "https://fail", InvoiceDuration: 10 * time.Minute, Client: ts.Client()}
 	prov := NewXenditProvider("secret-key", "token", opt)
-	pay := domain.Payment{ID: uuid.New(), BookingID: uuid.New(), Amount: 100000, Currency: "IDR"}
+	pay := domain.Payment{ID: uuid.New(), BookingID: uuid.New(), Amount: valueobject.AmountOf(100000), Currency: "IDR"}
 
 	got, err := prov.Initiate(context.Background(), pay)
 	if err != nil {
@@ -48,6 +49,9 @@ func TestXenditProvider_Initiate(t *testing.T) {
 	if !strings.Contains(receivedBody, `"external_id":"`+pay.ID.String()+`"`) {
 		t.Fatalf("body missing external_id: %s", receivedBody)
 	}
+	if !strings.Contains(receivedBody, `"amount":100000,`) {
+		t.Fatalf("body missing exact amount: %s", receivedBody)
+	}
 }
 
 func TestXenditProvider_VerifySignature(t *testing.T) {
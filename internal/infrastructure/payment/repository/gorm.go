@@ -7,9 +7,12 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/database"
 	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // GormRepository persists payments using GORM.
@@ -21,12 +24,26 @@ func NewGormRepository(db *gorm.DB) *GormRepository { return &GormRepository{db:
 
 // AutoMigrate ensures schema is present.
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(&paymentModel{})
+	if err := db.AutoMigrate(&paymentModel{}, &refundModel{}); err != nil {
+		return err
+	}
+	// One booking payment per booking; supplements are unrestricted. Declared in SQL because
+	// GORM turns single-column unique index tags into a plain UNIQUE column constraint.
+	return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_booking_primary ON payments(booking_id) WHERE kind = 'booking'").Error
+}
+
+// WithinTransaction runs fn in a transaction bound to its context; nested calls join it.
+func (r *GormRepository) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return database.WithinTransaction(ctx, r.db, fn)
+}
+
+func (r *GormRepository) conn(ctx context.Context) *gorm.DB {
+	return database.Conn(ctx, r.db)
 }
 
 func (r *GormRepository) Create(ctx context.Context, p domain.Payment) error {
 	model := toModel(p)
-	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+	if err := r.conn(ctx).Create(&model).Error; err != nil {
 		return err
 	}
 	return nil
@@ -34,7 +51,18 @@ func (r *GormRepository) Create(ctx context.Context, p domain.Payment) error {
 
 func (r *GormRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Payment, error) {
 	var model paymentModel
-	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+	if err := r.conn(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return domain.Payment{}, translateErr(err)
+	}
+	return toDomain(model), nil
+}
+
+func (r *GormRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (domain.Payment, error) {
+	var model paymentModel
+	err := r.conn(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&model, "id = ?", id).Error
+	if err != nil {
 		return domain.Payment{}, translateErr(err)
 	}
 	return toDomain(model), nil
@@ -42,12 +70,29 @@ func (r *GormRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Pay
 
 func (r *GormRepository) FindByBookingID(ctx context.Context, bookingID uuid.UUID) (domain.Payment, error) {
 	var model paymentModel
-	if err := r.db.WithContext(ctx).First(&model, "booking_id = ?", bookingID).Error; err != nil {
+	if err := r.conn(ctx).First(&model, "booking_id = ? AND kind = ?", bookingID, domain.KindBooking).Error; err != nil {
 		return domain.Payment{}, translateErr(err)
 	}
 	return toDomain(model), nil
 }
 
+func (r *GormRepository) ListByBookingForUpdate(ctx context.Context, bookingID uuid.UUID) ([]domain.Payment, error) {
+	var models []paymentModel
+	err := r.conn(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("booking_id = ?", bookingID).
+		Order("created_at, id").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	payments := make([]domain.Payment, 0, len(models))
+	for _, m := range models {
+		payments = append(payments, toDomain(m))
+	}
+	return payments, nil
+}
+
 func (r *GormRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status, paymentURL, rawPayload, signature string) error {
 	updates := map[string]any{"status": status}
 	if paymentURL != "" {
@@ -59,7 +104,7 @@ func (r *GormRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status,
 	if signature != "" {
 		updates["webhook_signature"] = signature
 	}
-	res := r.db.WithContext(ctx).Model(&paymentModel{}).Where("id = ?", id).Updates(updates)
+	res := r.conn(ctx).Model(&paymentModel{}).Where("id = ?", id).Updates(updates)
 	if err := res.Error; err != nil {
 		return err
 	}
@@ -70,13 +115,16 @@ func (r *GormRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status,
 }
 
 type paymentModel struct {
-	ID               uuid.UUID `gorm:"type:uuid;primaryKey"`
-	BookingID        uuid.UUID `gorm:"type:uuid;uniqueIndex"`
-	Amount           float64   `gorm:"type:numeric"`
+	ID               uuid.UUID          `gorm:"type:uuid;primaryKey"`
+	BookingID        uuid.UUID          `gorm:"type:uuid;index"`
+	UserID           uuid.UUID          `gorm:"type:uuid;index"`
+	Kind             string             `gorm:"not null;default:booking"`
+	Amount           valueobject.Amount `gorm:"type:numeric"`
 	Currency         string
 	Status           string `gorm:"index"`
 	Provider         string
 	PaymentURL       string
+	Description      string `gorm:"type:text"`
 	WebhookPayload   string `gorm:"type:text"`
 	WebhookSignature string
 	CreatedAt        time.Time `gorm:"column:created_at;autoCreateTime"`
@@ -88,11 +136,14 @@ func toModel(p domain.Payment) paymentModel {
 	return paymentModel{
 		ID:               p.ID,
 		BookingID:        p.BookingID,
+		UserID:           p.UserID,
+		Kind:             p.Kind,
 		Amount:           p.Amount,
 		Currency:         p.Currency,
 		Status:           p.Status,
 		Provider:         p.Provider,
 		PaymentURL:       p.PaymentURL,
+		Description:      p.Description,
 		WebhookPayload:   p.WebhookPayload,
 		WebhookSignature: p.WebhookSignature,
 		CreatedAt:        p.CreatedAt,
@@ -103,11 +154,14 @@ func toDomain(m paymentModel) domain.Payment {
 	return domain.Payment{
 		ID:               m.ID,
 		BookingID:        m.BookingID,
+		UserID:           m.UserID,
+		Kind:             m.Kind,
 		Amount:           m.Amount,
 		Currency:         m.Currency,
 		Status:           m.Status,
 		Provider:         m.Provider,
 		PaymentURL:       m.PaymentURL,
+		Description:      m.Description,
 		WebhookPayload:   m.WebhookPayload,
 		WebhookSignature: m.WebhookSignature,
 		CreatedAt:        m.CreatedAt,
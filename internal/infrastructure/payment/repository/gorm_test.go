@@ -11,6 +11,7 @@ import (
 
 	"github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
 	repo "github.com/ftryyln/hotel-booking-microservices/internal/infrastructure/payment/repository"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 func TestPaymentGormRepository(t *testing.T) {
@@ -21,7 +22,7 @@ func TestPaymentGormRepository(t *testing.T) {
 	p := payment.Payment{
 		ID:        uuid.New(),
 		BookingID: uuid.New(),
-		Amount:    100,
+		Amount:    valueobject.AmountOf(100),
 		Currency:  "IDR",
 		Status:    "pending",
 		Provider:  "mock",
@@ -46,3 +47,70 @@ func newTestDB(t *testing.T) *gorm.DB {
 	require.NoError(t, err)
 	return db
 }
+
+func TestPaymentGormRepositorySupplements(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+
+	bookingID := uuid.New()
+	primary := payment.Payment{ID: uuid.New(), BookingID: bookingID, Kind: payment.KindBooking, Amount: valueobject.AmountOf(100), Currency: "IDR", Status: "paid", Provider: "mock"}
+	supplement := payment.Payment{ID: uuid.New(), BookingID: bookingID, Kind: payment.KindSupplement, Amount: valueobject.AmountOf(50), Currency: "IDR", Status: "pending", Provider: "mock"}
+	require.NoError(t, r.Create(context.Background(), supplement))
+	require.NoError(t, r.Create(context.Background(), primary))
+
+	found, err := r.FindByBookingID(context.Background(), bookingID)
+	require.NoError(t, err)
+	require.Equal(t, primary.ID, found.ID)
+
+	require.NoError(t, r.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		all, err := r.ListByBookingForUpdate(ctx, bookingID)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		require.Equal(t, supplement.ID, all[0].ID)
+		return nil
+	}))
+
+	duplicate := primary
+	duplicate.ID = uuid.New()
+	require.Error(t, r.Create(context.Background(), duplicate))
+}
+
+func TestPaymentGormRepositoryRefunds(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, repo.AutoMigrate(db))
+	r := repo.NewGormRepository(db)
+	ctx := context.Background()
+
+	p := payment.Payment{ID: uuid.New(), BookingID: uuid.New(), Amount: valueobject.AmountOf(300), Currency: "IDR", Status: "paid", Provider: "mock"}
+	require.NoError(t, r.Create(ctx, p))
+
+	paid := payment.Refund{ID: uuid.New(), PaymentID: p.ID, Amount: valueobject.AmountOf(100), Reason: "first", Status: payment.RefundPending}
+	failed := payment.Refund{ID: uuid.New(), PaymentID: p.ID, Amount: valueobject.AmountOf(150), Reason: "second", Status: payment.RefundPending}
+	require.NoError(t, r.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := r.FindByIDForUpdate(ctx, p.ID); err != nil {
+			return err
+		}
+		if err := r.CreateRefund(ctx, paid); err != nil {
+			return err
+		}
+		return r.CreateRefund(ctx, failed)
+	}))
+
+	// Pending refunds already count against the payment.
+	refunded, err := r.RefundedAmount(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(250), refunded)
+
+	paid.Status, paid.Reference = payment.RefundSucceeded, "ref-1"
+	require.NoError(t, r.SaveRefund(ctx, paid))
+	failed.Status = payment.RefundFailed
+	require.NoError(t, r.SaveRefund(ctx, failed))
+	refunded, err = r.RefundedAmount(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(100), refunded)
+
+	require.Error(t, r.SaveRefund(ctx, payment.Refund{ID: uuid.New(), Status: payment.RefundFailed}))
+	_, err = r.FindByIDForUpdate(ctx, uuid.New())
+	require.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+func (r *GormRepository) CreateRefund(ctx context.Context, ref domain.Refund) error {
+	model := toRefundModel(ref)
+	return r.conn(ctx).Create(&model).Error
+}
+
+func (r *GormRepository) SaveRefund(ctx context.Context, ref domain.Refund) error {
+	res := r.conn(ctx).Model(&refundModel{}).Where("id = ?", ref.ID).Updates(map[string]any{
+		"status":    ref.Status,
+		"reference": ref.Reference,
+	})
+	if err := res.Error; err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return pkgErrors.New("not_found", "refund not found")
+	}
+	return nil
+}
+
+func (r *GormRepository) RefundedAmount(ctx context.Context, paymentID uuid.UUID) (valueobject.Amount, error) {
+	var models []refundModel
+	err := r.conn(ctx).
+		Where("payment_id = ? AND status <> ?", paymentID, domain.RefundFailed).
+		Find(&models).Error
+	if err != nil {
+		return valueobject.Amount{}, err
+	}
+	var total valueobject.Amount
+	for _, m := range models {
+		total = total.Add(m.Amount)
+	}
+	return total, nil
+}
+
+type refundModel struct {
+	ID        uuid.UUID          `gorm:"type:uuid;primaryKey"`
+	PaymentID uuid.UUID          `gorm:"type:uuid;index"`
+	Amount    valueobject.Amount `gorm:"type:numeric"`
+	Reason    string
+	Status    string `gorm:"not null"`
+	Reference string
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (refundModel) TableName() string { return "refunds" }
+
+func toRefundModel(ref domain.Refund) refundModel {
+	return refundModel{
+		ID:        ref.ID,
+		PaymentID: ref.PaymentID,
+		Amount:    ref.Amount,
+		Reason:    ref.Reason,
+		Status:    ref.Status,
+		Reference: ref.Reference,
+		CreatedAt: ref.CreatedAt,
+	}
+}
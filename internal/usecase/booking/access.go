@@ -0,0 +1,39 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/audit"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+)
+
+// findOwned loads a booking the caller on ctx may access: its owner or an admin.
+// Other users' bookings are reported as missing so their ids cannot be probed.
+func (s *Service) findOwned(ctx context.Context, id uuid.UUID) (domain.Booking, error) {
+	bk, err := s.repo.FindByID(ctx, id)
+	if err == sql.ErrNoRows || (err == nil && !audit.CanAccess(ctx, bk.UserID)) {
+		return domain.Booking{}, errors.New("not_found", "booking not found")
+	}
+	return bk, err
+}
+
+// recordOnBehalf audits an admin changing a booking that belongs to another user.
+func (s *Service) recordOnBehalf(ctx context.Context, action string, bk domain.Booking) error {
+	return audit.Record(ctx, s.audit, "booking."+action, "booking", bk.ID, bk.UserID)
+}
+
+// customerID returns the caller's user id when the caller is a customer, whose reads are limited
+// to their own bookings. Admins, services and background jobs see every booking.
+func customerID(ctx context.Context) (uuid.UUID, bool) {
+	claims, ok := middleware.ClaimsFrom(ctx)
+	if !ok || audit.Privileged(claims) {
+		return uuid.Nil, false
+	}
+	id, _ := uuid.Parse(claims.UserID)
+	return id, true
+}
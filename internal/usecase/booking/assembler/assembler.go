@@ -1,22 +1,108 @@
 package assembler
 
 import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	hdomain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
 	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // CreateCommand represents inbound booking creation intent.
+// RoomTypeID and Guests book a single room; Items books a group of rooms instead.
 type CreateCommand struct {
 	UserID     uuid.UUID
 	RoomTypeID uuid.UUID
+	RatePlanID uuid.UUID // uuid.Nil books the room type at its own terms
 	CheckIn    time.Time
 	CheckOut   time.Time
 	Guests     int
+	Items      []ItemCommand
+
+	Guest           valueobject.GuestDetails
+	ArrivalTime     string
+	SpecialRequests string
+	PromoCode       string // normalized, empty when none
+
+	DisplayCurrency string // empty when none was asked for
+	ChargeCurrency  string // empty when none was asked for
+
+	QuoteToken string    // keeps the price of an earlier quote, empty when none
+	HoldID     uuid.UUID // books rooms an inventory hold keeps aside, uuid.Nil when none
+}
+
+// ItemCommand requests one room of a group booking.
+type ItemCommand struct {
+	RoomTypeID uuid.UUID
+	RatePlanID uuid.UUID
+	Guests     int
+}
+
+// TotalGuests returns the number of guests across all requested rooms.
+func (c CreateCommand) TotalGuests() int {
+	total := 0
+	for _, item := range c.LineItems() {
+		total += item.Guests
+	}
+	return total
+}
+
+// LineItems returns the requested rooms, treating a single-room command as a group of one.
+func (c CreateCommand) LineItems() []ItemCommand {
+	if len(c.Items) > 0 {
+		return c.Items
+	}
+	return []ItemCommand{{RoomTypeID: c.RoomTypeID, RatePlanID: c.RatePlanID, Guests: c.Guests}}
+}
+
+// ModifyCommand represents a change of dates and/or guest count; zero values keep the current terms.
+type ModifyCommand struct {
+	CheckIn         time.Time
+	CheckOut        time.Time
+	Guests          int
+	ExpectedVersion int // from If-Match; zero skips the check
+}
+
+// ModificationResult describes a modified booking and how the price difference was settled.
+type ModificationResult struct {
+	Booking    domain.Booking
+	PriceDelta valueobject.Amount
+	Payment    domain.PaymentResult // supplementary payment, empty unless the price went up
+}
+
+// CancellationResult describes a cancelled booking and the refund its policy allowed.
+type CancellationResult struct {
+	Booking      domain.Booking
+	Policy       string
+	RefundAmount valueobject.Amount
+}
+
+// CheckpointCommand carries a front desk lifecycle action.
+type CheckpointCommand struct {
+	Action          string
+	RoomID          uuid.UUID // optional explicit room for check-in
+	ExpectedVersion int       // from If-Match; zero skips the check
+}
+
+// FromCheckpointRequest validates the checkpoint payload.
+func FromCheckpointRequest(req dto.CheckpointRequest) (CheckpointCommand, error) {
+	cmd := CheckpointCommand{Action: req.Action}
+	if req.RoomID != "" {
+		roomID, err := uuid.Parse(req.RoomID)
+		if err != nil {
+			return CheckpointCommand{}, pkgErrors.New("bad_request", "invalid room id")
+		}
+		cmd.RoomID = roomID
+	}
+	return cmd, nil
 }
 
 // ToResponse maps domain booking plus optional payment info to response DTO.
@@ -29,6 +115,52 @@ func ToResponse(b domain.Booking, payment domain.PaymentResult) dto.BookingRespo
 		TotalPrice:  b.TotalPrice,
 		CheckIn:     b.CheckIn,
 		CheckOut:    b.CheckOut,
+		Version:     b.Version,
+	}
+	if b.RoomID != uuid.Nil {
+		resp.RoomID = b.RoomID.String()
+	}
+	if !b.Guest.IsZero() {
+		resp.Guest = &dto.GuestDetails{
+			Name:             b.Guest.Name,
+			Phone:            b.Guest.Phone,
+			Nationality:      b.Guest.Nationality,
+			AdditionalGuests: b.Guest.AdditionalGuests,
+		}
+	}
+	resp.ArrivalTime = b.ArrivalTime
+	resp.SpecialRequests = b.SpecialRequests
+	resp.PromoCode = b.PromoCode
+	resp.PaymentTiming = valueobject.PayNow
+	if b.PaysAtHotel() {
+		resp.PaymentTiming = valueobject.PayAtHotel
+	}
+	resp.Lines = ToPriceLines(b.PriceLines())
+	resp.Currency = b.PriceCurrency()
+	if rate := b.DisplayRate(); !rate.IsIdentity() {
+		resp.Display = &dto.DisplayPrice{
+			Currency:     rate.Quote,
+			TotalPrice:   rate.Apply(b.TotalPrice),
+			Lines:        ToPriceLines(domain.ConvertLines(b.PriceLines(), b.TotalPrice, rate)),
+			ExchangeRate: ToExchangeRateResponse(rate),
+		}
+	}
+	charge := b.Charge(b.TotalPrice)
+	resp.ChargeCurrency = charge.Currency
+	resp.ChargeTotal = charge.Amount
+	for _, item := range b.LineItems() {
+		itemResp := dto.BookingItemResponse{
+			ID:                 item.ID.String(),
+			RoomTypeID:         item.RoomTypeID.String(),
+			CancellationPolicy: item.CancellationPolicy,
+			Guests:             item.Guests,
+			Status:             item.Status,
+			Price:              ToPriceResponse(item.Price),
+		}
+		if item.RatePlanID != uuid.Nil {
+			itemResp.RatePlanID = item.RatePlanID.String()
+		}
+		resp.Items = append(resp.Items, itemResp)
 	}
 	if payment.ID != uuid.Nil {
 		resp.Payment = &dto.PaymentResponse{
@@ -41,15 +173,81 @@ func ToResponse(b domain.Booking, payment domain.PaymentResult) dto.BookingRespo
 	return resp
 }
 
+// ToQuoteResponse maps a quote and its token to DTO, itemised like the booking it quotes.
+func ToQuoteResponse(q domain.Quote, token string) dto.QuoteResponse {
+	b := ToResponse(q.Booking(), domain.PaymentResult{})
+	for i := range b.Items {
+		b.Items[i].ID = ""
+	}
+	return dto.QuoteResponse{
+		Token:          token,
+		ExpiresAt:      q.ExpiresAt,
+		CheckIn:        b.CheckIn,
+		CheckOut:       b.CheckOut,
+		Guests:         b.Guests,
+		TotalNights:    b.TotalNights,
+		TotalPrice:     b.TotalPrice,
+		Items:          b.Items,
+		PromoCode:      b.PromoCode,
+		PaymentTiming:  b.PaymentTiming,
+		Lines:          b.Lines,
+		Currency:       b.Currency,
+		Display:        b.Display,
+		ChargeCurrency: b.ChargeCurrency,
+		ChargeTotal:    b.ChargeTotal,
+	}
+}
+
+// ToPriceResponse maps a line item price and its nights to DTO.
+func ToPriceResponse(p domain.PriceBreakdown) dto.PriceBreakdownResponse {
+	resp := dto.PriceBreakdownResponse{
+		Base:           p.Base,
+		GuestSurcharge: p.GuestSurcharge,
+		Discount:       p.Discount,
+		Promo:          p.Promo,
+		Total:          p.Total,
+	}
+	for _, c := range p.Charges {
+		resp.Charges = append(resp.Charges, dto.ChargeResponse{
+			Kind:      c.Kind,
+			Name:      c.Name,
+			Amount:    c.Amount,
+			Inclusive: c.Inclusive,
+		})
+	}
+	for _, n := range p.Nights {
+		resp.Nights = append(resp.Nights, dto.NightPriceResponse{
+			Date:      dto.Date{Time: n.Date},
+			Rate:      n.Rate,
+			Surcharge: n.Surcharge,
+			Discount:  n.Discount,
+			Total:     n.Total,
+			Rules:     n.Rules,
+		})
+	}
+	return resp
+}
+
+// ToPriceLines maps itemised price lines to DTOs.
+func ToPriceLines(lines []domain.PriceLine) []dto.PriceLine {
+	out := make([]dto.PriceLine, 0, len(lines))
+	for _, l := range lines {
+		out = append(out, dto.PriceLine(l))
+	}
+	return out
+}
+
 // FromRequest validates incoming DTO to command.
 func FromRequest(req dto.BookingRequest) (CreateCommand, error) {
-	userID, err := uuid.Parse(req.UserID)
-	if err != nil {
-		return CreateCommand{}, pkgErrors.New("bad_request", "invalid user id")
-	}
-	roomTypeID, err := uuid.Parse(req.RoomTypeID)
-	if err != nil {
-		return CreateCommand{}, pkgErrors.New("bad_request", "invalid room type id")
+	// The owner comes from the caller's token; user_id only lets admins book on behalf of a user.
+	var (
+		userID uuid.UUID
+		err    error
+	)
+	if req.UserID != "" {
+		if userID, err = uuid.Parse(req.UserID); err != nil {
+			return CreateCommand{}, pkgErrors.New("bad_request", "invalid user id")
+		}
 	}
 	if req.CheckIn.IsZero() || req.CheckOut.IsZero() {
 		return CreateCommand{}, pkgErrors.New("bad_request", "date required")
@@ -57,15 +255,479 @@ func FromRequest(req dto.BookingRequest) (CreateCommand, error) {
 	if !req.CheckIn.Time.Before(req.CheckOut.Time) {
 		return CreateCommand{}, pkgErrors.New("bad_request", "check_in must be before check_out")
 	}
-	guests := req.Guests
+	cmd := CreateCommand{
+		UserID:   userID,
+		CheckIn:  req.CheckIn.Time,
+		CheckOut: req.CheckOut.Time,
+	}
+
+	if len(req.Items) > 0 {
+		if req.RoomTypeID != "" || req.RatePlanID != "" {
+			return CreateCommand{}, pkgErrors.New("bad_request", "room_type_id and items cannot be combined")
+		}
+		for _, item := range req.Items {
+			ic, err := fromItemRequest(item.RoomTypeID, item.RatePlanID, item.Guests)
+			if err != nil {
+				return CreateCommand{}, err
+			}
+			cmd.Items = append(cmd.Items, ic)
+		}
+	} else {
+		ic, err := fromItemRequest(req.RoomTypeID, req.RatePlanID, req.Guests)
+		if err != nil {
+			return CreateCommand{}, err
+		}
+		cmd.RoomTypeID = ic.RoomTypeID
+		cmd.RatePlanID = ic.RatePlanID
+		cmd.Guests = ic.Guests
+	}
+
+	if req.Guest != nil {
+		cmd.Guest, err = valueobject.NewGuestDetails(req.Guest.Name, req.Guest.Phone, req.Guest.Nationality, req.Guest.AdditionalGuests)
+		if err != nil {
+			return CreateCommand{}, err
+		}
+		if cmd.Guest.Headcount() > cmd.TotalGuests() {
+			return CreateCommand{}, pkgErrors.New("bad_request", "more guest names than guests")
+		}
+	}
+	if cmd.ArrivalTime, err = valueobject.NormalizeArrivalTime(req.ArrivalTime); err != nil {
+		return CreateCommand{}, err
+	}
+	if cmd.SpecialRequests, err = valueobject.NormalizeSpecialRequests(req.SpecialRequests); err != nil {
+		return CreateCommand{}, err
+	}
+	cmd.PromoCode = domain.NormalizePromoCode(req.PromoCode)
+	if req.DisplayCurrency != "" {
+		if cmd.DisplayCurrency, err = valueobject.ParseCurrency(req.DisplayCurrency); err != nil {
+			return CreateCommand{}, err
+		}
+	}
+	if req.ChargeCurrency != "" {
+		if cmd.ChargeCurrency, err = valueobject.ParseCurrency(req.ChargeCurrency); err != nil {
+			return CreateCommand{}, err
+		}
+	}
+	cmd.QuoteToken = strings.TrimSpace(req.QuoteToken)
+	if req.HoldID != "" {
+		if cmd.HoldID, err = uuid.Parse(req.HoldID); err != nil {
+			return CreateCommand{}, pkgErrors.New("bad_request", "invalid hold id")
+		}
+	}
+	return cmd, nil
+}
+
+// fromItemRequest validates one requested room; missing guests default to one.
+func fromItemRequest(roomTypeID, ratePlanID string, guests int) (ItemCommand, error) {
+	id, err := uuid.Parse(roomTypeID)
+	if err != nil {
+		return ItemCommand{}, pkgErrors.New("bad_request", "invalid room type id")
+	}
+	var planID uuid.UUID
+	if ratePlanID != "" {
+		if planID, err = uuid.Parse(ratePlanID); err != nil {
+			return ItemCommand{}, pkgErrors.New("bad_request", "invalid rate plan id")
+		}
+	}
 	if guests <= 0 {
 		guests = 1
 	}
-	return CreateCommand{
-		UserID:     userID,
-		RoomTypeID: roomTypeID,
-		CheckIn:    req.CheckIn.Time,
-		CheckOut:   req.CheckOut.Time,
-		Guests:     guests,
-	}, nil
+	return ItemCommand{RoomTypeID: id, RatePlanID: planID, Guests: guests}, nil
+}
+
+// ToNoteResponse maps a staff note to its DTO.
+func ToNoteResponse(bookingID uuid.UUID, n domain.StaffNote) dto.BookingNoteResponse {
+	resp := dto.BookingNoteResponse{
+		ID:        n.ID.String(),
+		BookingID: bookingID.String(),
+		Body:      n.Body,
+		CreatedAt: n.CreatedAt,
+	}
+	if n.AuthorID != uuid.Nil {
+		resp.AuthorID = n.AuthorID.String()
+	}
+	return resp
+}
+
+// FromModifyRequest validates a modification payload.
+func FromModifyRequest(req dto.ModifyBookingRequest) (ModifyCommand, error) {
+	if req.CheckIn.IsZero() != req.CheckOut.IsZero() {
+		return ModifyCommand{}, pkgErrors.New("bad_request", "check_in and check_out must be changed together")
+	}
+	if req.Guests < 0 {
+		return ModifyCommand{}, pkgErrors.New("bad_request", "invalid guests")
+	}
+	if req.CheckIn.IsZero() && req.Guests == 0 {
+		return ModifyCommand{}, pkgErrors.New("bad_request", "nothing to modify")
+	}
+	if !req.CheckIn.IsZero() && !req.CheckIn.Time.Before(req.CheckOut.Time) {
+		return ModifyCommand{}, pkgErrors.New("bad_request", "check_in must be before check_out")
+	}
+	return ModifyCommand{CheckIn: req.CheckIn.Time, CheckOut: req.CheckOut.Time, Guests: req.Guests}, nil
+}
+
+// ToModificationResponse maps a modification result to its DTO.
+func ToModificationResponse(res ModificationResult) dto.BookingModificationResponse {
+	return dto.BookingModificationResponse{
+		BookingResponse: ToResponse(res.Booking, res.Payment),
+		PriceDelta:      res.PriceDelta,
+	}
+}
+
+// ToCancellationResponse maps a cancellation result to its DTO.
+func ToCancellationResponse(res CancellationResult) dto.BookingCancellationResponse {
+	return dto.BookingCancellationResponse{
+		BookingResponse:    ToResponse(res.Booking, domain.PaymentResult{}),
+		CancellationPolicy: res.Policy,
+		RefundAmount:       res.RefundAmount,
+	}
+}
+
+// AvailabilityQuery represents an availability search for a stay.
+type AvailabilityQuery struct {
+	HotelID  uuid.UUID
+	CheckIn  time.Time
+	CheckOut time.Time
+	Guests   int
+}
+
+// RoomAvailability is a room type that can host the whole stay.
+type RoomAvailability struct {
+	RoomType    hdomain.RoomType
+	RoomsLeft   int
+	TotalNights int
+	QuotedTotal valueobject.Amount // at the room type's own terms
+	RatePlans   []RatePlanQuote
+	Nights      []domain.NightAvailability
+}
+
+// RatePlanQuote is the price of the stay under one rate plan.
+type RatePlanQuote struct {
+	Plan        hdomain.RatePlan
+	QuotedTotal valueobject.Amount
+}
+
+// FromAvailabilityParams validates raw query parameters into an AvailabilityQuery.
+func FromAvailabilityParams(checkIn, checkOut, guests, hotelID string) (AvailabilityQuery, error) {
+	var q AvailabilityQuery
+	if checkIn == "" || checkOut == "" {
+		return q, pkgErrors.New("bad_request", "check_in and check_out required")
+	}
+	var err error
+	if q.CheckIn, err = time.Parse("2006-01-02", checkIn); err != nil {
+		return q, pkgErrors.New("bad_request", "invalid check_in")
+	}
+	if q.CheckOut, err = time.Parse("2006-01-02", checkOut); err != nil {
+		return q, pkgErrors.New("bad_request", "invalid check_out")
+	}
+	if !q.CheckIn.Before(q.CheckOut) {
+		return q, pkgErrors.New("bad_request", "check_in must be before check_out")
+	}
+	q.Guests = 1
+	if guests != "" {
+		if q.Guests, err = strconv.Atoi(guests); err != nil || q.Guests <= 0 {
+			return q, pkgErrors.New("bad_request", "invalid guests")
+		}
+	}
+	if hotelID != "" {
+		if q.HotelID, err = uuid.Parse(hotelID); err != nil {
+			return q, pkgErrors.New("bad_request", "invalid hotel id")
+		}
+	}
+	return q, nil
+}
+
+// ToAvailabilityResponse maps a room availability result to its DTO.
+func ToAvailabilityResponse(a RoomAvailability) dto.AvailabilityResponse {
+	nights := make([]dto.NightAvailabilityResponse, 0, len(a.Nights))
+	for _, n := range a.Nights {
+		nights = append(nights, dto.NightAvailabilityResponse{
+			Date:      dto.Date{Time: n.Date},
+			Available: n.Available,
+		})
+	}
+	return dto.AvailabilityResponse{
+		RoomTypeID:  a.RoomType.ID.String(),
+		HotelID:     a.RoomType.HotelID.String(),
+		Name:        a.RoomType.Name,
+		Capacity:    a.RoomType.Capacity,
+		RoomsLeft:   a.RoomsLeft,
+		TotalNights: a.TotalNights,
+		QuotedTotal: a.QuotedTotal,
+		RatePlans:   ratePlanQuotes(a.RatePlans),
+		Nights:      nights,
+	}
+}
+
+func ratePlanQuotes(quotes []RatePlanQuote) []dto.RatePlanQuoteResponse {
+	var out []dto.RatePlanQuoteResponse
+	for _, q := range quotes {
+		out = append(out, dto.RatePlanQuoteResponse{
+			RatePlanID:         q.Plan.ID.String(),
+			Name:               q.Plan.Plan.Name,
+			Inclusions:         q.Plan.Plan.Inclusions,
+			CancellationPolicy: q.Plan.Plan.CancellationPolicy,
+			PaymentTiming:      q.Plan.Plan.PaymentTiming,
+			QuotedTotal:        q.QuotedTotal,
+		})
+	}
+	return out
+}
+
+// ToHistoryResponse maps a booking history entry to its DTO.
+func ToHistoryResponse(e domain.HistoryEntry) dto.BookingHistoryEntryResponse {
+	return dto.BookingHistoryEntryResponse{
+		ID:         e.ID.String(),
+		BookingID:  e.BookingID.String(),
+		EventType:  e.EventType,
+		ActorID:    e.Actor.ID,
+		ActorRole:  e.Actor.Role,
+		OccurredAt: e.OccurredAt,
+		Payload:    json.RawMessage(e.Payload),
+	}
+}
+
+// ToOutboxResponse maps an outbox message to its DTO.
+func ToOutboxResponse(m domain.OutboxMessage) dto.OutboxMessageResponse {
+	resp := dto.OutboxMessageResponse{
+		ID:            m.ID.String(),
+		AggregateID:   m.AggregateID.String(),
+		EventType:     m.EventType,
+		Payload:       json.RawMessage(m.Payload),
+		Status:        m.Status,
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     m.LastError,
+		CreatedAt:     m.CreatedAt,
+	}
+	if !m.DeliveredAt.IsZero() {
+		deliveredAt := m.DeliveredAt
+		resp.DeliveredAt = &deliveredAt
+	}
+	return resp
+}
+
+// WaitlistCommand asks to wait for a room type to free up for a stay.
+type WaitlistCommand struct {
+	UserID     uuid.UUID
+	RoomTypeID uuid.UUID
+	CheckIn    time.Time
+	CheckOut   time.Time
+	Guests     int
+}
+
+// FromWaitlistRequest validates a waitlist payload; missing guests default to one.
+func FromWaitlistRequest(req dto.WaitlistRequest) (WaitlistCommand, error) {
+	var cmd WaitlistCommand
+	if req.UserID != "" {
+		userID, err := uuid.Parse(req.UserID)
+		if err != nil {
+			return WaitlistCommand{}, pkgErrors.New("bad_request", "invalid user id")
+		}
+		cmd.UserID = userID
+	}
+	item, err := fromItemRequest(req.RoomTypeID, "", req.Guests)
+	if err != nil {
+		return WaitlistCommand{}, err
+	}
+	if req.CheckIn.IsZero() || req.CheckOut.IsZero() {
+		return WaitlistCommand{}, pkgErrors.New("bad_request", "date required")
+	}
+	if !req.CheckIn.Time.Before(req.CheckOut.Time) {
+		return WaitlistCommand{}, pkgErrors.New("bad_request", "check_in must be before check_out")
+	}
+	cmd.RoomTypeID = item.RoomTypeID
+	cmd.Guests = item.Guests
+	cmd.CheckIn = req.CheckIn.Time
+	cmd.CheckOut = req.CheckOut.Time
+	return cmd, nil
+}
+
+// ToWaitlistResponse maps a waitlist entry to its DTO.
+func ToWaitlistResponse(e domain.WaitlistEntry) dto.WaitlistResponse {
+	resp := dto.WaitlistResponse{
+		ID:         e.ID.String(),
+		UserID:     e.UserID.String(),
+		RoomTypeID: e.RoomTypeID.String(),
+		CheckIn:    e.CheckIn,
+		CheckOut:   e.CheckOut,
+		Guests:     e.Guests,
+		Status:     e.Status,
+		CreatedAt:  e.CreatedAt,
+	}
+	if e.BookingID != uuid.Nil {
+		resp.BookingID = e.BookingID.String()
+	}
+	if !e.OfferExpiresAt.IsZero() {
+		expiresAt := e.OfferExpiresAt
+		resp.OfferExpiresAt = &expiresAt
+	}
+	return resp
+}
+
+// HoldCommand asks to keep rooms of a room type aside for a stay.
+type HoldCommand struct {
+	UserID     uuid.UUID
+	SessionID  string
+	RoomTypeID uuid.UUID
+	CheckIn    time.Time
+	CheckOut   time.Time
+	Rooms      int
+	Duration   time.Duration // zero keeps the rooms for domain.DefaultInventoryHold
+}
+
+// FromHoldRequest validates a hold payload; missing rooms default to one.
+func FromHoldRequest(req dto.HoldRequest) (HoldCommand, error) {
+	var cmd HoldCommand
+	if req.UserID != "" {
+		userID, err := uuid.Parse(req.UserID)
+		if err != nil {
+			return HoldCommand{}, pkgErrors.New("bad_request", "invalid user id")
+		}
+		cmd.UserID = userID
+	}
+	roomTypeID, err := uuid.Parse(req.RoomTypeID)
+	if err != nil {
+		return HoldCommand{}, pkgErrors.New("bad_request", "invalid room type id")
+	}
+	if req.CheckIn.IsZero() || req.CheckOut.IsZero() {
+		return HoldCommand{}, pkgErrors.New("bad_request", "date required")
+	}
+	if !req.CheckIn.Time.Before(req.CheckOut.Time) {
+		return HoldCommand{}, pkgErrors.New("bad_request", "check_in must be before check_out")
+	}
+	if req.Rooms < 0 || req.Minutes < 0 {
+		return HoldCommand{}, pkgErrors.New("bad_request", "rooms and minutes must not be negative")
+	}
+	if len(req.SessionID) > 255 {
+		return HoldCommand{}, pkgErrors.New("bad_request", "session_id is too long")
+	}
+	cmd.SessionID = strings.TrimSpace(req.SessionID)
+	cmd.RoomTypeID = roomTypeID
+	cmd.CheckIn = req.CheckIn.Time
+	cmd.CheckOut = req.CheckOut.Time
+	cmd.Rooms = req.Rooms
+	if cmd.Rooms == 0 {
+		cmd.Rooms = 1
+	}
+	cmd.Duration = time.Duration(req.Minutes) * time.Minute
+	return cmd, nil
+}
+
+// ToHoldResponse maps an inventory hold to its DTO.
+func ToHoldResponse(h domain.InventoryHold) dto.HoldResponse {
+	resp := dto.HoldResponse{
+		ID:         h.ID.String(),
+		UserID:     h.UserID.String(),
+		SessionID:  h.SessionID,
+		RoomTypeID: h.RoomTypeID.String(),
+		CheckIn:    h.CheckIn,
+		CheckOut:   h.CheckOut,
+		Rooms:      h.Rooms,
+		Status:     h.Status,
+		ExpiresAt:  h.ExpiresAt,
+		CreatedAt:  h.CreatedAt,
+	}
+	if h.BookingID != uuid.Nil {
+		resp.BookingID = h.BookingID.String()
+	}
+	return resp
+}
+
+// FromPromotionRequest maps a promotion payload onto a promotion; the caller validates it.
+func FromPromotionRequest(req dto.PromotionRequest) (domain.Promotion, error) {
+	p := domain.Promotion{
+		Code:           req.Code,
+		Kind:           req.Kind,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		MinNights:      req.MinNights,
+		MaxUses:        req.MaxUses,
+		MaxUsesPerUser: req.MaxUsesPerUser,
+	}
+	if req.ValidFrom != nil {
+		p.ValidFrom = *req.ValidFrom
+	}
+	if req.ValidUntil != nil {
+		p.ValidUntil = *req.ValidUntil
+	}
+	var err error
+	if p.HotelIDs, err = parseIDs(req.HotelIDs, "invalid hotel id"); err != nil {
+		return domain.Promotion{}, err
+	}
+	if p.RoomTypeIDs, err = parseIDs(req.RoomTypeIDs, "invalid room type id"); err != nil {
+		return domain.Promotion{}, err
+	}
+	return p, nil
+}
+
+func parseIDs(raw []string, invalid string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, s := range raw {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, pkgErrors.New("bad_request", invalid)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// ToPromotionResponse maps a promotion to its DTO.
+func ToPromotionResponse(p domain.Promotion) dto.PromotionResponse {
+	resp := dto.PromotionResponse{
+		ID:             p.ID.String(),
+		Code:           p.Code,
+		Kind:           p.Kind,
+		Amount:         p.Amount,
+		Currency:       p.Currency,
+		MinNights:      p.MinNights,
+		MaxUses:        p.MaxUses,
+		MaxUsesPerUser: p.MaxUsesPerUser,
+		CreatedAt:      p.CreatedAt,
+	}
+	if !p.ValidFrom.IsZero() {
+		validFrom := p.ValidFrom
+		resp.ValidFrom = &validFrom
+	}
+	if !p.ValidUntil.IsZero() {
+		validUntil := p.ValidUntil
+		resp.ValidUntil = &validUntil
+	}
+	for _, id := range p.HotelIDs {
+		resp.HotelIDs = append(resp.HotelIDs, id.String())
+	}
+	for _, id := range p.RoomTypeIDs {
+		resp.RoomTypeIDs = append(resp.RoomTypeIDs, id.String())
+	}
+	return resp
+}
+
+// FromExchangeRatesRequest validates uploaded rates. Rates published without a date are as of now.
+func FromExchangeRatesRequest(req dto.ExchangeRatesRequest, now time.Time) ([]valueobject.ExchangeRate, error) {
+	if len(req.Rates) == 0 {
+		return nil, pkgErrors.New("bad_request", "rates required")
+	}
+	asOf := now
+	if req.AsOf != nil {
+		asOf = *req.AsOf
+	}
+	quotes := make([]string, 0, len(req.Rates))
+	for quote := range req.Rates {
+		quotes = append(quotes, quote)
+	}
+	sort.Strings(quotes)
+	rates := make([]valueobject.ExchangeRate, 0, len(quotes))
+	for _, quote := range quotes {
+		rate, err := valueobject.NewExchangeRate(req.Base, quote, req.Rates[quote], asOf, "admin")
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func ToExchangeRateResponse(rate valueobject.ExchangeRate) dto.ExchangeRateResponse {
+	return dto.ExchangeRateResponse{Base: rate.Base, Quote: rate.Quote, Rate: rate.Rate, AsOf: rate.AsOf, Source: rate.Source}
 }
@@ -0,0 +1,122 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// cancel applies the cancellation policy of every booked room, persists the booking and stages a refund of what
+// the policies allow. It must run inside a transaction; the caller settles the returned refund once it commits.
+func (s *Service) cancel(ctx context.Context, bk *domain.Booking, reason string) (assembler.CancellationResult, *domain.SettlementDue, error) {
+	policies := make(map[uuid.UUID]valueobject.CancellationPolicy)
+	var primary valueobject.CancellationPolicy
+	for i, item := range bk.ActiveItems() {
+		policy, err := s.cancellationPolicy(ctx, item)
+		if err != nil {
+			return assembler.CancellationResult{}, nil, err
+		}
+		policies[item.ID] = policy
+		if i == 0 {
+			primary = policy
+		}
+	}
+	refund, err := bk.Cancel(reason, policies, time.Now())
+	if err != nil {
+		return assembler.CancellationResult{}, nil, err
+	}
+	if err := s.repo.Save(ctx, bk); err != nil {
+		return assembler.CancellationResult{}, nil, err
+	}
+	if err := s.releasePromotion(ctx, *bk); err != nil {
+		return assembler.CancellationResult{}, nil, err
+	}
+	if err := s.stageEvents(ctx, bk); err != nil {
+		return assembler.CancellationResult{}, nil, err
+	}
+	due, err := s.stageRefund(ctx, *bk, refund, reason)
+	if err != nil {
+		return assembler.CancellationResult{}, nil, err
+	}
+	return assembler.CancellationResult{Booking: *bk, Policy: primary.Code, RefundAmount: refund}, due, nil
+}
+
+// CancelBookingItem cancels one room of a confirmed group booking and refunds it according to its policy.
+// The rest of the group stays booked.
+// A non-zero expectedVersion must match the booking's current version.
+func (s *Service) CancelBookingItem(ctx context.Context, bookingID, itemID uuid.UUID, expectedVersion int) (assembler.CancellationResult, error) {
+	var (
+		result assembler.CancellationResult
+		due    *domain.SettlementDue
+	)
+	err := s.writeBooking(ctx, expectedVersion, func(ctx context.Context) error {
+		due = nil
+		bk, err := s.findOwned(ctx, bookingID)
+		if err != nil {
+			return err
+		}
+		if err := bk.CheckVersion(expectedVersion); err != nil {
+			return err
+		}
+		if err := s.recordOnBehalf(ctx, "cancel_item", bk); err != nil {
+			return err
+		}
+
+		var (
+			item  domain.LineItem
+			found bool
+		)
+		for _, it := range bk.LineItems() {
+			if it.ID == itemID {
+				item, found = it, true
+			}
+		}
+		if !found {
+			return errors.New("not_found", "booking item not found")
+		}
+		policy, err := s.cancellationPolicy(ctx, item)
+		if err != nil {
+			return err
+		}
+
+		refund, err := bk.CancelItem(itemID, policy, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Save(ctx, &bk); err != nil {
+			return err
+		}
+		if err := s.stageEvents(ctx, &bk); err != nil {
+			return err
+		}
+		if due, err = s.stageRefund(ctx, bk, refund, "item_cancelled"); err != nil {
+			return err
+		}
+		result = assembler.CancellationResult{Booking: bk, Policy: policy.Code, RefundAmount: refund}
+		return nil
+	})
+	if err != nil {
+		return assembler.CancellationResult{}, err
+	}
+	s.settleCommitted(ctx, due)
+	return result, nil
+}
+
+// cancellationPolicy resolves the policy of a booked room: the one of its rate plan when it was booked,
+// otherwise the one configured on its room type.
+func (s *Service) cancellationPolicy(ctx context.Context, item domain.LineItem) (valueobject.CancellationPolicy, error) {
+	if item.CancellationPolicy != "" {
+		return valueobject.ParseCancellationPolicy(item.CancellationPolicy)
+	}
+	rt, err := s.hotels.GetRoomType(ctx, item.RoomTypeID)
+	if err != nil {
+		return valueobject.CancellationPolicy{}, errors.New("not_found", "room type not found")
+	}
+	return valueobject.ParseCancellationPolicy(rt.CancellationPolicy)
+}
@@ -0,0 +1,25 @@
+package booking
+
+import (
+	"context"
+	"errors"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+)
+
+// maxConflictRetries bounds how often a write is retried after losing a version race.
+const maxConflictRetries = 3
+
+// writeBooking runs fn in a transaction and retries it when another writer saved the booking first.
+// A caller that pinned expectedVersion (If-Match) made its decision on a state that no longer exists,
+// so it gets the conflict back instead of a silent retry.
+func (s *Service) writeBooking(ctx context.Context, expectedVersion int, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; attempt < maxConflictRetries; attempt++ {
+		err = s.repo.WithinTransaction(ctx, fn)
+		if expectedVersion != 0 || !errors.Is(err, domain.ErrVersionConflict) {
+			return err
+		}
+	}
+	return err
+}
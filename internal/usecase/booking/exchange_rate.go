@@ -0,0 +1,100 @@
+package booking
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	hdomain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// SetExchangeRateProvider configures where RefreshExchangeRates fetches the latest rates from.
+func (s *Service) SetExchangeRateProvider(provider domain.ExchangeRateProvider) {
+	s.rates = provider
+}
+
+// UploadExchangeRates stores rates entered by an admin, replacing the stored rates of the same pairs.
+func (s *Service) UploadExchangeRates(ctx context.Context, rates []valueobject.ExchangeRate) error {
+	if len(rates) == 0 {
+		return errors.New("bad_request", "at least one rate required")
+	}
+	return s.repo.SaveExchangeRates(ctx, rates)
+}
+
+func (s *Service) ListExchangeRates(ctx context.Context) (valueobject.ExchangeRates, error) {
+	return s.repo.ListExchangeRates(ctx)
+}
+
+// RefreshExchangeRates stores the provider's latest rates and returns how many were stored. Without
+// a provider the rates are only ever uploaded by admins.
+func (s *Service) RefreshExchangeRates(ctx context.Context) (int, error) {
+	if s.rates == nil {
+		return 0, nil
+	}
+	rates, err := s.rates.LatestRates(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.repo.SaveExchangeRates(ctx, rates); err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
+
+// pickCurrencies works out the currencies of a new booking: the hotel currency its rooms are priced
+// in, a snapshot of the rate into the display currency, and the currency the guest pays in. The
+// display currency defaults to the charge currency, which defaults to the hotel's.
+func (s *Service) pickCurrencies(ctx context.Context, roomTypes map[uuid.UUID]hdomain.RoomType, cmd assembler.CreateCommand) (string, valueobject.ExchangeRate, string, error) {
+	currency := ""
+	for _, rt := range roomTypes {
+		hotelCurrency, err := s.hotelCurrency(ctx, rt.HotelID)
+		if err != nil {
+			return "", valueobject.ExchangeRate{}, "", err
+		}
+		if currency != "" && hotelCurrency != currency {
+			return "", valueobject.ExchangeRate{}, "", errors.New("bad_request", "rooms of one booking must be priced in the same currency")
+		}
+		currency = hotelCurrency
+	}
+
+	charge := cmd.ChargeCurrency
+	if charge == "" {
+		charge = currency
+	}
+	display := cmd.DisplayCurrency
+	if display == "" {
+		display = charge
+	}
+	if charge != currency && charge != display {
+		return "", valueobject.ExchangeRate{}, "", errors.New("bad_request", "charge currency must be the hotel currency or the display currency")
+	}
+	if display == currency {
+		return currency, valueobject.IdentityRate(currency), charge, nil
+	}
+
+	rates, err := s.repo.ListExchangeRates(ctx)
+	if err != nil {
+		return "", valueobject.ExchangeRate{}, "", err
+	}
+	rate, ok := rates.Find(currency, display)
+	if !ok {
+		return "", valueobject.ExchangeRate{}, "", errors.New("unprocessable_entity", "no exchange rate from "+currency+" to "+display)
+	}
+	return currency, rate, charge, nil
+}
+
+// hotelCurrency returns the currency the hotel's rooms are priced in.
+func (s *Service) hotelCurrency(ctx context.Context, hotelID uuid.UUID) (string, error) {
+	hotel, err := s.hotels.GetHotel(ctx, hotelID)
+	if err != nil {
+		return "", errors.New("not_found", "hotel not found")
+	}
+	if hotel.Currency == "" {
+		return valueobject.DefaultCurrency, nil
+	}
+	return hotel.Currency, nil
+}
@@ -0,0 +1,58 @@
+package booking
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// History returns the timeline of a booking: every recorded event with who caused it and when.
+func (s *Service) History(ctx context.Context, bookingID uuid.UUID) ([]domain.HistoryEntry, error) {
+	if _, err := s.GetBooking(ctx, bookingID); err != nil {
+		return nil, err
+	}
+	return s.repo.History(ctx, bookingID)
+}
+
+// RebuildFromHistory replays the event store and overwrites every booking that has recorded history
+// with the state its events describe. Bookings created before the event store existed are left untouched.
+func RebuildFromHistory(ctx context.Context, repo domain.Repository) (int, error) {
+	ids, err := repo.HistoryBookingIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i, id := range ids {
+		if err := RebuildBooking(ctx, repo, id); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
+
+// RebuildBooking replays the history of one booking and saves the result.
+func RebuildBooking(ctx context.Context, repo domain.Repository, bookingID uuid.UUID) error {
+	return repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		entries, err := repo.History(ctx, bookingID)
+		if err != nil {
+			return err
+		}
+		bk, err := domain.Replay(entries)
+		if err != nil {
+			return err
+		}
+		// History carries no versions: overwrite whatever is stored, or recreate a deleted row.
+		current, err := repo.FindByID(ctx, bookingID)
+		if errors.FromError(err).Code == "not_found" {
+			bk.Version = 1
+			return repo.Create(ctx, bk)
+		}
+		if err != nil {
+			return err
+		}
+		bk.Version = current.Version
+		return repo.Save(ctx, &bk)
+	})
+}
@@ -0,0 +1,92 @@
+package booking
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// maxExpiriesPerRun bounds a single ExpireHolds run so one replica cannot monopolise the job.
+const maxExpiriesPerRun = 500
+
+// ExpireHolds cancels pending bookings whose payment hold ran out and voids their payments.
+// Each booking is claimed in its own transaction with SKIP LOCKED, so replicas running the
+// job concurrently split the work instead of processing a booking twice. A booking whose
+// payment cannot be voided stays pending and is skipped for the rest of the run; the run goes
+// on with the others and reports every such failure in its error.
+func (s *Service) ExpireHolds(ctx context.Context) (int, error) {
+	var (
+		count  int
+		failed = map[uuid.UUID]bool{}
+		errs   []error
+	)
+	for claims := 0; claims < maxExpiriesPerRun; claims++ {
+		now := time.Now()
+		var (
+			bk      domain.Booking
+			claimed bool
+			expired bool
+		)
+		err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+			// Look past the bookings that already failed in this run.
+			holds, err := s.repo.FindExpiredHolds(ctx, now, len(failed)+1)
+			if err != nil {
+				return err
+			}
+			for _, h := range holds {
+				if !failed[h.ID] {
+					bk, claimed = h, true
+					break
+				}
+			}
+			if !claimed {
+				return nil
+			}
+
+			// The payment may have been settled just before the hold ran out; keep the booking then.
+			switch err := s.payments.Expire(ctx, bk.ID); {
+			case err == nil:
+				if err := bk.ExpireHold(now); err != nil {
+					return err
+				}
+				expired = true
+			case errors.FromError(err).Code == "conflict":
+				if err := bk.Confirm(); err != nil {
+					return err
+				}
+			default:
+				return err
+			}
+			if err := s.repo.Save(ctx, &bk); err != nil {
+				return err
+			}
+			if expired {
+				if err := s.releasePromotion(ctx, bk); err != nil {
+					return err
+				}
+			}
+			return s.stageEvents(ctx, &bk)
+		})
+		if err != nil && !claimed {
+			return count, stdErrors.Join(append(errs, err)...)
+		}
+		if err != nil {
+			failed[bk.ID] = true
+			errs = append(errs, fmt.Errorf("expire hold of booking %s: %w", bk.ID, err))
+			continue
+		}
+		if !claimed {
+			break
+		}
+		if expired {
+			count++
+		}
+	}
+	return count, stdErrors.Join(errs...)
+}
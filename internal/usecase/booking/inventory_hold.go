@@ -0,0 +1,182 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	hdomain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/audit"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// HoldInventory keeps rooms of a room type aside for the caller while they check out. Booking the
+// stay with the hold takes over the held rooms even when everything else sold out meanwhile. A new
+// hold of a checkout session replaces the session's earlier hold. Only admins may hold on behalf of
+// another user.
+func (s *Service) HoldInventory(ctx context.Context, cmd assembler.HoldCommand) (domain.InventoryHold, error) {
+	stay, err := valueobject.NewDateRange(cmd.CheckIn, cmd.CheckOut)
+	if err != nil {
+		return domain.InventoryHold{}, err
+	}
+	if stay.Start.Before(time.Now().Truncate(24 * time.Hour)) {
+		return domain.InventoryHold{}, errors.New("bad_request", "check_in has already passed")
+	}
+	owner, ok := audit.Owner(ctx, cmd.UserID)
+	if !ok {
+		return domain.InventoryHold{}, errors.New("forbidden", "cannot hold rooms on behalf of another user")
+	}
+	if owner == uuid.Nil {
+		return domain.InventoryHold{}, errors.New("bad_request", "invalid user id")
+	}
+	duration := cmd.Duration
+	if duration == 0 {
+		duration = domain.DefaultInventoryHold
+	}
+	if duration < 0 || duration > domain.MaxInventoryHold {
+		return domain.InventoryHold{}, errors.New("bad_request", "rooms can be held for at most 30 minutes")
+	}
+	if cmd.Rooms < 1 {
+		return domain.InventoryHold{}, errors.New("bad_request", "rooms must be at least 1")
+	}
+
+	var hold domain.InventoryHold
+	err = s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		rt, err := s.hotels.GetRoomTypeForUpdate(ctx, cmd.RoomTypeID)
+		if err != nil {
+			return errors.New("not_found", "room type not found")
+		}
+		if err := s.checkCalendar(ctx, rt.ID, stay); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if cmd.SessionID != "" {
+			if err := s.releaseSessionHolds(ctx, owner, cmd.SessionID, now); err != nil {
+				return err
+			}
+		}
+		if err := s.ensureInventory(ctx, rt.ID, stay, uuid.Nil, cmd.Rooms); err != nil {
+			return err
+		}
+
+		hold = domain.InventoryHold{
+			ID:         uuid.New(),
+			UserID:     owner,
+			SessionID:  cmd.SessionID,
+			RoomTypeID: rt.ID,
+			CheckIn:    cmd.CheckIn,
+			CheckOut:   cmd.CheckOut,
+			Rooms:      cmd.Rooms,
+			Status:     domain.HoldActive,
+			ExpiresAt:  now.Add(duration),
+			CreatedAt:  now,
+		}
+		if err := s.repo.CreateInventoryHold(ctx, hold); err != nil {
+			return err
+		}
+		return audit.Record(ctx, s.audit, "hold.create", "hold", hold.ID, hold.UserID)
+	})
+	if err != nil {
+		return domain.InventoryHold{}, err
+	}
+	return hold, nil
+}
+
+// GetInventoryHold returns an inventory hold of the caller; admins may read any hold.
+func (s *Service) GetInventoryHold(ctx context.Context, id uuid.UUID) (domain.InventoryHold, error) {
+	hold, err := s.repo.FindInventoryHold(ctx, id)
+	if err != nil {
+		return domain.InventoryHold{}, err
+	}
+	if !audit.CanAccess(ctx, hold.UserID) {
+		return domain.InventoryHold{}, errors.New("not_found", "hold not found")
+	}
+	return hold, nil
+}
+
+// ReleaseInventoryHold gives the held rooms up before the hold expires.
+func (s *Service) ReleaseInventoryHold(ctx context.Context, id uuid.UUID) (domain.InventoryHold, error) {
+	var hold domain.InventoryHold
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if hold, err = s.lockInventoryHold(ctx, id); err != nil {
+			return err
+		}
+		if err := hold.Release(time.Now()); err != nil {
+			return err
+		}
+		if err := s.repo.SaveInventoryHold(ctx, hold); err != nil {
+			return err
+		}
+		return audit.Record(ctx, s.audit, "hold.release", "hold", hold.ID, hold.UserID)
+	})
+	if err != nil {
+		return domain.InventoryHold{}, err
+	}
+	return hold, nil
+}
+
+// ExpireInventoryHolds marks the inventory holds that ran out as expired. Expired holds stop keeping
+// rooms aside when they run out whether or not this has run; it keeps their status honest.
+func (s *Service) ExpireInventoryHolds(ctx context.Context) (int, error) {
+	return s.repo.ExpireInventoryHolds(ctx, time.Now())
+}
+
+// releaseSessionHolds releases the active holds owner took in a checkout session.
+func (s *Service) releaseSessionHolds(ctx context.Context, owner uuid.UUID, sessionID string, now time.Time) error {
+	holds, err := s.repo.FindUserHolds(ctx, owner, now)
+	if err != nil {
+		return err
+	}
+	for _, h := range holds {
+		if h.SessionID != sessionID {
+			continue
+		}
+		if err := h.Release(now); err != nil {
+			return err
+		}
+		if err := s.repo.SaveInventoryHold(ctx, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// heldRooms returns the active inventory hold cmd books, once it has checked the hold belongs to
+// owner and keeps rooms of a requested room type aside for the same stay. The caller must hold the
+// room type locks.
+func (s *Service) heldRooms(ctx context.Context, owner uuid.UUID, cmd assembler.CreateCommand, roomTypes map[uuid.UUID]hdomain.RoomType) (domain.InventoryHold, error) {
+	hold, err := s.repo.FindInventoryHold(ctx, cmd.HoldID)
+	if err != nil {
+		return domain.InventoryHold{}, err
+	}
+	if hold.UserID != owner {
+		return domain.InventoryHold{}, errors.New("not_found", "hold not found")
+	}
+	if _, ok := roomTypes[hold.RoomTypeID]; !ok || !hold.CheckIn.Equal(cmd.CheckIn) || !hold.CheckOut.Equal(cmd.CheckOut) {
+		return domain.InventoryHold{}, errors.New("bad_request", "hold is for other rooms or dates")
+	}
+	if !hold.Active(time.Now()) {
+		return domain.InventoryHold{}, errors.New("conflict", "hold has expired, hold the rooms again")
+	}
+	return hold, nil
+}
+
+// lockInventoryHold loads a hold the caller may access and locks its room type, so nothing books
+// or holds the room type while the hold changes.
+func (s *Service) lockInventoryHold(ctx context.Context, id uuid.UUID) (domain.InventoryHold, error) {
+	hold, err := s.GetInventoryHold(ctx, id)
+	if err != nil {
+		return domain.InventoryHold{}, err
+	}
+	if _, err := s.hotels.GetRoomTypeForUpdate(ctx, hold.RoomTypeID); err != nil {
+		return domain.InventoryHold{}, errors.New("not_found", "room type not found")
+	}
+	// Re-read under the lock; a booking may have consumed the hold meanwhile.
+	return s.repo.FindInventoryHold(ctx, id)
+}
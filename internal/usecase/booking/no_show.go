@@ -0,0 +1,59 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+)
+
+// maxNoShowsPerRun bounds a single MarkNoShows run, mirroring maxExpiriesPerRun.
+const maxNoShowsPerRun = 500
+
+// MarkNoShows moves confirmed bookings whose check-in day has passed to no_show and
+// refunds whatever the no-show policy does not charge. Bookings are claimed one per
+// transaction with SKIP LOCKED so concurrent replicas never process the same booking.
+func (s *Service) MarkNoShows(ctx context.Context) (int, error) {
+	count := 0
+	for count < maxNoShowsPerRun {
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		claimed := false
+		var due *domain.SettlementDue
+		err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+			missed, err := s.repo.FindMissedCheckIns(ctx, today, 1)
+			if err != nil || len(missed) == 0 {
+				return err
+			}
+			bk := missed[0]
+			claimed = true
+			due, err = s.markNoShow(ctx, &bk, now)
+			return err
+		})
+		if err != nil {
+			return count, err
+		}
+		if !claimed {
+			break
+		}
+		s.settleCommitted(ctx, due)
+		count++
+	}
+	return count, nil
+}
+
+// markNoShow applies the no-show policy, persists the booking and stages a refund of the uncharged
+// remainder. It must run inside a transaction; the caller settles the returned refund once it commits.
+func (s *Service) markNoShow(ctx context.Context, bk *domain.Booking, now time.Time) (*domain.SettlementDue, error) {
+	refund, err := bk.MarkNoShow(s.noShow, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, bk); err != nil {
+		return nil, err
+	}
+	if err := s.stageEvents(ctx, bk); err != nil {
+		return nil, err
+	}
+	return s.stageRefund(ctx, *bk, refund, domain.StatusNoShow)
+}
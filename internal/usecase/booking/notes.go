@@ -0,0 +1,44 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// AddNote records an internal staff note on a booking.
+// Notes are never part of the guest-facing booking response.
+func (s *Service) AddNote(ctx context.Context, bookingID, authorID uuid.UUID, body string) (domain.StaffNote, error) {
+	var note domain.StaffNote
+	err := s.writeBooking(ctx, 0, func(ctx context.Context) error {
+		bk, err := s.repo.FindByID(ctx, bookingID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.New("not_found", "booking not found")
+			}
+			return err
+		}
+		if note, err = bk.AddNote(authorID, body, time.Now()); err != nil {
+			return err
+		}
+		return s.repo.Save(ctx, &bk)
+	})
+	if err != nil {
+		return domain.StaffNote{}, err
+	}
+	return note, nil
+}
+
+// ListNotes returns the staff notes of a booking, oldest first.
+func (s *Service) ListNotes(ctx context.Context, bookingID uuid.UUID) ([]domain.StaffNote, error) {
+	bk, err := s.GetBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	return bk.Notes, nil
+}
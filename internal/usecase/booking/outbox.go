@@ -0,0 +1,109 @@
+package booking
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+)
+
+const (
+	// outboxBatchSize is how many messages one relay transaction claims.
+	outboxBatchSize = 50
+	// maxRelayedPerRun bounds a single RelayOutbox run, mirroring maxExpiriesPerRun.
+	maxRelayedPerRun = 500
+)
+
+// RelayOutbox delivers due outbox messages through the notification gateway, and settlements
+// through the payment gateway.
+// Failed deliveries are retried with exponential backoff until the retry policy dead-letters them.
+// Messages are claimed with SKIP LOCKED, so replicas relaying concurrently never send a message twice;
+// delivery is still at-least-once, since a crash after Notify but before commit resends the message.
+func (s *Service) RelayOutbox(ctx context.Context) (int, error) {
+	// Only messages due when the run starts are claimed, so a failed message waits out its backoff.
+	due := time.Now()
+	delivered := 0
+	for processed := 0; processed < maxRelayedPerRun; {
+		claimed := 0
+		err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+			msgs, err := s.repo.ClaimOutbox(ctx, due, outboxBatchSize)
+			if err != nil {
+				return err
+			}
+			claimed = len(msgs)
+			for _, msg := range msgs {
+				now := time.Now()
+				if err := s.deliver(ctx, msg); err != nil {
+					msg.MarkFailed(err.Error(), now, s.retry)
+				} else {
+					msg.MarkDelivered(now)
+					delivered++
+				}
+				if err := s.repo.SaveOutbox(ctx, msg); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return delivered, err
+		}
+		if claimed == 0 {
+			break
+		}
+		processed += claimed
+	}
+	return delivered, nil
+}
+
+// deliver hands a settlement to the payment service and any other message to the notification gateway.
+func (s *Service) deliver(ctx context.Context, msg domain.OutboxMessage) error {
+	if msg.EventType != domain.EventTypeSettlementDue {
+		return s.notifier.Notify(ctx, msg.EventType, json.RawMessage(msg.Payload))
+	}
+	var due domain.SettlementDue
+	if err := json.Unmarshal(msg.Payload, &due); err != nil {
+		return err
+	}
+	_, err := s.settle(ctx, due)
+	return err
+}
+
+// ListOutbox returns outbox messages, optionally filtered by status, for inspection.
+func (s *Service) ListOutbox(ctx context.Context, status string, opts query.Options) ([]domain.OutboxMessage, error) {
+	switch status {
+	case "", domain.OutboxPending, domain.OutboxDelivered, domain.OutboxDead:
+	default:
+		return nil, errors.New("bad_request", "unknown outbox status")
+	}
+	return s.repo.ListOutbox(ctx, status, opts.Normalize(50))
+}
+
+// GetOutboxMessage returns a single outbox message.
+func (s *Service) GetOutboxMessage(ctx context.Context, id uuid.UUID) (domain.OutboxMessage, error) {
+	return s.repo.FindOutbox(ctx, id)
+}
+
+// ReplayOutboxMessage queues a stuck or dead-lettered message for immediate redelivery.
+func (s *Service) ReplayOutboxMessage(ctx context.Context, id uuid.UUID) (domain.OutboxMessage, error) {
+	var msg domain.OutboxMessage
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if msg, err = s.repo.FindOutbox(ctx, id); err != nil {
+			return err
+		}
+		if err := msg.Replay(time.Now()); err != nil {
+			return err
+		}
+		return s.repo.SaveOutbox(ctx, msg)
+	})
+	if err != nil {
+		return domain.OutboxMessage{}, err
+	}
+	return msg, nil
+}
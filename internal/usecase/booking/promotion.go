@@ -0,0 +1,137 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	hdomain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// CreatePromotion adds a promo code. Codes are unique regardless of case.
+func (s *Service) CreatePromotion(ctx context.Context, p domain.Promotion) (domain.Promotion, error) {
+	if err := p.Validate(); err != nil {
+		return domain.Promotion{}, err
+	}
+	if err := s.ensureCodeFree(ctx, p.Code, uuid.Nil); err != nil {
+		return domain.Promotion{}, err
+	}
+	p.ID = uuid.New()
+	p.CreatedAt = time.Now()
+	if err := s.repo.CreatePromotion(ctx, p); err != nil {
+		return domain.Promotion{}, err
+	}
+	return p, nil
+}
+
+// UpdatePromotion replaces a promo code. Bookings that already redeemed it keep their discount,
+// and their redemptions still count towards the new limits.
+func (s *Service) UpdatePromotion(ctx context.Context, id uuid.UUID, p domain.Promotion) (domain.Promotion, error) {
+	existing, err := s.repo.GetPromotion(ctx, id)
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+	if err := p.Validate(); err != nil {
+		return domain.Promotion{}, err
+	}
+	if err := s.ensureCodeFree(ctx, p.Code, id); err != nil {
+		return domain.Promotion{}, err
+	}
+	p.ID = existing.ID
+	p.CreatedAt = existing.CreatedAt
+	if err := s.repo.UpdatePromotion(ctx, p); err != nil {
+		return domain.Promotion{}, err
+	}
+	return p, nil
+}
+
+func (s *Service) DeletePromotion(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeletePromotion(ctx, id)
+}
+
+func (s *Service) GetPromotion(ctx context.Context, id uuid.UUID) (domain.Promotion, error) {
+	return s.repo.GetPromotion(ctx, id)
+}
+
+func (s *Service) ListPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	return s.repo.ListPromotions(ctx)
+}
+
+// ensureCodeFree rejects a code already used by another promotion than id.
+func (s *Service) ensureCodeFree(ctx context.Context, code string, id uuid.UUID) error {
+	other, err := s.repo.FindPromotionByCode(ctx, code)
+	switch {
+	case err == nil && other.ID != id:
+		return errors.New("conflict", "promo code already exists")
+	case err == nil || errors.FromError(err).Code == "not_found":
+		return nil
+	default:
+		return err
+	}
+}
+
+// applyPromotion validates the promo code for a booking of owner and takes its discount off the items,
+// which are priced in currency.
+// The promotion stays locked until the transaction ends, so concurrent bookings cannot both take the
+// last use of a code; the caller records the redemption before committing.
+func (s *Service) applyPromotion(ctx context.Context, owner uuid.UUID, code string, nights int, items []domain.LineItem, roomTypes map[uuid.UUID]hdomain.RoomType, currency string) (domain.Promotion, error) {
+	promo, err := s.repo.LockPromotionByCode(ctx, code)
+	if err != nil {
+		if errors.FromError(err).Code == "not_found" {
+			return domain.Promotion{}, errors.New("unprocessable_entity", "promo code not found")
+		}
+		return domain.Promotion{}, err
+	}
+	used, usedByOwner, err := s.repo.CountRedemptions(ctx, promo.ID, owner)
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+	if err := promo.CheckRedeemable(time.Now(), nights, used, usedByOwner); err != nil {
+		return domain.Promotion{}, err
+	}
+	if err := promo.CheckCurrency(currency); err != nil {
+		return domain.Promotion{}, err
+	}
+	if promo.Apply(items, hotelsOf(roomTypes), currency).IsZero() {
+		return domain.Promotion{}, errors.New("unprocessable_entity", "promo code does not apply to the booked rooms")
+	}
+	return promo, nil
+}
+
+// reapplyPromotion takes the discount of the booking's promo code off a repriced room. The code was
+// validated when it was redeemed, so only its discount is applied again; a deleted promotion, or a fixed
+// one since moved to another currency, no longer discounts anything.
+func (s *Service) reapplyPromotion(ctx context.Context, bk domain.Booking, rt hdomain.RoomType, price domain.PriceBreakdown) (domain.PriceBreakdown, error) {
+	if bk.PromoCode == "" {
+		return price, nil
+	}
+	promo, err := s.repo.FindPromotionByCode(ctx, bk.PromoCode)
+	if err != nil {
+		if errors.FromError(err).Code == "not_found" {
+			return price, nil
+		}
+		return domain.PriceBreakdown{}, err
+	}
+	items := []domain.LineItem{{RoomTypeID: rt.ID, Price: price, Status: domain.ItemStatusActive}}
+	promo.Apply(items, hotelsOf(map[uuid.UUID]hdomain.RoomType{rt.ID: rt}), bk.PriceCurrency())
+	return items[0].Price, nil
+}
+
+// releasePromotion frees the booking's use of its promo code once the booking no longer holds a room.
+func (s *Service) releasePromotion(ctx context.Context, bk domain.Booking) error {
+	if bk.PromoCode == "" {
+		return nil
+	}
+	return s.repo.ReleaseRedemptions(ctx, bk.ID)
+}
+
+func hotelsOf(roomTypes map[uuid.UUID]hdomain.RoomType) map[uuid.UUID]uuid.UUID {
+	hotels := make(map[uuid.UUID]uuid.UUID, len(roomTypes))
+	for id, rt := range roomTypes {
+		hotels[id] = rt.HotelID
+	}
+	return hotels
+}
@@ -0,0 +1,115 @@
+package booking
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// DefaultQuoteTTL is how long a quoted price is kept.
+const DefaultQuoteTTL = 15 * time.Minute
+
+// SetQuoteSigner configures how quote tokens are signed and how long a quoted price is kept.
+// Without a signer no quotes are given.
+func (s *Service) SetQuoteSigner(signer domain.QuoteSigner, ttl time.Duration) {
+	s.quotes = signer
+	if ttl > 0 {
+		s.quoteTTL = ttl
+	}
+}
+
+// CreateQuote prices a booking exactly as CreateBooking would, without holding anything, and signs
+// the quote into a token. Booking with the token keeps the quoted price until the quote expires.
+func (s *Service) CreateQuote(ctx context.Context, cmd assembler.CreateCommand) (domain.Quote, string, error) {
+	if s.quotes == nil {
+		return domain.Quote{}, "", errors.New("unprocessable_entity", "quotes are not enabled")
+	}
+	dateRange, err := valueobject.NewDateRange(cmd.CheckIn, cmd.CheckOut)
+	if err != nil {
+		return domain.Quote{}, "", err
+	}
+	owner, err := bookingOwner(ctx, cmd)
+	if err != nil {
+		return domain.Quote{}, "", err
+	}
+
+	// Pricing locks the room types and the promotion like booking does; nothing is written.
+	var stay pricedStay
+	err = s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		stay, err = s.priceStay(ctx, owner, dateRange, cmd)
+		return err
+	})
+	if err != nil {
+		return domain.Quote{}, "", err
+	}
+
+	for i := range stay.items {
+		stay.items[i].ID = uuid.Nil
+	}
+	quote := domain.Quote{
+		UserID:         owner,
+		CheckIn:        cmd.CheckIn,
+		CheckOut:       cmd.CheckOut,
+		Items:          stay.items,
+		PromoCode:      stay.promo.Code,
+		PaymentTiming:  stay.timing,
+		Currency:       stay.currency,
+		ExchangeRate:   stay.rate,
+		ChargeCurrency: stay.chargeCurrency,
+		ExpiresAt:      time.Now().Add(s.quoteTTL).Truncate(time.Second),
+	}
+	token, err := s.quotes.Sign(quote)
+	if err != nil {
+		return domain.Quote{}, "", err
+	}
+	return quote, token, nil
+}
+
+// keepQuotedPrice puts the prices and exchange rate of the quote token of cmd on a freshly priced
+// stay, once it has checked the token quotes the same booking. Everything else about the stay, such
+// as free inventory and the promo code, was checked again when pricing it.
+func (s *Service) keepQuotedPrice(owner uuid.UUID, cmd assembler.CreateCommand, stay *pricedStay) error {
+	if s.quotes == nil {
+		return errors.New("conflict", "invalid quote token")
+	}
+	quote, err := s.quotes.Verify(cmd.QuoteToken)
+	if err != nil {
+		return err
+	}
+	if !quotes(quote, owner, cmd, *stay) {
+		return errors.New("conflict", "quote token does not match the booking")
+	}
+	for i := range stay.items {
+		stay.items[i].Price = quote.Items[i].Price
+	}
+	stay.rate = quote.ExchangeRate
+	return nil
+}
+
+// quotes reports whether q quotes the stay owner books with cmd: the same dates, rooms, rate plans,
+// guests, promo code and currencies.
+func quotes(q domain.Quote, owner uuid.UUID, cmd assembler.CreateCommand, stay pricedStay) bool {
+	if q.UserID != owner || !q.CheckIn.Equal(cmd.CheckIn) || !q.CheckOut.Equal(cmd.CheckOut) {
+		return false
+	}
+	if q.PromoCode != stay.promo.Code || q.Currency != stay.currency || q.ChargeCurrency != stay.chargeCurrency ||
+		q.ExchangeRate.Base != stay.rate.Base || q.ExchangeRate.Quote != stay.rate.Quote {
+		return false
+	}
+	if len(q.Items) != len(stay.items) {
+		return false
+	}
+	for i, item := range stay.items {
+		quoted := q.Items[i]
+		if quoted.RoomTypeID != item.RoomTypeID || quoted.RatePlanID != item.RatePlanID || quoted.Guests != item.Guests {
+			return false
+		}
+	}
+	return true
+}
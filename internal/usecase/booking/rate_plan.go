@@ -0,0 +1,65 @@
+package booking
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	hdomain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// ratePlan loads the plan a room of rt is booked under. uuid.Nil books the room type at its own
+// terms and returns the zero plan.
+func (s *Service) ratePlan(ctx context.Context, rt hdomain.RoomType, id uuid.UUID) (hdomain.RatePlan, error) {
+	if id == uuid.Nil {
+		return hdomain.RatePlan{}, nil
+	}
+	plan, err := s.hotels.GetRatePlan(ctx, id)
+	if err != nil {
+		return hdomain.RatePlan{}, errors.New("not_found", "rate plan not found")
+	}
+	if plan.RoomTypeID != rt.ID {
+		return hdomain.RatePlan{}, errors.New("bad_request", "rate plan does not belong to the room type")
+	}
+	return plan, nil
+}
+
+// paymentTiming returns the payment timing shared by the rate plans of a booking. Rooms paid now
+// and rooms paid at the hotel cannot be mixed, since a booking is either charged up front or not.
+func paymentTiming(plans []hdomain.RatePlan) (string, error) {
+	timing := valueobject.PayNow
+	for i, plan := range plans {
+		t := plan.Plan.PaymentTiming
+		if t == "" {
+			t = valueobject.PayNow
+		}
+		if i > 0 && t != timing {
+			return "", errors.New("bad_request", "rate plans paid now and at the hotel cannot be booked together")
+		}
+		timing = t
+	}
+	return timing, nil
+}
+
+// ratePlanQuotes prices the stay under every rate plan of rt, taxes and fees included.
+func (s *Service) ratePlanQuotes(ctx context.Context, rt hdomain.RoomType, currency string, stay valueobject.DateRange, guests int) ([]assembler.RatePlanQuote, error) {
+	plans, err := s.hotels.ListRatePlans(ctx, rt.ID)
+	if err != nil {
+		return nil, err
+	}
+	quotes := make([]assembler.RatePlanQuote, 0, len(plans))
+	for _, plan := range plans {
+		price, err := s.quote(ctx, rt, plan.Plan, currency, stay, guests)
+		if err != nil {
+			return nil, err
+		}
+		if price, err = s.levyCharges(ctx, rt, currency, stay.Nights(), price); err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, assembler.RatePlanQuote{Plan: plan, QuotedTotal: price.Total})
+	}
+	return quotes, nil
+}
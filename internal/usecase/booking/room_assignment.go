@@ -0,0 +1,118 @@
+package booking
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	hdomain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// checkIn assigns a room (explicit or automatic), checks the guest in and marks the room occupied.
+func (s *Service) checkIn(ctx context.Context, bk *domain.Booking, roomID uuid.UUID) error {
+	// Locking the room type keeps two front desks from handing out the same room.
+	if _, err := s.hotels.GetRoomTypeForUpdate(ctx, bk.RoomTypeID); err != nil {
+		return errors.New("not_found", "room type not found")
+	}
+
+	room, err := s.pickRoom(ctx, *bk, roomID)
+	if err != nil {
+		return err
+	}
+	if err := bk.AssignRoom(room.ID, room.RoomTypeID); err != nil {
+		return err
+	}
+	if err := bk.GuestCheckIn(); err != nil {
+		return err
+	}
+
+	room.Status = string(valueobject.RoomOccupied)
+	return s.hotels.UpdateRoom(ctx, room.ID, room)
+}
+
+// complete finishes the stay and hands the room over to housekeeping.
+func (s *Service) complete(ctx context.Context, bk *domain.Booking) error {
+	if err := bk.Complete(); err != nil {
+		return err
+	}
+	if bk.RoomID == uuid.Nil {
+		return nil
+	}
+	room, err := s.hotels.GetRoom(ctx, bk.RoomID)
+	if err != nil {
+		return err
+	}
+	room.Status = string(valueobject.RoomCleaning)
+	return s.hotels.UpdateRoom(ctx, room.ID, room)
+}
+
+// pickRoom validates the requested room or auto-assigns the first free room of the booked type.
+func (s *Service) pickRoom(ctx context.Context, bk domain.Booking, roomID uuid.UUID) (hdomain.Room, error) {
+	if roomID != uuid.Nil {
+		room, err := s.hotels.GetRoom(ctx, roomID)
+		if err != nil {
+			return hdomain.Room{}, errors.New("not_found", "room not found")
+		}
+		if room.RoomTypeID != bk.RoomTypeID {
+			return hdomain.Room{}, errors.New("bad_request", "room does not belong to the booked room type")
+		}
+		free, err := s.roomFree(ctx, bk, room)
+		if err != nil {
+			return hdomain.Room{}, err
+		}
+		if !free {
+			return hdomain.Room{}, errors.New("conflict", "room is not free for the booked dates")
+		}
+		return room, nil
+	}
+
+	rooms, err := s.hotels.ListRoomsByType(ctx, bk.RoomTypeID)
+	if err != nil {
+		return hdomain.Room{}, err
+	}
+	// Rooms that are ready go first; one housekeeping is still turning over comes after them.
+	for _, status := range []valueobject.RoomStatus{valueobject.RoomAvailable, valueobject.RoomCleaning} {
+		for _, room := range rooms {
+			if room.Status != string(status) {
+				continue
+			}
+			free, err := s.roomFree(ctx, bk, room)
+			if err != nil {
+				return hdomain.Room{}, err
+			}
+			if free {
+				return room, nil
+			}
+		}
+	}
+	return hdomain.Room{}, errors.New("conflict", "no free room to assign")
+}
+
+// roomFree reports whether a room can be handed out and is not assigned to another stay sharing a
+// night with the booking. A room being cleaned after the last checkout counts as ready. Nights are
+// compared as calendar dates like the inventory check does, so a guest checking out in the morning
+// does not keep the room from a guest arriving that afternoon.
+func (s *Service) roomFree(ctx context.Context, bk domain.Booking, room hdomain.Room) (bool, error) {
+	if room.Status != string(valueobject.RoomAvailable) && room.Status != string(valueobject.RoomCleaning) {
+		return false, nil
+	}
+	assigned, err := s.repo.FindByRoomID(ctx, room.ID, bk.Stay())
+	if err != nil {
+		return false, err
+	}
+	nights := bk.Stay().NightDates()
+	for _, other := range assigned {
+		if other.ID == bk.ID || !other.HoldsInventory() {
+			continue
+		}
+		for _, night := range nights {
+			if other.Stay().Contains(night) {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
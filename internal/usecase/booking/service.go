@@ -2,7 +2,8 @@ package booking
 
 import (
 	"context"
-	"database/sql"
+	"fmt"
+	"sort"
 	"time" // Added time import
 
 	"github.com/google/uuid"
@@ -10,26 +11,62 @@ import (
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
 	hdomain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
 	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
-	pkgDomain "github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/audit"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
 
 
 	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
+// DefaultHoldDuration matches the default payment invoice lifetime.
+const DefaultHoldDuration = 15 * time.Minute
+
 // Service handles booking lifecycle.
 type Service struct {
-	repo     domain.Repository
-	hotels   hdomain.Repository
-	payments domain.PaymentGateway
-	notifier domain.NotificationGateway
+	repo         domain.Repository
+	hotels       hdomain.Repository
+	payments     domain.PaymentGateway
+	notifier     domain.NotificationGateway
+	holdDuration time.Duration
+	noShow       valueobject.NoShowPolicy
+	retry        valueobject.RetryPolicy
+	audit        audit.Log
+	rates        domain.ExchangeRateProvider
+	quotes       domain.QuoteSigner
+	quoteTTL     time.Duration
 }
 
 func NewService(repo domain.Repository, hotels hdomain.Repository, payments domain.PaymentGateway, notifier domain.NotificationGateway) *Service {
-	return &Service{repo: repo, hotels: hotels, payments: payments, notifier: notifier}
+	return &Service{repo: repo, hotels: hotels, payments: payments, notifier: notifier, holdDuration: DefaultHoldDuration, retry: valueobject.DefaultRetryPolicy(), quoteTTL: DefaultQuoteTTL}
+}
+
+// SetHoldDuration configures how long an unpaid booking holds inventory.
+func (s *Service) SetHoldDuration(d time.Duration) {
+	if d > 0 {
+		s.holdDuration = d
+	}
 }
 
+// SetNoShowPolicy configures what a guest who never arrived is charged.
+func (s *Service) SetNoShowPolicy(policy valueobject.NoShowPolicy) {
+	s.noShow = policy
+}
+
+// SetRetryPolicy configures how often the outbox relay retries an undeliverable event.
+func (s *Service) SetRetryPolicy(policy valueobject.RetryPolicy) {
+	if policy.MaxAttempts > 0 {
+		s.retry = policy
+	}
+}
+
+// SetAuditLog configures where actions admins take on other users' bookings are recorded.
+func (s *Service) SetAuditLog(log audit.Log) {
+	s.audit = log
+}
+
+// CreateBooking books for the caller on ctx. Only admins may book on behalf of another user.
 func (s *Service) CreateBooking(ctx context.Context, cmd assembler.CreateCommand) (domain.Booking, domain.PaymentResult, error) {
 	// Use value objects
 	dateRange, err := valueobject.NewDateRange(cmd.CheckIn, cmd.CheckOut)
@@ -37,158 +74,680 @@ func (s *Service) CreateBooking(ctx context.Context, cmd assembler.CreateCommand
 		return domain.Booking{}, domain.PaymentResult{}, err
 	}
 
-	rt, err := s.hotels.GetRoomType(ctx, cmd.RoomTypeID)
+	owner, err := bookingOwner(ctx, cmd)
 	if err != nil {
-		return domain.Booking{}, domain.PaymentResult{}, errors.New("not_found", "room type not found")
+		return domain.Booking{}, domain.PaymentResult{}, err
 	}
 
-	// Use domain service for pricing
-	pricingService := domain.NewPricingService()
-	baseTotal := pricingService.CalculateTotalPrice(rt.BasePrice, dateRange.Nights(), cmd.Guests)
-	totalPrice := pricingService.ApplyDiscount(baseTotal, dateRange.Nights())
+	var booking domain.Booking
+	err = s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		booking, err = s.reserve(ctx, owner, dateRange, cmd)
+		return err
+	})
+	if err != nil {
+		return domain.Booking{}, domain.PaymentResult{}, err
+	}
+
+	// Bookings paid at the hotel are confirmed without charging anything.
+	if booking.PaysAtHotel() {
+		return booking, domain.PaymentResult{}, nil
+	}
+	paymentResult, err := s.payments.Initiate(paymentRequest(ctx, booking, "payment"), booking.ID, booking.UserID, booking.Charge(booking.TotalPrice), booking.ChargeLines())
+	if err != nil {
+		return domain.Booking{}, domain.PaymentResult{}, err
+	}
+
+	return booking, paymentResult, nil
+}
 
+// bookingOwner returns who cmd books for: the caller on ctx, or the user an admin books on behalf of.
+func bookingOwner(ctx context.Context, cmd assembler.CreateCommand) (uuid.UUID, error) {
+	owner, ok := audit.Owner(ctx, cmd.UserID)
+	if !ok {
+		return uuid.Nil, errors.New("forbidden", "cannot book on behalf of another user")
+	}
+	if owner == uuid.Nil {
+		return uuid.Nil, errors.New("bad_request", "invalid user id")
+	}
+	return owner, nil
+}
+
+// reserve creates a pending booking holding the requested rooms for owner, or fails with a conflict
+// when the stay is sold out. A booking paid at the hotel is confirmed straight away instead, without
+// a payment hold. With a quote token the booking keeps the quoted price; with an inventory hold it
+// takes over the held rooms and consumes the hold. It must run inside a transaction.
+func (s *Service) reserve(ctx context.Context, owner uuid.UUID, dateRange valueobject.DateRange, cmd assembler.CreateCommand) (domain.Booking, error) {
+	stay, err := s.priceStay(ctx, owner, dateRange, cmd)
+	if err != nil {
+		return domain.Booking{}, err
+	}
+	if cmd.QuoteToken != "" {
+		if err := s.keepQuotedPrice(owner, cmd, &stay); err != nil {
+			return domain.Booking{}, err
+		}
+	}
+
+	now := time.Now()
 	booking := domain.Booking{
 		ID:          uuid.New(),
-		UserID:      cmd.UserID,
-		RoomTypeID:  cmd.RoomTypeID,
+		UserID:      owner,
 		CheckIn:     cmd.CheckIn,
 		CheckOut:    cmd.CheckOut,
 		Status:      string(valueobject.StatusPendingPayment),
-		Guests:      cmd.Guests,
-		TotalPrice:  totalPrice,
 		TotalNights: dateRange.Nights(),
-		CreatedAt:   time.Now(),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.holdDuration),
+		Version:     1,
+
+		Guest:           cmd.Guest,
+		ArrivalTime:     cmd.ArrivalTime,
+		SpecialRequests: cmd.SpecialRequests,
+		PromoCode:       stay.promo.Code,
+		PaymentTiming:   stay.timing,
+
+		Currency:       stay.currency,
+		ExchangeRate:   stay.rate,
+		ChargeCurrency: stay.chargeCurrency,
 	}
+	if booking.PaysAtHotel() {
+		// Nothing is charged up front, so there is no payment hold to expire.
+		booking.ExpiresAt = time.Time{}
+	}
+	booking.SetItems(stay.items)
 
 	// Record creation event
-	booking.RecordEvent(domain.NewBookingCreated(booking.ID, booking.UserID, booking.RoomTypeID, booking.TotalPrice, booking.Guests))
+	booking.RecordEvent(domain.NewBookingCreated(booking))
+	if booking.PaysAtHotel() {
+		if err := booking.Confirm(); err != nil {
+			return domain.Booking{}, err
+		}
+	}
 
 	if err := s.repo.Create(ctx, booking); err != nil {
-		return domain.Booking{}, domain.PaymentResult{}, err
+		return domain.Booking{}, err
+	}
+	if stay.hold.ID != uuid.Nil {
+		if err := stay.hold.Consume(booking.ID, now); err != nil {
+			return domain.Booking{}, err
+		}
+		if err := s.repo.SaveInventoryHold(ctx, stay.hold); err != nil {
+			return domain.Booking{}, err
+		}
+	}
+	if stay.promo.ID != uuid.Nil {
+		redemption := domain.Redemption{
+			ID:          uuid.New(),
+			PromotionID: stay.promo.ID,
+			BookingID:   booking.ID,
+			UserID:      owner,
+			Status:      domain.RedemptionActive,
+			CreatedAt:   now,
+		}
+		if err := s.repo.CreateRedemption(ctx, redemption); err != nil {
+			return domain.Booking{}, err
+		}
+	}
+	if err := s.recordOnBehalf(ctx, "create", booking); err != nil {
+		return domain.Booking{}, err
+	}
+	if err := s.stageEvents(ctx, &booking); err != nil {
+		return domain.Booking{}, err
 	}
+	return booking, nil
+}
 
-	// Publish domain events
-	s.publishEvents(ctx, booking.Events())
-	booking.ClearEvents()
+// pricedStay is a stay priced the way booking it charges: its rooms with taxes and fees and the
+// promotion taken off, and the currencies the price is shown and charged in.
+type pricedStay struct {
+	items          []domain.LineItem
+	timing         string
+	promo          domain.Promotion
+	currency       string
+	rate           valueobject.ExchangeRate
+	chargeCurrency string
+	hold           domain.InventoryHold // the inventory hold the stay books, zero when none
+}
 
-	paymentResult, err := s.payments.Initiate(ctx, booking.ID, booking.TotalPrice)
+// priceStay prices the rooms cmd asks for once it has checked owner can book them: the calendar,
+// capacity, rate plans, free inventory and the promo code. Rooms an inventory hold of owner keeps
+// aside skip the calendar and inventory checks. It locks the room types and the promotion, so it
+// must run inside a transaction.
+func (s *Service) priceStay(ctx context.Context, owner uuid.UUID, dateRange valueobject.DateRange, cmd assembler.CreateCommand) (pricedStay, error) {
+	requested := cmd.LineItems()
+	roomTypes, err := s.lockRoomTypes(ctx, requested)
 	if err != nil {
-		return domain.Booking{}, domain.PaymentResult{}, err
+		return pricedStay{}, err
+	}
+	var hold domain.InventoryHold
+	if cmd.HoldID != uuid.Nil {
+		if hold, err = s.heldRooms(ctx, owner, cmd, roomTypes); err != nil {
+			return pricedStay{}, err
+		}
+	}
+	for _, rt := range roomTypes {
+		// The calendar was checked when the rooms were held; closing it since does not take them away.
+		if rt.ID == hold.RoomTypeID {
+			continue
+		}
+		if err := s.checkCalendar(ctx, rt.ID, dateRange); err != nil {
+			return pricedStay{}, err
+		}
 	}
 
-	return booking, paymentResult, nil
+	currency, rate, chargeCurrency, err := s.pickCurrencies(ctx, roomTypes, cmd)
+	if err != nil {
+		return pricedStay{}, err
+	}
+
+	rooms := make(map[uuid.UUID]int, len(roomTypes))
+	items := make([]domain.LineItem, 0, len(requested))
+	plans := make([]hdomain.RatePlan, 0, len(requested))
+	for _, req := range requested {
+		rt := roomTypes[req.RoomTypeID]
+		if req.Guests > rt.Capacity {
+			return pricedStay{}, errors.New("bad_request", "guests exceed room type capacity")
+		}
+		plan, err := s.ratePlan(ctx, rt, req.RatePlanID)
+		if err != nil {
+			return pricedStay{}, err
+		}
+		price, err := s.quote(ctx, rt, plan.Plan, currency, dateRange, req.Guests)
+		if err != nil {
+			return pricedStay{}, err
+		}
+		rooms[rt.ID]++
+		plans = append(plans, plan)
+		items = append(items, domain.LineItem{
+			ID:                 uuid.New(),
+			RoomTypeID:         rt.ID,
+			RatePlanID:         plan.ID,
+			CancellationPolicy: plan.Plan.CancellationPolicy,
+			Guests:             req.Guests,
+			Price:              price,
+			Status:             domain.ItemStatusActive,
+		})
+	}
+	timing, err := paymentTiming(plans)
+	if err != nil {
+		return pricedStay{}, err
+	}
+	// Every room type must fit the whole group, otherwise nothing is booked.
+	for roomTypeID, n := range rooms {
+		exclude := uuid.Nil
+		if roomTypeID == hold.RoomTypeID {
+			// Rooms the hold keeps aside are booked whatever else sold meanwhile; only rooms
+			// beyond the hold compete for the free inventory.
+			if n <= hold.Rooms {
+				continue
+			}
+			exclude = hold.ID
+		}
+		if err := s.ensureInventory(ctx, roomTypeID, dateRange, exclude, n); err != nil {
+			return pricedStay{}, err
+		}
+	}
+	var promo domain.Promotion
+	if cmd.PromoCode != "" {
+		if promo, err = s.applyPromotion(ctx, owner, cmd.PromoCode, dateRange.Nights(), items, roomTypes, currency); err != nil {
+			return pricedStay{}, err
+		}
+	}
+	// Taxes and fees are levied on the discounted price.
+	for i := range items {
+		if items[i].Price, err = s.levyCharges(ctx, roomTypes[items[i].RoomTypeID], currency, dateRange.Nights(), items[i].Price); err != nil {
+			return pricedStay{}, err
+		}
+	}
+	return pricedStay{
+		items:          items,
+		timing:         timing,
+		promo:          promo,
+		currency:       currency,
+		rate:           rate,
+		chargeCurrency: chargeCurrency,
+		hold:           hold,
+	}, nil
+}
+
+// lockRoomTypes locks every requested room type, in a stable order so concurrent group
+// bookings cannot deadlock. Locking serializes bookings competing for the same inventory.
+func (s *Service) lockRoomTypes(ctx context.Context, items []assembler.ItemCommand) (map[uuid.UUID]hdomain.RoomType, error) {
+	ids := make([]uuid.UUID, 0, len(items))
+	seen := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		if !seen[item.RoomTypeID] {
+			seen[item.RoomTypeID] = true
+			ids = append(ids, item.RoomTypeID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	roomTypes := make(map[uuid.UUID]hdomain.RoomType, len(ids))
+	for _, id := range ids {
+		rt, err := s.hotels.GetRoomTypeForUpdate(ctx, id)
+		if err != nil {
+			return nil, errors.New("not_found", "room type not found")
+		}
+		roomTypes[id] = rt
+	}
+	return roomTypes, nil
 }
 
-func (s *Service) CancelBooking(ctx context.Context, id uuid.UUID) error {
-	booking, err := s.repo.FindByID(ctx, id)
+// ensureInventory rejects the stay when fewer than rooms bookable rooms of the type are free, counting
+// the rooms active inventory holds keep aside as taken. The booking or hold identified by exclude is
+// ignored so neither competes with itself. Callers must hold the room type lock so the check stays
+// valid until the booking is written.
+func (s *Service) ensureInventory(ctx context.Context, roomTypeID uuid.UUID, stay valueobject.DateRange, exclude uuid.UUID, rooms int) error {
+	totalRooms, err := s.hotels.CountBookableRooms(ctx, roomTypeID)
 	if err != nil {
 		return err
 	}
-
-	// Use domain method
-	if err := booking.Cancel("user_requested"); err != nil {
+	overlapping, err := s.repo.FindOverlapping(ctx, roomTypeID, stay)
+	if err != nil {
 		return err
 	}
-
-	if err := s.repo.Save(ctx, booking); err != nil {
+	others := overlapping[:0]
+	for _, b := range overlapping {
+		if b.ID != exclude {
+			others = append(others, b)
+		}
+	}
+	holds, err := s.repo.FindActiveHolds(ctx, roomTypeID, stay, time.Now())
+	if err != nil {
 		return err
 	}
-
-	s.publishEvents(ctx, booking.Events())
+	otherHolds := holds[:0]
+	for _, h := range holds {
+		if h.ID != exclude {
+			otherHolds = append(otherHolds, h)
+		}
+	}
+	if domain.NewInventoryService().RoomsLeft(roomTypeID, totalRooms, stay, others, otherHolds) < rooms {
+		return errors.New("conflict", "no rooms available for the selected dates")
+	}
 	return nil
 }
 
-func (s *Service) ApplyStatus(ctx context.Context, id uuid.UUID, status string) error {
-	// Deprecated: Use specific domain methods instead (Confirm, CheckIn, Complete)
-	// Keeping for backward compatibility if needed, but redirecting to domain methods where possible
-	booking, err := s.repo.FindByID(ctx, id)
+// SearchAvailability lists room types with free inventory for the whole stay, priced at their own
+// terms and under each of their rate plans.
+func (s *Service) SearchAvailability(ctx context.Context, q assembler.AvailabilityQuery, opts query.Options) ([]assembler.RoomAvailability, error) {
+	stay, err := valueobject.NewDateRange(q.CheckIn, q.CheckOut)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var updateErr error
-	switch status {
-	case domain.StatusConfirmed:
-		updateErr = booking.Confirm()
-	case domain.StatusCancelled:
-		updateErr = booking.Cancel("admin_requested")
-	case domain.StatusCheckedIn:
-		updateErr = booking.GuestCheckIn()
-
-	case domain.StatusCompleted:
-		updateErr = booking.Complete()
-	default:
-		// Fallback for direct status update (legacy)
-		return s.repo.UpdateStatus(ctx, id, status)
+	var roomTypes []hdomain.RoomType
+	if q.HotelID != uuid.Nil {
+		roomTypes, err = s.hotels.ListRoomTypes(ctx, q.HotelID)
+	} else {
+		roomTypes, err = s.hotels.ListAllRoomTypes(ctx, opts.Normalize(50))
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	if updateErr != nil {
-		return updateErr
+	inventory := domain.NewInventoryService()
+	results := make([]assembler.RoomAvailability, 0, len(roomTypes))
+	for _, rt := range roomTypes {
+		if rt.Capacity < q.Guests {
+			continue
+		}
+		// Stays the calendar closes cannot be booked, so they are not offered either.
+		switch err := s.checkCalendar(ctx, rt.ID, stay); {
+		case errors.FromError(err).Code == "unprocessable_entity":
+			continue
+		case err != nil:
+			return nil, err
+		}
+		totalRooms, err := s.hotels.CountBookableRooms(ctx, rt.ID)
+		if err != nil {
+			return nil, err
+		}
+		overlapping, err := s.repo.FindOverlapping(ctx, rt.ID, stay)
+		if err != nil {
+			return nil, err
+		}
+		holds, err := s.repo.FindActiveHolds(ctx, rt.ID, stay, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		nights := inventory.NightlyAvailability(rt.ID, totalRooms, stay, overlapping, holds)
+		left := domain.MinAvailable(nights)
+		if left <= 0 {
+			continue
+		}
+		currency, err := s.hotelCurrency(ctx, rt.HotelID)
+		if err != nil {
+			return nil, err
+		}
+		price, err := s.quote(ctx, rt, valueobject.RatePlan{}, currency, stay, q.Guests)
+		if err != nil {
+			return nil, err
+		}
+		if price, err = s.levyCharges(ctx, rt, currency, stay.Nights(), price); err != nil {
+			return nil, err
+		}
+		plans, err := s.ratePlanQuotes(ctx, rt, currency, stay, q.Guests)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, assembler.RoomAvailability{
+			RoomType:    rt,
+			RoomsLeft:   left,
+			TotalNights: stay.Nights(),
+			QuotedTotal: price.Total,
+			RatePlans:   plans,
+			Nights:      nights,
+		})
 	}
+	return results, nil
+}
 
-	if err := s.repo.Save(ctx, booking); err != nil {
-		return err
+// quote prices one room for a stay night by night, from the calendar rates of its room type and the
+// pricing rules of its room type and hotel, adjusted by the rate plan, in the hotel's currency.
+func (s *Service) quote(ctx context.Context, rt hdomain.RoomType, plan valueobject.RatePlan, currency string, stay valueobject.DateRange, guests int) (domain.PriceBreakdown, error) {
+	rules, err := s.hotels.ListPricingRules(ctx, rt.HotelID)
+	if err != nil {
+		return domain.PriceBreakdown{}, err
+	}
+	calendar, err := s.hotels.ListCalendar(ctx, rt.ID, stay.Start, stay.End)
+	if err != nil {
+		return domain.PriceBreakdown{}, err
 	}
+	return domain.NewPricingService().Quote(currency, rt.BasePrice, calendar.Rates(), stay, guests, hdomain.RulesFor(rules, rt.ID), plan), nil
+}
 
-	s.publishEvents(ctx, booking.Events())
-	return nil
+// levyCharges adds the taxes and fees of the room type's hotel to a priced room.
+func (s *Service) levyCharges(ctx context.Context, rt hdomain.RoomType, currency string, nights int, price domain.PriceBreakdown) (domain.PriceBreakdown, error) {
+	rules, err := s.hotels.ListChargeRules(ctx, rt.HotelID)
+	if err != nil {
+		return domain.PriceBreakdown{}, err
+	}
+	return domain.NewPricingService().ApplyCharges(currency, price, nights, hdomain.ChargesOf(rules)), nil
 }
 
-func (s *Service) Checkpoint(ctx context.Context, id uuid.UUID, action string) error {
-	bk, err := s.repo.FindByID(ctx, id)
+// checkCalendar rejects stays the room type's calendar closes: arrivals or departures on closed
+// dates, and stays shorter or longer than the arrival date allows.
+func (s *Service) checkCalendar(ctx context.Context, roomTypeID uuid.UUID, stay valueobject.DateRange) error {
+	calendar, err := s.hotels.ListCalendar(ctx, roomTypeID, stay.Start, stay.End)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return errors.New("not_found", "booking not found")
-		}
 		return err
 	}
+	return calendar.CheckStay(stay)
+}
 
-	var updateErr error
-	switch action {
-	case "check_in":
-		updateErr = bk.GuestCheckIn()
+// ModifyBooking moves a confirmed booking to new dates or a new guest count.
+// The new stay is repriced and the difference is charged as a supplementary payment or partially refunded,
+// unless the guest pays at the hotel.
+func (s *Service) ModifyBooking(ctx context.Context, id uuid.UUID, cmd assembler.ModifyCommand) (assembler.ModificationResult, error) {
+	var (
+		bk     domain.Booking
+		result assembler.ModificationResult
+		due    *domain.SettlementDue
+	)
+	err := s.writeBooking(ctx, cmd.ExpectedVersion, func(ctx context.Context) error {
+		due = nil
+		var err error
+		bk, err = s.findOwned(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := bk.CheckVersion(cmd.ExpectedVersion); err != nil {
+			return err
+		}
+
+		stay := bk.Stay()
+		if !cmd.CheckIn.IsZero() {
+			if stay, err = valueobject.NewDateRange(cmd.CheckIn, cmd.CheckOut); err != nil {
+				return err
+			}
+		}
+		guests := bk.Guests
+		if cmd.Guests > 0 {
+			guests = cmd.Guests
+		}
+
+		rt, err := s.hotels.GetRoomTypeForUpdate(ctx, bk.RoomTypeID)
+		if err != nil {
+			return errors.New("not_found", "room type not found")
+		}
+		if guests > rt.Capacity {
+			return errors.New("bad_request", "guests exceed room type capacity")
+		}
+		// Restrictions apply to new dates; a guest count change keeps the booked stay.
+		if !cmd.CheckIn.IsZero() {
+			if err := s.checkCalendar(ctx, rt.ID, stay); err != nil {
+				return err
+			}
+		}
+		if err := s.ensureInventory(ctx, rt.ID, stay, bk.ID, 1); err != nil {
+			return err
+		}
 
-	case "complete":
-		updateErr = bk.Complete()
-	default:
-		return errors.New("bad_request", "unknown checkpoint action")
+		// The stay is repriced under the rate plan it was booked on.
+		plan, err := s.ratePlan(ctx, rt, bk.ActiveItems()[0].RatePlanID)
+		if err != nil {
+			return err
+		}
+		price, err := s.quote(ctx, rt, plan.Plan, bk.PriceCurrency(), stay, guests)
+		if err != nil {
+			return err
+		}
+		if price, err = s.reapplyPromotion(ctx, bk, rt, price); err != nil {
+			return err
+		}
+		if price, err = s.levyCharges(ctx, rt, bk.PriceCurrency(), stay.Nights(), price); err != nil {
+			return err
+		}
+		oldTotal := bk.TotalPrice
+		if err := bk.Modify(stay, guests, price); err != nil {
+			return err
+		}
+		if err := s.repo.Save(ctx, &bk); err != nil {
+			return err
+		}
+		if err := s.recordOnBehalf(ctx, "modify", bk); err != nil {
+			return err
+		}
+		if err := s.stageEvents(ctx, &bk); err != nil {
+			return err
+		}
+
+		// The difference is staged in the outbox with the modification and only settled once it
+		// has committed. A booking paid at the hotel settles the new total on arrival.
+		result.PriceDelta = bk.TotalPrice.Sub(oldTotal)
+		var settlement domain.SettlementDue
+		switch {
+		case bk.PaysAtHotel():
+			return nil
+		case result.PriceDelta.IsPositive():
+			settlement = domain.NewSettlementDue(bk, domain.SettleSupplement, bk.Charge(result.PriceDelta), "")
+		case result.PriceDelta.IsNegative():
+			settlement = domain.NewSettlementDue(bk, domain.SettleRefund, bk.Charge(result.PriceDelta.Neg()), "booking_modified")
+		default:
+			return nil
+		}
+		due = &settlement
+		return s.stageSettlement(ctx, settlement)
+	})
+	if err != nil {
+		return assembler.ModificationResult{}, err
 	}
 
-	if updateErr != nil {
-		return updateErr
+	result.Booking = bk
+	// Settling right away lets the caller pay a supplement from the response. When it fails the
+	// modification stands and the outbox relay settles it later.
+	if due != nil {
+		if payment, err := s.settle(ctx, *due); err == nil {
+			result.Payment = payment
+		}
 	}
+	return result, nil
+}
 
-	if err := s.repo.Save(ctx, bk); err != nil {
+// CancelBooking cancels a booking and refunds a confirmed one according to its cancellation policy.
+// A non-zero expectedVersion must match the booking's current version; otherwise a concurrent
+// change is retried against the fresh state.
+func (s *Service) CancelBooking(ctx context.Context, id uuid.UUID, expectedVersion int) (assembler.CancellationResult, error) {
+	var (
+		result assembler.CancellationResult
+		due    *domain.SettlementDue
+	)
+	err := s.writeBooking(ctx, expectedVersion, func(ctx context.Context) error {
+		booking, err := s.findOwned(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := booking.CheckVersion(expectedVersion); err != nil {
+			return err
+		}
+		if err := s.recordOnBehalf(ctx, "cancel", booking); err != nil {
+			return err
+		}
+		result, due, err = s.cancel(ctx, &booking, "user_requested")
 		return err
+	})
+	if err != nil {
+		return assembler.CancellationResult{}, err
 	}
+	s.settleCommitted(ctx, due)
+	return result, nil
+}
+
+func (s *Service) ApplyStatus(ctx context.Context, id uuid.UUID, status string, expectedVersion int) error {
+	// Deprecated: Use specific domain methods instead (Confirm, CheckIn, Complete)
+	// Keeping for backward compatibility if needed, but redirecting to domain methods where possible
+	var (
+		booking domain.Booking
+		due     *domain.SettlementDue
+	)
+	err := s.writeBooking(ctx, expectedVersion, func(ctx context.Context) error {
+		var err error
+		due = nil
+		booking, err = s.findOwned(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := booking.CheckVersion(expectedVersion); err != nil {
+			return err
+		}
+		if err := s.recordOnBehalf(ctx, "status."+status, booking); err != nil {
+			return err
+		}
 
-	s.publishEvents(ctx, bk.Events())
+		var updateErr error
+		switch status {
+		case domain.StatusConfirmed:
+			updateErr = booking.Confirm()
+		case domain.StatusCancelled:
+			_, due, err = s.cancel(ctx, &booking, "admin_requested")
+			return err
+		case domain.StatusNoShow:
+			due, err = s.markNoShow(ctx, &booking, time.Now())
+			return err
+		case domain.StatusCheckedIn:
+			updateErr = s.checkIn(ctx, &booking, uuid.Nil)
+
+		case domain.StatusCompleted:
+			updateErr = s.complete(ctx, &booking)
+		default:
+			return errors.New("bad_request", "unknown booking status")
+		}
+
+		if updateErr != nil {
+			return updateErr
+		}
+
+		if err := s.repo.Save(ctx, &booking); err != nil {
+			return err
+		}
+		return s.stageEvents(ctx, &booking)
+	})
+	if err != nil {
+		return err
+	}
+	s.settleCommitted(ctx, due)
 	return nil
 }
 
-func (s *Service) GetBooking(ctx context.Context, id uuid.UUID) (domain.Booking, error) {
-	b, err := s.repo.FindByID(ctx, id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return domain.Booking{}, errors.New("not_found", "booking not found")
+func (s *Service) Checkpoint(ctx context.Context, id uuid.UUID, cmd assembler.CheckpointCommand) error {
+	var bk domain.Booking
+	err := s.writeBooking(ctx, cmd.ExpectedVersion, func(ctx context.Context) error {
+		var err error
+		bk, err = s.findOwned(ctx, id)
+		if err != nil {
+			return err
 		}
-		return domain.Booking{}, err
-	}
-	return b, nil
+		if err := bk.CheckVersion(cmd.ExpectedVersion); err != nil {
+			return err
+		}
+		if err := s.recordOnBehalf(ctx, "checkpoint."+cmd.Action, bk); err != nil {
+			return err
+		}
+
+		var updateErr error
+		switch cmd.Action {
+		case "check_in":
+			updateErr = s.checkIn(ctx, &bk, cmd.RoomID)
+
+		case "complete":
+			updateErr = s.complete(ctx, &bk)
+		default:
+			return errors.New("bad_request", "unknown checkpoint action")
+		}
+
+		if updateErr != nil {
+			return updateErr
+		}
+
+		if err := s.repo.Save(ctx, &bk); err != nil {
+			return err
+		}
+		return s.stageEvents(ctx, &bk)
+	})
+	return err
+}
+
+// GetBooking returns a booking of the caller on ctx; admins may read any booking.
+func (s *Service) GetBooking(ctx context.Context, id uuid.UUID) (domain.Booking, error) {
+	return s.findOwned(ctx, id)
 }
 
-func (s *Service) ListBookings(ctx context.Context, opts query.Options) ([]domain.Booking, error) {
-	bks, err := s.repo.List(ctx, opts.Normalize(50))
+// ListBookings lists every booking for admins and only their own for customers.
+func (s *Service) ListBookings(ctx context.Context, opts query.Options) (query.Page[domain.Booking], error) {
+	opts = opts.Normalize(50)
+	var (
+		page query.Page[domain.Booking]
+		err  error
+	)
+	if userID, ok := customerID(ctx); ok {
+		page, err = s.repo.FindByUserID(ctx, userID, opts)
+	} else {
+		page, err = s.repo.List(ctx, opts)
+	}
 	if err != nil {
-		return nil, err
+		return query.Page[domain.Booking]{}, err
 	}
-	return bks, nil
+	return page, nil
 }
 
-func (s *Service) publishEvents(ctx context.Context, events []pkgDomain.DomainEvent) {
-	for _, event := range events {
-		_ = s.notifier.Notify(ctx, event.EventType(), event)
+// stageEvents records the booking's pending events in its history and the outbox, then clears them.
+// It must run in the transaction that saves the booking so events are never lost or sent for rolled-back changes.
+func (s *Service) stageEvents(ctx context.Context, bk *domain.Booking) error {
+	if err := s.repo.AppendHistory(ctx, bk.Events()); err != nil {
+		return err
 	}
+	if err := s.repo.AppendEvents(ctx, bk.Events()); err != nil {
+		return err
+	}
+	bk.ClearEvents()
+	return nil
+}
+
+// paymentRequest attaches the Idempotency-Key of a payment initiation to ctx. The key is derived
+// from the booking version that caused the charge, so resending it never creates a second payment.
+func paymentRequest(ctx context.Context, bk domain.Booking, kind string) context.Context {
+	return middleware.WithIdempotencyKey(ctx, fmt.Sprintf("booking:%s:%s:v%d", bk.ID, kind, bk.Version))
 }
 
 // AutoCheckout processes bookings that should be automatically checked out.
@@ -198,32 +757,34 @@ func (s *Service) AutoCheckout(ctx context.Context) (int, error) {
 	today := time.Now().Truncate(24 * time.Hour)
 	
 	// Find bookings that need auto-checkout
-	// We'll use the repository's List method with a filter
-	// For simplicity, we'll get all bookings and filter in memory
-	// In production, you'd want to add a specific repository method
-	allBookings, err := s.repo.List(ctx, query.Options{Limit: 1000})
+	// The repository narrows the list to checked-in bookings; the checkout date is matched in memory
+	checkedIn, err := s.repo.List(ctx, query.Options{
+		Limit:   1000,
+		Filters: []query.Filter{{Field: "status", Op: query.OpEq, Value: domain.StatusCheckedIn}},
+	})
 	if err != nil {
 		return 0, err
 	}
 
 	count := 0
-	for _, booking := range allBookings {
+	for _, booking := range checkedIn.Items {
 		// Check if booking should be auto-checked-out
 		checkoutDate := booking.CheckOut.Truncate(24 * time.Hour)
 		if checkoutDate.Equal(today) && booking.Status == string(valueobject.StatusCheckedIn) {
-			// Complete the booking
-			if err := booking.Complete(); err != nil {
+			// Complete the booking and hand the room to housekeeping
+			err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+				if err := s.complete(ctx, &booking); err != nil {
+					return err
+				}
+				if err := s.repo.Save(ctx, &booking); err != nil {
+					return err
+				}
+				return s.stageEvents(ctx, &booking)
+			})
+			if err != nil {
 				// Log error but continue with other bookings
 				continue
 			}
-
-			if err := s.repo.Save(ctx, booking); err != nil {
-				// Log error but continue
-				continue
-			}
-
-			s.publishEvents(ctx, booking.Events())
-			booking.ClearEvents()
 			count++
 		}
 	}
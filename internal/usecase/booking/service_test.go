@@ -2,7 +2,8 @@ package booking_test
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
+	stdErrors "errors"
 	"testing"
 	"time"
 
@@ -13,7 +14,11 @@ import (
 	hdomain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
 	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking"
 	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/audit"
+	pkgDomain "github.com/ftryyln/hotel-booking-microservices/pkg/domain"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
@@ -21,7 +26,7 @@ import (
 func TestCreateBooking(t *testing.T) {
 	roomTypeID := uuid.New()
 	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
-	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: 500000}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(500000), Capacity: 2}, rooms: 1}
 	payment := &paymentGatewayStub{}
 	notifier := &notificationGatewayStub{}
 	service := booking.NewService(repo, hotelRepo, payment, notifier)
@@ -65,7 +70,7 @@ func TestCreateBooking(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.name == "missing room type" {
-				hotelRepo.err = errors.New("not found")
+				hotelRepo.err = stdErrors.New("not found")
 			} else {
 				hotelRepo.err = nil
 			}
@@ -83,27 +88,1397 @@ func TestCreateBooking(t *testing.T) {
 	}
 }
 
+func TestCreateBookingWithGuestDetails(t *testing.T) {
+	roomTypeID := uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(500000), Capacity: 2}, rooms: 1}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	req := dto.BookingRequest{
+		UserID:     uuid.New().String(),
+		RoomTypeID: roomTypeID.String(),
+		CheckIn:    dto.Date{Time: time.Now().Add(24 * time.Hour)},
+		CheckOut:   dto.Date{Time: time.Now().Add(48 * time.Hour)},
+		Guests:     2,
+		Guest: &dto.GuestDetails{
+			Name:             "Jane Doe",
+			Phone:            "+62 812 3456 7890",
+			Nationality:      "id",
+			AdditionalGuests: []string{"John Doe"},
+		},
+		ArrivalTime:     "21:30",
+		SpecialRequests: "extra bed, high floor",
+	}
+	cmd, err := assembler.FromRequest(req)
+	require.NoError(t, err)
+	bk, _, err := service.CreateBooking(context.Background(), cmd)
+	require.NoError(t, err)
+	require.Equal(t, "+6281234567890", bk.Guest.Phone)
+	require.Equal(t, "ID", bk.Guest.Nationality)
+	require.Equal(t, "21:30", bk.ArrivalTime)
+
+	resp := assembler.ToResponse(bk, domain.PaymentResult{})
+	require.Equal(t, "Jane Doe", resp.Guest.Name)
+	require.Equal(t, []string{"John Doe"}, resp.Guest.AdditionalGuests)
+	require.Equal(t, "extra bed, high floor", resp.SpecialRequests)
+
+	// More named guests than booked guests is rejected.
+	req.Guests = 1
+	_, err = assembler.FromRequest(req)
+	require.Error(t, err)
+}
+
+func TestAddNote(t *testing.T) {
+	bk := domain.Booking{ID: uuid.New(), Status: domain.StatusConfirmed}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{bk.ID: bk}}
+	service := booking.NewService(repo, &hotelRepoStub{}, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	author := uuid.New()
+	note, err := service.AddNote(context.Background(), bk.ID, author, "  late check-out approved ")
+	require.NoError(t, err)
+	require.Equal(t, "late check-out approved", note.Body)
+
+	notes, err := service.ListNotes(context.Background(), bk.ID)
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	require.Equal(t, author, notes[0].AuthorID)
+
+	_, err = service.AddNote(context.Background(), bk.ID, author, " ")
+	require.Error(t, err)
+}
+
+func TestCreateBookingRejectsOverbooking(t *testing.T) {
+	roomTypeID := uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(500000), Capacity: 2}, rooms: 1}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	checkIn := time.Now().Add(24 * time.Hour)
+	cmd := assembler.CreateCommand{
+		UserID:     uuid.New(),
+		RoomTypeID: roomTypeID,
+		CheckIn:    checkIn,
+		CheckOut:   checkIn.Add(48 * time.Hour),
+		Guests:     1,
+	}
+	_, _, err := service.CreateBooking(context.Background(), cmd)
+	require.NoError(t, err)
+
+	// Overlapping stay for the last room must be rejected.
+	cmd.CheckIn = checkIn.Add(24 * time.Hour)
+	cmd.CheckOut = checkIn.Add(72 * time.Hour)
+	_, _, err = service.CreateBooking(context.Background(), cmd)
+	require.Error(t, err)
+	require.Equal(t, "conflict", err.(errors.APIError).Code)
+
+	// Back-to-back stay reuses the room once the first guest checks out.
+	cmd.CheckIn = checkIn.Add(48 * time.Hour)
+	_, _, err = service.CreateBooking(context.Background(), cmd)
+	require.NoError(t, err)
+}
+
+func TestCreateBookingRejectsGuestsAboveCapacity(t *testing.T) {
+	roomTypeID := uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(500000), Capacity: 2}, rooms: 5}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	_, _, err := service.CreateBooking(context.Background(), assembler.CreateCommand{
+		UserID:     uuid.New(),
+		RoomTypeID: roomTypeID,
+		CheckIn:    time.Now().Add(24 * time.Hour),
+		CheckOut:   time.Now().Add(48 * time.Hour),
+		Guests:     3,
+	})
+	require.Error(t, err)
+	require.Empty(t, repo.store)
+}
+
+func TestSearchAvailability(t *testing.T) {
+	roomTypeID := uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(500000), Capacity: 2}, rooms: 2}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	day := time.Date(2030, 3, 1, 0, 0, 0, 0, time.UTC)
+	existing := domain.Booking{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: day, CheckOut: day.AddDate(0, 0, 1), Status: domain.StatusConfirmed}
+	repo.store[existing.ID] = existing
+
+	q := assembler.AvailabilityQuery{CheckIn: day, CheckOut: day.AddDate(0, 0, 2), Guests: 2}
+	results, err := service.SearchAvailability(context.Background(), q, query.Options{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, 1, results[0].RoomsLeft)
+	require.Equal(t, valueobject.AmountOf(1000000), results[0].QuotedTotal)
+	require.Len(t, results[0].Nights, 2)
+	require.Equal(t, 1, results[0].Nights[0].Available)
+	require.Equal(t, 2, results[0].Nights[1].Available)
+
+	q.Guests = 3
+	results, err = service.SearchAvailability(context.Background(), q, query.Options{})
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	// Stays the calendar closes are not offered.
+	hotelRepo.calendar = []hdomain.CalendarDay{{RoomTypeID: roomTypeID, Date: day, MinStay: 3}}
+	q.Guests = 2
+	results, err = service.SearchAvailability(context.Background(), q, query.Options{})
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestSearchAvailabilityTimedCheckout(t *testing.T) {
+	roomTypeID := uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(500000), Capacity: 2}, rooms: 1}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	// The only room is taken for the nights of 1 and 2 November, checking out at noon on the 3rd.
+	day := time.Date(2030, 11, 1, 0, 0, 0, 0, time.UTC)
+	existing := domain.Booking{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: day.Add(14 * time.Hour), CheckOut: day.AddDate(0, 0, 2).Add(12 * time.Hour), Status: domain.StatusConfirmed}
+	repo.store[existing.ID] = existing
+
+	// The night of the checkout day is free for the next guest.
+	results, err := service.SearchAvailability(context.Background(), assembler.AvailabilityQuery{CheckIn: day.AddDate(0, 0, 2), CheckOut: day.AddDate(0, 0, 3), Guests: 1}, query.Options{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, 1, results[0].RoomsLeft)
+
+	results, err = service.SearchAvailability(context.Background(), assembler.AvailabilityQuery{CheckIn: day.AddDate(0, 0, 1), CheckOut: day.AddDate(0, 0, 3), Guests: 1}, query.Options{})
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestCheckpointAssignsRoomOnCheckIn(t *testing.T) {
+	roomTypeID := uuid.New()
+	busy := hdomain.Room{ID: uuid.New(), RoomTypeID: roomTypeID, Number: "101", Status: "available"}
+	free := hdomain.Room{ID: uuid.New(), RoomTypeID: roomTypeID, Number: "102", Status: "available"}
+	other := hdomain.Room{ID: uuid.New(), RoomTypeID: uuid.New(), Number: "201", Status: "available"}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID}, roomStore: []hdomain.Room{busy, free, other}}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	day := time.Now().Truncate(24 * time.Hour)
+	inHouse := domain.Booking{ID: uuid.New(), RoomTypeID: roomTypeID, RoomID: busy.ID, CheckIn: day, CheckOut: day.AddDate(0, 0, 3), Status: domain.StatusCheckedIn}
+	arriving := domain.Booking{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: day, CheckOut: day.AddDate(0, 0, 1), Status: domain.StatusConfirmed}
+	repo.store[inHouse.ID] = inHouse
+	repo.store[arriving.ID] = arriving
+
+	err := service.Checkpoint(context.Background(), arriving.ID, assembler.CheckpointCommand{Action: "check_in", RoomID: other.ID})
+	require.Error(t, err)
+	err = service.Checkpoint(context.Background(), arriving.ID, assembler.CheckpointCommand{Action: "check_in", RoomID: busy.ID})
+	require.Error(t, err)
+
+	require.NoError(t, service.Checkpoint(context.Background(), arriving.ID, assembler.CheckpointCommand{Action: "check_in"}))
+	checkedIn := repo.store[arriving.ID]
+	require.Equal(t, domain.StatusCheckedIn, checkedIn.Status)
+	require.Equal(t, free.ID, checkedIn.RoomID)
+	room, _ := hotelRepo.GetRoom(context.Background(), free.ID)
+	require.Equal(t, string(valueobject.RoomOccupied), room.Status)
+
+	require.NoError(t, service.Checkpoint(context.Background(), arriving.ID, assembler.CheckpointCommand{Action: "complete"}))
+	room, _ = hotelRepo.GetRoom(context.Background(), free.ID)
+	require.Equal(t, string(valueobject.RoomCleaning), room.Status)
+}
+
+func TestCheckpointReusesRoomTurnedOverToday(t *testing.T) {
+	roomTypeID := uuid.New()
+	room := hdomain.Room{ID: uuid.New(), RoomTypeID: roomTypeID, Number: "101", Status: string(valueobject.RoomCleaning)}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID}, roomStore: []hdomain.Room{room}}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	// The last guest checked out of the room at 11:00 today and housekeeping is still on it.
+	day := time.Now().Truncate(24 * time.Hour)
+	departed := domain.Booking{ID: uuid.New(), RoomTypeID: roomTypeID, RoomID: room.ID, CheckIn: day.AddDate(0, 0, -2), CheckOut: day.Add(11 * time.Hour), Status: domain.StatusCompleted}
+	arriving := domain.Booking{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: day, CheckOut: day.AddDate(0, 0, 1), Status: domain.StatusConfirmed}
+	repo.store[departed.ID] = departed
+	repo.store[arriving.ID] = arriving
+
+	require.NoError(t, service.Checkpoint(context.Background(), arriving.ID, assembler.CheckpointCommand{Action: "check_in"}))
+	require.Equal(t, room.ID, repo.store[arriving.ID].RoomID)
+}
+
+func TestModifyBookingSettlesPriceDelta(t *testing.T) {
+	roomTypeID := uuid.New()
+	checkIn := time.Now().Add(24 * time.Hour).Truncate(24 * time.Hour)
+	bk := domain.Booking{
+		ID:          uuid.New(),
+		RoomTypeID:  roomTypeID,
+		CheckIn:     checkIn,
+		CheckOut:    checkIn.Add(48 * time.Hour),
+		Status:      domain.StatusConfirmed,
+		Guests:      1,
+		TotalPrice:  valueobject.AmountOf(200),
+		TotalNights: 2,
+	}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{bk.ID: bk}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2}, rooms: 1}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
+
+	// Shortening the stay refunds the difference; the booking does not block itself.
+	res, err := service.ModifyBooking(context.Background(), bk.ID, assembler.ModifyCommand{CheckIn: checkIn, CheckOut: checkIn.Add(24 * time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(-100), res.PriceDelta)
+	require.Equal(t, []valueobject.Amount{valueobject.AmountOf(100)}, payments.refunds)
+	require.Equal(t, 1, repo.store[bk.ID].TotalNights)
+
+	// Extending it again charges a supplement.
+	res, err = service.ModifyBooking(context.Background(), bk.ID, assembler.ModifyCommand{CheckIn: checkIn, CheckOut: checkIn.Add(48 * time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(100), res.PriceDelta)
+	require.Equal(t, []valueobject.Amount{valueobject.AmountOf(100)}, payments.supplements)
+	require.NotEqual(t, uuid.Nil, res.Payment.ID)
+	require.Equal(t, valueobject.AmountOf(200), repo.store[bk.ID].TotalPrice)
+}
+
+func TestModifyBookingSettlesThroughOutbox(t *testing.T) {
+	roomTypeID := uuid.New()
+	checkIn := time.Now().Add(24 * time.Hour).Truncate(24 * time.Hour)
+	bk := domain.Booking{
+		ID:          uuid.New(),
+		RoomTypeID:  roomTypeID,
+		CheckIn:     checkIn,
+		CheckOut:    checkIn.Add(24 * time.Hour),
+		Status:      domain.StatusConfirmed,
+		Guests:      1,
+		TotalPrice:  valueobject.AmountOf(100),
+		TotalNights: 1,
+	}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{bk.ID: bk}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2}, rooms: 1}
+	payments := &paymentGatewayStub{settleErr: stdErrors.New("payment service down")}
+	notifier := &notificationGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, notifier)
+
+	// The payment service being down does not undo the modification; the supplement stays staged.
+	res, err := service.ModifyBooking(context.Background(), bk.ID, assembler.ModifyCommand{CheckIn: checkIn, CheckOut: checkIn.Add(48 * time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(100), res.PriceDelta)
+	require.Equal(t, uuid.Nil, res.Payment.ID)
+	require.Equal(t, 2, repo.store[bk.ID].TotalNights)
+	require.Empty(t, payments.supplements)
+
+	// The relay settles it later under the key of the first attempt, and does not notify it.
+	payments.settleErr = nil
+	_, err = service.RelayOutbox(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []valueobject.Amount{valueobject.AmountOf(100)}, payments.supplements)
+	require.Len(t, payments.settleKeys, 2)
+	require.Equal(t, payments.settleKeys[0], payments.settleKeys[1])
+	require.NotContains(t, notifier.events, domain.EventTypeSettlementDue)
+}
+
+func TestModifyBookingRejectsUnavailableDates(t *testing.T) {
+	roomTypeID := uuid.New()
+	checkIn := time.Now().Add(24 * time.Hour).Truncate(24 * time.Hour)
+	bk := domain.Booking{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkIn.Add(24 * time.Hour), Status: domain.StatusConfirmed, Guests: 1, TotalPrice: valueobject.AmountOf(100)}
+	other := domain.Booking{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: checkIn.Add(24 * time.Hour), CheckOut: checkIn.Add(48 * time.Hour), Status: domain.StatusConfirmed, Guests: 1}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{bk.ID: bk, other.ID: other}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2}, rooms: 1}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
+
+	_, err := service.ModifyBooking(context.Background(), bk.ID, assembler.ModifyCommand{CheckIn: checkIn, CheckOut: checkIn.Add(48 * time.Hour)})
+	require.Error(t, err)
+	require.Equal(t, "conflict", err.(errors.APIError).Code)
+	require.Empty(t, payments.supplements)
+
+	_, err = service.ModifyBooking(context.Background(), bk.ID, assembler.ModifyCommand{Guests: 3})
+	require.Error(t, err)
+	require.Equal(t, "bad_request", err.(errors.APIError).Code)
+}
+
+func TestCancelBookingAppliesPolicy(t *testing.T) {
+	roomTypeID := uuid.New()
+	soon := time.Now().Add(24 * time.Hour)
+	later := time.Now().Add(10 * 24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		policy     string
+		status     string
+		checkIn    time.Time
+		wantRefund float64
+	}{
+		{"flexible before window", valueobject.PolicyFlexible, domain.StatusConfirmed, later, 300},
+		{"flexible inside window", valueobject.PolicyFlexible, domain.StatusConfirmed, soon, 200},
+		{"non refundable", valueobject.PolicyNonRefundable, domain.StatusConfirmed, later, 0},
+		{"unpaid booking", valueobject.PolicyFlexible, domain.StatusPendingPayment, later, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bk := domain.Booking{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: tt.checkIn, CheckOut: tt.checkIn.Add(72 * time.Hour), Status: tt.status, Guests: 1, TotalPrice: valueobject.AmountOf(300), TotalNights: 3}
+			repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{bk.ID: bk}}
+			hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2, CancellationPolicy: tt.policy}}
+			payments := &paymentGatewayStub{}
+			service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
+
+			res, err := service.CancelBooking(context.Background(), bk.ID, 0)
+			require.NoError(t, err)
+			require.Equal(t, tt.policy, res.Policy)
+			require.Equal(t, valueobject.AmountOf(tt.wantRefund), res.RefundAmount)
+			require.Equal(t, domain.StatusCancelled, repo.store[bk.ID].Status)
+			if tt.wantRefund > 0 {
+				require.Len(t, payments.refunds, 1)
+			} else {
+				require.Empty(t, payments.refunds)
+			}
+		})
+	}
+}
+
+func TestCancelBookingRefundsThroughOutbox(t *testing.T) {
+	roomTypeID := uuid.New()
+	checkIn := time.Now().Add(10 * 24 * time.Hour)
+	bk := domain.Booking{ID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkIn.Add(72 * time.Hour), Status: domain.StatusConfirmed, Guests: 1, TotalPrice: valueobject.AmountOf(300), TotalNights: 3}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{bk.ID: bk}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2, CancellationPolicy: valueobject.PolicyFlexible}}
+	payments := &paymentGatewayStub{settleErr: stdErrors.New("payment service down")}
+	notifier := &notificationGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, notifier)
+
+	// The payment service being down does not undo the cancellation; the refund stays staged.
+	res, err := service.CancelBooking(context.Background(), bk.ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(300), res.RefundAmount)
+	require.Equal(t, domain.StatusCancelled, repo.store[bk.ID].Status)
+	require.Empty(t, payments.refunds)
+
+	// The relay refunds it later under the key of the first attempt.
+	payments.settleErr = nil
+	_, err = service.RelayOutbox(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []valueobject.Amount{valueobject.AmountOf(300)}, payments.refunds)
+	require.Len(t, payments.settleKeys, 2)
+	require.Equal(t, payments.settleKeys[0], payments.settleKeys[1])
+	require.NotContains(t, notifier.events, domain.EventTypeSettlementDue)
+}
+
+func TestCreateGroupBooking(t *testing.T) {
+	standard := hdomain.RoomType{ID: uuid.New(), BasePrice: valueobject.AmountOf(100), Capacity: 2}
+	family := hdomain.RoomType{ID: uuid.New(), BasePrice: valueobject.AmountOf(200), Capacity: 4}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomTypes: map[uuid.UUID]hdomain.RoomType{standard.ID: standard, family.ID: family}, rooms: 2}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
+
+	checkIn := time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC)
+	cmd := assembler.CreateCommand{
+		UserID:   uuid.New(),
+		CheckIn:  checkIn,
+		CheckOut: checkIn.AddDate(0, 0, 2),
+		Items: []assembler.ItemCommand{
+			{RoomTypeID: standard.ID, Guests: 2},
+			{RoomTypeID: standard.ID, Guests: 1},
+			{RoomTypeID: family.ID, Guests: 3},
+		},
+	}
+	bk, _, err := service.CreateBooking(context.Background(), cmd)
+	require.NoError(t, err)
+	require.Len(t, bk.Items, 3)
+	require.Equal(t, 6, bk.Guests)
+	require.Equal(t, standard.ID, bk.RoomTypeID)
+	// 2 standard rooms at 200 each plus a family room at 400 with an 80 surcharge for the third guest.
+	require.Equal(t, valueobject.AmountOf(880), bk.TotalPrice)
+	require.Equal(t, valueobject.AmountOf(80), bk.Items[2].Price.GuestSurcharge)
+	require.Equal(t, []valueobject.Amount{bk.TotalPrice}, payments.initiated)
+
+	// Only one standard room is left, so a group needing two of them is rejected as a whole.
+	cmd.Items = []assembler.ItemCommand{{RoomTypeID: family.ID, Guests: 2}, {RoomTypeID: standard.ID, Guests: 1}, {RoomTypeID: standard.ID, Guests: 1}}
+	_, _, err = service.CreateBooking(context.Background(), cmd)
+	require.Error(t, err)
+	require.Equal(t, "conflict", err.(errors.APIError).Code)
+	require.Len(t, repo.store, 1)
+	require.Len(t, payments.initiated, 1)
+}
+
+func TestCreateBookingPricesEachNightFromRules(t *testing.T) {
+	hotelID := uuid.New()
+	rt := hdomain.RoomType{ID: uuid.New(), HotelID: hotelID, BasePrice: valueobject.AmountOf(100), Capacity: 2}
+	day := func(d int) time.Time { return time.Date(2030, 3, d, 0, 0, 0, 0, time.UTC) }
+	rule := func(roomTypeID uuid.UUID, r valueobject.PricingRule) hdomain.PricingRule {
+		return hdomain.PricingRule{ID: uuid.New(), HotelID: hotelID, RoomTypeID: roomTypeID, Rule: r}
+	}
+	// Priced in dollars, so the cents of the long stay discount are kept.
+	hotelRepo := &hotelRepoStub{roomType: rt, rooms: 1, currency: "USD", rules: []hdomain.PricingRule{
+		rule(uuid.New(), valueobject.PricingRule{Kind: valueobject.PricingSeason, Name: "other room", StartDate: day(1), EndDate: day(31), Rate: valueobject.AmountOf(999)}),
+		rule(rt.ID, valueobject.PricingRule{Kind: valueobject.PricingSeason, Name: "festival", StartDate: day(2), EndDate: day(3), Rate: valueobject.AmountOf(200)}),
+		rule(uuid.Nil, valueobject.PricingRule{Kind: valueobject.PricingSeason, Name: "spring", StartDate: day(1), EndDate: day(31), Percent: 50}),
+		rule(uuid.Nil, valueobject.PricingRule{Kind: valueobject.PricingDayOfWeek, Name: "weekend", Weekdays: []time.Weekday{time.Saturday, time.Sunday}, Percent: 10}),
+	}}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	// Friday to Monday: three nights, so the default 5% long stay discount applies too.
+	bk, _, err := service.CreateBooking(context.Background(), assembler.CreateCommand{
+		UserID: uuid.New(), RoomTypeID: rt.ID, CheckIn: day(1), CheckOut: day(4), Guests: 1,
+	})
+	require.NoError(t, err)
+	nights := bk.Items[0].Price.Nights
+	require.Len(t, nights, 3)
+	require.Equal(t, []string{"spring", "long stay"}, nights[0].Rules)
+	require.Equal(t, valueobject.AmountOf(142.5), nights[0].Total)
+	require.Equal(t, []string{"festival", "weekend", "long stay"}, nights[1].Rules)
+	require.Equal(t, valueobject.AmountOf(220), nights[1].Rate)
+	require.Equal(t, valueobject.AmountOf(209), nights[1].Total)
+	require.Equal(t, valueobject.AmountOf(156.75), nights[2].Total)
+	require.Equal(t, valueobject.AmountOf(535), bk.Items[0].Price.Base)
+	require.Equal(t, valueobject.AmountOf(508.25), bk.TotalPrice)
+	require.Len(t, repo.store[bk.ID].Items[0].Price.Nights, 3)
+
+	resp := assembler.ToResponse(bk, domain.PaymentResult{})
+	require.Len(t, resp.Items[0].Price.Nights, 3)
+	require.Equal(t, day(2), resp.Items[0].Price.Nights[1].Date.Time)
+}
+
+func TestCreateBookingHonoursCalendar(t *testing.T) {
+	rt := hdomain.RoomType{ID: uuid.New(), HotelID: uuid.New(), BasePrice: valueobject.AmountOf(100), Capacity: 2}
+	day := func(d int) time.Time { return time.Date(2030, 12, d, 0, 0, 0, 0, time.UTC) }
+	hotelRepo := &hotelRepoStub{roomType: rt, rooms: 1, calendar: []hdomain.CalendarDay{
+		{RoomTypeID: rt.ID, Date: day(24), ClosedToArrival: true},
+		{RoomTypeID: rt.ID, Date: day(27), ClosedToDeparture: true},
+		{RoomTypeID: rt.ID, Date: day(30), MinStay: 2},
+		{RoomTypeID: rt.ID, Date: day(31), Price: valueobject.AmountOf(400)},
+	}}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+	book := func(in, out time.Time) (domain.Booking, error) {
+		bk, _, err := service.CreateBooking(context.Background(), assembler.CreateCommand{
+			UserID: uuid.New(), RoomTypeID: rt.ID, CheckIn: in, CheckOut: out, Guests: 1,
+		})
+		return bk, err
+	}
+
+	for name, stay := range map[string][2]time.Time{
+		"closed to arrival":   {day(24), day(25)},
+		"closed to departure": {day(26), day(27)},
+		"below minimum stay":  {day(30), day(31)},
+	} {
+		_, err := book(stay[0], stay[1])
+		require.Error(t, err, name)
+		require.Equal(t, "unprocessable_entity", errors.FromError(err).Code, name)
+	}
+	require.Empty(t, repo.store)
+
+	// New Year's Eve uses its calendar price, the night before falls back to the base price.
+	bk, err := book(day(30), day(31).AddDate(0, 0, 1))
+	require.NoError(t, err)
+	nights := bk.Items[0].Price.Nights
+	require.Equal(t, valueobject.AmountOf(100), nights[0].Rate)
+	require.Equal(t, valueobject.AmountOf(400), nights[1].Rate)
+	require.Equal(t, []string{domain.CalendarRule}, nights[1].Rules)
+	require.Equal(t, valueobject.AmountOf(500), bk.TotalPrice)
+}
+
+func TestPromotionRedemption(t *testing.T) {
+	standard := hdomain.RoomType{ID: uuid.New(), HotelID: uuid.New(), BasePrice: valueobject.AmountOf(100), Capacity: 2}
+	family := hdomain.RoomType{ID: uuid.New(), HotelID: standard.HotelID, BasePrice: valueobject.AmountOf(200), Capacity: 4}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomTypes: map[uuid.UUID]hdomain.RoomType{standard.ID: standard, family.ID: family}, rooms: 10}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
+	ctx := context.Background()
+
+	promo, err := service.CreatePromotion(ctx, domain.Promotion{
+		Code: " summer10 ", Kind: domain.PromotionPercent, Amount: valueobject.AmountOf(10),
+		MinNights: 2, MaxUses: 2, MaxUsesPerUser: 1, RoomTypeIDs: []uuid.UUID{standard.ID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "SUMMER10", promo.Code)
+	_, err = service.CreatePromotion(ctx, domain.Promotion{Code: "Summer10", Kind: domain.PromotionFixed, Amount: valueobject.AmountOf(50)})
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+
+	checkIn := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	book := func(userID uuid.UUID, nights int, items ...assembler.ItemCommand) (domain.Booking, error) {
+		bk, _, err := service.CreateBooking(ctx, assembler.CreateCommand{
+			UserID: userID, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, nights), Items: items, PromoCode: "SUMMER10",
+		})
+		return bk, err
+	}
+	standardRoom := assembler.ItemCommand{RoomTypeID: standard.ID, Guests: 1}
+	familyRoom := assembler.ItemCommand{RoomTypeID: family.ID, Guests: 2}
+
+	// Only the standard room is discounted: 10% of 200.
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	first, err := book(alice, 2, standardRoom, familyRoom)
+	require.NoError(t, err)
+	require.Equal(t, "SUMMER10", first.PromoCode)
+	require.Equal(t, valueobject.AmountOf(20), first.Items[0].Price.Promo)
+	require.Zero(t, first.Items[1].Price.Promo)
+	require.Equal(t, valueobject.AmountOf(580), first.TotalPrice)
+	require.Equal(t, []valueobject.Amount{valueobject.AmountOf(580)}, payments.initiated)
+
+	for name, attempt := range map[string]func() (domain.Booking, error){
+		"used by guest":    func() (domain.Booking, error) { return book(alice, 2, standardRoom) },
+		"too short":        func() (domain.Booking, error) { return book(bob, 1, standardRoom) },
+		"room not covered": func() (domain.Booking, error) { return book(bob, 2, familyRoom) },
+	} {
+		_, err := attempt()
+		require.Equal(t, "unprocessable_entity", errors.FromError(err).Code, name)
+	}
+	_, _, err = service.CreateBooking(ctx, assembler.CreateCommand{
+		UserID: bob, RoomTypeID: standard.ID, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), Guests: 1, PromoCode: "WINTER",
+	})
+	require.Equal(t, "unprocessable_entity", errors.FromError(err).Code)
+	require.Len(t, repo.store, 1)
+
+	second, err := book(bob, 2, standardRoom)
+	require.NoError(t, err)
+	_, err = book(carol, 2, standardRoom)
+	require.Equal(t, "unprocessable_entity", errors.FromError(err).Code, "used up")
+
+	// Cancelling a booking or letting its hold expire gives the use back.
+	_, err = service.CancelBooking(ctx, first.ID, 0)
+	require.NoError(t, err)
+	_, err = book(carol, 2, standardRoom)
+	require.NoError(t, err)
+	expired := repo.store[second.ID]
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	repo.store[second.ID] = expired
+	count, err := service.ExpireHolds(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	_, err = book(alice, 2, standardRoom)
+	require.NoError(t, err)
+
+	// A fixed discount never exceeds the price of the rooms it covers.
+	promo.Kind, promo.Amount, promo.MaxUses, promo.MaxUsesPerUser = domain.PromotionFixed, valueobject.AmountOf(500), 0, 0
+	_, err = service.UpdatePromotion(ctx, promo.ID, promo)
+	require.NoError(t, err)
+	free, err := book(uuid.New(), 2, standardRoom)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(200), free.Items[0].Price.Promo)
+	require.Zero(t, free.TotalPrice)
+
+	// A fixed discount only applies to bookings priced in its currency.
+	promo.Currency = "USD"
+	_, err = service.UpdatePromotion(ctx, promo.ID, promo)
+	require.NoError(t, err)
+	_, err = book(uuid.New(), 2, standardRoom)
+	require.Equal(t, "bad_request", errors.FromError(err).Code)
+	released := 0
+	for _, r := range repo.redemptions {
+		if r.Status == domain.RedemptionReleased {
+			released++
+		}
+	}
+	require.Equal(t, 2, released)
+}
+
+func TestCreateBookingItemisesTaxesAndFees(t *testing.T) {
+	rt := hdomain.RoomType{ID: uuid.New(), HotelID: uuid.New(), BasePrice: valueobject.AmountOf(500000), Capacity: 2}
+	charge := func(r valueobject.ChargeRule) hdomain.ChargeRule {
+		return hdomain.ChargeRule{ID: uuid.New(), HotelID: rt.HotelID, Rule: r}
+	}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: rt, rooms: 5, charges: []hdomain.ChargeRule{
+		charge(valueobject.ChargeRule{Kind: valueobject.ChargeTax, Name: "PB1", Basis: valueobject.ChargePercent, Amount: valueobject.AmountOf(10)}),
+		charge(valueobject.ChargeRule{Kind: valueobject.ChargeFee, Name: "Service charge", Basis: valueobject.ChargePercent, Amount: valueobject.AmountOf(10)}),
+		charge(valueobject.ChargeRule{Kind: valueobject.ChargeFee, Name: "City fee", Basis: valueobject.ChargeFlat, Amount: valueobject.AmountOf(15000), Per: valueobject.ChargePerStay}),
+		charge(valueobject.ChargeRule{Kind: valueobject.ChargeTax, Name: "VAT", Basis: valueobject.ChargePercent, Amount: valueobject.AmountOf(11), Inclusive: true}),
+	}}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
+
+	checkIn := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	room := assembler.ItemCommand{RoomTypeID: rt.ID, Guests: 1}
+	bk, _, err := service.CreateBooking(context.Background(), assembler.CreateCommand{
+		UserID: uuid.New(), CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), Items: []assembler.ItemCommand{room, room},
+	})
+	require.NoError(t, err)
+
+	// Fees come first; PB1 is due on the room and the service charge, VAT is already in the room price.
+	price := bk.Items[0].Price
+	require.Len(t, price.Charges, 4)
+	require.Equal(t, "Service charge", price.Charges[0].Name)
+	require.Equal(t, valueobject.AmountOf(111500), price.Charges[2].Amount)
+	require.Equal(t, valueobject.AmountOf(99099), price.Charges[3].Amount)
+	require.Equal(t, valueobject.AmountOf(1226500), price.Total)
+	require.Equal(t, valueobject.AmountOf(2453000), bk.TotalPrice)
+
+	lines := bk.PriceLines()
+	require.Equal(t, []string{"Room nights", "Service charge", "City fee", "PB1", "VAT"}, []string{lines[0].Name, lines[1].Name, lines[2].Name, lines[3].Name, lines[4].Name})
+	require.Equal(t, domain.LineTax, lines[3].Type)
+	require.True(t, lines[4].Included)
+	require.Equal(t, valueobject.AmountOf(30000), lines[2].Amount)
+	var sum valueobject.Amount
+	for _, l := range lines {
+		if !l.Included {
+			sum = sum.Add(l.Amount)
+		}
+	}
+	require.Equal(t, bk.TotalPrice, sum)
+	require.Equal(t, []valueobject.Amount{bk.TotalPrice}, payments.initiated)
+	require.Equal(t, lines, payments.lines)
+}
+
+func TestCreateBookingUnderRatePlans(t *testing.T) {
+	rt := hdomain.RoomType{ID: uuid.New(), HotelID: uuid.New(), BasePrice: valueobject.AmountOf(500000), Capacity: 2, CancellationPolicy: valueobject.PolicyFlexible}
+	saver := hdomain.RatePlan{ID: uuid.New(), RoomTypeID: rt.ID, Plan: valueobject.RatePlan{
+		Name: "Saver", Percent: -10, CancellationPolicy: valueobject.PolicyNonRefundable, PaymentTiming: valueobject.PayNow,
+	}}
+	breakfast := hdomain.RatePlan{ID: uuid.New(), RoomTypeID: rt.ID, Plan: valueobject.RatePlan{
+		Name: "Bed and breakfast", PerNight: valueobject.AmountOf(150000), Inclusions: []string{"breakfast"},
+		CancellationPolicy: valueobject.PolicyFreeCancellation, PaymentTiming: valueobject.PayAtHotel,
+	}}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: rt, rooms: 5, plans: []hdomain.RatePlan{saver, breakfast}}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
+	ctx := context.Background()
+
+	checkIn := time.Now().AddDate(0, 0, 10).Truncate(24 * time.Hour)
+	checkOut := checkIn.AddDate(0, 0, 2)
+	results, err := service.SearchAvailability(ctx, assembler.AvailabilityQuery{CheckIn: checkIn, CheckOut: checkOut, Guests: 1}, query.Options{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, valueobject.AmountOf(1000000), results[0].QuotedTotal)
+	require.Len(t, results[0].RatePlans, 2)
+	require.Equal(t, valueobject.AmountOf(900000), results[0].RatePlans[0].QuotedTotal)
+	require.Equal(t, valueobject.AmountOf(1300000), results[0].RatePlans[1].QuotedTotal)
+
+	// A pay-now plan is charged up front and refunded by its own policy.
+	bk, _, err := service.CreateBooking(ctx, assembler.CreateCommand{UserID: uuid.New(), RoomTypeID: rt.ID, RatePlanID: saver.ID, CheckIn: checkIn, CheckOut: checkOut, Guests: 1})
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(900000), bk.TotalPrice)
+	require.Equal(t, saver.ID, bk.Items[0].RatePlanID)
+	require.Equal(t, []string{"Saver"}, bk.Items[0].Price.Nights[0].Rules)
+	require.Equal(t, []valueobject.Amount{bk.TotalPrice}, payments.initiated)
+	require.NoError(t, service.ApplyStatus(ctx, bk.ID, domain.StatusConfirmed, 0))
+	res, err := service.CancelBooking(ctx, bk.ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.PolicyNonRefundable, res.Policy)
+	require.True(t, res.RefundAmount.IsZero())
+
+	// A pay-at-hotel plan is confirmed without a payment, and nothing is refunded on cancellation.
+	bk, pay, err := service.CreateBooking(ctx, assembler.CreateCommand{UserID: uuid.New(), RoomTypeID: rt.ID, RatePlanID: breakfast.ID, CheckIn: checkIn, CheckOut: checkOut, Guests: 1})
+	require.NoError(t, err)
+	require.Equal(t, domain.StatusConfirmed, bk.Status)
+	require.True(t, bk.ExpiresAt.IsZero())
+	require.Equal(t, uuid.Nil, pay.ID)
+	require.Len(t, payments.initiated, 1)
+	require.Equal(t, valueobject.AmountOf(1300000), bk.TotalPrice)
+	res, err = service.CancelBooking(ctx, bk.ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.PolicyFreeCancellation, res.Policy)
+	require.True(t, res.RefundAmount.IsZero())
+	require.Empty(t, payments.refunds)
+
+	// Rooms paid now and at the hotel cannot share a booking, and plans belong to their room type.
+	_, _, err = service.CreateBooking(ctx, assembler.CreateCommand{UserID: uuid.New(), CheckIn: checkIn, CheckOut: checkOut, Items: []assembler.ItemCommand{
+		{RoomTypeID: rt.ID, RatePlanID: saver.ID, Guests: 1},
+		{RoomTypeID: rt.ID, RatePlanID: breakfast.ID, Guests: 1},
+	}})
+	require.Equal(t, "bad_request", errors.FromError(err).Code)
+	hotelRepo.plans = append(hotelRepo.plans, hdomain.RatePlan{ID: uuid.New(), RoomTypeID: uuid.New(), Plan: valueobject.RatePlan{Name: "Other"}})
+	_, _, err = service.CreateBooking(ctx, assembler.CreateCommand{UserID: uuid.New(), RoomTypeID: rt.ID, RatePlanID: hotelRepo.plans[2].ID, CheckIn: checkIn, CheckOut: checkOut, Guests: 1})
+	require.Equal(t, "bad_request", errors.FromError(err).Code)
+}
+
+func TestCreateBookingChargesInDisplayCurrency(t *testing.T) {
+	ctx := context.Background()
+	rt := hdomain.RoomType{ID: uuid.New(), HotelID: uuid.New(), BasePrice: valueobject.AmountOf(613250), Capacity: 2, CancellationPolicy: valueobject.PolicyFlexible}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: rt, rooms: 5, currency: "IDR"}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
+	usdIDR := func(rate float64) []valueobject.ExchangeRate {
+		return []valueobject.ExchangeRate{{Base: "USD", Quote: "IDR", Rate: rate, AsOf: time.Now(), Source: "admin"}}
+	}
+	require.NoError(t, service.UploadExchangeRates(ctx, usdIDR(16000)))
+
+	checkIn := time.Now().AddDate(0, 0, 30)
+	cmd := assembler.CreateCommand{UserID: uuid.New(), RoomTypeID: rt.ID, Guests: 1, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), ChargeCurrency: "USD"}
+	bk, _, err := service.CreateBooking(ctx, cmd)
+	require.NoError(t, err)
+
+	// Prices stay in the hotel's currency; the guest pays in USD at the rate of the moment.
+	require.Equal(t, "IDR", bk.Currency)
+	require.Equal(t, "USD", bk.ChargeCurrency)
+	require.Equal(t, "USD", bk.ExchangeRate.Quote)
+	require.InDelta(t, 1.0/16000, bk.ExchangeRate.Rate, 1e-12)
+	require.Equal(t, valueobject.AmountOf(1226500), bk.TotalPrice)
+	require.Equal(t, []valueobject.Amount{valueobject.AmountOf(76.66)}, payments.initiated)
+	require.Equal(t, "USD", payments.currency)
+	var sum valueobject.Amount
+	for _, l := range payments.lines {
+		sum = sum.Add(l.Amount)
+	}
+	require.Equal(t, valueobject.AmountOf(76.66), sum)
+
+	// A refund returns what was paid, however the rate moved since.
+	require.NoError(t, service.UploadExchangeRates(ctx, usdIDR(15000)))
+	stored := repo.store[bk.ID]
+	stored.Status = domain.StatusConfirmed
+	repo.store[bk.ID] = stored
+	_, err = service.CancelBooking(ctx, bk.ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, []valueobject.Amount{valueobject.AmountOf(76.66)}, payments.refunds)
+	require.Equal(t, "USD", payments.currency)
+
+	// Guests pay in the hotel's currency or the one they see prices in.
+	cmd.DisplayCurrency, cmd.ChargeCurrency = "USD", "SGD"
+	_, _, err = service.CreateBooking(ctx, cmd)
+	require.Equal(t, "bad_request", errors.FromError(err).Code)
+
+	cmd.DisplayCurrency, cmd.ChargeCurrency = "AUD", ""
+	_, _, err = service.CreateBooking(ctx, cmd)
+	require.Equal(t, "unprocessable_entity", errors.FromError(err).Code)
+}
+
+func TestCreateBookingKeepsQuotedPrice(t *testing.T) {
+	ctx := context.Background()
+	rt := hdomain.RoomType{ID: uuid.New(), HotelID: uuid.New(), BasePrice: valueobject.AmountOf(500000), Capacity: 2, CancellationPolicy: valueobject.PolicyFlexible}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: rt, rooms: 5, currency: "IDR"}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
+	usdIDR := func(rate float64) []valueobject.ExchangeRate {
+		return []valueobject.ExchangeRate{{Base: "USD", Quote: "IDR", Rate: rate, AsOf: time.Now(), Source: "admin"}}
+	}
+	require.NoError(t, service.UploadExchangeRates(ctx, usdIDR(16000)))
+
+	checkIn := time.Now().AddDate(0, 0, 10).Truncate(24 * time.Hour)
+	cmd := assembler.CreateCommand{UserID: uuid.New(), RoomTypeID: rt.ID, Guests: 1, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), ChargeCurrency: "USD"}
+	_, _, err := service.CreateQuote(ctx, cmd)
+	require.Equal(t, "unprocessable_entity", errors.FromError(err).Code)
+
+	signer := &quoteSignerStub{quotes: map[string]domain.Quote{}}
+	service.SetQuoteSigner(signer, time.Minute)
+	quote, token, err := service.CreateQuote(ctx, cmd)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(1000000), quote.Booking().TotalPrice)
+	require.Equal(t, "USD", quote.ChargeCurrency)
+	require.WithinDuration(t, time.Now().Add(time.Minute), quote.ExpiresAt, 2*time.Second)
+	require.Empty(t, repo.store, "quoting holds nothing")
+
+	// Prices and rates move after the quote; booking with the token keeps the quoted price.
+	hotelRepo.roomType.BasePrice = valueobject.AmountOf(600000)
+	require.NoError(t, service.UploadExchangeRates(ctx, usdIDR(15000)))
+	cmd.QuoteToken = token
+	bk, _, err := service.CreateBooking(ctx, cmd)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(1000000), bk.TotalPrice)
+	require.Equal(t, quote.ExchangeRate, bk.ExchangeRate)
+	require.Equal(t, bk.Charge(bk.TotalPrice).Amount, payments.initiated[0])
+	require.NotEqual(t, uuid.Nil, bk.Items[0].ID)
+
+	cmd.QuoteToken = ""
+	bk, _, err = service.CreateBooking(ctx, cmd)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(1200000), bk.TotalPrice)
+
+	// A token quoting another stay, a forged one and an expired one are conflicts.
+	other := cmd
+	other.QuoteToken, other.Guests = token, 2
+	_, _, err = service.CreateBooking(ctx, other)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+	other.Guests, other.UserID = 1, uuid.New()
+	_, _, err = service.CreateBooking(ctx, other)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+	other.UserID, other.QuoteToken = cmd.UserID, "forged"
+	_, _, err = service.CreateBooking(ctx, other)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+	expired := signer.quotes[token]
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	signer.quotes[token] = expired
+	other.QuoteToken = token
+	_, _, err = service.CreateBooking(ctx, other)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+	require.Len(t, payments.initiated, 2)
+}
+
+func TestCancelBookingItem(t *testing.T) {
+	standard := hdomain.RoomType{ID: uuid.New(), BasePrice: valueobject.AmountOf(100), Capacity: 2, CancellationPolicy: valueobject.PolicyFlexible}
+	suite := hdomain.RoomType{ID: uuid.New(), BasePrice: valueobject.AmountOf(300), Capacity: 2, CancellationPolicy: valueobject.PolicyNonRefundable}
+	checkIn := time.Now().Add(10 * 24 * time.Hour)
+	bk := domain.Booking{ID: uuid.New(), CheckIn: checkIn, CheckOut: checkIn.Add(72 * time.Hour), Status: domain.StatusConfirmed, TotalNights: 3}
+	bk.SetItems([]domain.LineItem{
+		{ID: uuid.New(), RoomTypeID: standard.ID, Guests: 2, Price: domain.PriceBreakdown{Base: valueobject.AmountOf(300), Total: valueobject.AmountOf(300)}, Status: domain.ItemStatusActive},
+		{ID: uuid.New(), RoomTypeID: suite.ID, Guests: 2, Price: domain.PriceBreakdown{Base: valueobject.AmountOf(900), Total: valueobject.AmountOf(900)}, Status: domain.ItemStatusActive},
+	})
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{bk.ID: bk}}
+	hotelRepo := &hotelRepoStub{roomTypes: map[uuid.UUID]hdomain.RoomType{standard.ID: standard, suite.ID: suite}}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
+
+	res, err := service.CancelBookingItem(context.Background(), bk.ID, bk.Items[0].ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.PolicyFlexible, res.Policy)
+	require.Equal(t, valueobject.AmountOf(300), res.RefundAmount)
+	require.Equal(t, []valueobject.Amount{valueobject.AmountOf(300)}, payments.refunds)
+
+	stored := repo.store[bk.ID]
+	require.Equal(t, domain.StatusConfirmed, stored.Status)
+	require.Equal(t, domain.ItemStatusCancelled, stored.Items[0].Status)
+	require.Equal(t, valueobject.AmountOf(900), stored.TotalPrice)
+	require.Equal(t, suite.ID, stored.RoomTypeID)
+
+	// The last room cannot be cancelled on its own.
+	_, err = service.CancelBookingItem(context.Background(), bk.ID, bk.Items[1].ID, 0)
+	require.Error(t, err)
+	require.Equal(t, "bad_request", err.(errors.APIError).Code)
+
+	// Cancelling the booking refunds the remaining room under its own policy.
+	res, err = service.CancelBooking(context.Background(), bk.ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.PolicyNonRefundable, res.Policy)
+	require.Zero(t, res.RefundAmount)
+}
+
+func TestExpireHolds(t *testing.T) {
+	now := time.Now()
+	expired := domain.Booking{ID: uuid.New(), Status: domain.StatusPendingPayment, ExpiresAt: now.Add(-time.Minute)}
+	active := domain.Booking{ID: uuid.New(), Status: domain.StatusPendingPayment, ExpiresAt: now.Add(time.Minute)}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{expired.ID: expired, active.ID: active}}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, &hotelRepoStub{}, payments, &notificationGatewayStub{})
+
+	count, err := service.ExpireHolds(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, domain.StatusCancelled, repo.store[expired.ID].Status)
+	require.Equal(t, domain.StatusPendingPayment, repo.store[active.ID].Status)
+	require.Equal(t, []uuid.UUID{expired.ID}, payments.expired)
+}
+
+func TestExpireHoldsSkipsFailingBooking(t *testing.T) {
+	now := time.Now()
+	stuck := domain.Booking{ID: uuid.New(), Status: domain.StatusPendingPayment, ExpiresAt: now.Add(-time.Hour)}
+	expired := domain.Booking{ID: uuid.New(), Status: domain.StatusPendingPayment, ExpiresAt: now.Add(-time.Minute)}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{stuck.ID: stuck, expired.ID: expired}}
+	payments := &paymentGatewayStub{expireErrs: map[uuid.UUID]error{stuck.ID: stdErrors.New("payment service down")}}
+	service := booking.NewService(repo, &hotelRepoStub{}, payments, &notificationGatewayStub{})
+
+	// The failing booking does not hold up the others, and its failure is reported.
+	count, err := service.ExpireHolds(context.Background())
+	require.ErrorContains(t, err, stuck.ID.String())
+	require.ErrorContains(t, err, "payment service down")
+	require.Equal(t, 1, count)
+	require.Equal(t, domain.StatusPendingPayment, repo.store[stuck.ID].Status)
+	require.Equal(t, domain.StatusCancelled, repo.store[expired.ID].Status)
+
+	// The next run retries it.
+	delete(payments.expireErrs, stuck.ID)
+	count, err = service.ExpireHolds(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, domain.StatusCancelled, repo.store[stuck.ID].Status)
+}
+
+func TestExpireHoldsKeepsSettledPayment(t *testing.T) {
+	bk := domain.Booking{ID: uuid.New(), Status: domain.StatusPendingPayment, ExpiresAt: time.Now().Add(-time.Minute)}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{bk.ID: bk}}
+	payments := &paymentGatewayStub{expireErr: errors.New("conflict", "payment already settled")}
+	service := booking.NewService(repo, &hotelRepoStub{}, payments, &notificationGatewayStub{})
+
+	count, err := service.ExpireHolds(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+	require.Equal(t, domain.StatusConfirmed, repo.store[bk.ID].Status)
+}
+
+func TestMarkNoShows(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	missed := domain.Booking{ID: uuid.New(), Status: domain.StatusConfirmed, CheckIn: yesterday, CheckOut: yesterday.AddDate(0, 0, 3), TotalNights: 3, TotalPrice: valueobject.AmountOf(900)}
+	today := domain.Booking{ID: uuid.New(), Status: domain.StatusConfirmed, CheckIn: time.Now(), CheckOut: time.Now().AddDate(0, 0, 1), TotalNights: 1, TotalPrice: valueobject.AmountOf(300)}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{missed.ID: missed, today.ID: today}}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, &hotelRepoStub{}, payments, &notificationGatewayStub{})
+	service.SetNoShowPolicy(valueobject.NoShowPolicy{ChargeNights: 1})
+
+	count, err := service.MarkNoShows(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, domain.StatusNoShow, repo.store[missed.ID].Status)
+	require.Equal(t, domain.StatusConfirmed, repo.store[today.ID].Status)
+	require.Equal(t, []valueobject.Amount{valueobject.AmountOf(600)}, payments.refunds)
+}
+
+func TestMarkNoShowsRefundsThroughOutbox(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	bk := domain.Booking{ID: uuid.New(), Status: domain.StatusConfirmed, CheckIn: yesterday, CheckOut: yesterday.AddDate(0, 0, 3), TotalNights: 3, TotalPrice: valueobject.AmountOf(900)}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{bk.ID: bk}}
+	payments := &paymentGatewayStub{settleErr: stdErrors.New("payment service down")}
+	service := booking.NewService(repo, &hotelRepoStub{}, payments, &notificationGatewayStub{})
+	service.SetNoShowPolicy(valueobject.NoShowPolicy{ChargeNights: 1})
+
+	// The payment service being down does not keep the booking confirmed; the refund stays staged.
+	count, err := service.MarkNoShows(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, domain.StatusNoShow, repo.store[bk.ID].Status)
+	require.Empty(t, payments.refunds)
+
+	payments.settleErr = nil
+	_, err = service.RelayOutbox(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []valueobject.Amount{valueobject.AmountOf(600)}, payments.refunds)
+	require.Len(t, payments.settleKeys, 2)
+	require.Equal(t, payments.settleKeys[0], payments.settleKeys[1])
+}
+
+func TestMarkNoShowsForfeitsByDefault(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	bk := domain.Booking{ID: uuid.New(), Status: domain.StatusConfirmed, CheckIn: yesterday, CheckOut: yesterday.AddDate(0, 0, 2), TotalNights: 2, TotalPrice: valueobject.AmountOf(600)}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{bk.ID: bk}}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, &hotelRepoStub{}, payments, &notificationGatewayStub{})
+
+	count, err := service.MarkNoShows(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, domain.StatusNoShow, repo.store[bk.ID].Status)
+	require.Empty(t, payments.refunds)
+}
+
+func TestRelayOutboxDeliversInOrderWithRetries(t *testing.T) {
+	roomTypeID := uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2}, rooms: 1}
+	notifier := &notificationGatewayStub{err: stdErrors.New("notification service down")}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, notifier)
+	service.SetRetryPolicy(valueobject.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	checkIn := time.Now().AddDate(0, 1, 0)
+	bk, _, err := service.CreateBooking(context.Background(), assembler.CreateCommand{
+		UserID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), Guests: 1,
+	})
+	require.NoError(t, err)
+	_, err = service.CancelBooking(context.Background(), bk.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, repo.outbox, 2)
+
+	// The first event fails and the second waits behind it.
+	delivered, err := service.RelayOutbox(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, delivered)
+	require.Equal(t, 1, repo.outbox[0].Attempts)
+	require.Equal(t, "notification service down", repo.outbox[0].LastError)
+	require.Zero(t, repo.outbox[1].Attempts)
+
+	time.Sleep(2 * time.Millisecond)
+	notifier.err = nil
+	delivered, err = service.RelayOutbox(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, delivered)
+	require.Equal(t, []string{domain.EventTypeBookingCreated, domain.EventTypeBookingCancelled}, notifier.events)
+	require.Equal(t, domain.OutboxDelivered, repo.outbox[0].Status)
+	require.Equal(t, domain.OutboxDelivered, repo.outbox[1].Status)
+}
+
+func TestRelayOutboxDeadLettersAndReplays(t *testing.T) {
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	notifier := &notificationGatewayStub{err: stdErrors.New("boom")}
+	service := booking.NewService(repo, &hotelRepoStub{}, &paymentGatewayStub{}, notifier)
+	service.SetRetryPolicy(valueobject.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	require.NoError(t, repo.AppendEvents(context.Background(), []pkgDomain.DomainEvent{domain.NewBookingConfirmed(uuid.New())}))
+	msgID := repo.outbox[0].ID
+
+	for i := 0; i < 2; i++ {
+		_, err := service.RelayOutbox(context.Background())
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+	}
+	dead, err := service.ListOutbox(context.Background(), domain.OutboxDead, query.Options{})
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	require.Equal(t, 2, dead[0].Attempts)
+
+	// Dead letters are left alone until replayed.
+	notifier.err = nil
+	delivered, err := service.RelayOutbox(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, delivered)
+
+	msg, err := service.ReplayOutboxMessage(context.Background(), msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.OutboxPending, msg.Status)
+	require.Zero(t, msg.Attempts)
+
+	delivered, err = service.RelayOutbox(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, delivered)
+
+	_, err = service.ReplayOutboxMessage(context.Background(), msgID)
+	require.Equal(t, "bad_request", errors.FromError(err).Code)
+	_, err = service.ListOutbox(context.Background(), "stuck", query.Options{})
+	require.Equal(t, "bad_request", errors.FromError(err).Code)
+}
+
+func TestHistoryRebuildsBooking(t *testing.T) {
+	roomTypeID := uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2}, rooms: 2}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	checkIn := time.Now().AddDate(0, 2, 0)
+	guest, err := valueobject.NewGuestDetails("Jane Doe", "+6281234567", "ID", nil)
+	require.NoError(t, err)
+	bk, _, err := service.CreateBooking(context.Background(), assembler.CreateCommand{
+		UserID: uuid.New(), CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), Guest: guest,
+		Items: []assembler.ItemCommand{{RoomTypeID: roomTypeID, Guests: 2}, {RoomTypeID: roomTypeID, Guests: 1}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, service.ApplyStatus(context.Background(), bk.ID, domain.StatusConfirmed, 0))
+	_, err = service.CancelBookingItem(context.Background(), bk.ID, bk.Items[1].ID, 0)
+	require.NoError(t, err)
+
+	history, err := service.History(context.Background(), bk.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, []string{domain.EventTypeBookingCreated, domain.EventTypeBookingConfirmed, domain.EventTypeBookingItemCancelled},
+		[]string{history[0].EventType, history[1].EventType, history[2].EventType})
+
+	// Losing the row is recoverable from the event log.
+	want := repo.store[bk.ID]
+	delete(repo.store, bk.ID)
+	count, err := booking.RebuildFromHistory(context.Background(), repo)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	got := repo.store[bk.ID]
+	require.Equal(t, want.Status, got.Status)
+	require.Equal(t, want.TotalPrice, got.TotalPrice)
+	require.Equal(t, want.Guests, got.Guests)
+	require.Equal(t, want.Guest, got.Guest)
+	require.True(t, want.CheckIn.Equal(got.CheckIn))
+	require.Len(t, got.Items, 2)
+	require.Equal(t, domain.ItemStatusCancelled, got.Items[1].Status)
+
+	_, err = service.History(context.Background(), uuid.New())
+	require.Error(t, err)
+}
+
+func TestBookingWritesHandleVersionConflicts(t *testing.T) {
+	roomTypeID := uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2}, rooms: 1}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	checkIn := time.Now().AddDate(0, 3, 0)
+	bk, _, err := service.CreateBooking(context.Background(), assembler.CreateCommand{
+		UserID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkIn.AddDate(0, 0, 2), Guests: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, bk.Version)
+
+	// Without If-Match a lost race is retried against the fresh state.
+	repo.racingWrites = 1
+	require.NoError(t, service.ApplyStatus(context.Background(), bk.ID, domain.StatusConfirmed, 0))
+	require.Equal(t, domain.StatusConfirmed, repo.store[bk.ID].Status)
+	require.Equal(t, 3, repo.store[bk.ID].Version)
+
+	// A stale If-Match is rejected before anything changes.
+	_, err = service.CancelBooking(context.Background(), bk.ID, 1)
+	require.Equal(t, "precondition_failed", errors.FromError(err).Code)
+	require.Equal(t, domain.StatusConfirmed, repo.store[bk.ID].Status)
+
+	// A pinned version that loses the race reports the conflict instead of retrying.
+	repo.racingWrites = 1
+	_, err = service.CancelBooking(context.Background(), bk.ID, 3)
+	require.ErrorIs(t, err, domain.ErrVersionConflict)
+	require.Equal(t, domain.StatusConfirmed, repo.store[bk.ID].Status)
+
+	res, err := service.CancelBooking(context.Background(), bk.ID, repo.store[bk.ID].Version)
+	require.NoError(t, err)
+	require.Equal(t, 5, res.Booking.Version)
+}
+
 func TestApplyStatusInvalidTransition(t *testing.T) {
 	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
-	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: uuid.New(), BasePrice: 500000}}
-	payment := &paymentGatewayStub{}
-	notifier := &notificationGatewayStub{}
-	service := booking.NewService(repo, hotelRepo, payment, notifier)
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: uuid.New(), BasePrice: valueobject.AmountOf(500000)}}
+	payment := &paymentGatewayStub{}
+	notifier := &notificationGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payment, notifier)
+
+	id := uuid.New()
+	repo.store[id] = domain.Booking{ID: id, Status: string(valueobject.StatusCancelled)}
+
+	err := service.ApplyStatus(context.Background(), id, string(valueobject.StatusConfirmed), 0)
+	require.Error(t, err)
+
+	// Unknown statuses are rejected rather than written through.
+	err = service.ApplyStatus(context.Background(), id, "on_hold", 0)
+	require.Equal(t, "bad_request", errors.FromError(err).Code)
+	require.Equal(t, string(valueobject.StatusCancelled), repo.store[id].Status)
+}
+
+func TestBookingOwnership(t *testing.T) {
+	roomTypeID := uuid.New()
+	alice, bob := uuid.New(), uuid.New()
+	checkIn := time.Now().Add(10 * 24 * time.Hour)
+	bobs := domain.Booking{ID: uuid.New(), UserID: bob, RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkIn.Add(24 * time.Hour), Status: domain.StatusPendingPayment, Guests: 1}
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{bobs.ID: bobs}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2}, rooms: 5}
+	auditLog := &auditLogStub{}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+	service.SetAuditLog(auditLog)
+
+	as := func(userID uuid.UUID, role valueobject.Role) context.Context {
+		claims := &middleware.Claims{UserID: userID.String(), Role: string(role)}
+		return context.WithValue(context.Background(), middleware.AuthContextKey, claims)
+	}
+	aliceCtx, adminCtx := as(alice, valueobject.RoleCustomer), as(uuid.New(), valueobject.RoleAdmin)
+
+	// Customers book for themselves whatever the payload says, unless they name someone else.
+	cmd := assembler.CreateCommand{RoomTypeID: roomTypeID, Guests: 1, CheckIn: checkIn, CheckOut: checkIn.Add(24 * time.Hour)}
+	own, _, err := service.CreateBooking(aliceCtx, cmd)
+	require.NoError(t, err)
+	require.Equal(t, alice, own.UserID)
+	cmd.UserID = bob
+	_, _, err = service.CreateBooking(aliceCtx, cmd)
+	require.Equal(t, "forbidden", errors.FromError(err).Code)
+
+	page, err := service.ListBookings(aliceCtx, query.Options{})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, own.ID, page.Items[0].ID)
+
+	_, err = service.GetBooking(aliceCtx, bobs.ID)
+	require.Equal(t, "not_found", errors.FromError(err).Code)
+	_, err = service.CancelBooking(aliceCtx, bobs.ID, 0)
+	require.Equal(t, "not_found", errors.FromError(err).Code)
+	require.Empty(t, auditLog.entries)
+
+	// Admins act on behalf of the owner, and every such action is audited.
+	onBehalf, _, err := service.CreateBooking(adminCtx, cmd)
+	require.NoError(t, err)
+	require.Equal(t, bob, onBehalf.UserID)
+	_, err = service.CancelBooking(adminCtx, bobs.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, auditLog.entries, 2)
+	require.Equal(t, "booking.create", auditLog.entries[0].Action)
+	require.Equal(t, "booking.cancel", auditLog.entries[1].Action)
+	require.Equal(t, bob, auditLog.entries[1].OwnerID)
+	require.Equal(t, bobs.ID, auditLog.entries[1].ResourceID)
+}
+
+func TestWaitlistOffersFreedRoomToOldestEntry(t *testing.T) {
+	roomTypeID := uuid.New()
+	alice, bob := uuid.New(), uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2}, rooms: 1}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
+
+	as := func(userID uuid.UUID) context.Context {
+		claims := &middleware.Claims{UserID: userID.String(), Role: string(valueobject.RoleCustomer)}
+		return context.WithValue(context.Background(), middleware.AuthContextKey, claims)
+	}
+	checkIn := time.Now().Add(10 * 24 * time.Hour)
+	join := assembler.WaitlistCommand{RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkIn.Add(48 * time.Hour), Guests: 1}
+
+	// Free rooms are booked, not waited for.
+	_, err := service.JoinWaitlist(as(alice), join)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+
+	taken, _, err := service.CreateBooking(context.Background(), assembler.CreateCommand{
+		UserID: uuid.New(), RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkIn.Add(24 * time.Hour), Guests: 1,
+	})
+	require.NoError(t, err)
+
+	first, err := service.JoinWaitlist(as(alice), join)
+	require.NoError(t, err)
+	require.Equal(t, alice, first.UserID)
+	require.Equal(t, domain.WaitlistWaiting, first.Status)
+	_, err = service.JoinWaitlist(as(alice), join)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+	second, err := service.JoinWaitlist(as(bob), join)
+	require.NoError(t, err)
+
+	// Customers only see and leave their own entries.
+	page, err := service.ListWaitlist(as(alice), query.Options{})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	_, err = service.LeaveWaitlist(as(alice), second.ID)
+	require.Equal(t, "not_found", errors.FromError(err).Code)
+
+	// Nothing is offered while the room type stays sold out.
+	offered, err := service.OfferWaitlist(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, offered)
+
+	_, err = service.CancelBooking(context.Background(), taken.ID, 0)
+	require.NoError(t, err)
+	offered, err = service.OfferWaitlist(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, offered)
+
+	entry, err := repo.FindWaitlistEntry(context.Background(), first.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.WaitlistOffered, entry.Status)
+	hold := repo.store[entry.BookingID]
+	require.Equal(t, alice, hold.UserID)
+	require.Equal(t, domain.StatusPendingPayment, hold.Status)
+	require.Equal(t, hold.ExpiresAt, entry.OfferExpiresAt)
+	require.Len(t, payments.initiated, 2)
+	require.Equal(t, domain.EventTypeWaitlistOffered, repo.outbox[len(repo.outbox)-1].EventType)
+
+	// The offered guest cancels the hold rather than leaving; the next guest keeps waiting.
+	_, err = service.LeaveWaitlist(as(alice), first.ID)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+	left, err := service.LeaveWaitlist(as(bob), second.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.WaitlistLeft, left.Status)
+}
+
+func TestWaitlistKeepsOfferingWhenPaymentFails(t *testing.T) {
+	roomTypeID := uuid.New()
+	alice, bob := uuid.New(), uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2}, rooms: 2}
+	payments := &paymentGatewayStub{}
+	service := booking.NewService(repo, hotelRepo, payments, &notificationGatewayStub{})
 
-	id := uuid.New()
-	repo.store[id] = domain.Booking{ID: id, Status: string(valueobject.StatusCancelled)}
+	checkIn := time.Now().Add(10 * 24 * time.Hour)
+	cmd := assembler.CreateCommand{RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkIn.Add(24 * time.Hour), Guests: 1}
+	var taken []uuid.UUID
+	for i := 0; i < 2; i++ {
+		cmd.UserID = uuid.New()
+		bk, _, err := service.CreateBooking(context.Background(), cmd)
+		require.NoError(t, err)
+		taken = append(taken, bk.ID)
+	}
+	var entries []uuid.UUID
+	for _, user := range []uuid.UUID{alice, bob} {
+		entry, err := service.JoinWaitlist(context.Background(), assembler.WaitlistCommand{UserID: user, RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkIn.Add(24 * time.Hour), Guests: 1})
+		require.NoError(t, err)
+		entries = append(entries, entry.ID)
+	}
+	for _, id := range taken {
+		_, err := service.CancelBooking(context.Background(), id, 0)
+		require.NoError(t, err)
+	}
 
-	err := service.ApplyStatus(context.Background(), id, string(valueobject.StatusConfirmed))
+	// The first offer's payment cannot be initiated; the second guest is still offered a room.
+	payments.initiateErrs = []error{errors.New("internal_error", "payment service down")}
+	offered, err := service.OfferWaitlist(context.Background())
 	require.Error(t, err)
+	require.Equal(t, 2, offered)
+	for _, id := range entries {
+		entry, err := repo.FindWaitlistEntry(context.Background(), id)
+		require.NoError(t, err)
+		require.Equal(t, domain.WaitlistOffered, entry.Status)
+	}
+	require.Len(t, payments.initiated, 3)
+}
+
+func TestInventoryHoldKeepsRoomsForCheckout(t *testing.T) {
+	roomTypeID := uuid.New()
+	alice, bob := uuid.New(), uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2}, rooms: 1}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	as := func(userID uuid.UUID) context.Context {
+		claims := &middleware.Claims{UserID: userID.String(), Role: string(valueobject.RoleCustomer)}
+		return context.WithValue(context.Background(), middleware.AuthContextKey, claims)
+	}
+	checkIn := time.Now().Add(10 * 24 * time.Hour).Truncate(24 * time.Hour)
+	checkOut := checkIn.Add(48 * time.Hour)
+	holdCmd := assembler.HoldCommand{SessionID: "checkout-1", RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkOut, Rooms: 1}
+	book := func(userID, holdID uuid.UUID) (domain.Booking, error) {
+		bk, _, err := service.CreateBooking(as(userID), assembler.CreateCommand{
+			RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkOut, Guests: 1, HoldID: holdID,
+		})
+		return bk, err
+	}
+
+	_, err := service.HoldInventory(as(alice), assembler.HoldCommand{RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkOut, Rooms: 1, Duration: time.Hour})
+	require.Equal(t, "bad_request", errors.FromError(err).Code)
+	first, err := service.HoldInventory(as(alice), holdCmd)
+	require.NoError(t, err)
+	require.Equal(t, domain.HoldActive, first.Status)
+	require.WithinDuration(t, time.Now().Add(domain.DefaultInventoryHold), first.ExpiresAt, time.Minute)
+
+	// A new hold of the same checkout session replaces the earlier one.
+	hold, err := service.HoldInventory(as(alice), holdCmd)
+	require.NoError(t, err)
+	released, err := service.GetInventoryHold(as(alice), first.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.HoldReleased, released.Status)
+
+	// Held rooms are neither bookable, holdable nor offered to anyone else.
+	_, err = book(bob, uuid.Nil)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+	_, err = service.HoldInventory(as(bob), holdCmd)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+	results, err := service.SearchAvailability(context.Background(), assembler.AvailabilityQuery{CheckIn: checkIn, CheckOut: checkOut, Guests: 1}, query.Options{})
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	// Only the holder sees and books the hold.
+	_, err = service.GetInventoryHold(as(bob), hold.ID)
+	require.Equal(t, "not_found", errors.FromError(err).Code)
+	_, err = book(bob, hold.ID)
+	require.Equal(t, "not_found", errors.FromError(err).Code)
+
+	bk, err := book(alice, hold.ID)
+	require.NoError(t, err)
+	consumed, err := service.GetInventoryHold(as(alice), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.HoldConsumed, consumed.Status)
+	require.Equal(t, bk.ID, consumed.BookingID)
+	_, err = book(alice, hold.ID)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+	_, err = service.ReleaseInventoryHold(as(alice), hold.ID)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+}
+
+func TestInventoryHoldExpires(t *testing.T) {
+	roomTypeID := uuid.New()
+	alice, bob := uuid.New(), uuid.New()
+	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: roomTypeID, BasePrice: valueobject.AmountOf(100), Capacity: 2}, rooms: 1}
+	service := booking.NewService(repo, hotelRepo, &paymentGatewayStub{}, &notificationGatewayStub{})
+
+	as := func(userID uuid.UUID) context.Context {
+		claims := &middleware.Claims{UserID: userID.String(), Role: string(valueobject.RoleCustomer)}
+		return context.WithValue(context.Background(), middleware.AuthContextKey, claims)
+	}
+	checkIn := time.Now().Add(10 * 24 * time.Hour).Truncate(24 * time.Hour)
+	holdCmd := assembler.HoldCommand{RoomTypeID: roomTypeID, CheckIn: checkIn, CheckOut: checkIn.Add(24 * time.Hour), Rooms: 1}
+
+	hold, err := service.HoldInventory(as(alice), holdCmd)
+	require.NoError(t, err)
+	_, err = service.HoldInventory(as(bob), holdCmd)
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+
+	// A hold stops keeping its rooms the moment it runs out, before the sweep marks it.
+	repo.holds[0].ExpiresAt = time.Now().Add(-time.Second)
+	_, _, err = service.CreateBooking(as(alice), assembler.CreateCommand{
+		RoomTypeID: roomTypeID, CheckIn: holdCmd.CheckIn, CheckOut: holdCmd.CheckOut, Guests: 1, HoldID: hold.ID,
+	})
+	require.Equal(t, "conflict", errors.FromError(err).Code)
+	_, err = service.HoldInventory(as(bob), holdCmd)
+	require.NoError(t, err)
+
+	expired, err := service.ExpireInventoryHolds(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, expired)
+	got, err := service.GetInventoryHold(as(alice), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.HoldExpired, got.Status)
 }
 
 // stubs
 
 type bookingRepoStub struct {
-	store map[uuid.UUID]domain.Booking
+	store        map[uuid.UUID]domain.Booking
+	outbox       []domain.OutboxMessage
+	history      []domain.HistoryEntry
+	waitlist     []domain.WaitlistEntry
+	promotions   []domain.Promotion
+	redemptions  []domain.Redemption
+	rates        valueobject.ExchangeRates
+	holds        []domain.InventoryHold
+	racingWrites int // saves that lose to a concurrent writer
 }
 
 func (b *bookingRepoStub) Create(ctx context.Context, bk domain.Booking) error {
+	bk.ClearEvents() // like the database, the store keeps state but not pending events
 	b.store[bk.ID] = bk
 	return nil
 }
@@ -111,18 +1486,18 @@ func (b *bookingRepoStub) Create(ctx context.Context, bk domain.Booking) error {
 func (b *bookingRepoStub) FindByID(ctx context.Context, id uuid.UUID) (domain.Booking, error) {
 	bk, ok := b.store[id]
 	if !ok {
-		return domain.Booking{}, errors.New("not found")
+		return domain.Booking{}, errors.New("not_found", "booking not found")
 	}
 	return bk, nil
 }
-func (b *bookingRepoStub) FindByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Booking, error) {
+func (b *bookingRepoStub) FindByUserID(ctx context.Context, userID uuid.UUID, _ query.Options) (query.Page[domain.Booking], error) {
 	var out []domain.Booking
 	for _, v := range b.store {
 		if v.UserID == userID {
 			out = append(out, v)
 		}
 	}
-	return out, nil
+	return query.Page[domain.Booking]{Items: out, Total: int64(len(out))}, nil
 }
 
 func (b *bookingRepoStub) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
@@ -131,29 +1506,353 @@ func (b *bookingRepoStub) UpdateStatus(ctx context.Context, id uuid.UUID, status
 		b.store[id] = bk
 		return nil
 	}
-	return errors.New("not found")
+	return stdErrors.New("not found")
 }
 
-func (b *bookingRepoStub) List(ctx context.Context, _ query.Options) ([]domain.Booking, error) {
+func (b *bookingRepoStub) List(ctx context.Context, _ query.Options) (query.Page[domain.Booking], error) {
 	var out []domain.Booking
 	for _, v := range b.store {
 		out = append(out, v)
 	}
+	return query.Page[domain.Booking]{Items: out, Total: int64(len(out))}, nil
+}
+func (b *bookingRepoStub) Save(ctx context.Context, bk *domain.Booking) error {
+	stored := b.store[bk.ID]
+	if b.racingWrites > 0 { // another request saves the booking first
+		b.racingWrites--
+		stored.Version++
+		b.store[bk.ID] = stored
+	}
+	if stored.Version != bk.Version {
+		return domain.ErrVersionConflict
+	}
+	bk.Version++
+	saved := *bk
+	saved.ClearEvents() // like the database, the store keeps state but not pending events
+	b.store[bk.ID] = saved
+	return nil
+}
+func (b *bookingRepoStub) FindOverlapping(ctx context.Context, roomTypeID uuid.UUID, stay valueobject.DateRange) ([]domain.Booking, error) {
+	var out []domain.Booking
+	for _, v := range b.store {
+		if v.RoomsHeld(roomTypeID) > 0 && v.Stay().Overlaps(stay) {
+			out = append(out, v)
+		}
+	}
 	return out, nil
 }
-func (b *bookingRepoStub) Save(ctx context.Context, bk domain.Booking) error {
-	b.store[bk.ID] = bk
+func (b *bookingRepoStub) FindByRoomID(ctx context.Context, roomID uuid.UUID, stay valueobject.DateRange) ([]domain.Booking, error) {
+	var out []domain.Booking
+	for _, v := range b.store {
+		if v.RoomID == roomID && v.HoldsInventory() && v.Stay().Overlaps(stay) {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+func (b *bookingRepoStub) FindExpiredHolds(_ context.Context, now time.Time, limit int) ([]domain.Booking, error) {
+	var out []domain.Booking
+	for _, v := range b.store {
+		if v.HoldExpired(now) && len(out) < limit {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+func (b *bookingRepoStub) FindMissedCheckIns(_ context.Context, before time.Time, limit int) ([]domain.Booking, error) {
+	var out []domain.Booking
+	for _, v := range b.store {
+		if v.Status == domain.StatusConfirmed && v.CheckIn.Before(before) && len(out) < limit {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+func (b *bookingRepoStub) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+func (b *bookingRepoStub) AppendEvents(_ context.Context, events []pkgDomain.DomainEvent) error {
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		b.outbox = append(b.outbox, domain.OutboxMessage{
+			ID:            uuid.New(),
+			AggregateID:   event.AggregateID(),
+			EventType:     event.EventType(),
+			Payload:       payload,
+			Status:        domain.OutboxPending,
+			NextAttemptAt: event.OccurredAt(),
+			CreatedAt:     event.OccurredAt(),
+		})
+	}
+	return nil
+}
+func (b *bookingRepoStub) AppendHistory(_ context.Context, events []pkgDomain.DomainEvent) error {
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		b.history = append(b.history, domain.HistoryEntry{
+			ID:         uuid.New(),
+			BookingID:  event.AggregateID(),
+			EventType:  event.EventType(),
+			Payload:    payload,
+			Actor:      domain.Actor{ID: domain.SystemActor},
+			OccurredAt: event.OccurredAt(),
+		})
+	}
+	return nil
+}
+func (b *bookingRepoStub) History(_ context.Context, bookingID uuid.UUID) ([]domain.HistoryEntry, error) {
+	var out []domain.HistoryEntry
+	for _, e := range b.history {
+		if e.BookingID == bookingID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+func (b *bookingRepoStub) HistoryBookingIDs(context.Context) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, e := range b.history {
+		if e.EventType == domain.EventTypeBookingCreated {
+			ids = append(ids, e.BookingID)
+		}
+	}
+	return ids, nil
+}
+func (b *bookingRepoStub) ClaimOutbox(_ context.Context, now time.Time, limit int) ([]domain.OutboxMessage, error) {
+	var out []domain.OutboxMessage
+	blocked := map[uuid.UUID]bool{}
+	for _, m := range b.outbox {
+		if m.Status != domain.OutboxPending {
+			continue
+		}
+		if !blocked[m.AggregateID] && !m.NextAttemptAt.After(now) && len(out) < limit {
+			out = append(out, m)
+		}
+		blocked[m.AggregateID] = true
+	}
+	return out, nil
+}
+func (b *bookingRepoStub) SaveOutbox(_ context.Context, msg domain.OutboxMessage) error {
+	for i, m := range b.outbox {
+		if m.ID == msg.ID {
+			b.outbox[i] = msg
+			return nil
+		}
+	}
+	return errors.New("not_found", "outbox message not found")
+}
+func (b *bookingRepoStub) ListOutbox(_ context.Context, status string, _ query.Options) ([]domain.OutboxMessage, error) {
+	var out []domain.OutboxMessage
+	for _, m := range b.outbox {
+		if status == "" || m.Status == status {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+func (b *bookingRepoStub) FindOutbox(_ context.Context, id uuid.UUID) (domain.OutboxMessage, error) {
+	for _, m := range b.outbox {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return domain.OutboxMessage{}, errors.New("not_found", "outbox message not found")
+}
+
+func (b *bookingRepoStub) CreateWaitlistEntry(_ context.Context, e domain.WaitlistEntry) error {
+	b.waitlist = append(b.waitlist, e)
+	return nil
+}
+func (b *bookingRepoStub) SaveWaitlistEntry(_ context.Context, e domain.WaitlistEntry) error {
+	for i, w := range b.waitlist {
+		if w.ID == e.ID {
+			b.waitlist[i] = e
+			return nil
+		}
+	}
+	return errors.New("not_found", "waitlist entry not found")
+}
+func (b *bookingRepoStub) FindWaitlistEntry(_ context.Context, id uuid.UUID) (domain.WaitlistEntry, error) {
+	for _, w := range b.waitlist {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+	return domain.WaitlistEntry{}, errors.New("not_found", "waitlist entry not found")
+}
+func (b *bookingRepoStub) ListWaitlist(_ context.Context, opts query.Options) (query.Page[domain.WaitlistEntry], error) {
+	var out []domain.WaitlistEntry
+	for _, w := range b.waitlist {
+		fields := map[string]any{"status": w.Status, "user_id": w.UserID, "room_type_id": w.RoomTypeID}
+		match := true
+		for _, f := range opts.Filters {
+			if fields[f.Field] != f.Value {
+				match = false
+			}
+		}
+		if match {
+			out = append(out, w)
+		}
+	}
+	return query.Page[domain.WaitlistEntry]{Items: out, Total: int64(len(out))}, nil
+}
+func (b *bookingRepoStub) CreatePromotion(_ context.Context, p domain.Promotion) error {
+	b.promotions = append(b.promotions, p)
+	return nil
+}
+func (b *bookingRepoStub) UpdatePromotion(_ context.Context, p domain.Promotion) error {
+	for i, existing := range b.promotions {
+		if existing.ID == p.ID {
+			b.promotions[i] = p
+			return nil
+		}
+	}
+	return errors.New("not_found", "promotion not found")
+}
+func (b *bookingRepoStub) DeletePromotion(_ context.Context, id uuid.UUID) error {
+	for i, p := range b.promotions {
+		if p.ID == id {
+			b.promotions = append(b.promotions[:i], b.promotions[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not_found", "promotion not found")
+}
+func (b *bookingRepoStub) GetPromotion(_ context.Context, id uuid.UUID) (domain.Promotion, error) {
+	for _, p := range b.promotions {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return domain.Promotion{}, errors.New("not_found", "promotion not found")
+}
+func (b *bookingRepoStub) ListPromotions(context.Context) ([]domain.Promotion, error) {
+	return b.promotions, nil
+}
+func (b *bookingRepoStub) FindPromotionByCode(_ context.Context, code string) (domain.Promotion, error) {
+	for _, p := range b.promotions {
+		if p.Code == code {
+			return p, nil
+		}
+	}
+	return domain.Promotion{}, errors.New("not_found", "promotion not found")
+}
+func (b *bookingRepoStub) LockPromotionByCode(ctx context.Context, code string) (domain.Promotion, error) {
+	return b.FindPromotionByCode(ctx, code)
+}
+func (b *bookingRepoStub) CountRedemptions(_ context.Context, promotionID, userID uuid.UUID) (int, int, error) {
+	total, byUser := 0, 0
+	for _, r := range b.redemptions {
+		if r.PromotionID == promotionID && r.Status == domain.RedemptionActive {
+			total++
+			if r.UserID == userID {
+				byUser++
+			}
+		}
+	}
+	return total, byUser, nil
+}
+func (b *bookingRepoStub) CreateRedemption(_ context.Context, r domain.Redemption) error {
+	b.redemptions = append(b.redemptions, r)
+	return nil
+}
+func (b *bookingRepoStub) ReleaseRedemptions(_ context.Context, bookingID uuid.UUID) error {
+	for i, r := range b.redemptions {
+		if r.BookingID == bookingID {
+			b.redemptions[i].Status = domain.RedemptionReleased
+		}
+	}
+	return nil
+}
+func (b *bookingRepoStub) SaveExchangeRates(_ context.Context, rates []valueobject.ExchangeRate) error {
+	for _, rate := range rates {
+		replaced := false
+		for i, r := range b.rates {
+			if r.Base == rate.Base && r.Quote == rate.Quote {
+				b.rates[i], replaced = rate, true
+			}
+		}
+		if !replaced {
+			b.rates = append(b.rates, rate)
+		}
+	}
 	return nil
 }
+func (b *bookingRepoStub) ListExchangeRates(context.Context) (valueobject.ExchangeRates, error) {
+	return b.rates, nil
+}
+func (b *bookingRepoStub) CreateInventoryHold(_ context.Context, h domain.InventoryHold) error {
+	b.holds = append(b.holds, h)
+	return nil
+}
+func (b *bookingRepoStub) SaveInventoryHold(_ context.Context, h domain.InventoryHold) error {
+	for i, existing := range b.holds {
+		if existing.ID == h.ID {
+			b.holds[i] = h
+			return nil
+		}
+	}
+	return errors.New("not_found", "hold not found")
+}
+func (b *bookingRepoStub) FindInventoryHold(_ context.Context, id uuid.UUID) (domain.InventoryHold, error) {
+	for _, h := range b.holds {
+		if h.ID == id {
+			return h, nil
+		}
+	}
+	return domain.InventoryHold{}, errors.New("not_found", "hold not found")
+}
+func (b *bookingRepoStub) FindActiveHolds(_ context.Context, roomTypeID uuid.UUID, stay valueobject.DateRange, now time.Time) ([]domain.InventoryHold, error) {
+	var out []domain.InventoryHold
+	for _, h := range b.holds {
+		if h.RoomTypeID == roomTypeID && h.Active(now) && h.Stay().Overlaps(stay) {
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+func (b *bookingRepoStub) FindUserHolds(_ context.Context, userID uuid.UUID, now time.Time) ([]domain.InventoryHold, error) {
+	var out []domain.InventoryHold
+	for _, h := range b.holds {
+		if h.UserID == userID && h.Active(now) {
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+func (b *bookingRepoStub) ExpireInventoryHolds(_ context.Context, now time.Time) (int, error) {
+	count := 0
+	for i, h := range b.holds {
+		if h.Status == domain.HoldActive && !now.Before(h.ExpiresAt) {
+			b.holds[i].Status = domain.HoldExpired
+			count++
+		}
+	}
+	return count, nil
+}
 
 type hotelRepoStub struct {
-	roomType hdomain.RoomType
-	err      error
+	roomType  hdomain.RoomType
+	roomTypes map[uuid.UUID]hdomain.RoomType // optional, for bookings spanning several room types
+	rooms     int
+	roomStore []hdomain.Room
+	rules     []hdomain.PricingRule // newest first
+	calendar  []hdomain.CalendarDay
+	charges   []hdomain.ChargeRule
+	plans     []hdomain.RatePlan
+	currency  string // of every hotel, the default when empty
+	err       error
 }
 
 func (h *hotelRepoStub) CreateHotel(context.Context, hdomain.Hotel) error { return nil }
-func (h *hotelRepoStub) ListHotels(context.Context, query.Options) ([]hdomain.Hotel, error) {
-	return nil, nil
+func (h *hotelRepoStub) ListHotels(context.Context, query.Options) (query.Page[hdomain.Hotel], error) {
+	return query.Page[hdomain.Hotel]{}, nil
 }
 func (h *hotelRepoStub) CreateRoomType(context.Context, hdomain.RoomType) error { return nil }
 func (h *hotelRepoStub) ListRoomTypes(context.Context, uuid.UUID) ([]hdomain.RoomType, error) {
@@ -167,25 +1866,174 @@ func (h *hotelRepoStub) GetRoomType(ctx context.Context, id uuid.UUID) (hdomain.
 	if h.err != nil {
 		return hdomain.RoomType{}, h.err
 	}
+	if h.roomTypes != nil {
+		rt, ok := h.roomTypes[id]
+		if !ok {
+			return hdomain.RoomType{}, stdErrors.New("not found")
+		}
+		return rt, nil
+	}
 	return h.roomType, nil
 }
 func (h *hotelRepoStub) ListRooms(context.Context, query.Options) ([]hdomain.Room, error) {
 	return nil, nil
 }
-func (h *hotelRepoStub) GetHotel(context.Context, uuid.UUID) (hdomain.Hotel, error) {
-	return hdomain.Hotel{}, nil
+func (h *hotelRepoStub) GetRoomTypeForUpdate(ctx context.Context, id uuid.UUID) (hdomain.RoomType, error) {
+	return h.GetRoomType(ctx, id)
+}
+func (h *hotelRepoStub) CountBookableRooms(context.Context, uuid.UUID) (int, error) {
+	return h.rooms, nil
+}
+func (h *hotelRepoStub) GetHotel(_ context.Context, id uuid.UUID) (hdomain.Hotel, error) {
+	return hdomain.Hotel{ID: id, Currency: h.currency}, nil
 }
 func (h *hotelRepoStub) UpdateHotel(context.Context, uuid.UUID, hdomain.Hotel) error { return nil }
 func (h *hotelRepoStub) DeleteHotel(context.Context, uuid.UUID) error                { return nil }
-func (h *hotelRepoStub) GetRoom(context.Context, uuid.UUID) (hdomain.Room, error) {
-	return hdomain.Room{}, nil
+func (h *hotelRepoStub) GetRoom(ctx context.Context, id uuid.UUID) (hdomain.Room, error) {
+	for _, r := range h.roomStore {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return hdomain.Room{}, stdErrors.New("not found")
+}
+func (h *hotelRepoStub) UpdateRoom(ctx context.Context, id uuid.UUID, room hdomain.Room) error {
+	for i, r := range h.roomStore {
+		if r.ID == id {
+			h.roomStore[i] = room
+		}
+	}
+	return nil
+}
+func (h *hotelRepoStub) ListRoomsByType(ctx context.Context, roomTypeID uuid.UUID) ([]hdomain.Room, error) {
+	var out []hdomain.Room
+	for _, r := range h.roomStore {
+		if r.RoomTypeID == roomTypeID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+func (h *hotelRepoStub) DeleteRoom(context.Context, uuid.UUID) error                  { return nil }
+func (h *hotelRepoStub) CreatePricingRule(context.Context, hdomain.PricingRule) error { return nil }
+func (h *hotelRepoStub) UpdatePricingRule(context.Context, hdomain.PricingRule) error { return nil }
+func (h *hotelRepoStub) DeletePricingRule(context.Context, uuid.UUID) error           { return nil }
+func (h *hotelRepoStub) GetPricingRule(context.Context, uuid.UUID) (hdomain.PricingRule, error) {
+	return hdomain.PricingRule{}, nil
+}
+func (h *hotelRepoStub) ListPricingRules(ctx context.Context, hotelID uuid.UUID) ([]hdomain.PricingRule, error) {
+	var out []hdomain.PricingRule
+	for _, r := range h.rules {
+		if r.HotelID == hotelID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+func (h *hotelRepoStub) ListCalendar(ctx context.Context, roomTypeID uuid.UUID, from, to time.Time) (hdomain.Calendar, error) {
+	var out hdomain.Calendar
+	for _, day := range h.calendar {
+		if day.RoomTypeID == roomTypeID && !day.Date.Before(hdomain.CalendarDate(from)) && !day.Date.After(hdomain.CalendarDate(to)) {
+			out = append(out, day)
+		}
+	}
+	return out, nil
+}
+func (h *hotelRepoStub) SaveCalendar(ctx context.Context, days []hdomain.CalendarDay) error {
+	for _, day := range days {
+		replaced := false
+		for i, existing := range h.calendar {
+			if existing.RoomTypeID == day.RoomTypeID && existing.Date.Equal(day.Date) {
+				h.calendar[i], replaced = day, true
+			}
+		}
+		if !replaced {
+			h.calendar = append(h.calendar, day)
+		}
+	}
+	return nil
+}
+func (h *hotelRepoStub) CreateChargeRule(context.Context, hdomain.ChargeRule) error { return nil }
+func (h *hotelRepoStub) UpdateChargeRule(context.Context, hdomain.ChargeRule) error { return nil }
+func (h *hotelRepoStub) DeleteChargeRule(context.Context, uuid.UUID) error          { return nil }
+func (h *hotelRepoStub) GetChargeRule(context.Context, uuid.UUID) (hdomain.ChargeRule, error) {
+	return hdomain.ChargeRule{}, nil
+}
+func (h *hotelRepoStub) ListChargeRules(ctx context.Context, hotelID uuid.UUID) ([]hdomain.ChargeRule, error) {
+	var out []hdomain.ChargeRule
+	for _, r := range h.charges {
+		if r.HotelID == hotelID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+func (h *hotelRepoStub) CreateRatePlan(context.Context, hdomain.RatePlan) error { return nil }
+func (h *hotelRepoStub) UpdateRatePlan(context.Context, hdomain.RatePlan) error { return nil }
+func (h *hotelRepoStub) DeleteRatePlan(context.Context, uuid.UUID) error        { return nil }
+func (h *hotelRepoStub) GetRatePlan(ctx context.Context, id uuid.UUID) (hdomain.RatePlan, error) {
+	for _, p := range h.plans {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return hdomain.RatePlan{}, stdErrors.New("not found")
+}
+func (h *hotelRepoStub) ListRatePlans(ctx context.Context, roomTypeID uuid.UUID) ([]hdomain.RatePlan, error) {
+	var out []hdomain.RatePlan
+	for _, p := range h.plans {
+		if p.RoomTypeID == roomTypeID {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// quoteSignerStub hands out opaque tokens for the quotes it signed.
+type quoteSignerStub struct {
+	quotes map[string]domain.Quote
+}
+
+func (q *quoteSignerStub) Sign(quote domain.Quote) (string, error) {
+	token := uuid.NewString()
+	q.quotes[token] = quote
+	return token, nil
+}
+
+func (q *quoteSignerStub) Verify(token string) (domain.Quote, error) {
+	quote, ok := q.quotes[token]
+	if !ok {
+		return domain.Quote{}, errors.New("conflict", "invalid quote token")
+	}
+	if time.Now().After(quote.ExpiresAt) {
+		return domain.Quote{}, errors.New("conflict", "quote expired, request a new quote")
+	}
+	return quote, nil
 }
-func (h *hotelRepoStub) UpdateRoom(context.Context, uuid.UUID, hdomain.Room) error { return nil }
-func (h *hotelRepoStub) DeleteRoom(context.Context, uuid.UUID) error               { return nil }
 
-type paymentGatewayStub struct{}
+type paymentGatewayStub struct {
+	initiated    []valueobject.Amount
+	lines        []domain.PriceLine // lines of the last initiated payment
+	supplements  []valueobject.Amount
+	refunds      []valueobject.Amount
+	currency     string // of the last payment or refund
+	expired      []uuid.UUID
+	expireErr    error
+	expireErrs   map[uuid.UUID]error // per booking, ahead of expireErr
+	settleErr    error               // fails supplements and refunds
+	settleKeys   []string            // Idempotency-Keys of the supplements and refunds sent
+	initiateErrs []error             // fail the next payment initiations, in order
+}
 
-func (p *paymentGatewayStub) Initiate(context.Context, uuid.UUID, float64) (domain.PaymentResult, error) {
+func (p *paymentGatewayStub) Initiate(_ context.Context, _, _ uuid.UUID, amount valueobject.Money, lines []domain.PriceLine) (domain.PaymentResult, error) {
+	if len(p.initiateErrs) > 0 {
+		err := p.initiateErrs[0]
+		p.initiateErrs = p.initiateErrs[1:]
+		return domain.PaymentResult{}, err
+	}
+	p.initiated = append(p.initiated, amount.Amount)
+	p.lines = lines
+	p.currency = amount.Currency
 	return domain.PaymentResult{
 		ID:         uuid.New(),
 		Status:     "pending",
@@ -194,15 +2042,63 @@ func (p *paymentGatewayStub) Initiate(context.Context, uuid.UUID, float64) (doma
 	}, nil
 }
 
-type notificationGatewayStub struct{}
+func (p *paymentGatewayStub) InitiateSupplement(ctx context.Context, bookingID, userID uuid.UUID, amount valueobject.Money) (domain.PaymentResult, error) {
+	key, _ := middleware.IdempotencyKeyFrom(ctx)
+	p.settleKeys = append(p.settleKeys, key)
+	if p.settleErr != nil {
+		return domain.PaymentResult{}, p.settleErr
+	}
+	p.supplements = append(p.supplements, amount.Amount)
+	return p.Initiate(ctx, bookingID, userID, amount, nil)
+}
+
+func (p *paymentGatewayStub) Refund(ctx context.Context, _ uuid.UUID, amount valueobject.Money, _ string) error {
+	key, _ := middleware.IdempotencyKeyFrom(ctx)
+	p.settleKeys = append(p.settleKeys, key)
+	if p.settleErr != nil {
+		return p.settleErr
+	}
+	p.refunds = append(p.refunds, amount.Amount)
+	p.currency = amount.Currency
+	return nil
+}
+
+func (p *paymentGatewayStub) Expire(_ context.Context, bookingID uuid.UUID) error {
+	if err := p.expireErrs[bookingID]; err != nil {
+		return err
+	}
+	if p.expireErr != nil {
+		return p.expireErr
+	}
+	p.expired = append(p.expired, bookingID)
+	return nil
+}
 
-func (n *notificationGatewayStub) Notify(context.Context, string, any) error {
+type auditLogStub struct {
+	entries []audit.Entry
+}
+
+func (a *auditLogStub) Record(_ context.Context, e audit.Entry) error {
+	a.entries = append(a.entries, e)
+	return nil
+}
+
+type notificationGatewayStub struct {
+	events []string
+	err    error
+}
+
+func (n *notificationGatewayStub) Notify(_ context.Context, event string, _ any) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.events = append(n.events, event)
 	return nil
 }
 
 func TestAutoCheckout(t *testing.T) {
 	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
-	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: uuid.New(), BasePrice: 500000}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: uuid.New(), BasePrice: valueobject.AmountOf(500000)}}
 	payment := &paymentGatewayStub{}
 	notifier := &notificationGatewayStub{}
 	service := booking.NewService(repo, hotelRepo, payment, notifier)
@@ -265,7 +2161,7 @@ func TestAutoCheckout(t *testing.T) {
 
 func TestAutoCheckoutNoBookings(t *testing.T) {
 	repo := &bookingRepoStub{store: map[uuid.UUID]domain.Booking{}}
-	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: uuid.New(), BasePrice: 500000}}
+	hotelRepo := &hotelRepoStub{roomType: hdomain.RoomType{ID: uuid.New(), BasePrice: valueobject.AmountOf(500000)}}
 	payment := &paymentGatewayStub{}
 	notifier := &notificationGatewayStub{}
 	service := booking.NewService(repo, hotelRepo, payment, notifier)
@@ -0,0 +1,55 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	pkgDomain "github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// stageSettlement puts a settlement in the outbox; call it in the transaction changing the price.
+// Settlements stay out of the booking history, which rebuilding replays.
+func (s *Service) stageSettlement(ctx context.Context, due domain.SettlementDue) error {
+	return s.repo.AppendEvents(ctx, []pkgDomain.DomainEvent{due})
+}
+
+// stageRefund stages a refund of amount for a booking the transaction has just saved, and returns it for
+// settleCommitted. Nothing is staged when there is nothing to refund.
+func (s *Service) stageRefund(ctx context.Context, bk domain.Booking, amount valueobject.Amount, reason string) (*domain.SettlementDue, error) {
+	if !amount.IsPositive() {
+		return nil, nil
+	}
+	due := domain.NewSettlementDue(bk, domain.SettleRefund, bk.Charge(amount), reason)
+	if err := s.stageSettlement(ctx, due); err != nil {
+		return nil, err
+	}
+	return &due, nil
+}
+
+// settleCommitted attempts a staged settlement once its write has committed. A failed attempt is
+// left in the outbox for the relay to retry.
+func (s *Service) settleCommitted(ctx context.Context, due *domain.SettlementDue) {
+	if due == nil {
+		return
+	}
+	_, _ = s.settle(ctx, *due)
+}
+
+// settle asks the payment service for a staged settlement. Every attempt at the same settlement
+// carries the same Idempotency-Key, so the attempt made after the write commits and the relay's
+// retries collect or pay back the difference once.
+func (s *Service) settle(ctx context.Context, due domain.SettlementDue) (domain.PaymentResult, error) {
+	ctx = middleware.WithIdempotencyKey(ctx, fmt.Sprintf("booking:%s:%s:v%d", due.BookingID, due.Kind, due.Version))
+	switch due.Kind {
+	case domain.SettleSupplement:
+		return s.payments.InitiateSupplement(ctx, due.BookingID, due.UserID, due.Amount)
+	case domain.SettleRefund:
+		return domain.PaymentResult{}, s.payments.Refund(ctx, due.BookingID, due.Amount, due.Reason)
+	default:
+		return domain.PaymentResult{}, errors.New("bad_request", "unknown settlement kind")
+	}
+}
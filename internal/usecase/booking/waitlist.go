@@ -0,0 +1,217 @@
+package booking
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/booking"
+	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/booking/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/audit"
+	pkgDomain "github.com/ftryyln/hotel-booking-microservices/pkg/domain"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// maxWaitlistPerRun bounds how many waiting entries a single OfferWaitlist run considers.
+const maxWaitlistPerRun = 500
+
+// JoinWaitlist puts the caller on the waitlist of a room type that is sold out for the stay.
+// Only admins may join on behalf of another user.
+func (s *Service) JoinWaitlist(ctx context.Context, cmd assembler.WaitlistCommand) (domain.WaitlistEntry, error) {
+	stay, err := valueobject.NewDateRange(cmd.CheckIn, cmd.CheckOut)
+	if err != nil {
+		return domain.WaitlistEntry{}, err
+	}
+	if stay.Start.Before(time.Now().Truncate(24 * time.Hour)) {
+		return domain.WaitlistEntry{}, errors.New("bad_request", "check_in has already passed")
+	}
+	owner, ok := audit.Owner(ctx, cmd.UserID)
+	if !ok {
+		return domain.WaitlistEntry{}, errors.New("forbidden", "cannot join the waitlist on behalf of another user")
+	}
+	if owner == uuid.Nil {
+		return domain.WaitlistEntry{}, errors.New("bad_request", "invalid user id")
+	}
+
+	entry := domain.WaitlistEntry{
+		ID:         uuid.New(),
+		UserID:     owner,
+		RoomTypeID: cmd.RoomTypeID,
+		CheckIn:    cmd.CheckIn,
+		CheckOut:   cmd.CheckOut,
+		Guests:     cmd.Guests,
+		Status:     domain.WaitlistWaiting,
+		CreatedAt:  time.Now(),
+	}
+	err = s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		rt, err := s.hotels.GetRoomTypeForUpdate(ctx, cmd.RoomTypeID)
+		if err != nil {
+			return errors.New("not_found", "room type not found")
+		}
+		if cmd.Guests > rt.Capacity {
+			return errors.New("bad_request", "guests exceed room type capacity")
+		}
+		// A freed room could not be offered for a stay the calendar closes.
+		if err := s.checkCalendar(ctx, rt.ID, stay); err != nil {
+			return err
+		}
+		// Guests wait only for sold-out stays; free rooms are booked directly.
+		switch err := s.ensureInventory(ctx, rt.ID, stay, uuid.Nil, 1); {
+		case err == nil:
+			return errors.New("conflict", "rooms are available for the selected dates, book instead")
+		case errors.FromError(err).Code != "conflict":
+			return err
+		}
+
+		waiting, err := s.repo.ListWaitlist(ctx, query.Options{Filters: []query.Filter{
+			{Field: "user_id", Op: query.OpEq, Value: owner},
+			{Field: "room_type_id", Op: query.OpEq, Value: rt.ID},
+			{Field: "status", Op: query.OpEq, Value: domain.WaitlistWaiting},
+		}}.Normalize(maxWaitlistPerRun))
+		if err != nil {
+			return err
+		}
+		for _, e := range waiting.Items {
+			if e.CheckIn.Equal(entry.CheckIn) && e.CheckOut.Equal(entry.CheckOut) {
+				return errors.New("conflict", "already on the waitlist for these dates")
+			}
+		}
+
+		if err := s.repo.CreateWaitlistEntry(ctx, entry); err != nil {
+			return err
+		}
+		return audit.Record(ctx, s.audit, "waitlist.join", "waitlist", entry.ID, entry.UserID)
+	})
+	if err != nil {
+		return domain.WaitlistEntry{}, err
+	}
+	return entry, nil
+}
+
+// LeaveWaitlist takes a waiting entry off the waitlist.
+func (s *Service) LeaveWaitlist(ctx context.Context, id uuid.UUID) (domain.WaitlistEntry, error) {
+	var entry domain.WaitlistEntry
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if entry, err = s.lockWaitlistEntry(ctx, id); err != nil {
+			return err
+		}
+		if err := entry.Leave(); err != nil {
+			return err
+		}
+		if err := s.repo.SaveWaitlistEntry(ctx, entry); err != nil {
+			return err
+		}
+		return audit.Record(ctx, s.audit, "waitlist.leave", "waitlist", entry.ID, entry.UserID)
+	})
+	if err != nil {
+		return domain.WaitlistEntry{}, err
+	}
+	return entry, nil
+}
+
+// ListWaitlist lists every waitlist entry for admins and only their own for customers.
+func (s *Service) ListWaitlist(ctx context.Context, opts query.Options) (query.Page[domain.WaitlistEntry], error) {
+	opts = opts.Normalize(50)
+	if userID, ok := customerID(ctx); ok {
+		filters := []query.Filter{{Field: "user_id", Op: query.OpEq, Value: userID}}
+		for _, f := range opts.Filters {
+			if f.Field != "user_id" {
+				filters = append(filters, f)
+			}
+		}
+		opts.Filters = filters
+	}
+	return s.repo.ListWaitlist(ctx, opts)
+}
+
+// OfferWaitlist offers rooms freed by cancellations and expired holds to waiting guests, oldest entry
+// first. Each offer is an ordinary pending booking holding the room until its payment hold runs out;
+// the guest is notified through the outbox. An offer that lapses unpaid frees the room again, so a
+// later run offers it to the next guest. Entries whose stay has begun are expired. An offer whose
+// payment cannot be initiated stands, and lapses like any unpaid offer; the run goes on with the
+// other entries and reports every such failure in its error.
+func (s *Service) OfferWaitlist(ctx context.Context) (int, error) {
+	waiting, err := s.repo.ListWaitlist(ctx, query.Options{Filters: []query.Filter{
+		{Field: "status", Op: query.OpEq, Value: domain.WaitlistWaiting},
+	}}.Normalize(maxWaitlistPerRun))
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		offered int
+		errs    []error
+	)
+	for _, candidate := range waiting.Items {
+		var hold domain.Booking
+		err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+			entry, err := s.lockWaitlistEntry(ctx, candidate.ID)
+			if err != nil || entry.Status != domain.WaitlistWaiting {
+				return err
+			}
+			now := time.Now()
+			if now.After(entry.CheckIn) {
+				if err := entry.Expire(now); err != nil {
+					return err
+				}
+				return s.repo.SaveWaitlistEntry(ctx, entry)
+			}
+
+			cmd := assembler.CreateCommand{
+				UserID:     entry.UserID,
+				RoomTypeID: entry.RoomTypeID,
+				CheckIn:    entry.CheckIn,
+				CheckOut:   entry.CheckOut,
+				Guests:     entry.Guests,
+			}
+			if hold, err = s.reserve(ctx, entry.UserID, entry.Stay(), cmd); err != nil {
+				return err
+			}
+			if err := entry.Offer(hold); err != nil {
+				return err
+			}
+			if err := s.repo.SaveWaitlistEntry(ctx, entry); err != nil {
+				return err
+			}
+			return s.repo.AppendEvents(ctx, []pkgDomain.DomainEvent{domain.NewWaitlistOffered(entry)})
+		})
+		if err != nil {
+			if errors.FromError(err).Code == "internal_error" {
+				return offered, stdErrors.Join(append(errs, err)...)
+			}
+			// Still sold out, or the room type changed since the guest joined: the entry keeps waiting.
+			continue
+		}
+		if hold.ID == uuid.Nil {
+			continue
+		}
+		offered++
+		if _, err := s.payments.Initiate(paymentRequest(ctx, hold, "payment"), hold.ID, hold.UserID, hold.Charge(hold.TotalPrice), hold.ChargeLines()); err != nil {
+			errs = append(errs, fmt.Errorf("initiate payment of waitlist offer %s: %w", hold.ID, err))
+		}
+	}
+	return offered, stdErrors.Join(errs...)
+}
+
+// lockWaitlistEntry loads an entry the caller on ctx may access and locks its room type, which
+// serializes changes to the entry with the offer sweep. It must run inside a transaction.
+func (s *Service) lockWaitlistEntry(ctx context.Context, id uuid.UUID) (domain.WaitlistEntry, error) {
+	entry, err := s.repo.FindWaitlistEntry(ctx, id)
+	if err != nil {
+		return domain.WaitlistEntry{}, err
+	}
+	if !audit.CanAccess(ctx, entry.UserID) {
+		return domain.WaitlistEntry{}, errors.New("not_found", "waitlist entry not found")
+	}
+	if _, err := s.hotels.GetRoomTypeForUpdate(ctx, entry.RoomTypeID); err != nil {
+		return domain.WaitlistEntry{}, errors.New("not_found", "room type not found")
+	}
+	// Re-read under the lock; the sweep may have offered the entry meanwhile.
+	return s.repo.FindWaitlistEntry(ctx, id)
+}
@@ -1,8 +1,11 @@
 package assembler
 
 import (
+	"github.com/google/uuid"
+
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // HotelAggregate represents hotel with its room types.
@@ -27,6 +30,7 @@ func ToHotelResponse(agg HotelAggregate) dto.HotelResponse {
 		Name:        agg.Hotel.Name,
 		Description: agg.Hotel.Description,
 		Address:     agg.Hotel.Address,
+		Currency:    agg.Hotel.Currency,
 		CreatedAt:   agg.Hotel.CreatedAt,
 		RoomTypes:   summaries,
 	}
@@ -46,12 +50,13 @@ func RoomTypesToDTO(rts []domain.RoomType) []dto.RoomTypeResponse {
 	out := make([]dto.RoomTypeResponse, 0, len(rts))
 	for _, rt := range rts {
 		out = append(out, dto.RoomTypeResponse{
-			ID:        rt.ID.String(),
-			HotelID:   rt.HotelID.String(),
-			Name:      rt.Name,
-			Capacity:  rt.Capacity,
-			BasePrice: rt.BasePrice,
-			Amenities: rt.Amenities,
+			ID:                 rt.ID.String(),
+			HotelID:            rt.HotelID.String(),
+			Name:               rt.Name,
+			Capacity:           rt.Capacity,
+			BasePrice:          rt.BasePrice,
+			Amenities:          rt.Amenities,
+			CancellationPolicy: rt.CancellationPolicy,
 		})
 	}
 	return out
@@ -75,3 +80,104 @@ func RoomResponse(r domain.Room) dto.RoomResponse {
 		Status:     r.Status,
 	}
 }
+
+// PricingRuleResponse maps a pricing rule to DTO.
+func PricingRuleResponse(r domain.PricingRule) dto.PricingRuleResponse {
+	resp := dto.PricingRuleResponse{
+		ID:        r.ID.String(),
+		HotelID:   r.HotelID.String(),
+		Kind:      r.Rule.Kind,
+		Name:      r.Rule.Name,
+		StartDate: dto.Date{Time: r.Rule.StartDate},
+		EndDate:   dto.Date{Time: r.Rule.EndDate},
+		Rate:      r.Rule.Rate,
+		Percent:   r.Rule.Percent,
+		Guests:    r.Rule.Guests,
+		MinNights: r.Rule.MinNights,
+		CreatedAt: r.CreatedAt,
+	}
+	if r.RoomTypeID != uuid.Nil {
+		resp.RoomTypeID = r.RoomTypeID.String()
+	}
+	if len(r.Rule.Weekdays) > 0 {
+		resp.Days = valueobject.WeekdayNames(r.Rule.Weekdays)
+	}
+	return resp
+}
+
+// PricingRuleResponses maps pricing rules to DTOs.
+func PricingRuleResponses(rules []domain.PricingRule) []dto.PricingRuleResponse {
+	out := make([]dto.PricingRuleResponse, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, PricingRuleResponse(r))
+	}
+	return out
+}
+
+// ChargeRuleResponse maps a charge rule to DTO.
+func ChargeRuleResponse(r domain.ChargeRule) dto.ChargeRuleResponse {
+	return dto.ChargeRuleResponse{
+		ID:        r.ID.String(),
+		HotelID:   r.HotelID.String(),
+		Kind:      r.Rule.Kind,
+		Name:      r.Rule.Name,
+		Basis:     r.Rule.Basis,
+		Amount:    r.Rule.Amount,
+		Per:       r.Rule.Per,
+		Inclusive: r.Rule.Inclusive,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// ChargeRuleResponses maps charge rules to DTOs.
+func ChargeRuleResponses(rules []domain.ChargeRule) []dto.ChargeRuleResponse {
+	out := make([]dto.ChargeRuleResponse, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, ChargeRuleResponse(r))
+	}
+	return out
+}
+
+// RatePlanResponse maps a rate plan to DTO.
+func RatePlanResponse(p domain.RatePlan) dto.RatePlanResponse {
+	return dto.RatePlanResponse{
+		ID:                 p.ID.String(),
+		RoomTypeID:         p.RoomTypeID.String(),
+		Name:               p.Plan.Name,
+		Percent:            p.Plan.Percent,
+		PerNight:           p.Plan.PerNight,
+		Inclusions:         p.Plan.Inclusions,
+		CancellationPolicy: p.Plan.CancellationPolicy,
+		PaymentTiming:      p.Plan.PaymentTiming,
+		CreatedAt:          p.CreatedAt,
+	}
+}
+
+// RatePlanResponses maps rate plans to DTOs.
+func RatePlanResponses(plans []domain.RatePlan) []dto.RatePlanResponse {
+	out := make([]dto.RatePlanResponse, 0, len(plans))
+	for _, p := range plans {
+		out = append(out, RatePlanResponse(p))
+	}
+	return out
+}
+
+// CalendarResponses maps calendar days to DTOs, pricing dates without an override at the base price.
+func CalendarResponses(rt domain.RoomType, days []domain.CalendarDay) []dto.CalendarDayResponse {
+	out := make([]dto.CalendarDayResponse, 0, len(days))
+	for _, d := range days {
+		resp := dto.CalendarDayResponse{
+			Date:              dto.Date{Time: d.Date},
+			Price:             rt.BasePrice,
+			ClosedToArrival:   d.ClosedToArrival,
+			ClosedToDeparture: d.ClosedToDeparture,
+			MinStay:           d.MinStay,
+			MaxStay:           d.MaxStay,
+		}
+		if !d.Price.IsZero() {
+			resp.Price, resp.PriceOverride = d.Price, true
+		}
+		out = append(out, resp)
+	}
+	return out
+}
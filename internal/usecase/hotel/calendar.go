@@ -0,0 +1,109 @@
+package hotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// UpdateCalendar changes the rate and restrictions of a room type over a range of dates and returns
+// the room type with the updated days. It applies to bookings made from then on.
+func (s *Service) UpdateCalendar(ctx context.Context, roomTypeID uuid.UUID, req dto.CalendarUpdateRequest) (domain.RoomType, []domain.CalendarDay, error) {
+	rt, err := s.repo.GetRoomType(ctx, roomTypeID)
+	if err != nil {
+		return domain.RoomType{}, nil, errors.New("not_found", "room type not found")
+	}
+	dates, err := calendarRange(req.StartDate.Time, req.EndDate.Time)
+	if err != nil {
+		return domain.RoomType{}, nil, err
+	}
+	weekdays, err := valueobject.ParseWeekdays(req.Days)
+	if err != nil {
+		return domain.RoomType{}, nil, err
+	}
+	update := domain.CalendarUpdate{
+		Price:             req.Price,
+		ClosedToArrival:   req.ClosedToArrival,
+		ClosedToDeparture: req.ClosedToDeparture,
+		MinStay:           req.MinStay,
+		MaxStay:           req.MaxStay,
+	}
+	if err := update.Validate(); err != nil {
+		return domain.RoomType{}, nil, err
+	}
+
+	calendar, err := s.repo.ListCalendar(ctx, roomTypeID, dates.Start, dates.End)
+	if err != nil {
+		return domain.RoomType{}, nil, err
+	}
+	var days []domain.CalendarDay
+	for _, date := range dates.NightDates() {
+		if len(weekdays) > 0 && !containsWeekday(weekdays, date.Weekday()) {
+			continue
+		}
+		day := calendar.Day(date)
+		day.RoomTypeID = roomTypeID
+		if err := day.Apply(update); err != nil {
+			return domain.RoomType{}, nil, err
+		}
+		days = append(days, day)
+	}
+	if err := s.repo.SaveCalendar(ctx, days); err != nil {
+		return domain.RoomType{}, nil, err
+	}
+	return rt, days, nil
+}
+
+// Calendar returns the room type with its settings for every date from start up to end.
+// Dates without stored settings come back as unrestricted days without a price override.
+func (s *Service) Calendar(ctx context.Context, roomTypeID uuid.UUID, start, end time.Time) (domain.RoomType, []domain.CalendarDay, error) {
+	rt, err := s.repo.GetRoomType(ctx, roomTypeID)
+	if err != nil {
+		return domain.RoomType{}, nil, errors.New("not_found", "room type not found")
+	}
+	dates, err := calendarRange(start, end)
+	if err != nil {
+		return domain.RoomType{}, nil, err
+	}
+	calendar, err := s.repo.ListCalendar(ctx, roomTypeID, dates.Start, dates.End)
+	if err != nil {
+		return domain.RoomType{}, nil, err
+	}
+	days := make([]domain.CalendarDay, 0, dates.Nights())
+	for _, date := range dates.NightDates() {
+		day := calendar.Day(date)
+		day.RoomTypeID = roomTypeID
+		days = append(days, day)
+	}
+	return rt, days, nil
+}
+
+// calendarRange validates a range of calendar dates, end excluded.
+func calendarRange(start, end time.Time) (valueobject.DateRange, error) {
+	if start.IsZero() || end.IsZero() || !start.Before(end) {
+		return valueobject.DateRange{}, errors.New("bad_request", "start_date must be before end_date")
+	}
+	dates, err := valueobject.NewDateRange(domain.CalendarDate(start), domain.CalendarDate(end))
+	if err != nil {
+		return valueobject.DateRange{}, err
+	}
+	if dates.Nights() > domain.MaxCalendarDays {
+		return valueobject.DateRange{}, errors.New("bad_request", "date range too long")
+	}
+	return dates, nil
+}
+
+func containsWeekday(days []time.Weekday, d time.Weekday) bool {
+	for _, day := range days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
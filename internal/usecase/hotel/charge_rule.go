@@ -0,0 +1,77 @@
+package hotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// CreateChargeRule adds a tax or fee to a hotel. It applies to bookings priced from then on;
+// existing bookings keep their charges.
+func (s *Service) CreateChargeRule(ctx context.Context, req dto.ChargeRuleRequest) (domain.ChargeRule, error) {
+	rule, err := s.chargeRule(ctx, req)
+	if err != nil {
+		return domain.ChargeRule{}, err
+	}
+	rule.ID = uuid.New()
+	rule.CreatedAt = time.Now()
+	if err := s.repo.CreateChargeRule(ctx, rule); err != nil {
+		return domain.ChargeRule{}, err
+	}
+	return rule, nil
+}
+
+// UpdateChargeRule replaces a charge rule. The rule keeps its place in the breakdown, which follows creation time.
+func (s *Service) UpdateChargeRule(ctx context.Context, id uuid.UUID, req dto.ChargeRuleRequest) (domain.ChargeRule, error) {
+	existing, err := s.repo.GetChargeRule(ctx, id)
+	if err != nil {
+		return domain.ChargeRule{}, err
+	}
+	rule, err := s.chargeRule(ctx, req)
+	if err != nil {
+		return domain.ChargeRule{}, err
+	}
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	if err := s.repo.UpdateChargeRule(ctx, rule); err != nil {
+		return domain.ChargeRule{}, err
+	}
+	return rule, nil
+}
+
+func (s *Service) DeleteChargeRule(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteChargeRule(ctx, id)
+}
+
+// ListChargeRules lists a hotel's taxes and fees in the order they are itemised.
+func (s *Service) ListChargeRules(ctx context.Context, hotelID uuid.UUID) ([]domain.ChargeRule, error) {
+	return s.repo.ListChargeRules(ctx, hotelID)
+}
+
+func (s *Service) chargeRule(ctx context.Context, req dto.ChargeRuleRequest) (domain.ChargeRule, error) {
+	hotelID, err := uuid.Parse(req.HotelID)
+	if err != nil {
+		return domain.ChargeRule{}, errors.New("bad_request", "invalid hotel_id")
+	}
+	if _, err := s.repo.GetHotel(ctx, hotelID); err != nil {
+		return domain.ChargeRule{}, errors.New("not_found", "hotel not found")
+	}
+	rule, err := valueobject.NewChargeRule(valueobject.ChargeRule{
+		Kind:      req.Kind,
+		Name:      req.Name,
+		Basis:     req.Basis,
+		Amount:    req.Amount,
+		Per:       req.Per,
+		Inclusive: req.Inclusive,
+	})
+	if err != nil {
+		return domain.ChargeRule{}, err
+	}
+	return domain.ChargeRule{HotelID: hotelID, Rule: rule}, nil
+}
@@ -0,0 +1,106 @@
+package hotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// CreatePricingRule adds a pricing rule to a hotel or one of its room types.
+// It applies to bookings priced from then on; existing bookings keep their price.
+func (s *Service) CreatePricingRule(ctx context.Context, req dto.PricingRuleRequest) (domain.PricingRule, error) {
+	rule, err := s.pricingRule(ctx, req)
+	if err != nil {
+		return domain.PricingRule{}, err
+	}
+	rule.ID = uuid.New()
+	rule.CreatedAt = time.Now()
+	if err := s.repo.CreatePricingRule(ctx, rule); err != nil {
+		return domain.PricingRule{}, err
+	}
+	return rule, nil
+}
+
+// UpdatePricingRule replaces a pricing rule. The rule keeps its precedence, which follows creation time.
+func (s *Service) UpdatePricingRule(ctx context.Context, id uuid.UUID, req dto.PricingRuleRequest) (domain.PricingRule, error) {
+	existing, err := s.repo.GetPricingRule(ctx, id)
+	if err != nil {
+		return domain.PricingRule{}, err
+	}
+	rule, err := s.pricingRule(ctx, req)
+	if err != nil {
+		return domain.PricingRule{}, err
+	}
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	if err := s.repo.UpdatePricingRule(ctx, rule); err != nil {
+		return domain.PricingRule{}, err
+	}
+	return rule, nil
+}
+
+func (s *Service) DeletePricingRule(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeletePricingRule(ctx, id)
+}
+
+// ListPricingRules lists a hotel's rules, newest first. With a room type, only the rules pricing it
+// are listed: its own and the hotel-wide ones.
+func (s *Service) ListPricingRules(ctx context.Context, hotelID, roomTypeID uuid.UUID) ([]domain.PricingRule, error) {
+	rules, err := s.repo.ListPricingRules(ctx, hotelID)
+	if err != nil || roomTypeID == uuid.Nil {
+		return rules, err
+	}
+	out := make([]domain.PricingRule, 0, len(rules))
+	for _, r := range rules {
+		if r.RoomTypeID == roomTypeID || r.RoomTypeID == uuid.Nil {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// pricingRule validates a request against the catalog: the room type, when given, must belong to the hotel.
+func (s *Service) pricingRule(ctx context.Context, req dto.PricingRuleRequest) (domain.PricingRule, error) {
+	hotelID, err := uuid.Parse(req.HotelID)
+	if err != nil {
+		return domain.PricingRule{}, errors.New("bad_request", "invalid hotel_id")
+	}
+	if _, err := s.repo.GetHotel(ctx, hotelID); err != nil {
+		return domain.PricingRule{}, errors.New("not_found", "hotel not found")
+	}
+	roomTypeID := uuid.Nil
+	if req.RoomTypeID != "" {
+		if roomTypeID, err = uuid.Parse(req.RoomTypeID); err != nil {
+			return domain.PricingRule{}, errors.New("bad_request", "invalid room_type_id")
+		}
+		rt, err := s.repo.GetRoomType(ctx, roomTypeID)
+		if err != nil || rt.HotelID != hotelID {
+			return domain.PricingRule{}, errors.New("not_found", "room type not found")
+		}
+	}
+	days, err := valueobject.ParseWeekdays(req.Days)
+	if err != nil {
+		return domain.PricingRule{}, err
+	}
+	rule, err := valueobject.NewPricingRule(valueobject.PricingRule{
+		Kind:      req.Kind,
+		Name:      req.Name,
+		StartDate: req.StartDate.Time,
+		EndDate:   req.EndDate.Time,
+		Weekdays:  days,
+		Rate:      req.Rate,
+		Percent:   req.Percent,
+		Guests:    req.Guests,
+		MinNights: req.MinNights,
+	})
+	if err != nil {
+		return domain.PricingRule{}, err
+	}
+	return domain.PricingRule{HotelID: hotelID, RoomTypeID: roomTypeID, Rule: rule}, nil
+}
@@ -0,0 +1,83 @@
+package hotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/hotel"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// CreateRatePlan adds terms a room type can be booked under. Bookings keep the cancellation policy
+// and payment timing of the plan they were made on, even if the plan later changes.
+func (s *Service) CreateRatePlan(ctx context.Context, req dto.RatePlanRequest) (domain.RatePlan, error) {
+	plan, err := s.ratePlan(ctx, req)
+	if err != nil {
+		return domain.RatePlan{}, err
+	}
+	plan.ID = uuid.New()
+	plan.CreatedAt = time.Now()
+	if err := s.repo.CreateRatePlan(ctx, plan); err != nil {
+		return domain.RatePlan{}, err
+	}
+	return plan, nil
+}
+
+// UpdateRatePlan replaces a rate plan, keeping its place in the room type's list.
+func (s *Service) UpdateRatePlan(ctx context.Context, id uuid.UUID, req dto.RatePlanRequest) (domain.RatePlan, error) {
+	existing, err := s.repo.GetRatePlan(ctx, id)
+	if err != nil {
+		return domain.RatePlan{}, err
+	}
+	plan, err := s.ratePlan(ctx, req)
+	if err != nil {
+		return domain.RatePlan{}, err
+	}
+	plan.ID = existing.ID
+	plan.CreatedAt = existing.CreatedAt
+	if err := s.repo.UpdateRatePlan(ctx, plan); err != nil {
+		return domain.RatePlan{}, err
+	}
+	return plan, nil
+}
+
+func (s *Service) DeleteRatePlan(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteRatePlan(ctx, id)
+}
+
+// ListRatePlans lists the plans a room type is sold under, oldest first.
+func (s *Service) ListRatePlans(ctx context.Context, roomTypeID uuid.UUID) ([]domain.RatePlan, error) {
+	return s.repo.ListRatePlans(ctx, roomTypeID)
+}
+
+// ratePlan validates a request; a plan without a cancellation policy takes the room type's.
+func (s *Service) ratePlan(ctx context.Context, req dto.RatePlanRequest) (domain.RatePlan, error) {
+	roomTypeID, err := uuid.Parse(req.RoomTypeID)
+	if err != nil {
+		return domain.RatePlan{}, errors.New("bad_request", "invalid room_type_id")
+	}
+	rt, err := s.repo.GetRoomType(ctx, roomTypeID)
+	if err != nil {
+		return domain.RatePlan{}, errors.New("not_found", "room type not found")
+	}
+	policy := req.CancellationPolicy
+	if policy == "" {
+		policy = rt.CancellationPolicy
+	}
+	plan, err := valueobject.NewRatePlan(valueobject.RatePlan{
+		Name:               req.Name,
+		Percent:            req.Percent,
+		PerNight:           req.PerNight,
+		Inclusions:         req.Inclusions,
+		CancellationPolicy: policy,
+		PaymentTiming:      req.PaymentTiming,
+	})
+	if err != nil {
+		return domain.RatePlan{}, err
+	}
+	return domain.RatePlan{RoomTypeID: roomTypeID, Plan: plan}, nil
+}
@@ -28,37 +28,48 @@ func (s *Service) CreateHotel(ctx context.Context, req dto.HotelRequest) (uuid.U
 	if err != nil {
 		return uuid.Nil, err
 	}
-	h := domain.Hotel{ID: uuid.New(), Name: name, Description: req.Description, Address: addr}
+	currency := valueobject.DefaultCurrency
+	if req.Currency != "" {
+		if currency, err = valueobject.ParseCurrency(req.Currency); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	h := domain.Hotel{ID: uuid.New(), Name: name, Description: req.Description, Address: addr, Currency: currency}
 	return h.ID, s.repo.CreateHotel(ctx, h)
 }
 
-func (s *Service) ListHotels(ctx context.Context, opts query.Options) ([]assembler.HotelAggregate, error) {
+func (s *Service) ListHotels(ctx context.Context, opts query.Options) (query.Page[assembler.HotelAggregate], error) {
 	hotels, err := s.repo.ListHotels(ctx, opts.Normalize(50))
 	if err != nil {
-		return nil, err
+		return query.Page[assembler.HotelAggregate]{}, err
 	}
 	var aggs []assembler.HotelAggregate
-	for _, h := range hotels {
+	for _, h := range hotels.Items {
 		roomTypes, _ := s.repo.ListRoomTypes(ctx, h.ID)
 		aggs = append(aggs, assembler.HotelAggregate{
 			Hotel:     h,
 			RoomTypes: roomTypes,
 		})
 	}
-	return aggs, nil
+	return query.Page[assembler.HotelAggregate]{Items: aggs, NextCursor: hotels.NextCursor, Total: hotels.Total}, nil
 }
 
 func (s *Service) CreateRoomType(ctx context.Context, req dto.RoomTypeRequest) (uuid.UUID, error) {
 	if err := valueobject.RoomTypeSpec(req.Capacity, req.BasePrice); err != nil {
 		return uuid.Nil, err
 	}
+	policy, err := valueobject.ParseCancellationPolicy(req.CancellationPolicy)
+	if err != nil {
+		return uuid.Nil, err
+	}
 	rt := domain.RoomType{
-		ID:        uuid.New(),
-		HotelID:   uuid.MustParse(req.HotelID),
-		Name:      req.Name,
-		Capacity:  req.Capacity,
-		BasePrice: req.BasePrice,
-		Amenities: req.Amenities,
+		ID:                 uuid.New(),
+		HotelID:            uuid.MustParse(req.HotelID),
+		Name:               req.Name,
+		Capacity:           req.Capacity,
+		BasePrice:          req.BasePrice,
+		Amenities:          req.Amenities,
+		CancellationPolicy: policy.Code,
 	}
 	return rt.ID, s.repo.CreateRoomType(ctx, rt)
 }
@@ -115,6 +126,11 @@ func (s *Service) UpdateHotel(ctx context.Context, id uuid.UUID, req dto.HotelUp
 		Description: req.Description,
 		Address:     addr,
 	}
+	if req.Currency != "" {
+		if h.Currency, err = valueobject.ParseCurrency(req.Currency); err != nil {
+			return err
+		}
+	}
 	return s.repo.UpdateHotel(ctx, id, h)
 }
 
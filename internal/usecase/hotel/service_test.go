@@ -13,6 +13,7 @@ import (
 	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/hotel"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 func TestCreateHotelValidates(t *testing.T) {
@@ -36,10 +37,20 @@ func TestCreateRoomTypeAndRoom(t *testing.T) {
 		HotelID:   hID.String(),
 		Name:      "Deluxe",
 		Capacity:  2,
-		BasePrice: 1000,
+		BasePrice: valueobject.AmountOf(1000),
 	})
 	require.NoError(t, err)
 	require.NotEqual(t, uuid.Nil, rtID)
+	require.Equal(t, valueobject.PolicyFlexible, repo.roomTypes[0].CancellationPolicy)
+
+	_, err = svc.CreateRoomType(context.Background(), dto.RoomTypeRequest{
+		HotelID:            hID.String(),
+		Name:               "Saver",
+		Capacity:           2,
+		BasePrice:          valueobject.AmountOf(800),
+		CancellationPolicy: "refund_maybe",
+	})
+	require.Error(t, err)
 
 	_, err = svc.CreateRoom(context.Background(), dto.RoomRequest{
 		RoomTypeID: rtID.String(),
@@ -61,13 +72,13 @@ func TestListHotelsRooms(t *testing.T) {
 	hID := uuid.New()
 	now := time.Now()
 	repo.hotels = append(repo.hotels, domain.Hotel{ID: hID, Name: "H", Address: "Addr", CreatedAt: now})
-	repo.roomTypes = append(repo.roomTypes, domain.RoomType{ID: uuid.New(), HotelID: hID, Name: "RT", Capacity: 2, BasePrice: 10})
+	repo.roomTypes = append(repo.roomTypes, domain.RoomType{ID: uuid.New(), HotelID: hID, Name: "RT", Capacity: 2, BasePrice: valueobject.AmountOf(10)})
 	repo.rooms = append(repo.rooms, domain.Room{ID: uuid.New(), RoomTypeID: repo.roomTypes[0].ID, Number: "1", Status: "available"})
 	svc := hotel.NewService(repo)
 
 	hotels, err := svc.ListHotels(context.Background(), query.Options{Limit: 10})
 	require.NoError(t, err)
-	require.Len(t, hotels, 1)
+	require.Len(t, hotels.Items, 1)
 
 	rt, err := svc.ListRoomTypes(context.Background(), query.Options{Limit: 10})
 	require.NoError(t, err)
@@ -83,14 +94,18 @@ type hotelRepoStub struct {
 	hotels    []domain.Hotel
 	roomTypes []domain.RoomType
 	rooms     []domain.Room
+	rules     []domain.PricingRule
+	calendar  []domain.CalendarDay
+	charges   []domain.ChargeRule
+	plans     []domain.RatePlan
 }
 
 func (h *hotelRepoStub) CreateHotel(ctx context.Context, v domain.Hotel) error {
 	h.hotels = append(h.hotels, v)
 	return nil
 }
-func (h *hotelRepoStub) ListHotels(ctx context.Context, opts query.Options) ([]domain.Hotel, error) {
-	return h.hotels, nil
+func (h *hotelRepoStub) ListHotels(ctx context.Context, opts query.Options) (query.Page[domain.Hotel], error) {
+	return query.Page[domain.Hotel]{Items: h.hotels, Total: int64(len(h.hotels))}, nil
 }
 func (h *hotelRepoStub) CreateRoomType(ctx context.Context, rt domain.RoomType) error {
 	h.roomTypes = append(h.roomTypes, rt)
@@ -138,6 +153,9 @@ func (h *hotelRepoStub) UpdateHotel(ctx context.Context, id uuid.UUID, hotel dom
 			h.hotels[i].Name = hotel.Name
 			h.hotels[i].Description = hotel.Description
 			h.hotels[i].Address = hotel.Address
+			if hotel.Currency != "" {
+				h.hotels[i].Currency = hotel.Currency
+			}
 			return nil
 		}
 	}
@@ -183,6 +201,153 @@ func (h *hotelRepoStub) DeleteRoom(ctx context.Context, id uuid.UUID) error {
 	}
 	return stdErrors.New("not found")
 }
+func (h *hotelRepoStub) GetRoomTypeForUpdate(ctx context.Context, id uuid.UUID) (domain.RoomType, error) {
+	return h.GetRoomType(ctx, id)
+}
+func (h *hotelRepoStub) CountBookableRooms(context.Context, uuid.UUID) (int, error) { return 0, nil }
+func (h *hotelRepoStub) ListRoomsByType(context.Context, uuid.UUID) ([]domain.Room, error) {
+	return nil, nil
+}
+func (h *hotelRepoStub) CreatePricingRule(ctx context.Context, r domain.PricingRule) error {
+	h.rules = append(h.rules, r)
+	return nil
+}
+func (h *hotelRepoStub) UpdatePricingRule(ctx context.Context, r domain.PricingRule) error {
+	for i, existing := range h.rules {
+		if existing.ID == r.ID {
+			h.rules[i] = r
+			return nil
+		}
+	}
+	return stdErrors.New("not found")
+}
+func (h *hotelRepoStub) DeletePricingRule(ctx context.Context, id uuid.UUID) error {
+	for i, r := range h.rules {
+		if r.ID == id {
+			h.rules = append(h.rules[:i], h.rules[i+1:]...)
+			return nil
+		}
+	}
+	return stdErrors.New("not found")
+}
+func (h *hotelRepoStub) GetPricingRule(ctx context.Context, id uuid.UUID) (domain.PricingRule, error) {
+	for _, r := range h.rules {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return domain.PricingRule{}, stdErrors.New("not found")
+}
+func (h *hotelRepoStub) ListPricingRules(ctx context.Context, hotelID uuid.UUID) ([]domain.PricingRule, error) {
+	var out []domain.PricingRule
+	for i := len(h.rules) - 1; i >= 0; i-- {
+		if h.rules[i].HotelID == hotelID {
+			out = append(out, h.rules[i])
+		}
+	}
+	return out, nil
+}
+func (h *hotelRepoStub) ListCalendar(ctx context.Context, roomTypeID uuid.UUID, from, to time.Time) (domain.Calendar, error) {
+	var out domain.Calendar
+	for _, day := range h.calendar {
+		if day.RoomTypeID == roomTypeID && !day.Date.Before(domain.CalendarDate(from)) && !day.Date.After(domain.CalendarDate(to)) {
+			out = append(out, day)
+		}
+	}
+	return out, nil
+}
+func (h *hotelRepoStub) SaveCalendar(ctx context.Context, days []domain.CalendarDay) error {
+	for _, day := range days {
+		replaced := false
+		for i, existing := range h.calendar {
+			if existing.RoomTypeID == day.RoomTypeID && existing.Date.Equal(day.Date) {
+				h.calendar[i], replaced = day, true
+			}
+		}
+		if !replaced {
+			h.calendar = append(h.calendar, day)
+		}
+	}
+	return nil
+}
+func (h *hotelRepoStub) CreateChargeRule(ctx context.Context, r domain.ChargeRule) error {
+	h.charges = append(h.charges, r)
+	return nil
+}
+func (h *hotelRepoStub) UpdateChargeRule(ctx context.Context, r domain.ChargeRule) error {
+	for i, existing := range h.charges {
+		if existing.ID == r.ID {
+			h.charges[i] = r
+			return nil
+		}
+	}
+	return stdErrors.New("not found")
+}
+func (h *hotelRepoStub) DeleteChargeRule(ctx context.Context, id uuid.UUID) error {
+	for i, r := range h.charges {
+		if r.ID == id {
+			h.charges = append(h.charges[:i], h.charges[i+1:]...)
+			return nil
+		}
+	}
+	return stdErrors.New("not found")
+}
+func (h *hotelRepoStub) GetChargeRule(ctx context.Context, id uuid.UUID) (domain.ChargeRule, error) {
+	for _, r := range h.charges {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return domain.ChargeRule{}, stdErrors.New("not found")
+}
+func (h *hotelRepoStub) ListChargeRules(ctx context.Context, hotelID uuid.UUID) ([]domain.ChargeRule, error) {
+	var out []domain.ChargeRule
+	for _, r := range h.charges {
+		if r.HotelID == hotelID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+func (h *hotelRepoStub) CreateRatePlan(ctx context.Context, p domain.RatePlan) error {
+	h.plans = append(h.plans, p)
+	return nil
+}
+func (h *hotelRepoStub) UpdateRatePlan(ctx context.Context, p domain.RatePlan) error {
+	for i, existing := range h.plans {
+		if existing.ID == p.ID {
+			h.plans[i] = p
+			return nil
+		}
+	}
+	return stdErrors.New("not found")
+}
+func (h *hotelRepoStub) DeleteRatePlan(ctx context.Context, id uuid.UUID) error {
+	for i, p := range h.plans {
+		if p.ID == id {
+			h.plans = append(h.plans[:i], h.plans[i+1:]...)
+			return nil
+		}
+	}
+	return stdErrors.New("not found")
+}
+func (h *hotelRepoStub) GetRatePlan(ctx context.Context, id uuid.UUID) (domain.RatePlan, error) {
+	for _, p := range h.plans {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return domain.RatePlan{}, stdErrors.New("not found")
+}
+func (h *hotelRepoStub) ListRatePlans(ctx context.Context, roomTypeID uuid.UUID) ([]domain.RatePlan, error) {
+	var out []domain.RatePlan
+	for _, p := range h.plans {
+		if p.RoomTypeID == roomTypeID {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
 
 func TestUpdateHotel(t *testing.T) {
 	repo := &hotelRepoStub{}
@@ -210,6 +375,17 @@ func TestUpdateHotel(t *testing.T) {
 	require.Equal(t, "Updated Hotel", h.Hotel.Name)
 	require.Equal(t, "Updated description", h.Hotel.Description)
 	require.Equal(t, "Updated address", h.Hotel.Address)
+	require.Equal(t, "IDR", h.Hotel.Currency)
+
+	// Prices of the hotel move to another currency only when asked to.
+	err = svc.UpdateHotel(context.Background(), hID, dto.HotelUpdateRequest{Name: "Updated Hotel", Address: "Updated address", Currency: "sgd"})
+	require.NoError(t, err)
+	h, err = svc.GetHotel(context.Background(), hID, query.Options{})
+	require.NoError(t, err)
+	require.Equal(t, "SGD", h.Hotel.Currency)
+
+	err = svc.UpdateHotel(context.Background(), hID, dto.HotelUpdateRequest{Name: "Updated Hotel", Address: "Updated address", Currency: "dollars"})
+	require.Error(t, err)
 }
 
 func TestUpdateHotelNotFound(t *testing.T) {
@@ -252,7 +428,7 @@ func TestGetRoom(t *testing.T) {
 		HotelID:   uuid.New().String(),
 		Name:      "Deluxe",
 		Capacity:  2,
-		BasePrice: 1000,
+		BasePrice: valueobject.AmountOf(1000),
 	})
 	
 	roomID, err := svc.CreateRoom(context.Background(), dto.RoomRequest{
@@ -278,7 +454,7 @@ func TestUpdateRoom(t *testing.T) {
 		HotelID:   uuid.New().String(),
 		Name:      "Deluxe",
 		Capacity:  2,
-		BasePrice: 1000,
+		BasePrice: valueobject.AmountOf(1000),
 	})
 	
 	roomID, err := svc.CreateRoom(context.Background(), dto.RoomRequest{
@@ -311,7 +487,7 @@ func TestUpdateRoomPartial(t *testing.T) {
 		HotelID:   uuid.New().String(),
 		Name:      "Deluxe",
 		Capacity:  2,
-		BasePrice: 1000,
+		BasePrice: valueobject.AmountOf(1000),
 	})
 	
 	roomID, err := svc.CreateRoom(context.Background(), dto.RoomRequest{
@@ -343,7 +519,7 @@ func TestDeleteRoom(t *testing.T) {
 		HotelID:   uuid.New().String(),
 		Name:      "Deluxe",
 		Capacity:  2,
-		BasePrice: 1000,
+		BasePrice: valueobject.AmountOf(1000),
 	})
 	
 	roomID, err := svc.CreateRoom(context.Background(), dto.RoomRequest{
@@ -361,3 +537,175 @@ func TestDeleteRoom(t *testing.T) {
 	_, err = svc.GetRoom(context.Background(), roomID)
 	require.Error(t, err)
 }
+
+func TestPricingRules(t *testing.T) {
+	repo := &hotelRepoStub{}
+	svc := hotel.NewService(repo)
+	ctx := context.Background()
+
+	hID, err := svc.CreateHotel(ctx, dto.HotelRequest{Name: "Hilton", Address: "Jakarta"})
+	require.NoError(t, err)
+	rtID, err := svc.CreateRoomType(ctx, dto.RoomTypeRequest{HotelID: hID.String(), Name: "Deluxe", Capacity: 2, BasePrice: valueobject.AmountOf(1000)})
+	require.NoError(t, err)
+	otherRT, err := svc.CreateRoomType(ctx, dto.RoomTypeRequest{HotelID: uuid.New().String(), Name: "Suite", Capacity: 2, BasePrice: valueobject.AmountOf(2000)})
+	require.NoError(t, err)
+
+	weekend, err := svc.CreatePricingRule(ctx, dto.PricingRuleRequest{
+		HotelID: hID.String(), Kind: "day_of_week", Name: "weekend", Days: []string{"fri", "sat"}, Percent: 15,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []time.Weekday{time.Friday, time.Saturday}, weekend.Rule.Weekdays)
+
+	peak, err := svc.CreatePricingRule(ctx, dto.PricingRuleRequest{
+		HotelID: hID.String(), RoomTypeID: rtID.String(), Kind: "season", Name: "peak",
+		StartDate: dto.Date{Time: time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)},
+		EndDate:   dto.Date{Time: time.Date(2031, 1, 3, 0, 0, 0, 0, time.UTC)},
+		Rate:      valueobject.AmountOf(1500),
+	})
+	require.NoError(t, err)
+	require.Equal(t, rtID, peak.RoomTypeID)
+
+	// The room type must belong to the hotel, and the rule must be valid for its kind.
+	_, err = svc.CreatePricingRule(ctx, dto.PricingRuleRequest{HotelID: hID.String(), RoomTypeID: otherRT.String(), Kind: "occupancy", Name: "x", Guests: 2, Percent: 10})
+	require.Error(t, err)
+	_, err = svc.CreatePricingRule(ctx, dto.PricingRuleRequest{HotelID: hID.String(), Kind: "day_of_week", Name: "x", Days: []string{"someday"}, Percent: 10})
+	require.Error(t, err)
+	_, err = svc.CreatePricingRule(ctx, dto.PricingRuleRequest{HotelID: hID.String(), Kind: "length_of_stay", Name: "x", Percent: -10})
+	require.Error(t, err)
+
+	updated, err := svc.UpdatePricingRule(ctx, weekend.ID, dto.PricingRuleRequest{
+		HotelID: hID.String(), Kind: "day_of_week", Name: "weekend", Days: []string{"sat"}, Percent: 20,
+	})
+	require.NoError(t, err)
+	require.Equal(t, weekend.CreatedAt, updated.CreatedAt)
+
+	rules, err := svc.ListPricingRules(ctx, hID, rtID)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, peak.ID, rules[0].ID)
+	require.Equal(t, 20.0, rules[1].Rule.Percent)
+
+	rules, err = svc.ListPricingRules(ctx, hID, uuid.New())
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	require.NoError(t, svc.DeletePricingRule(ctx, peak.ID))
+	rules, err = svc.ListPricingRules(ctx, hID, uuid.Nil)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+}
+
+func TestChargeRules(t *testing.T) {
+	repo := &hotelRepoStub{}
+	svc := hotel.NewService(repo)
+	ctx := context.Background()
+
+	hID, err := svc.CreateHotel(ctx, dto.HotelRequest{Name: "Hilton", Address: "Jakarta"})
+	require.NoError(t, err)
+
+	pb1, err := svc.CreateChargeRule(ctx, dto.ChargeRuleRequest{HotelID: hID.String(), Kind: "tax", Name: "PB1", Basis: "percent", Amount: valueobject.AmountOf(10)})
+	require.NoError(t, err)
+	fee, err := svc.CreateChargeRule(ctx, dto.ChargeRuleRequest{HotelID: hID.String(), Kind: "fee", Name: "City fee", Basis: "flat", Amount: valueobject.AmountOf(15000), Per: "night"})
+	require.NoError(t, err)
+	require.Equal(t, "night", fee.Rule.Per)
+
+	_, err = svc.CreateChargeRule(ctx, dto.ChargeRuleRequest{HotelID: uuid.New().String(), Kind: "tax", Name: "PB1", Basis: "percent", Amount: valueobject.AmountOf(10)})
+	require.Error(t, err)
+	_, err = svc.CreateChargeRule(ctx, dto.ChargeRuleRequest{HotelID: hID.String(), Kind: "fee", Name: "City fee", Basis: "flat", Amount: valueobject.AmountOf(15000)})
+	require.Error(t, err)
+
+	updated, err := svc.UpdateChargeRule(ctx, pb1.ID, dto.ChargeRuleRequest{HotelID: hID.String(), Kind: "tax", Name: "PB1", Basis: "percent", Amount: valueobject.AmountOf(10), Inclusive: true})
+	require.NoError(t, err)
+	require.Equal(t, pb1.CreatedAt, updated.CreatedAt)
+
+	rules, err := svc.ListChargeRules(ctx, hID)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.True(t, rules[0].Rule.Inclusive)
+
+	require.NoError(t, svc.DeleteChargeRule(ctx, fee.ID))
+	rules, err = svc.ListChargeRules(ctx, hID)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+}
+
+func TestRatePlans(t *testing.T) {
+	repo := &hotelRepoStub{}
+	svc := hotel.NewService(repo)
+	ctx := context.Background()
+
+	rtID, err := svc.CreateRoomType(ctx, dto.RoomTypeRequest{HotelID: uuid.New().String(), Name: "Deluxe", Capacity: 2, BasePrice: valueobject.AmountOf(1000000), CancellationPolicy: "free_cancellation"})
+	require.NoError(t, err)
+
+	flexible, err := svc.CreateRatePlan(ctx, dto.RatePlanRequest{RoomTypeID: rtID.String(), Name: "Flexible"})
+	require.NoError(t, err)
+	require.Equal(t, valueobject.PolicyFreeCancellation, flexible.Plan.CancellationPolicy)
+	require.Equal(t, valueobject.PayNow, flexible.Plan.PaymentTiming)
+
+	saver, err := svc.CreateRatePlan(ctx, dto.RatePlanRequest{RoomTypeID: rtID.String(), Name: "Saver", Percent: -15, CancellationPolicy: "non_refundable"})
+	require.NoError(t, err)
+	require.Equal(t, valueobject.PolicyNonRefundable, saver.Plan.CancellationPolicy)
+
+	_, err = svc.CreateRatePlan(ctx, dto.RatePlanRequest{RoomTypeID: uuid.New().String(), Name: "Flexible"})
+	require.Error(t, err)
+	_, err = svc.CreateRatePlan(ctx, dto.RatePlanRequest{RoomTypeID: rtID.String(), Name: "Later", PaymentTiming: "pay_later"})
+	require.Error(t, err)
+
+	updated, err := svc.UpdateRatePlan(ctx, flexible.ID, dto.RatePlanRequest{RoomTypeID: rtID.String(), Name: "Bed and breakfast", PerNight: valueobject.AmountOf(150000), Inclusions: []string{"Breakfast"}, PaymentTiming: "pay_at_hotel"})
+	require.NoError(t, err)
+	require.Equal(t, flexible.CreatedAt, updated.CreatedAt)
+
+	plans, err := svc.ListRatePlans(ctx, rtID)
+	require.NoError(t, err)
+	require.Len(t, plans, 2)
+	require.Equal(t, []string{"breakfast"}, plans[0].Plan.Inclusions)
+	require.True(t, plans[0].Plan.PaysAtHotel())
+
+	require.NoError(t, svc.DeleteRatePlan(ctx, saver.ID))
+	plans, err = svc.ListRatePlans(ctx, rtID)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+}
+
+func TestUpdateCalendar(t *testing.T) {
+	repo := &hotelRepoStub{}
+	svc := hotel.NewService(repo)
+	ctx := context.Background()
+
+	rtID, err := svc.CreateRoomType(ctx, dto.RoomTypeRequest{HotelID: uuid.New().String(), Name: "Deluxe", Capacity: 2, BasePrice: valueobject.AmountOf(1000)})
+	require.NoError(t, err)
+	day := func(d int) dto.Date { return dto.Date{Time: time.Date(2030, 12, d, 0, 0, 0, 0, time.UTC)} }
+	price, minStay, closed := valueobject.AmountOf(2500), 2, true
+
+	// Weekends of December cost more, and arrivals need two nights.
+	_, days, err := svc.UpdateCalendar(ctx, rtID, dto.CalendarUpdateRequest{
+		StartDate: day(1), EndDate: day(31), Days: []string{"fri", "sat"}, Price: &price, MinStay: &minStay,
+	})
+	require.NoError(t, err)
+	require.Len(t, days, 8)
+	for _, d := range days {
+		require.Contains(t, []time.Weekday{time.Friday, time.Saturday}, d.Date.Weekday())
+	}
+
+	// A later update keeps the fields it does not set.
+	_, _, err = svc.UpdateCalendar(ctx, rtID, dto.CalendarUpdateRequest{StartDate: day(6), EndDate: day(8), ClosedToArrival: &closed})
+	require.NoError(t, err)
+	rt, calendar, err := svc.Calendar(ctx, rtID, day(5).Time, day(9).Time)
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(1000), rt.BasePrice)
+	require.Len(t, calendar, 4)
+	require.Equal(t, domain.CalendarDay{RoomTypeID: rtID, Date: day(6).Time, Price: valueobject.AmountOf(2500), ClosedToArrival: true, MinStay: 2}, calendar[1])
+	require.True(t, calendar[2].ClosedToArrival)
+	require.Equal(t, valueobject.AmountOf(2500), calendar[2].Price)
+	require.Equal(t, domain.CalendarDay{RoomTypeID: rtID, Date: day(8).Time}, calendar[3])
+
+	maxStay := 1
+	_, _, err = svc.UpdateCalendar(ctx, rtID, dto.CalendarUpdateRequest{StartDate: day(6), EndDate: day(7), MaxStay: &maxStay})
+	require.Error(t, err)
+	_, _, err = svc.UpdateCalendar(ctx, rtID, dto.CalendarUpdateRequest{StartDate: day(6), EndDate: day(7)})
+	require.Error(t, err)
+	_, _, err = svc.UpdateCalendar(ctx, rtID, dto.CalendarUpdateRequest{StartDate: day(1), EndDate: dto.Date{Time: day(1).AddDate(2, 0, 0)}, MinStay: &minStay})
+	require.Error(t, err)
+	_, _, err = svc.UpdateCalendar(ctx, uuid.New(), dto.CalendarUpdateRequest{StartDate: day(6), EndDate: day(7), MinStay: &minStay})
+	require.Error(t, err)
+}
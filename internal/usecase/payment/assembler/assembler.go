@@ -14,7 +14,10 @@ import (
 // InitiateCommand represents inbound payment initiation intent.
 type InitiateCommand struct {
 	BookingID uuid.UUID
+	UserID    uuid.UUID // requested owner; zero means the caller
+	Kind      string
 	Money     valueobject.Money
+	Lines     []domain.Line // optional itemisation of Money
 }
 
 // WebhookCommand represents inbound webhook update.
@@ -28,14 +31,19 @@ type WebhookCommand struct {
 // RefundCommand represents refund intent.
 type RefundCommand struct {
 	PaymentID uuid.UUID
+	BookingID uuid.UUID          // set instead of PaymentID to refund across the booking's payments
+	Amount    valueobject.Amount // zero refunds the full payment
+	Currency  string             // empty means the payment's currency
 	Reason    string
 }
 
 // RefundResult represents refund outcome for handler mapping.
 type RefundResult struct {
 	PaymentID uuid.UUID
+	Amount    valueobject.Amount
 	Status    string
 	Reference string
+	Parts     []RefundResult // per payment, when a booking refund spans several
 }
 
 // FromPaymentRequest validates and builds an initiate command.
@@ -44,11 +52,32 @@ func FromPaymentRequest(req dto.PaymentRequest) (InitiateCommand, error) {
 	if err != nil {
 		return InitiateCommand{}, errors.New("bad_request", "invalid booking id")
 	}
+	var userID uuid.UUID
+	if req.UserID != "" {
+		if userID, err = uuid.Parse(req.UserID); err != nil {
+			return InitiateCommand{}, errors.New("bad_request", "invalid user id")
+		}
+	}
+	kind := req.Kind
+	switch kind {
+	case "":
+		kind = domain.KindBooking
+	case domain.KindBooking, domain.KindSupplement:
+	default:
+		return InitiateCommand{}, errors.New("bad_request", "invalid payment kind")
+	}
 	money, err := valueobject.NewMoney(req.Amount, req.Currency)
 	if err != nil {
 		return InitiateCommand{}, err
 	}
-	return InitiateCommand{BookingID: bookingID, Money: money}, nil
+	lines := make([]domain.Line, 0, len(req.Lines))
+	for _, l := range req.Lines {
+		lines = append(lines, domain.Line(l))
+	}
+	if err := domain.CheckLines(money.Amount, lines); err != nil {
+		return InitiateCommand{}, err
+	}
+	return InitiateCommand{BookingID: bookingID, UserID: userID, Kind: kind, Money: money, Lines: lines}, nil
 }
 
 // FromWebhook builds webhook command.
@@ -70,25 +99,46 @@ func FromWebhook(req dto.WebhookRequest, raw string) (WebhookCommand, error) {
 
 // FromRefundRequest builds refund command.
 func FromRefundRequest(req dto.RefundRequest) (RefundCommand, error) {
-	paymentID, err := uuid.Parse(req.PaymentID)
-	if err != nil {
-		return RefundCommand{}, errors.New("bad_request", "invalid payment id")
+	if req.Amount.IsNegative() {
+		return RefundCommand{}, errors.New("bad_request", "invalid refund amount")
 	}
-	return RefundCommand{PaymentID: paymentID, Reason: req.Reason}, nil
+	cmd := RefundCommand{Amount: req.Amount, Reason: req.Reason}
+	var err error
+	switch {
+	case req.PaymentID != "" && req.BookingID != "":
+		return RefundCommand{}, errors.New("bad_request", "refund either a payment or a booking")
+	case req.BookingID != "":
+		if cmd.BookingID, err = uuid.Parse(req.BookingID); err != nil {
+			return RefundCommand{}, errors.New("bad_request", "invalid booking id")
+		}
+	default:
+		if cmd.PaymentID, err = uuid.Parse(req.PaymentID); err != nil {
+			return RefundCommand{}, errors.New("bad_request", "invalid payment id")
+		}
+	}
+	if req.Currency != "" {
+		if cmd.Currency, err = valueobject.ParseCurrency(req.Currency); err != nil {
+			return RefundCommand{}, err
+		}
+	}
+	return cmd, nil
 }
 
 // ToRefundResult maps provider response to result.
-func ToRefundResult(paymentID uuid.UUID, ref string) RefundResult {
-	return RefundResult{PaymentID: paymentID, Status: "refunded", Reference: ref}
+func ToRefundResult(paymentID uuid.UUID, amount valueobject.Amount, ref string) RefundResult {
+	return RefundResult{PaymentID: paymentID, Amount: amount, Status: "refunded", Reference: ref}
 }
 
 // ToResponse maps domain Payment to DTO.
 func ToResponse(p domain.Payment) dto.PaymentResponse {
 	return dto.PaymentResponse{
-		ID:         p.ID.String(),
-		Status:     p.Status,
-		Provider:   p.Provider,
-		PaymentURL: p.PaymentURL,
+		ID:          p.ID.String(),
+		Kind:        p.Kind,
+		Amount:      p.Amount,
+		Status:      p.Status,
+		Provider:    p.Provider,
+		PaymentURL:  p.PaymentURL,
+		Description: p.Description,
 	}
 }
 
@@ -96,9 +146,22 @@ func ToResponse(p domain.Payment) dto.PaymentResponse {
 func ToRefundResponse(res RefundResult) dto.RefundResponse {
 	return dto.RefundResponse{
 		ID:        res.PaymentID.String(),
+		Amount:    res.Amount,
 		Status:    res.Status,
         Reference: res.Reference,
+		Payments:  toRefundResponses(res.Parts),
+	}
+}
+
+func toRefundResponses(parts []RefundResult) []dto.RefundResponse {
+	if len(parts) == 0 {
+		return nil
+	}
+	out := make([]dto.RefundResponse, 0, len(parts))
+	for _, p := range parts {
+		out = append(out, ToRefundResponse(p))
 	}
+	return out
 }
 
 // CanonicalPayload constructs canonical payload for signature verify.
@@ -7,22 +7,43 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ftryyln/hotel-booking-microservices/pkg/dto"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 func TestFromPaymentRequest(t *testing.T) {
 	req := dto.PaymentRequest{
 		BookingID: uuid.New().String(),
-		Amount:    1000,
+		Amount:    valueobject.AmountOf(1000),
 		Currency:  "IDR",
 	}
 	cmd, err := FromPaymentRequest(req)
 	require.NoError(t, err)
 	require.Equal(t, req.Currency, cmd.Money.Currency)
 
-	_, err = FromPaymentRequest(dto.PaymentRequest{BookingID: "bad", Amount: 1000, Currency: "IDR"})
+	_, err = FromPaymentRequest(dto.PaymentRequest{BookingID: "bad", Amount: valueobject.AmountOf(1000), Currency: "IDR"})
 	require.Error(t, err)
 
-	_, err = FromPaymentRequest(dto.PaymentRequest{BookingID: uuid.New().String(), Amount: -1, Currency: "IDR"})
+	_, err = FromPaymentRequest(dto.PaymentRequest{BookingID: uuid.New().String(), Amount: valueobject.AmountOf(-1), Currency: "IDR"})
+	require.Error(t, err)
+}
+
+func TestFromPaymentRequestChecksLines(t *testing.T) {
+	req := dto.PaymentRequest{
+		BookingID: uuid.New().String(),
+		Amount:    valueobject.AmountOf(1100),
+		Currency:  "IDR",
+		Lines: []dto.PriceLine{
+			{Type: "room", Name: "Room nights", Amount: valueobject.AmountOf(1000)},
+			{Type: "tax", Name: "PB1", Amount: valueobject.AmountOf(100)},
+			{Type: "tax", Name: "VAT", Amount: valueobject.AmountOf(99), Included: true},
+		},
+	}
+	cmd, err := FromPaymentRequest(req)
+	require.NoError(t, err)
+	require.Len(t, cmd.Lines, 3)
+
+	req.Amount = valueobject.AmountOf(1199)
+	_, err = FromPaymentRequest(req)
 	require.Error(t, err)
 }
 
@@ -44,5 +65,14 @@ func TestFromRefundRequest(t *testing.T) {
 
 	_, err = FromRefundRequest(dto.RefundRequest{PaymentID: "bad"})
 	require.Error(t, err)
+
+	bookingID := uuid.New()
+	cmd, err = FromRefundRequest(dto.RefundRequest{BookingID: bookingID.String()})
+	require.NoError(t, err)
+	require.Equal(t, bookingID, cmd.BookingID)
+	require.Equal(t, uuid.Nil, cmd.PaymentID)
+
+	_, err = FromRefundRequest(dto.RefundRequest{PaymentID: uuid.New().String(), BookingID: bookingID.String()})
+	require.Error(t, err)
 }
 
@@ -3,12 +3,15 @@ package payment
 import (
 	"context"
 	"errors"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
 	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/payment/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/audit"
 	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
@@ -18,25 +21,46 @@ type Service struct {
 	repo           domain.Repository
 	provider       domain.Provider
 	bookingUpdater domain.BookingStatusUpdater
+	audit          audit.Log
 }
 
 func NewService(repo domain.Repository, provider domain.Provider, updater domain.BookingStatusUpdater) *Service {
 	return &Service{repo: repo, provider: provider, bookingUpdater: updater}
 }
 
+// SetAuditLog configures where actions admins take on other users' payments are recorded.
+func (s *Service) SetAuditLog(log audit.Log) {
+	s.audit = log
+}
+
 // Initiate creates a new payment from a validated command.
+// A booking has exactly one KindBooking payment; supplements may be added freely.
+// The payment belongs to the caller unless an admin initiates it on behalf of a user.
 func (s *Service) Initiate(ctx context.Context, cmd assembler.InitiateCommand) (domain.Payment, error) {
-	if existing, err := s.repo.FindByBookingID(ctx, cmd.BookingID); err == nil {
-		return existing, pkgErrors.New("conflict", "payment already exists for booking")
+	if cmd.Kind == "" {
+		cmd.Kind = domain.KindBooking
+	}
+	owner, ok := audit.Owner(ctx, cmd.UserID)
+	if !ok {
+		return domain.Payment{}, pkgErrors.New("forbidden", "cannot pay on behalf of another user")
+	}
+	if cmd.Kind == domain.KindBooking {
+		if existing, err := s.repo.FindByBookingID(ctx, cmd.BookingID); err == nil {
+			return existing, pkgErrors.New("conflict", "payment already exists for booking")
+		}
 	}
 
 	payment := domain.Payment{
 		ID:        uuid.New(),
 		BookingID: cmd.BookingID,
+		UserID:    owner,
+		Kind:      cmd.Kind,
 		Amount:    cmd.Money.Amount,
 		Currency:  cmd.Money.Currency,
 		Status:    string(valueobject.PaymentPending),
 		Provider:  "xendit-mock",
+
+		Description: domain.Describe(cmd.BookingID, cmd.Money.Currency, cmd.Lines),
 	}
 
 	initiated, err := s.provider.Initiate(ctx, payment)
@@ -52,6 +76,9 @@ func (s *Service) Initiate(ctx context.Context, cmd assembler.InitiateCommand) (
 		}
 		return domain.Payment{}, err
 	}
+	if err := audit.Record(ctx, s.audit, "payment.initiate", "payment", initiated.ID, owner); err != nil {
+		return domain.Payment{}, err
+	}
 
 	return initiated, nil
 }
@@ -85,7 +112,8 @@ func (s *Service) HandleWebhook(ctx context.Context, cmd assembler.WebhookComman
 		return err
 	}
 
-	if s.bookingUpdater != nil {
+	// Only the booking payment drives booking status; supplements settle on their own.
+	if s.bookingUpdater != nil && payment.Kind != domain.KindSupplement {
 		var bookingStatus string
 		switch cmd.Status {
 		case domain.StatusPaid:
@@ -114,33 +142,221 @@ func mapProviderStatus(status string) (valueobject.PaymentStatus, error) {
 	}
 }
 
-// Refund requests refund via provider.
+// Refund pays back part or all of what a paid payment collected; a zero amount refunds whatever is
+// left. Refunds add up: each is recorded, and one that would take them past the paid amount is
+// rejected. The refund is recorded under the payment lock before the provider is asked, so
+// concurrent refunds cannot both pass the check; one the provider rejects frees its amount again.
+//
+// A booking refund (cmd.BookingID) spreads the amount over the booking's paid payments, the
+// booking payment first and then its supplements, each up to what it has left to refund. When the
+// provider rejects one of them the rest are not attempted; those paid back before stay refunded.
 func (s *Service) Refund(ctx context.Context, cmd assembler.RefundCommand) (assembler.RefundResult, error) {
-	payment, err := s.repo.FindByID(ctx, cmd.PaymentID)
+	var refunds []pendingRefund
+	err := s.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		payments, err := s.lockRefundable(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		left := make([]valueobject.Amount, len(payments))
+		var total valueobject.Amount
+		for i, payment := range payments {
+			refunded, err := s.repo.RefundedAmount(ctx, payment.ID)
+			if err != nil {
+				return err
+			}
+			left[i] = payment.Refundable(refunded)
+			total = total.Add(left[i])
+		}
+		amount := cmd.Amount
+		if amount.IsZero() {
+			amount = total
+		}
+		if !amount.IsPositive() {
+			return pkgErrors.New("conflict", "payment is already fully refunded")
+		}
+		if amount.Cmp(total) > 0 {
+			return pkgErrors.New("bad_request", "refund exceeds payment amount")
+		}
+
+		now := time.Now()
+		for i, payment := range payments {
+			share := left[i]
+			if share.Cmp(amount) > 0 {
+				share = amount
+			}
+			if !share.IsPositive() {
+				continue
+			}
+			if err := audit.Record(ctx, s.audit, "payment.refund", "payment", payment.ID, payment.UserID); err != nil {
+				return err
+			}
+			refund := domain.Refund{
+				ID:        uuid.New(),
+				PaymentID: payment.ID,
+				Amount:    share,
+				Reason:    cmd.Reason,
+				Status:    domain.RefundPending,
+				CreatedAt: now,
+			}
+			if err := s.repo.CreateRefund(ctx, refund); err != nil {
+				return err
+			}
+			refunds = append(refunds, pendingRefund{payment: payment, refund: refund})
+			if amount = amount.Sub(share); amount.IsZero() {
+				break
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return assembler.RefundResult{}, err
 	}
 
-	ref, err := s.provider.Refund(ctx, payment, cmd.Reason)
+	var result assembler.RefundResult
+	for i := range refunds {
+		r := &refunds[i]
+		if err := s.payOut(ctx, r.payment, &r.refund); err != nil {
+			for _, rest := range refunds[i+1:] {
+				rest.refund.Status = domain.RefundFailed
+				if saveErr := s.repo.SaveRefund(ctx, rest.refund); saveErr != nil {
+					return assembler.RefundResult{}, saveErr
+				}
+			}
+			return assembler.RefundResult{}, err
+		}
+		result.Parts = append(result.Parts, assembler.ToRefundResult(r.payment.ID, r.refund.Amount, r.refund.Reference))
+	}
+	if len(result.Parts) == 1 {
+		return result.Parts[0], nil
+	}
+	// A refund spanning several payments is reported under the first, the booking payment.
+	result.PaymentID, result.Status, result.Reference = result.Parts[0].PaymentID, result.Parts[0].Status, result.Parts[0].Reference
+	for _, part := range result.Parts {
+		result.Amount = result.Amount.Add(part.Amount)
+	}
+	return result, nil
+}
+
+// pendingRefund is a refund recorded for a payment and not paid out yet.
+type pendingRefund struct {
+	payment domain.Payment
+	refund  domain.Refund
+}
+
+// lockRefundable locks the payments cmd refunds, in the order they are refunded: the payment it
+// names, or the paid payments of the booking it names.
+func (s *Service) lockRefundable(ctx context.Context, cmd assembler.RefundCommand) ([]domain.Payment, error) {
+	if cmd.BookingID == uuid.Nil {
+		payment, err := s.lockOwned(ctx, cmd.PaymentID)
+		if err != nil {
+			return nil, err
+		}
+		if cmd.Currency != "" && cmd.Currency != payment.Currency {
+			return nil, pkgErrors.New("bad_request", "refund currency does not match payment currency")
+		}
+		if payment.Status != domain.StatusPaid {
+			return nil, pkgErrors.New("conflict", "only paid payments can be refunded")
+		}
+		return []domain.Payment{payment}, nil
+	}
+
+	payments, err := s.repo.ListByBookingForUpdate(ctx, cmd.BookingID)
 	if err != nil {
-		return assembler.RefundResult{}, err
+		return nil, err
+	}
+	if len(payments) == 0 {
+		return nil, pkgErrors.New("not_found", "payment not found")
+	}
+	var paid []domain.Payment
+	for _, payment := range payments {
+		if !audit.CanAccess(ctx, payment.UserID) {
+			return nil, pkgErrors.New("not_found", "payment not found")
+		}
+		if payment.Status != domain.StatusPaid {
+			continue
+		}
+		if cmd.Currency != "" && cmd.Currency != payment.Currency {
+			return nil, pkgErrors.New("bad_request", "refund currency does not match payment currency")
+		}
+		paid = append(paid, payment)
+	}
+	if len(paid) == 0 {
+		return nil, pkgErrors.New("conflict", "only paid payments can be refunded")
+	}
+	sort.SliceStable(paid, func(i, j int) bool {
+		return paid[i].Kind == domain.KindBooking && paid[j].Kind != domain.KindBooking
+	})
+	return paid, nil
+}
+
+// payOut asks the provider to pay a recorded refund out and records the outcome.
+func (s *Service) payOut(ctx context.Context, payment domain.Payment, refund *domain.Refund) error {
+	ref, err := s.provider.Refund(ctx, payment, refund.Amount, refund.Reason)
+	if err != nil {
+		refund.Status = domain.RefundFailed
+		if saveErr := s.repo.SaveRefund(ctx, *refund); saveErr != nil {
+			return saveErr
+		}
+		return err
 	}
+	refund.Status, refund.Reference = domain.RefundSucceeded, ref
+	return s.repo.SaveRefund(ctx, *refund)
+}
 
-	return assembler.ToRefundResult(payment.ID, ref), nil
+// Expire voids a pending payment whose booking hold ran out. Expiring twice is a no-op,
+// while a payment that was already paid is reported as a conflict so the booking can be kept.
+func (s *Service) Expire(ctx context.Context, id uuid.UUID) (domain.Payment, error) {
+	payment, err := s.findOwned(ctx, id)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	switch payment.Status {
+	case string(valueobject.PaymentFailed):
+		return payment, nil
+	case string(valueobject.PaymentPaid):
+		return payment, pkgErrors.New("conflict", "payment already settled")
+	}
+	if err := audit.Record(ctx, s.audit, "payment.expire", "payment", payment.ID, payment.UserID); err != nil {
+		return domain.Payment{}, err
+	}
+	if err := s.repo.UpdateStatus(ctx, payment.ID, string(valueobject.PaymentFailed), "", "", ""); err != nil {
+		return domain.Payment{}, err
+	}
+	payment.Status = string(valueobject.PaymentFailed)
+	return payment, nil
 }
 
 // GetPayment fetches payment by ID.
 func (s *Service) GetPayment(ctx context.Context, id uuid.UUID) (domain.Payment, error) {
+	return s.findOwned(ctx, id)
+}
+
+// GetByBooking returns payment for a given booking.
+func (s *Service) GetByBooking(ctx context.Context, bookingID uuid.UUID) (domain.Payment, error) {
+	pay, err := s.repo.FindByBookingID(ctx, bookingID)
+	if err == nil && !audit.CanAccess(ctx, pay.UserID) {
+		return domain.Payment{}, pkgErrors.New("not_found", "payment not found")
+	}
+	return pay, err
+}
+
+// findOwned loads a payment the caller on ctx may access: its owner or an admin.
+// Other users' payments are reported as missing so their ids cannot be probed.
+func (s *Service) findOwned(ctx context.Context, id uuid.UUID) (domain.Payment, error) {
 	pay, err := s.repo.FindByID(ctx, id)
-	if err != nil {
+	if err != nil || !audit.CanAccess(ctx, pay.UserID) {
 		return domain.Payment{}, pkgErrors.New("not_found", "payment not found")
 	}
 	return pay, nil
 }
 
-// GetByBooking returns payment for a given booking.
-func (s *Service) GetByBooking(ctx context.Context, bookingID uuid.UUID) (domain.Payment, error) {
-	return s.repo.FindByBookingID(ctx, bookingID)
+// lockOwned loads and locks a payment the caller on ctx may access. It must run inside a transaction.
+func (s *Service) lockOwned(ctx context.Context, id uuid.UUID) (domain.Payment, error) {
+	pay, err := s.repo.FindByIDForUpdate(ctx, id)
+	if err != nil || !audit.CanAccess(ctx, pay.UserID) {
+		return domain.Payment{}, pkgErrors.New("not_found", "payment not found")
+	}
+	return pay, nil
 }
 
 func isUniqueViolation(err error) bool {
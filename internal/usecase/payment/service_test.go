@@ -3,7 +3,9 @@ package payment_test
 import (
 	"context"
 	"errors"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -11,6 +13,9 @@ import (
 	domain "github.com/ftryyln/hotel-booking-microservices/internal/domain/payment"
 	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/payment"
 	"github.com/ftryyln/hotel-booking-microservices/internal/usecase/payment/assembler"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/audit"
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
 	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
@@ -57,41 +62,219 @@ func TestHandleWebhook(t *testing.T) {
 func TestRefund(t *testing.T) {
 	paymentID := uuid.New()
 	repo := &paymentRepoStub{store: map[uuid.UUID]domain.Payment{
-		paymentID: {ID: paymentID},
+		paymentID: {ID: paymentID, Amount: valueobject.AmountOf(300), Status: domain.StatusPaid},
 	}}
 	provider := &providerStub{signatureValid: true}
 	service := payment.NewService(repo, provider, nil)
 
-	tests := []struct {
-		name       string
-		shouldFail bool
-	}{
-		{"success", false},
-		{"provider failure", true},
-	}
+	// A refund the provider rejects is recorded as failed and frees its amount again.
+	provider.refundErr = errors.New("fail")
+	_, err := service.Refund(context.Background(), assembler.RefundCommand{PaymentID: paymentID, Reason: "test"})
+	require.Error(t, err)
+	require.Len(t, repo.refunds, 1)
+	require.Equal(t, domain.RefundFailed, repo.refunds[0].Status)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if tt.shouldFail {
-				provider.refundErr = errors.New("fail")
-			} else {
-				provider.refundErr = nil
-			}
-			cmd := assembler.RefundCommand{PaymentID: paymentID, Reason: "test"}
-			_, err := service.Refund(context.Background(), cmd)
-			if tt.shouldFail {
-				require.Error(t, err)
-			} else {
-				require.NoError(t, err)
-			}
-		})
+	provider.refundErr = nil
+	res, err := service.Refund(context.Background(), assembler.RefundCommand{PaymentID: paymentID, Reason: "test"})
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(300), res.Amount)
+	require.Equal(t, "ref", res.Reference)
+	require.Equal(t, domain.RefundSucceeded, repo.refunds[1].Status)
+}
+
+func TestInitiateSupplementAlongsideBookingPayment(t *testing.T) {
+	repo := &paymentRepoStub{store: map[uuid.UUID]domain.Payment{}}
+	service := payment.NewService(repo, &providerStub{}, nil)
+	bookingID := uuid.New()
+	money, err := valueobject.NewMoney(valueobject.AmountOf(200), "IDR")
+	require.NoError(t, err)
+
+	_, err = service.Initiate(context.Background(), assembler.InitiateCommand{BookingID: bookingID, Money: money})
+	require.NoError(t, err)
+
+	supplement, err := service.Initiate(context.Background(), assembler.InitiateCommand{BookingID: bookingID, Kind: domain.KindSupplement, Money: money})
+	require.NoError(t, err)
+	require.Equal(t, domain.KindSupplement, supplement.Kind)
+
+	_, err = service.Initiate(context.Background(), assembler.InitiateCommand{BookingID: bookingID, Money: money})
+	require.Error(t, err)
+}
+
+func TestInitiateDescribesLines(t *testing.T) {
+	repo := &paymentRepoStub{store: map[uuid.UUID]domain.Payment{}}
+	service := payment.NewService(repo, &providerStub{}, nil)
+	bookingID := uuid.New()
+	money, err := valueobject.NewMoney(valueobject.AmountOf(1226500), "IDR")
+	require.NoError(t, err)
+
+	pay, err := service.Initiate(context.Background(), assembler.InitiateCommand{BookingID: bookingID, Money: money, Lines: []domain.Line{
+		{Type: "room", Name: "Room nights", Amount: valueobject.AmountOf(1000000)},
+		{Type: "fee", Name: "Service charge", Amount: valueobject.AmountOf(115000)},
+		{Type: "tax", Name: "PB1", Amount: valueobject.AmountOf(111500)},
+		{Type: "tax", Name: "VAT", Amount: valueobject.AmountOf(99099.099), Included: true},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, "Booking "+bookingID.String()+": Room nights 1000000; Service charge 115000; PB1 111500; VAT 99099 (included)", pay.Description)
+	require.Equal(t, pay.Description, repo.store[pay.ID].Description)
+}
+
+func TestHandleWebhookSupplementKeepsBookingStatus(t *testing.T) {
+	paymentID := uuid.New()
+	repo := &paymentRepoStub{store: map[uuid.UUID]domain.Payment{
+		paymentID: {ID: paymentID, BookingID: uuid.New(), Kind: domain.KindSupplement, Status: string(valueobject.PaymentPending)},
+	}}
+	updater := &bookingUpdaterStub{}
+	service := payment.NewService(repo, &providerStub{signatureValid: true}, updater)
+
+	cmd := assembler.WebhookCommand{PaymentID: paymentID, Status: domain.StatusPaid, Signature: "sig"}
+	require.NoError(t, service.HandleWebhook(context.Background(), cmd))
+	require.Empty(t, updater.statuses)
+}
+
+func TestPartialRefund(t *testing.T) {
+	paymentID, pendingID := uuid.New(), uuid.New()
+	repo := &paymentRepoStub{store: map[uuid.UUID]domain.Payment{
+		paymentID: {ID: paymentID, Amount: valueobject.AmountOf(300), Currency: "USD", Status: domain.StatusPaid},
+		pendingID: {ID: pendingID, Amount: valueobject.AmountOf(300), Currency: "USD", Status: domain.StatusPending},
+	}}
+	provider := &providerStub{}
+	service := payment.NewService(repo, provider, nil)
+
+	res, err := service.Refund(context.Background(), assembler.RefundCommand{PaymentID: paymentID, Amount: valueobject.AmountOf(100)})
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(100), res.Amount)
+	require.Equal(t, valueobject.AmountOf(100), provider.refundAmount)
+
+	// Refunds add up and never exceed what was paid.
+	_, err = service.Refund(context.Background(), assembler.RefundCommand{PaymentID: paymentID, Amount: valueobject.AmountOf(201)})
+	require.Equal(t, "bad_request", pkgErrors.FromError(err).Code)
+
+	// Refunds are in the currency the payment was made in.
+	_, err = service.Refund(context.Background(), assembler.RefundCommand{PaymentID: paymentID, Amount: valueobject.AmountOf(100), Currency: "IDR"})
+	require.Error(t, err)
+	_, err = service.Refund(context.Background(), assembler.RefundCommand{PaymentID: paymentID, Amount: valueobject.AmountOf(50), Currency: "USD"})
+	require.NoError(t, err)
+
+	// A zero amount refunds whatever is left.
+	res, err = service.Refund(context.Background(), assembler.RefundCommand{PaymentID: paymentID})
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(150), res.Amount)
+	_, err = service.Refund(context.Background(), assembler.RefundCommand{PaymentID: paymentID, Amount: valueobject.AmountOf(1)})
+	require.Error(t, err)
+	_, err = service.Refund(context.Background(), assembler.RefundCommand{PaymentID: paymentID})
+	require.Equal(t, "conflict", pkgErrors.FromError(err).Code)
+
+	// Nothing was collected from a payment that was never paid.
+	_, err = service.Refund(context.Background(), assembler.RefundCommand{PaymentID: pendingID, Amount: valueobject.AmountOf(100)})
+	require.Equal(t, "conflict", pkgErrors.FromError(err).Code)
+}
+
+func TestRefundBookingSpansSupplements(t *testing.T) {
+	bookingID, paymentID, supplementID, pendingID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+	repo := &paymentRepoStub{store: map[uuid.UUID]domain.Payment{
+		supplementID: {ID: supplementID, BookingID: bookingID, Kind: domain.KindSupplement, Amount: valueobject.AmountOf(100), Currency: "USD", Status: domain.StatusPaid, CreatedAt: now},
+		paymentID:    {ID: paymentID, BookingID: bookingID, Kind: domain.KindBooking, Amount: valueobject.AmountOf(300), Currency: "USD", Status: domain.StatusPaid, CreatedAt: now.Add(-time.Hour)},
+		pendingID:    {ID: pendingID, BookingID: bookingID, Kind: domain.KindSupplement, Amount: valueobject.AmountOf(50), Currency: "USD", Status: domain.StatusPending, CreatedAt: now.Add(time.Hour)},
+	}}
+	service := payment.NewService(repo, &providerStub{}, nil)
+
+	// More than the booking payment collected: the paid supplement covers the rest.
+	res, err := service.Refund(context.Background(), assembler.RefundCommand{BookingID: bookingID, Amount: valueobject.AmountOf(350), Reason: "cancelled"})
+	require.NoError(t, err)
+	require.Equal(t, valueobject.AmountOf(350), res.Amount)
+	require.Equal(t, paymentID, res.PaymentID)
+	require.Len(t, res.Parts, 2)
+	require.Equal(t, paymentID, res.Parts[0].PaymentID)
+	require.Equal(t, valueobject.AmountOf(300), res.Parts[0].Amount)
+	require.Equal(t, supplementID, res.Parts[1].PaymentID)
+	require.Equal(t, valueobject.AmountOf(50), res.Parts[1].Amount)
+
+	// The unpaid supplement collected nothing, so only 50 is left.
+	_, err = service.Refund(context.Background(), assembler.RefundCommand{BookingID: bookingID, Amount: valueobject.AmountOf(100)})
+	require.Equal(t, "bad_request", pkgErrors.FromError(err).Code)
+	res, err = service.Refund(context.Background(), assembler.RefundCommand{BookingID: bookingID})
+	require.NoError(t, err)
+	require.Equal(t, supplementID, res.PaymentID)
+	require.Equal(t, valueobject.AmountOf(50), res.Amount)
+	_, err = service.Refund(context.Background(), assembler.RefundCommand{BookingID: bookingID})
+	require.Equal(t, "conflict", pkgErrors.FromError(err).Code)
+
+	_, err = service.Refund(context.Background(), assembler.RefundCommand{BookingID: uuid.New()})
+	require.Equal(t, "not_found", pkgErrors.FromError(err).Code)
+}
+
+func TestExpire(t *testing.T) {
+	pendingID, paidID := uuid.New(), uuid.New()
+	repo := &paymentRepoStub{store: map[uuid.UUID]domain.Payment{
+		pendingID: {ID: pendingID, Status: string(valueobject.PaymentPending)},
+		paidID:    {ID: paidID, Status: string(valueobject.PaymentPaid)},
+	}}
+	service := payment.NewService(repo, &providerStub{}, nil)
+
+	pay, err := service.Expire(context.Background(), pendingID)
+	require.NoError(t, err)
+	require.Equal(t, string(valueobject.PaymentFailed), pay.Status)
+	require.Equal(t, string(valueobject.PaymentFailed), repo.store[pendingID].Status)
+
+	// Expiring again is a no-op.
+	_, err = service.Expire(context.Background(), pendingID)
+	require.NoError(t, err)
+
+	_, err = service.Expire(context.Background(), paidID)
+	require.Error(t, err)
+}
+
+func TestPaymentOwnership(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	bobsID := uuid.New()
+	repo := &paymentRepoStub{store: map[uuid.UUID]domain.Payment{
+		bobsID: {ID: bobsID, BookingID: uuid.New(), UserID: bob, Amount: valueobject.AmountOf(300), Status: string(valueobject.PaymentPaid)},
+	}}
+	auditLog := &auditLogStub{}
+	service := payment.NewService(repo, &providerStub{}, nil)
+	service.SetAuditLog(auditLog)
+
+	as := func(userID uuid.UUID, role valueobject.Role) context.Context {
+		claims := &middleware.Claims{UserID: userID.String(), Role: string(role)}
+		return context.WithValue(context.Background(), middleware.AuthContextKey, claims)
 	}
+	aliceCtx, adminCtx := as(alice, valueobject.RoleCustomer), as(uuid.New(), valueobject.RoleAdmin)
+	money, err := valueobject.NewMoney(valueobject.AmountOf(200), "IDR")
+	require.NoError(t, err)
+
+	own, err := service.Initiate(aliceCtx, assembler.InitiateCommand{BookingID: uuid.New(), Money: money})
+	require.NoError(t, err)
+	require.Equal(t, alice, own.UserID)
+	_, err = service.Initiate(aliceCtx, assembler.InitiateCommand{BookingID: uuid.New(), UserID: bob, Money: money})
+	require.Equal(t, "forbidden", pkgErrors.FromError(err).Code)
+
+	_, err = service.GetPayment(aliceCtx, bobsID)
+	require.Equal(t, "not_found", pkgErrors.FromError(err).Code)
+	_, err = service.Refund(aliceCtx, assembler.RefundCommand{PaymentID: bobsID})
+	require.Equal(t, "not_found", pkgErrors.FromError(err).Code)
+	own.Status = domain.StatusPaid
+	repo.store[own.ID] = own
+	_, err = service.Refund(aliceCtx, assembler.RefundCommand{PaymentID: own.ID, Amount: valueobject.AmountOf(50)})
+	require.NoError(t, err)
+	require.Empty(t, auditLog.entries)
+
+	_, err = service.Refund(adminCtx, assembler.RefundCommand{PaymentID: bobsID, Amount: valueobject.AmountOf(100)})
+	require.NoError(t, err)
+	require.Len(t, auditLog.entries, 1)
+	require.Equal(t, "payment.refund", auditLog.entries[0].Action)
+	require.Equal(t, bob, auditLog.entries[0].OwnerID)
 }
 
 // stubs
 
 type paymentRepoStub struct {
-	store map[uuid.UUID]domain.Payment
+	store   map[uuid.UUID]domain.Payment
+	refunds []domain.Refund
+}
+
+func (p *paymentRepoStub) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
 }
 
 func (p *paymentRepoStub) Create(ctx context.Context, pay domain.Payment) error {
@@ -107,15 +290,30 @@ func (p *paymentRepoStub) FindByID(ctx context.Context, id uuid.UUID) (domain.Pa
 	return pay, nil
 }
 
+func (p *paymentRepoStub) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (domain.Payment, error) {
+	return p.FindByID(ctx, id)
+}
+
 func (p *paymentRepoStub) FindByBookingID(ctx context.Context, bookingID uuid.UUID) (domain.Payment, error) {
 	for _, pay := range p.store {
-		if pay.BookingID == bookingID {
+		if pay.BookingID == bookingID && pay.Kind != domain.KindSupplement {
 			return pay, nil
 		}
 	}
 	return domain.Payment{}, errors.New("not found")
 }
 
+func (p *paymentRepoStub) ListByBookingForUpdate(ctx context.Context, bookingID uuid.UUID) ([]domain.Payment, error) {
+	var payments []domain.Payment
+	for _, pay := range p.store {
+		if pay.BookingID == bookingID {
+			payments = append(payments, pay)
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool { return payments[i].CreatedAt.Before(payments[j].CreatedAt) })
+	return payments, nil
+}
+
 func (p *paymentRepoStub) UpdateStatus(ctx context.Context, id uuid.UUID, status, paymentURL, rawPayload, signature string) error {
 	if pay, ok := p.store[id]; ok {
 		pay.Status = status
@@ -127,6 +325,31 @@ func (p *paymentRepoStub) UpdateStatus(ctx context.Context, id uuid.UUID, status
 	return errors.New("not found")
 }
 
+func (p *paymentRepoStub) CreateRefund(_ context.Context, r domain.Refund) error {
+	p.refunds = append(p.refunds, r)
+	return nil
+}
+
+func (p *paymentRepoStub) SaveRefund(_ context.Context, r domain.Refund) error {
+	for i, existing := range p.refunds {
+		if existing.ID == r.ID {
+			p.refunds[i] = r
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (p *paymentRepoStub) RefundedAmount(_ context.Context, paymentID uuid.UUID) (valueobject.Amount, error) {
+	var total valueobject.Amount
+	for _, r := range p.refunds {
+		if r.PaymentID == paymentID && r.Status != domain.RefundFailed {
+			total = total.Add(r.Amount)
+		}
+	}
+	return total, nil
+}
+
 func (p *paymentRepoStub) Initiate(context.Context, uuid.UUID, float64) (string, error) {
 	return "", nil
 }
@@ -134,6 +357,7 @@ func (p *paymentRepoStub) Initiate(context.Context, uuid.UUID, float64) (string,
 type providerStub struct {
 	signatureValid bool
 	refundErr      error
+	refundAmount   valueobject.Amount
 }
 
 func (p *providerStub) Initiate(ctx context.Context, payment domain.Payment) (domain.Payment, error) {
@@ -144,10 +368,20 @@ func (p *providerStub) VerifySignature(ctx context.Context, payload, signature s
 	return p.signatureValid
 }
 
-func (p *providerStub) Refund(ctx context.Context, payment domain.Payment, reason string) (string, error) {
+func (p *providerStub) Refund(ctx context.Context, payment domain.Payment, amount valueobject.Amount, reason string) (string, error) {
+	p.refundAmount = amount
 	return "ref", p.refundErr
 }
 
+type auditLogStub struct {
+	entries []audit.Entry
+}
+
+func (a *auditLogStub) Record(_ context.Context, e audit.Entry) error {
+	a.entries = append(a.entries, e)
+	return nil
+}
+
 type bookingUpdaterStub struct {
 	statuses []string
 }
@@ -0,0 +1,5 @@
+-- Speed up overlap lookups used by the booking inventory check
+-- Migration: 004_booking_inventory.sql
+
+CREATE INDEX IF NOT EXISTS idx_bookings_room_type_stay ON bookings(room_type_id, check_in, check_out)
+WHERE status <> 'cancelled';
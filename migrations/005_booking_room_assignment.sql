@@ -0,0 +1,7 @@
+-- Track the physical room assigned to a booking at check-in
+-- Migration: 005_booking_room_assignment.sql
+
+ALTER TABLE bookings
+ADD COLUMN IF NOT EXISTS room_id UUID REFERENCES rooms(id);
+
+CREATE INDEX IF NOT EXISTS idx_bookings_room ON bookings(room_id) WHERE room_id IS NOT NULL;
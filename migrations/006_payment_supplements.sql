@@ -0,0 +1,13 @@
+-- Allow supplementary payments next to the single booking payment
+-- Migration: 006_payment_supplements.sql
+
+ALTER TABLE payments
+ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'booking';
+
+-- Replace the booking_id uniqueness (column constraint or GORM unique index) with a partial one
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS payments_booking_id_key;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS uni_payments_booking_id;
+DROP INDEX IF EXISTS idx_payments_booking_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_booking_primary ON payments(booking_id) WHERE kind = 'booking';
+CREATE INDEX IF NOT EXISTS idx_payments_booking_id ON payments(booking_id);
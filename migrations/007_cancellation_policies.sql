@@ -0,0 +1,5 @@
+-- Cancellation policy per room type (free_cancellation, flexible, non_refundable)
+-- Migration: 007_cancellation_policies.sql
+
+ALTER TABLE room_types
+ADD COLUMN IF NOT EXISTS cancellation_policy TEXT NOT NULL DEFAULT 'flexible';
@@ -0,0 +1,7 @@
+-- Expire unpaid pending_payment holds
+-- Migration: 008_booking_hold_expiry.sql
+
+ALTER TABLE bookings
+ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_expires_at ON bookings(expires_at) WHERE status = 'pending_payment';
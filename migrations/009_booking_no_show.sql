@@ -0,0 +1,9 @@
+-- No-show bookings release their inventory like cancelled ones
+-- Migration: 009_booking_no_show.sql
+
+DROP INDEX IF EXISTS idx_bookings_room_type_stay;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_room_type_stay ON bookings(room_type_id, check_in, check_out)
+WHERE status NOT IN ('cancelled', 'no_show');
+
+CREATE INDEX IF NOT EXISTS idx_bookings_confirmed_check_in ON bookings(check_in) WHERE status = 'confirmed';
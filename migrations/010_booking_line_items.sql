@@ -0,0 +1,23 @@
+-- Group bookings: one line item per booked room
+-- Migration: 010_booking_line_items.sql
+
+CREATE TABLE IF NOT EXISTS booking_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    room_type_id UUID NOT NULL REFERENCES room_types(id),
+    position INT NOT NULL DEFAULT 0,
+    guests INT NOT NULL DEFAULT 1,
+    base_price NUMERIC NOT NULL DEFAULT 0,
+    guest_surcharge NUMERIC NOT NULL DEFAULT 0,
+    discount NUMERIC NOT NULL DEFAULT 0,
+    total NUMERIC NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'active'
+);
+CREATE INDEX IF NOT EXISTS idx_booking_items_booking ON booking_items(booking_id);
+CREATE INDEX IF NOT EXISTS idx_booking_items_room_type ON booking_items(room_type_id) WHERE status = 'active';
+
+-- Existing bookings become single-item bookings; the item reuses the booking id.
+INSERT INTO booking_items (id, booking_id, room_type_id, guests, base_price, total)
+SELECT b.id, b.id, b.room_type_id, b.guests, b.total_price, b.total_price
+FROM bookings b
+WHERE NOT EXISTS (SELECT 1 FROM booking_items i WHERE i.booking_id = b.id);
@@ -0,0 +1,19 @@
+-- Lead guest details, special requests and internal staff notes
+-- Migration: 011_booking_guest_details.sql
+
+ALTER TABLE bookings
+ADD COLUMN IF NOT EXISTS guest_name TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS guest_phone TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS guest_nationality TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS additional_guests TEXT,
+ADD COLUMN IF NOT EXISTS arrival_time TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS special_requests TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS booking_notes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    author_id UUID,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_booking_notes_booking ON booking_notes(booking_id, created_at);
@@ -0,0 +1,21 @@
+-- Transactional outbox for booking domain events
+-- Migration: 012_booking_outbox.sql
+
+CREATE TABLE IF NOT EXISTS booking_outbox (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE DEFAULT uuid_generate_v4(),
+    aggregate_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ
+);
+
+-- The relay scans due pending messages and checks for older pending messages of the same aggregate.
+CREATE INDEX IF NOT EXISTS idx_booking_outbox_due ON booking_outbox(next_attempt_at, seq) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_booking_outbox_aggregate ON booking_outbox(aggregate_id, seq) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_booking_outbox_status ON booking_outbox(status, seq);
@@ -0,0 +1,26 @@
+-- Append-only event store of booking changes
+-- Migration: 013_booking_events.sql
+
+CREATE TABLE IF NOT EXISTS booking_events (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE DEFAULT uuid_generate_v4(),
+    booking_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    actor_id TEXT NOT NULL DEFAULT '',
+    actor_role TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_booking_events_booking ON booking_events(booking_id, seq);
+
+-- History is never rewritten.
+CREATE OR REPLACE FUNCTION booking_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'booking_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_booking_events_append_only ON booking_events;
+CREATE TRIGGER trg_booking_events_append_only
+BEFORE UPDATE OR DELETE ON booking_events
+FOR EACH ROW EXECUTE FUNCTION booking_events_append_only();
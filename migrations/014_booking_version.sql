@@ -0,0 +1,5 @@
+-- Optimistic concurrency control for bookings
+-- Migration: 014_booking_version.sql
+
+ALTER TABLE bookings
+ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
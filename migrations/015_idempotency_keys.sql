@@ -0,0 +1,14 @@
+-- Idempotency-Key storage shared by booking and payment services
+-- Migration: 015_idempotency_keys.sql
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key TEXT PRIMARY KEY,               -- service:caller:client key
+    fingerprint TEXT NOT NULL,          -- sha256 of method, path and body
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    status_code INT,
+    content_type TEXT,
+    body BYTEA,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,27 @@
+-- Payment ownership and the audit log of admins acting on other users' bookings and payments
+-- Migration: 016_ownership_audit.sql
+
+ALTER TABLE payments
+ADD COLUMN IF NOT EXISTS user_id UUID;
+
+-- Existing payments belong to the owner of their booking
+UPDATE payments p
+SET user_id = b.user_id
+FROM bookings b
+WHERE p.booking_id = b.id AND p.user_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payments_user_id ON payments(user_id);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY,
+    actor_id TEXT NOT NULL,             -- admin user id, or the calling service
+    owner_id UUID NOT NULL,             -- user the resource belongs to
+    action TEXT NOT NULL,               -- e.g. booking.cancel, payment.refund
+    resource TEXT NOT NULL,
+    resource_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_owner_id ON audit_log (owner_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log (resource, resource_id);
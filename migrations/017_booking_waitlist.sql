@@ -0,0 +1,20 @@
+-- Waitlist for sold-out room types
+-- Migration: 017_booking_waitlist.sql
+
+CREATE TABLE IF NOT EXISTS booking_waitlist (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    room_type_id UUID NOT NULL,
+    check_in TIMESTAMPTZ NOT NULL,
+    check_out TIMESTAMPTZ NOT NULL,
+    guests INT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'waiting',
+    booking_id UUID REFERENCES bookings(id),
+    offer_expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- The offer sweep walks waiting entries oldest first.
+CREATE INDEX IF NOT EXISTS idx_booking_waitlist_waiting ON booking_waitlist(created_at, id) WHERE status = 'waiting';
+CREATE INDEX IF NOT EXISTS idx_booking_waitlist_user ON booking_waitlist(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_booking_waitlist_room_type ON booking_waitlist(room_type_id, status);
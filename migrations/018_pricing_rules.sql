@@ -0,0 +1,23 @@
+-- Rule-based pricing: rules per hotel or room type, and the nightly breakdown of each booked room
+-- Migration: 018_pricing_rules.sql
+
+CREATE TABLE IF NOT EXISTS pricing_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    hotel_id UUID NOT NULL REFERENCES hotels(id) ON DELETE CASCADE,
+    room_type_id UUID REFERENCES room_types(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    name TEXT NOT NULL,
+    start_date TIMESTAMPTZ,
+    end_date TIMESTAMPTZ,
+    weekdays TEXT NOT NULL DEFAULT '',
+    rate NUMERIC NOT NULL DEFAULT 0,
+    percent NUMERIC NOT NULL DEFAULT 0,
+    guests INT NOT NULL DEFAULT 0,
+    min_nights INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_pricing_rules_hotel ON pricing_rules(hotel_id, created_at DESC);
+
+-- JSON list of the nights a line item was priced from; empty for items booked before.
+ALTER TABLE booking_items
+ADD COLUMN IF NOT EXISTS nights TEXT NOT NULL DEFAULT '';
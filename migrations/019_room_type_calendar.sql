@@ -0,0 +1,13 @@
+-- Per-date rates and stay restrictions of room types
+-- Migration: 019_room_type_calendar.sql
+
+CREATE TABLE IF NOT EXISTS room_type_calendar (
+    room_type_id UUID NOT NULL REFERENCES room_types(id) ON DELETE CASCADE,
+    date TIMESTAMPTZ NOT NULL,
+    price NUMERIC NOT NULL DEFAULT 0,
+    closed_to_arrival BOOLEAN NOT NULL DEFAULT FALSE,
+    closed_to_departure BOOLEAN NOT NULL DEFAULT FALSE,
+    min_stay INT NOT NULL DEFAULT 0,
+    max_stay INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (room_type_id, date)
+);
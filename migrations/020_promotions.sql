@@ -0,0 +1,35 @@
+-- Promo codes: promotions, their redemptions and the discount each booked room received
+-- Migration: 020_promotions.sql
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code TEXT NOT NULL UNIQUE,
+    kind TEXT NOT NULL,
+    amount NUMERIC NOT NULL,
+    valid_from TIMESTAMPTZ,
+    valid_until TIMESTAMPTZ,
+    min_nights INT NOT NULL DEFAULT 0,
+    max_uses INT NOT NULL DEFAULT 0,
+    max_uses_per_user INT NOT NULL DEFAULT 0,
+    hotel_ids TEXT,
+    room_type_ids TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    promotion_id UUID NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    booking_id UUID NOT NULL REFERENCES bookings(id),
+    user_id UUID NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- Limits only count active redemptions.
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_active ON promotion_redemptions(promotion_id, user_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_booking ON promotion_redemptions(booking_id);
+
+ALTER TABLE bookings
+ADD COLUMN IF NOT EXISTS promo_code TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE booking_items
+ADD COLUMN IF NOT EXISTS promo NUMERIC NOT NULL DEFAULT 0;
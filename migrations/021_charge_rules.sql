@@ -0,0 +1,22 @@
+-- Taxes and fees per hotel, the charges levied on each booked room and the itemised payment description
+-- Migration: 021_charge_rules.sql
+
+CREATE TABLE IF NOT EXISTS charge_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    hotel_id UUID NOT NULL REFERENCES hotels(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,                 -- tax or fee
+    name TEXT NOT NULL,
+    basis TEXT NOT NULL,                -- percent or flat
+    amount NUMERIC NOT NULL,
+    per TEXT NOT NULL DEFAULT '',       -- flat charges: night or stay
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_charge_rules_hotel_id ON charge_rules(hotel_id);
+
+-- JSON list of the taxes and fees of the room; exclusive ones are included in total
+ALTER TABLE booking_items
+ADD COLUMN IF NOT EXISTS charges TEXT;
+
+ALTER TABLE payments
+ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,24 @@
+-- Multi-currency: a base currency per hotel, exchange rates and the rate snapshot each booking was made at
+-- Migration: 022_currencies.sql
+
+ALTER TABLE hotels
+ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'IDR';
+
+-- Latest rate of every currency pair: one unit of base is worth rate units of quote.
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    base TEXT NOT NULL,
+    quote TEXT NOT NULL,
+    rate NUMERIC NOT NULL CHECK (rate > 0),
+    as_of TIMESTAMPTZ NOT NULL,
+    source TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (base, quote)
+);
+
+-- Prices stay in the hotel's currency; the snapshot converts them into the guest's display currency.
+ALTER TABLE bookings
+ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'IDR',
+ADD COLUMN IF NOT EXISTS charge_currency TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS rate_currency TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS rate_as_of TIMESTAMPTZ,
+ADD COLUMN IF NOT EXISTS rate_source TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,37 @@
+-- Exact amounts: money columns hold four decimal places, the precision the services compute in
+-- Migration: 023_exact_amounts.sql
+--
+-- The columns were unconstrained NUMERIC written from floats, so they may carry binary noise such as
+-- 99099.0990990991. ROUND keeps every value within half a ten-thousandth of what was stored; amounts
+-- already rounded to their currency are left unchanged.
+
+ALTER TABLE room_types
+ALTER COLUMN base_price TYPE NUMERIC(19, 4) USING ROUND(base_price, 4);
+
+ALTER TABLE bookings
+ALTER COLUMN total_price TYPE NUMERIC(19, 4) USING ROUND(total_price, 4);
+
+ALTER TABLE booking_items
+ALTER COLUMN base_price TYPE NUMERIC(19, 4) USING ROUND(base_price, 4),
+ALTER COLUMN guest_surcharge TYPE NUMERIC(19, 4) USING ROUND(guest_surcharge, 4),
+ALTER COLUMN discount TYPE NUMERIC(19, 4) USING ROUND(discount, 4),
+ALTER COLUMN promo TYPE NUMERIC(19, 4) USING ROUND(promo, 4),
+ALTER COLUMN total TYPE NUMERIC(19, 4) USING ROUND(total, 4);
+
+ALTER TABLE payments
+ALTER COLUMN amount TYPE NUMERIC(19, 4) USING ROUND(amount, 4);
+
+ALTER TABLE refunds
+ALTER COLUMN amount TYPE NUMERIC(19, 4) USING ROUND(amount, 4);
+
+ALTER TABLE promotions
+ALTER COLUMN amount TYPE NUMERIC(19, 4) USING ROUND(amount, 4);
+
+ALTER TABLE pricing_rules
+ALTER COLUMN rate TYPE NUMERIC(19, 4) USING ROUND(rate, 4);
+
+ALTER TABLE charge_rules
+ALTER COLUMN amount TYPE NUMERIC(19, 4) USING ROUND(amount, 4);
+
+ALTER TABLE room_type_calendar
+ALTER COLUMN price TYPE NUMERIC(19, 4) USING ROUND(price, 4);
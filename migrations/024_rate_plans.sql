@@ -0,0 +1,23 @@
+-- Rate plans: the terms a room type is sold under, and the plan each booked room was sold on
+-- Migration: 024_rate_plans.sql
+
+CREATE TABLE IF NOT EXISTS rate_plans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    room_type_id UUID NOT NULL REFERENCES room_types(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    percent NUMERIC NOT NULL DEFAULT 0,             -- signed adjustment of the nightly rate
+    per_night NUMERIC(19, 4) NOT NULL DEFAULT 0,    -- added to the nightly rate, e.g. breakfast
+    inclusions TEXT NOT NULL DEFAULT '',            -- comma separated, e.g. breakfast
+    cancellation_policy TEXT NOT NULL,
+    payment_timing TEXT NOT NULL DEFAULT 'pay_now', -- pay_now or pay_at_hotel
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_rate_plans_room_type_id ON rate_plans(room_type_id);
+
+-- Bookings keep the terms of the plan they were made on, so plans may change or go away later
+ALTER TABLE booking_items
+ADD COLUMN IF NOT EXISTS rate_plan_id UUID,
+ADD COLUMN IF NOT EXISTS cancellation_policy TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE bookings
+ADD COLUMN IF NOT EXISTS payment_timing TEXT NOT NULL DEFAULT '';
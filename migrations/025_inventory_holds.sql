@@ -0,0 +1,21 @@
+-- Inventory holds: rooms kept aside for a stay while a guest checks out
+-- Migration: 025_inventory_holds.sql
+
+CREATE TABLE IF NOT EXISTS inventory_holds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    session_id TEXT NOT NULL DEFAULT '',
+    room_type_id UUID NOT NULL,
+    check_in TIMESTAMPTZ NOT NULL,
+    check_out TIMESTAMPTZ NOT NULL,
+    rooms INT NOT NULL DEFAULT 1,
+    status TEXT NOT NULL DEFAULT 'active', -- active, released, consumed or expired
+    booking_id UUID REFERENCES bookings(id),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Availability counts the active holds of a room type; the expiry sweep walks them by expiry.
+CREATE INDEX IF NOT EXISTS idx_inventory_holds_room_type ON inventory_holds(room_type_id, check_in) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_inventory_holds_expiry ON inventory_holds(expires_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_inventory_holds_user ON inventory_holds(user_id, created_at);
@@ -0,0 +1,7 @@
+-- Refund tracking: every refund is recorded so refunds never add up to more than was paid
+-- Migration: 026_refund_tracking.sql
+
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS reference TEXT;
+
+-- Refunds that failed at the provider no longer count against the payment.
+CREATE INDEX IF NOT EXISTS idx_refunds_payment_status ON refunds(payment_id, status);
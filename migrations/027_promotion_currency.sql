@@ -0,0 +1,8 @@
+-- Promotion currency: fixed discounts are an amount in a currency, and only apply to bookings priced in it
+-- Migration: 027_promotion_currency.sql
+
+ALTER TABLE promotions
+ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT '';
+
+-- Fixed codes created before had no currency; they were meant for the default one.
+UPDATE promotions SET currency = 'IDR' WHERE kind = 'fixed' AND currency = '';
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
+
+// Entry records an admin acting on a resource that belongs to another user.
+type Entry struct {
+	ID         uuid.UUID
+	ActorID    string    // admin user id, or the calling service for service tokens
+	OwnerID    uuid.UUID // user the resource belongs to
+	Action     string    // e.g. booking.cancel
+	Resource   string
+	ResourceID uuid.UUID
+	CreatedAt  time.Time
+}
+
+// Log persists audit entries.
+type Log interface {
+	Record(ctx context.Context, e Entry) error
+}
+
+// CanAccess reports whether the caller on ctx may see or change a resource owned by owner:
+// the owner, admins and other services may, any other user may not. Background jobs call
+// usecases without claims and act as the system.
+func CanAccess(ctx context.Context, owner uuid.UUID) bool {
+	claims, ok := middleware.ClaimsFrom(ctx)
+	if !ok || Privileged(claims) {
+		return true
+	}
+	return claims.UserID != "" && claims.UserID == owner.String()
+}
+
+// Owner resolves who a new resource belongs to. Customers always own what they create, so a
+// requested owner naming someone else is rejected; admins and services may create for any
+// user and default to themselves.
+func Owner(ctx context.Context, requested uuid.UUID) (uuid.UUID, bool) {
+	claims, ok := middleware.ClaimsFrom(ctx)
+	if !ok {
+		return requested, true
+	}
+	self, _ := uuid.Parse(claims.UserID)
+	if requested == uuid.Nil {
+		return self, true
+	}
+	if Privileged(claims) {
+		return requested, true
+	}
+	return self, requested == self
+}
+
+// Record writes an entry to log when the caller on ctx is an admin or a service acting on a resource
+// owned by someone else. Owners acting on their own resources and background jobs are not recorded.
+// A nil log records nothing.
+func Record(ctx context.Context, log Log, action, resource string, resourceID, owner uuid.UUID) error {
+	claims, ok := middleware.ClaimsFrom(ctx)
+	if log == nil || !ok || !Privileged(claims) || claims.UserID == owner.String() {
+		return nil
+	}
+	actor := claims.UserID
+	if actor == "" {
+		actor = claims.Subject
+	}
+	return log.Record(ctx, Entry{
+		ID:         uuid.New(),
+		ActorID:    actor,
+		OwnerID:    owner,
+		Action:     action,
+		Resource:   resource,
+		ResourceID: resourceID,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// Privileged reports whether claims act on behalf of other users: admins, and services calling
+// with service tokens.
+func Privileged(claims *middleware.Claims) bool {
+	return claims.Role == string(valueobject.RoleAdmin) || claims.Role == string(valueobject.RoleService)
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"log"
 	"os"
 	"strconv"
@@ -26,6 +29,16 @@ type Config struct {
 	XenditSuccessURL   string
 	XenditFailureURL   string
 	XenditInvoiceDuration time.Duration
+	BookingHoldDuration   time.Duration
+	NoShowChargeNights    int
+	OutboxMaxAttempts     int
+	OutboxRetryBaseDelay  time.Duration
+	OutboxRetryMaxDelay   time.Duration
+	IdempotencyKeyTTL     time.Duration
+	ExchangeRateURL       string
+	ExchangeRateInterval  time.Duration
+	QuoteSecret           string
+	QuoteTTL              time.Duration
 	SMTPHost           string
 	SMTPPort           int
 	SMTPUsername       string
@@ -63,6 +76,14 @@ func Load() Config {
 		XenditSuccessURL:   getEnv("XENDIT_SUCCESS_URL", ""),
 		XenditFailureURL:   getEnv("XENDIT_FAILURE_URL", ""),
 		XenditInvoiceDuration: durationEnv("XENDIT_INVOICE_DURATION", 15*time.Minute),
+		NoShowChargeNights: intEnv("NO_SHOW_CHARGE_NIGHTS", 0),
+		OutboxMaxAttempts:    intEnv("OUTBOX_MAX_ATTEMPTS", 10),
+		OutboxRetryBaseDelay: durationEnv("OUTBOX_RETRY_BASE_DELAY", time.Second),
+		OutboxRetryMaxDelay:  durationEnv("OUTBOX_RETRY_MAX_DELAY", time.Hour),
+		IdempotencyKeyTTL:    durationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		ExchangeRateURL:      getEnv("EXCHANGE_RATE_URL", ""),
+		ExchangeRateInterval: durationEnv("EXCHANGE_RATE_REFRESH_INTERVAL", time.Hour),
+		QuoteTTL:             durationEnv("QUOTE_TTL", 15*time.Minute),
 		SMTPHost:           getEnv("SMTP_HOST", ""),
 		SMTPPort:           intEnv("SMTP_PORT", 587),
 		SMTPUsername:       getEnv("SMTP_USERNAME", ""),
@@ -79,6 +100,11 @@ func Load() Config {
 		CircuitCooldown:    durationEnv("CIRCUIT_BREAKER_COOLDOWN", 15*time.Second),
 	}
 
+	// Unpaid bookings hold inventory as long as their invoice is payable unless overridden.
+	cfg.BookingHoldDuration = durationEnv("BOOKING_HOLD_DURATION", cfg.XenditInvoiceDuration)
+	// Quote tokens get their own key, derived from the JWT secret unless they are given one,
+	// so a quote token is never accepted as a bearer token.
+	cfg.QuoteSecret = getEnv("QUOTE_SECRET", deriveSecret(cfg.JWTSecret, "quote"))
 	if cfg.ServiceName == "" {
 		log.Println("SERVICE_NAME not provided; using hotel-service")
 	}
@@ -86,6 +112,14 @@ func Load() Config {
 	return cfg
 }
 
+// deriveSecret derives a key for purpose from secret, distinct from secret and from the keys of
+// other purposes.
+func deriveSecret(secret, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func getEnv(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/audit"
+)
+
+// AuditLog appends audit entries to postgres. Entries recorded inside WithinTransaction are
+// written in that transaction, so an audited change and its entry commit or roll back together.
+type AuditLog struct {
+	db *gorm.DB
+}
+
+func NewAuditLog(db *gorm.DB) *AuditLog {
+	return &AuditLog{db: db}
+}
+
+// AutoMigrate creates the audit_log table.
+func (l *AuditLog) AutoMigrate() error {
+	return l.db.AutoMigrate(&auditModel{})
+}
+
+func (l *AuditLog) Record(ctx context.Context, e audit.Entry) error {
+	model := auditModel(e)
+	return Conn(ctx, l.db).Create(&model).Error
+}
+
+type auditModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ActorID    string    `gorm:"index"`
+	OwnerID    uuid.UUID `gorm:"type:uuid;index"`
+	Action     string
+	Resource   string    `gorm:"index:idx_audit_log_resource"`
+	ResourceID uuid.UUID `gorm:"type:uuid;index:idx_audit_log_resource"`
+	CreatedAt  time.Time
+}
+
+func (auditModel) TableName() string { return "audit_log" }
@@ -0,0 +1,46 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/audit"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/database"
+)
+
+func TestAuditLogRecordsInsideTransaction(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	log := database.NewAuditLog(db)
+	require.NoError(t, log.AutoMigrate())
+	ctx := context.Background()
+
+	resourceID := uuid.New()
+	entry := func() audit.Entry {
+		return audit.Entry{ID: uuid.New(), ActorID: "admin", OwnerID: uuid.New(), Action: "booking.cancel", Resource: "booking", ResourceID: resourceID, CreatedAt: time.Now()}
+	}
+	count := func() int64 {
+		var n int64
+		require.NoError(t, db.Table("audit_log").Where("resource_id = ?", resourceID).Count(&n).Error)
+		return n
+	}
+
+	require.NoError(t, log.Record(ctx, entry()))
+	require.EqualValues(t, 1, count())
+
+	// An entry recorded in a rolled back transaction disappears with the change it audited.
+	rollback := errors.New("rollback")
+	err = database.WithinTransaction(ctx, db, func(ctx context.Context) error {
+		require.NoError(t, log.Record(ctx, entry()))
+		return rollback
+	})
+	require.ErrorIs(t, err, rollback)
+	require.EqualValues(t, 1, count())
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+)
+
+// idempotencyPurgeInterval is how often a store deletes expired keys.
+const idempotencyPurgeInterval = time.Minute
+
+// IdempotencyStore keeps Idempotency-Keys in postgres so every replica of a service sees them.
+type IdempotencyStore struct {
+	db *gorm.DB
+
+	mu         sync.Mutex
+	lastPurged time.Time
+}
+
+func NewIdempotencyStore(db *gorm.DB) *IdempotencyStore {
+	return &IdempotencyStore{db: db}
+}
+
+// AutoMigrate creates the idempotency_keys table.
+func (s *IdempotencyStore) AutoMigrate() error {
+	return s.db.AutoMigrate(&idempotencyModel{})
+}
+
+// Reserve inserts the key, or takes over an expired one, in a single statement each, so two
+// concurrent requests with the same key can never both win.
+func (s *IdempotencyStore) Reserve(ctx context.Context, rec middleware.IdempotencyRecord, now time.Time) (middleware.IdempotencyRecord, bool, error) {
+	s.purgeExpired(ctx, now)
+
+	model := toIdempotencyModel(rec)
+	res := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+	if res.Error != nil {
+		return middleware.IdempotencyRecord{}, false, res.Error
+	}
+	if res.RowsAffected == 1 {
+		return rec, true, nil
+	}
+
+	res = s.db.WithContext(ctx).Model(&idempotencyModel{}).
+		Where("key = ? AND expires_at <= ?", rec.Key, now).
+		Select("*").Updates(&model)
+	if res.Error != nil {
+		return middleware.IdempotencyRecord{}, false, res.Error
+	}
+	if res.RowsAffected == 1 {
+		return rec, true, nil
+	}
+
+	var stored idempotencyModel
+	if err := s.db.WithContext(ctx).First(&stored, "key = ?", rec.Key).Error; err != nil {
+		return middleware.IdempotencyRecord{}, false, err
+	}
+	return stored.toRecord(), false, nil
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, rec middleware.IdempotencyRecord) error {
+	return s.db.WithContext(ctx).Model(&idempotencyModel{}).Where("key = ?", rec.Key).Updates(map[string]any{
+		"completed":    true,
+		"status_code":  rec.StatusCode,
+		"content_type": rec.ContentType,
+		"body":         rec.Body,
+	}).Error
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Delete(&idempotencyModel{}, "key = ?", key).Error
+}
+
+// purgeExpired deletes keys whose TTL has passed, at most once per idempotencyPurgeInterval.
+// Expired keys are taken over by Reserve anyway; purging only keeps the table small.
+func (s *IdempotencyStore) purgeExpired(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastPurged) < idempotencyPurgeInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPurged = now
+	s.mu.Unlock()
+	_ = s.db.WithContext(ctx).Delete(&idempotencyModel{}, "expires_at <= ?", now).Error
+}
+
+type idempotencyModel struct {
+	Key         string `gorm:"primaryKey"`
+	Fingerprint string
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time `gorm:"index"`
+}
+
+func (idempotencyModel) TableName() string { return "idempotency_keys" }
+
+func toIdempotencyModel(rec middleware.IdempotencyRecord) idempotencyModel {
+	return idempotencyModel{
+		Key:         rec.Key,
+		Fingerprint: rec.Fingerprint,
+		Completed:   rec.Completed,
+		StatusCode:  rec.StatusCode,
+		ContentType: rec.ContentType,
+		Body:        rec.Body,
+		ExpiresAt:   rec.ExpiresAt,
+	}
+}
+
+func (m idempotencyModel) toRecord() middleware.IdempotencyRecord {
+	return middleware.IdempotencyRecord{
+		Key:         m.Key,
+		Fingerprint: m.Fingerprint,
+		Completed:   m.Completed,
+		StatusCode:  m.StatusCode,
+		ContentType: m.ContentType,
+		Body:        m.Body,
+		ExpiresAt:   m.ExpiresAt,
+	}
+}
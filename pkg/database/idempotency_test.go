@@ -0,0 +1,53 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/database"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+)
+
+func TestIdempotencyStoreReservesCompletesAndExpires(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	store := database.NewIdempotencyStore(db)
+	require.NoError(t, store.AutoMigrate())
+	ctx := context.Background()
+
+	now := time.Now()
+	rec := middleware.IdempotencyRecord{Key: "booking:alice:" + now.String(), Fingerprint: "first", ExpiresAt: now.Add(time.Hour)}
+	_, reserved, err := store.Reserve(ctx, rec, now)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	rec.Completed, rec.StatusCode, rec.ContentType, rec.Body = true, 201, "application/json", []byte(`{"id":"1"}`)
+	require.NoError(t, store.Complete(ctx, rec))
+
+	retry := middleware.IdempotencyRecord{Key: rec.Key, Fingerprint: "second", ExpiresAt: now.Add(time.Hour)}
+	stored, reserved, err := store.Reserve(ctx, retry, now)
+	require.NoError(t, err)
+	require.False(t, reserved)
+	require.Equal(t, "first", stored.Fingerprint)
+	require.True(t, stored.Completed)
+	require.Equal(t, 201, stored.StatusCode)
+	require.Equal(t, `{"id":"1"}`, string(stored.Body))
+
+	// Once the TTL has passed the key is free again.
+	later := now.Add(2 * time.Hour)
+	retry.ExpiresAt = later.Add(time.Hour)
+	stored, reserved, err = store.Reserve(ctx, retry, later)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.Equal(t, "second", stored.Fingerprint)
+
+	require.NoError(t, store.Release(ctx, rec.Key))
+	_, reserved, err = store.Reserve(ctx, rec, later)
+	require.NoError(t, err)
+	require.True(t, reserved)
+}
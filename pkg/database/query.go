@@ -0,0 +1,108 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+)
+
+// Listing maps the fields of a query.Schema onto the columns of one table.
+type Listing struct {
+	Columns     map[string]string // schema field -> column
+	DefaultSort query.Sort
+	DefaultSize int
+}
+
+// Paginate loads one page of models matching opts. Only columns named in the listing reach the SQL
+// text and every value is bound as a parameter, so query input cannot inject SQL. Pages after a
+// cursor use keyset pagination on (sort column, id), which stays fast however deep the client pages.
+func Paginate[M any](tx *gorm.DB, l Listing, opts query.Options) (query.Page[M], error) {
+	opts = opts.Normalize(l.DefaultSize)
+	tx = tx.Model(new(M))
+	for _, f := range opts.Filters {
+		col, ok := l.Columns[f.Field]
+		if !ok {
+			return query.Page[M]{}, errors.New("bad_request", "cannot filter by "+f.Field)
+		}
+		column := clause.Column{Table: clause.CurrentTable, Name: col}
+		switch f.Op {
+		case query.OpEq:
+			tx = tx.Where(clause.Eq{Column: column, Value: f.Value})
+		case query.OpGte:
+			tx = tx.Where(clause.Gte{Column: column, Value: f.Value})
+		case query.OpLte:
+			tx = tx.Where(clause.Lte{Column: column, Value: f.Value})
+		case query.OpContains:
+			s, _ := f.Value.(string)
+			tx = tx.Where(`LOWER(?) LIKE ? ESCAPE '\'`, column, "%"+escapeLike(strings.ToLower(s))+"%")
+		default:
+			return query.Page[M]{}, errors.New("bad_request", "unsupported filter operator")
+		}
+	}
+	tx = tx.Session(&gorm.Session{})
+
+	var page query.Page[M]
+	if err := tx.Count(&page.Total).Error; err != nil {
+		return query.Page[M]{}, err
+	}
+
+	s := opts.Sort
+	if s.Field == "" {
+		s = l.DefaultSort
+	}
+	col, ok := l.Columns[s.Field]
+	if !ok {
+		return query.Page[M]{}, errors.New("bad_request", "cannot sort by "+s.Field)
+	}
+	sortColumn := clause.Column{Table: clause.CurrentTable, Name: col}
+	idColumn := clause.Column{Table: clause.CurrentTable, Name: "id"}
+
+	find := tx
+	if after := opts.After; after != nil {
+		if after.Sort != s {
+			return query.Page[M]{}, errors.New("bad_request", "cursor does not match the requested sort")
+		}
+		cmp := ">"
+		if s.Desc {
+			cmp = "<"
+		}
+		find = find.Where("(? "+cmp+" ? OR (? = ? AND ? "+cmp+" ?))",
+			sortColumn, after.Value, sortColumn, after.Value, idColumn, after.ID)
+	} else {
+		find = find.Offset(opts.Offset)
+	}
+	var models []M
+	res := find.Clauses(clause.OrderBy{Columns: []clause.OrderByColumn{
+		{Column: sortColumn, Desc: s.Desc},
+		{Column: idColumn, Desc: s.Desc},
+	}}).Limit(opts.Limit + 1).Find(&models)
+	if res.Error != nil {
+		return query.Page[M]{}, res.Error
+	}
+
+	if len(models) > opts.Limit {
+		models = models[:opts.Limit]
+		last := reflect.ValueOf(&models[len(models)-1]).Elem()
+		value, _ := res.Statement.Schema.LookUpField(col).ValueOf(tx.Statement.Context, last)
+		id, _ := res.Statement.Schema.LookUpField("id").ValueOf(tx.Statement.Context, last)
+		page.NextCursor = query.EncodeCursor(s, value, toString(id))
+	}
+	page.Items = models
+	return page, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func toString(v any) string {
+	if s, ok := v.(interface{ String() string }); ok {
+		return s.String()
+	}
+	return reflect.ValueOf(v).String()
+}
@@ -0,0 +1,60 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/database"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/query"
+)
+
+type pagedModel struct {
+	ID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Group string
+	Rank  int
+}
+
+func TestPaginateOrdersAndFollowsCursor(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&pagedModel{}))
+
+	group := uuid.NewString()
+	for _, rank := range []int{3, 1, 4, 2} { // inserted out of order
+		require.NoError(t, db.Create(&pagedModel{ID: uuid.New(), Group: group, Rank: rank}).Error)
+	}
+	listing := database.Listing{
+		Columns:     map[string]string{"group": "group", "rank": "rank"},
+		DefaultSort: query.Sort{Field: "rank"},
+		DefaultSize: 2,
+	}
+	schema := query.Schema{Fields: map[string]query.Field{"rank": {Type: query.String, Sortable: true}}}
+	inGroup := []query.Filter{{Field: "group", Op: query.OpEq, Value: group}}
+	ranks := func(page query.Page[pagedModel]) []int {
+		var out []int
+		for _, m := range page.Items {
+			out = append(out, m.Rank)
+		}
+		return out
+	}
+
+	first, err := database.Paginate[pagedModel](db, listing, query.Options{Filters: inGroup})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, ranks(first))
+	require.EqualValues(t, 4, first.Total)
+
+	cursor, err := query.DecodeCursor(first.NextCursor, schema)
+	require.NoError(t, err)
+	second, err := database.Paginate[pagedModel](db, listing, query.Options{Filters: inGroup, Sort: cursor.Sort, After: &cursor})
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 4}, ranks(second))
+	require.Empty(t, second.NextCursor)
+
+	desc, err := database.Paginate[pagedModel](db, listing, query.Options{Filters: inGroup, Sort: query.Sort{Field: "rank", Desc: true}})
+	require.NoError(t, err)
+	require.Equal(t, []int{4, 3}, ranks(desc))
+}
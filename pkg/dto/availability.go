@@ -0,0 +1,32 @@
+package dto
+
+import "github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+
+// AvailabilityResponse describes a room type that can host the requested stay.
+type AvailabilityResponse struct {
+	RoomTypeID  string                      `json:"room_type_id"`
+	HotelID     string                      `json:"hotel_id"`
+	Name        string                      `json:"name"`
+	Capacity    int                         `json:"capacity"`
+	RoomsLeft   int                         `json:"rooms_left"`
+	TotalNights int                         `json:"total_nights"`
+	QuotedTotal valueobject.Amount          `json:"quoted_total"` // at the room type's own terms
+	RatePlans   []RatePlanQuoteResponse     `json:"rate_plans,omitempty"`
+	Nights      []NightAvailabilityResponse `json:"nights"`
+}
+
+// RatePlanQuoteResponse prices the stay under one rate plan of the room type.
+type RatePlanQuoteResponse struct {
+	RatePlanID         string             `json:"rate_plan_id"`
+	Name               string             `json:"name"`
+	Inclusions         []string           `json:"inclusions,omitempty"`
+	CancellationPolicy string             `json:"cancellation_policy"`
+	PaymentTiming      string             `json:"payment_timing"`
+	QuotedTotal        valueobject.Amount `json:"quoted_total"`
+}
+
+// NightAvailabilityResponse shows free rooms for a single night (calendar view).
+type NightAvailabilityResponse struct {
+	Date      Date `json:"date"`
+	Available int  `json:"available"`
+}
@@ -1,9 +1,12 @@
 package dto
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 // Date allows YYYY-MM-DD or RFC3339 in JSON and stores as time.Time.
@@ -36,24 +39,160 @@ func (d Date) MarshalJSON() ([]byte, error) {
 }
 
 // BookingRequest is used for booking creation.
+// A single room is booked with room_type_id and guests; a group books several rooms through items.
 type BookingRequest struct {
-	UserID     string `json:"user_id"`
+	UserID     string               `json:"user_id,omitempty"` // admins only: book on behalf of this user
+	RoomTypeID string               `json:"room_type_id,omitempty"`
+	RatePlanID string               `json:"rate_plan_id,omitempty"` // book the room type under one of its rate plans
+	CheckIn    Date                 `json:"check_in"`
+	CheckOut   Date                 `json:"check_out"`
+	Guests     int                  `json:"guests,omitempty"`
+	Items      []BookingItemRequest `json:"items,omitempty"`
+
+	Guest           *GuestDetails `json:"guest,omitempty"`
+	ArrivalTime     string        `json:"arrival_time,omitempty"`
+	SpecialRequests string        `json:"special_requests,omitempty"`
+	PromoCode       string        `json:"promo_code,omitempty"`
+
+	DisplayCurrency string `json:"display_currency,omitempty"` // show prices in this currency, the charge currency by default
+	ChargeCurrency  string `json:"charge_currency,omitempty"`  // pay in the hotel's currency (default) or the display currency
+
+	QuoteToken string `json:"quote_token,omitempty"` // from POST /quotes: keep the quoted price while it is valid
+	HoldID     string `json:"hold_id,omitempty"`     // from POST /holds: book the rooms the hold keeps aside
+}
+
+// GuestDetails describes the lead guest and the names of everyone travelling with them.
+type GuestDetails struct {
+	Name             string   `json:"name"`
+	Phone            string   `json:"phone"`
+	Nationality      string   `json:"nationality,omitempty"`
+	AdditionalGuests []string `json:"additional_guests,omitempty"`
+}
+
+// BookingItemRequest books one room of a group booking.
+type BookingItemRequest struct {
 	RoomTypeID string `json:"room_type_id"`
-	CheckIn    Date   `json:"check_in"`
-	CheckOut   Date   `json:"check_out"`
+	RatePlanID string `json:"rate_plan_id,omitempty"`
 	Guests     int    `json:"guests"`
 }
 
 // BookingResponse returns booking info.
 type BookingResponse struct {
-	ID          string           `json:"id"`
-	Status      string           `json:"status"`
-	Guests      int              `json:"guests"`
-	TotalNights int              `json:"total_nights"`
-	TotalPrice  float64          `json:"total_price"`
-	CheckIn     time.Time        `json:"check_in"`
-	CheckOut    time.Time        `json:"check_out"`
-	Payment     *PaymentResponse `json:"payment,omitempty"`
+	ID              string                `json:"id"`
+	Status          string                `json:"status"`
+	RoomID          string                `json:"room_id,omitempty"`
+	Guests          int                   `json:"guests"`
+	TotalNights     int                   `json:"total_nights"`
+	TotalPrice      valueobject.Amount    `json:"total_price"`
+	CheckIn         time.Time             `json:"check_in"`
+	CheckOut        time.Time             `json:"check_out"`
+	Items           []BookingItemResponse `json:"items,omitempty"`
+	Guest           *GuestDetails         `json:"guest,omitempty"`
+	ArrivalTime     string                `json:"arrival_time,omitempty"`
+	SpecialRequests string                `json:"special_requests,omitempty"`
+	PromoCode       string                `json:"promo_code,omitempty"`
+	PaymentTiming   string                `json:"payment_timing"`  // pay_now or pay_at_hotel
+	Lines           []PriceLine           `json:"lines,omitempty"` // itemised total_price
+	Currency        string                `json:"currency"`        // currency of the hotel, which every price above is in
+	Display         *DisplayPrice         `json:"display,omitempty"`
+	ChargeCurrency  string                `json:"charge_currency"`
+	ChargeTotal     valueobject.Amount    `json:"charge_total"` // total_price in the charge currency
+	Payment         *PaymentResponse      `json:"payment,omitempty"`
+	Version         int                   `json:"version"`
+}
+
+// QuoteResponse is the price POST /bookings charges for the same request, kept by passing token as
+// quote_token until expires_at.
+type QuoteResponse struct {
+	Token          string                `json:"token"`
+	ExpiresAt      time.Time             `json:"expires_at"`
+	CheckIn        time.Time             `json:"check_in"`
+	CheckOut       time.Time             `json:"check_out"`
+	Guests         int                   `json:"guests"`
+	TotalNights    int                   `json:"total_nights"`
+	TotalPrice     valueobject.Amount    `json:"total_price"`
+	Items          []BookingItemResponse `json:"items,omitempty"`
+	PromoCode      string                `json:"promo_code,omitempty"`
+	PaymentTiming  string                `json:"payment_timing"`  // pay_now or pay_at_hotel
+	Lines          []PriceLine           `json:"lines,omitempty"` // itemised total_price
+	Currency       string                `json:"currency"`
+	Display        *DisplayPrice         `json:"display,omitempty"`
+	ChargeCurrency string                `json:"charge_currency"`
+	ChargeTotal    valueobject.Amount    `json:"charge_total"`
+}
+
+// DisplayPrice shows a booking's total in the currency the guest asked for, at the rate snapshot
+// taken when booking.
+type DisplayPrice struct {
+	Currency     string               `json:"currency"`
+	TotalPrice   valueobject.Amount   `json:"total_price"`
+	Lines        []PriceLine          `json:"lines,omitempty"`
+	ExchangeRate ExchangeRateResponse `json:"exchange_rate"`
+}
+
+// ExchangeRatesRequest uploads the rates of several currencies quoted against one base currency.
+type ExchangeRatesRequest struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"` // units of each currency one unit of base is worth
+	AsOf  *time.Time         `json:"as_of,omitempty"`
+}
+
+// ExchangeRateResponse returns one rate: one unit of base is worth rate units of quote.
+type ExchangeRateResponse struct {
+	Base   string    `json:"base"`
+	Quote  string    `json:"quote"`
+	Rate   float64   `json:"rate"`
+	AsOf   time.Time `json:"as_of"`
+	Source string    `json:"source,omitempty"`
+}
+
+// BookingItemResponse returns one room of a booking with its price breakdown.
+type BookingItemResponse struct {
+	ID                 string                 `json:"id,omitempty"` // empty on quotes
+	RoomTypeID         string                 `json:"room_type_id"`
+	RatePlanID         string                 `json:"rate_plan_id,omitempty"`
+	CancellationPolicy string                 `json:"cancellation_policy,omitempty"` // of the rate plan when booked
+	Guests             int                    `json:"guests"`
+	Status             string                 `json:"status"`
+	Price              PriceBreakdownResponse `json:"price"`
+}
+
+// PriceBreakdownResponse itemises how a room price was reached.
+type PriceBreakdownResponse struct {
+	Base           valueobject.Amount   `json:"base"`
+	GuestSurcharge valueobject.Amount   `json:"guest_surcharge"`
+	Discount       valueobject.Amount   `json:"discount"`
+	Promo          valueobject.Amount   `json:"promo"`
+	Charges        []ChargeResponse     `json:"charges,omitempty"`
+	Total          valueobject.Amount   `json:"total"`
+	Nights         []NightPriceResponse `json:"nights,omitempty"`
+}
+
+// ChargeResponse is a tax or fee levied on a room. Inclusive charges are part of the room price.
+type ChargeResponse struct {
+	Kind      string             `json:"kind"`
+	Name      string             `json:"name"`
+	Amount    valueobject.Amount `json:"amount"`
+	Inclusive bool               `json:"inclusive,omitempty"`
+}
+
+// PriceLine is one line of an itemised price: room, surcharge, discount, promo, tax or fee.
+// Discounts are negative; included lines are already part of the room price and are not added up.
+type PriceLine struct {
+	Type     string             `json:"type"`
+	Name     string             `json:"name"`
+	Amount   valueobject.Amount `json:"amount"`
+	Included bool               `json:"included,omitempty"`
+}
+
+// NightPriceResponse is the price of one night and the pricing rules behind it.
+type NightPriceResponse struct {
+	Date      Date               `json:"date"`
+	Rate      valueobject.Amount `json:"rate"`
+	Surcharge valueobject.Amount `json:"surcharge"`
+	Discount  valueobject.Amount `json:"discount"`
+	Total     valueobject.Amount `json:"total"`
+	Rules     []string           `json:"rules,omitempty"`
 }
 
 // BookingAggregateResponse merges booking+payment.
@@ -62,7 +201,150 @@ type BookingAggregateResponse struct {
 	Payment PaymentResponse `json:"payment"`
 }
 
+// ModifyBookingRequest changes the dates and/or guest count of a booking.
+type ModifyBookingRequest struct {
+	CheckIn  Date `json:"check_in"`
+	CheckOut Date `json:"check_out"`
+	Guests   int  `json:"guests"`
+}
+
+// BookingModificationResponse returns the modified booking and the price difference.
+// A positive delta comes with a supplementary payment; a negative one was refunded.
+type BookingModificationResponse struct {
+	BookingResponse
+	PriceDelta valueobject.Amount `json:"price_delta"`
+}
+
+// BookingCancellationResponse returns the cancelled booking and the refund granted by its policy.
+type BookingCancellationResponse struct {
+	BookingResponse
+	CancellationPolicy string             `json:"cancellation_policy"`
+	RefundAmount       valueobject.Amount `json:"refund_amount"`
+}
+
+// BookingNoteRequest adds an internal staff note to a booking.
+type BookingNoteRequest struct {
+	Body string `json:"body"`
+}
+
+// BookingNoteResponse returns a staff note; notes are only visible to staff.
+type BookingNoteResponse struct {
+	ID        string    `json:"id"`
+	BookingID string    `json:"booking_id"`
+	AuthorID  string    `json:"author_id,omitempty"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BookingHistoryEntryResponse is one event in a booking's timeline.
+type BookingHistoryEntryResponse struct {
+	ID         string          `json:"id"`
+	BookingID  string          `json:"booking_id"`
+	EventType  string          `json:"event_type"`
+	ActorID    string          `json:"actor_id"`
+	ActorRole  string          `json:"actor_role,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// OutboxMessageResponse exposes a booking event waiting in, or delivered from, the outbox.
+type OutboxMessageResponse struct {
+	ID            string          `json:"id"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+}
+
 // CheckpointRequest handles lifecycle updates.
 type CheckpointRequest struct {
 	Action string `json:"action"`
+	RoomID string `json:"room_id,omitempty"`
+}
+
+// WaitlistRequest joins the waitlist of a sold-out room type for a stay.
+type WaitlistRequest struct {
+	UserID     string `json:"user_id,omitempty"` // admins only: join on behalf of this user
+	RoomTypeID string `json:"room_type_id"`
+	CheckIn    Date   `json:"check_in"`
+	CheckOut   Date   `json:"check_out"`
+	Guests     int    `json:"guests,omitempty"`
+}
+
+// WaitlistResponse returns a waitlist entry. Offered entries name the booking holding a room for the guest.
+type WaitlistResponse struct {
+	ID             string     `json:"id"`
+	UserID         string     `json:"user_id"`
+	RoomTypeID     string     `json:"room_type_id"`
+	CheckIn        time.Time  `json:"check_in"`
+	CheckOut       time.Time  `json:"check_out"`
+	Guests         int        `json:"guests"`
+	Status         string     `json:"status"`
+	BookingID      string     `json:"booking_id,omitempty"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// HoldRequest keeps rooms of a room type aside for a stay while the guest checks out.
+type HoldRequest struct {
+	UserID     string `json:"user_id,omitempty"`    // admins only: hold on behalf of this user
+	SessionID  string `json:"session_id,omitempty"` // a new hold replaces the earlier hold of the same checkout session
+	RoomTypeID string `json:"room_type_id"`
+	CheckIn    Date   `json:"check_in"`
+	CheckOut   Date   `json:"check_out"`
+	Rooms      int    `json:"rooms,omitempty"`   // default 1
+	Minutes    int    `json:"minutes,omitempty"` // default 10, at most 30
+}
+
+// HoldResponse returns an inventory hold. Consumed holds name the booking made from them.
+type HoldResponse struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	SessionID  string    `json:"session_id,omitempty"`
+	RoomTypeID string    `json:"room_type_id"`
+	CheckIn    time.Time `json:"check_in"`
+	CheckOut   time.Time `json:"check_out"`
+	Rooms      int       `json:"rooms"`
+	Status     string    `json:"status"` // active, released, consumed or expired
+	BookingID  string    `json:"booking_id,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// PromotionRequest creates or replaces a promo code. The validity window bounds when bookings may
+// use the code; limits of 0 and empty hotel or room type lists leave the code unrestricted.
+type PromotionRequest struct {
+	Code           string             `json:"code"`
+	Kind           string             `json:"kind"` // percent or fixed
+	Amount         valueobject.Amount `json:"amount"`
+	Currency       string             `json:"currency,omitempty"` // of a fixed amount, IDR by default; ignored for percent
+	ValidFrom      *time.Time         `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time         `json:"valid_until,omitempty"`
+	MinNights      int                `json:"min_nights,omitempty"`
+	MaxUses        int                `json:"max_uses,omitempty"`
+	MaxUsesPerUser int                `json:"max_uses_per_user,omitempty"`
+	HotelIDs       []string           `json:"hotel_ids,omitempty"`
+	RoomTypeIDs    []string           `json:"room_type_ids,omitempty"`
+}
+
+// PromotionResponse returns a promo code.
+type PromotionResponse struct {
+	ID             string             `json:"id"`
+	Code           string             `json:"code"`
+	Kind           string             `json:"kind"`
+	Amount         valueobject.Amount `json:"amount"`
+	Currency       string             `json:"currency,omitempty"`
+	ValidFrom      *time.Time         `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time         `json:"valid_until,omitempty"`
+	MinNights      int                `json:"min_nights"`
+	MaxUses        int                `json:"max_uses"`
+	MaxUsesPerUser int                `json:"max_uses_per_user"`
+	HotelIDs       []string           `json:"hotel_ids,omitempty"`
+	RoomTypeIDs    []string           `json:"room_type_ids,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
 }
@@ -1,31 +1,38 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+)
 
 // HotelRequest defines admin input.
 type HotelRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Address     string `json:"address"`
+	Currency    string `json:"currency,omitempty"` // base currency of the hotel's prices, IDR by default
 }
 
 // RoomTypeRequest configures hotel room types.
 type RoomTypeRequest struct {
-	HotelID   string  `json:"hotel_id"`
-	Name      string  `json:"name"`
-	Capacity  int     `json:"capacity"`
-	BasePrice float64 `json:"base_price"`
-	Amenities string  `json:"amenities"`
+	HotelID            string             `json:"hotel_id"`
+	Name               string             `json:"name"`
+	Capacity           int                `json:"capacity"`
+	BasePrice          valueobject.Amount `json:"base_price"`
+	Amenities          string             `json:"amenities"`
+	CancellationPolicy string             `json:"cancellation_policy,omitempty"` // free_cancellation, flexible (default) or non_refundable
 }
 
 // RoomTypeResponse exposes room type details.
 type RoomTypeResponse struct {
-	ID        string  `json:"id"`
-	HotelID   string  `json:"hotel_id"`
-	Name      string  `json:"name"`
-	Capacity  int     `json:"capacity"`
-	BasePrice float64 `json:"base_price"`
-	Amenities string  `json:"amenities"`
+	ID                 string             `json:"id"`
+	HotelID            string             `json:"hotel_id"`
+	Name               string             `json:"name"`
+	Capacity           int                `json:"capacity"`
+	BasePrice          valueobject.Amount `json:"base_price"`
+	Amenities          string             `json:"amenities"`
+	CancellationPolicy string             `json:"cancellation_policy"`
 }
 
 // RoomRequest describes a physical room.
@@ -49,16 +56,17 @@ type HotelResponse struct {
 	Name        string            `json:"name"`
 	Description string            `json:"description"`
 	Address     string            `json:"address"`
+	Currency    string            `json:"currency"`
 	CreatedAt   time.Time         `json:"created_at"`
 	RoomTypes   []RoomTypeSummary `json:"room_types"`
 }
 
 // RoomTypeSummary short view.
 type RoomTypeSummary struct {
-	ID       string  `json:"id"`
-	Name     string  `json:"name"`
-	Capacity int     `json:"capacity"`
-	Price    float64 `json:"price"`
+	ID       string             `json:"id"`
+	Name     string             `json:"name"`
+	Capacity int                `json:"capacity"`
+	Price    valueobject.Amount `json:"price"`
 }
 
 // HotelUpdateRequest for updating hotel details.
@@ -66,6 +74,7 @@ type HotelUpdateRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Address     string `json:"address"`
+	Currency    string `json:"currency,omitempty"` // omitted keeps the current currency
 }
 
 // RoomUpdateRequest for updating room details.
@@ -73,3 +82,109 @@ type RoomUpdateRequest struct {
 	Number string `json:"number,omitempty"`
 	Status string `json:"status,omitempty"`
 }
+
+// PricingRuleRequest configures a pricing rule for a hotel, or for one room type when room_type_id is set.
+// Fields a kind does not use are ignored.
+type PricingRuleRequest struct {
+	HotelID    string             `json:"hotel_id"`
+	RoomTypeID string             `json:"room_type_id,omitempty"`
+	Kind       string             `json:"kind"` // season, day_of_week, occupancy or length_of_stay
+	Name       string             `json:"name"`
+	StartDate  Date               `json:"start_date,omitempty"` // season: first night covered
+	EndDate    Date               `json:"end_date,omitempty"`   // season: first night no longer covered
+	Days       []string           `json:"days,omitempty"`       // day_of_week: e.g. ["fri", "sat"]
+	Rate       valueobject.Amount `json:"rate,omitempty"`       // season: fixed nightly rate instead of percent
+	Percent    float64            `json:"percent,omitempty"`    // signed: 20 adds 20%, -10 takes 10% off
+	Guests     int                `json:"guests,omitempty"`     // occupancy: guests included in the rate
+	MinNights  int                `json:"min_nights,omitempty"` // length_of_stay
+}
+
+// PricingRuleResponse exposes a pricing rule.
+type PricingRuleResponse struct {
+	ID         string             `json:"id"`
+	HotelID    string             `json:"hotel_id"`
+	RoomTypeID string             `json:"room_type_id,omitempty"`
+	Kind       string             `json:"kind"`
+	Name       string             `json:"name"`
+	StartDate  Date               `json:"start_date,omitempty"`
+	EndDate    Date               `json:"end_date,omitempty"`
+	Days       []string           `json:"days,omitempty"`
+	Rate       valueobject.Amount `json:"rate,omitempty"`
+	Percent    float64            `json:"percent,omitempty"`
+	Guests     int                `json:"guests,omitempty"`
+	MinNights  int                `json:"min_nights,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// ChargeRuleRequest configures a tax or fee charged on every room of a hotel.
+type ChargeRuleRequest struct {
+	HotelID   string             `json:"hotel_id"`
+	Kind      string             `json:"kind"`          // tax or fee
+	Name      string             `json:"name"`          // e.g. "PB1" or "Service charge"
+	Basis     string             `json:"basis"`         // percent or flat
+	Amount    valueobject.Amount `json:"amount"`        // percentage, or flat amount per room
+	Per       string             `json:"per,omitempty"` // flat: night or stay
+	Inclusive bool               `json:"inclusive"`     // already part of the room price
+}
+
+// ChargeRuleResponse exposes a charge rule.
+type ChargeRuleResponse struct {
+	ID        string             `json:"id"`
+	HotelID   string             `json:"hotel_id"`
+	Kind      string             `json:"kind"`
+	Name      string             `json:"name"`
+	Basis     string             `json:"basis"`
+	Amount    valueobject.Amount `json:"amount"`
+	Per       string             `json:"per,omitempty"`
+	Inclusive bool               `json:"inclusive"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// RatePlanRequest configures terms a room type is sold under. The nightly rate is adjusted by
+// percent, then per_night is added to it.
+type RatePlanRequest struct {
+	RoomTypeID         string             `json:"room_type_id"`
+	Name               string             `json:"name"`                          // e.g. "Non-refundable" or "Bed and breakfast"
+	Percent            float64            `json:"percent,omitempty"`             // signed: -10 takes 10% off the nightly rate
+	PerNight           valueobject.Amount `json:"per_night,omitempty"`           // added to the nightly rate, e.g. breakfast
+	Inclusions         []string           `json:"inclusions,omitempty"`          // e.g. ["breakfast"]
+	CancellationPolicy string             `json:"cancellation_policy,omitempty"` // the room type's policy by default
+	PaymentTiming      string             `json:"payment_timing,omitempty"`      // pay_now (default) or pay_at_hotel
+}
+
+// RatePlanResponse exposes a rate plan.
+type RatePlanResponse struct {
+	ID                 string             `json:"id"`
+	RoomTypeID         string             `json:"room_type_id"`
+	Name               string             `json:"name"`
+	Percent            float64            `json:"percent,omitempty"`
+	PerNight           valueobject.Amount `json:"per_night"`
+	Inclusions         []string           `json:"inclusions,omitempty"`
+	CancellationPolicy string             `json:"cancellation_policy"`
+	PaymentTiming      string             `json:"payment_timing"`
+	CreatedAt          time.Time          `json:"created_at"`
+}
+
+// CalendarUpdateRequest changes the calendar of a room type for every date from start_date up to
+// end_date, optionally only on some weekdays. Omitted fields keep their current value.
+type CalendarUpdateRequest struct {
+	StartDate         Date                `json:"start_date"`
+	EndDate           Date                `json:"end_date"`        // first date not updated
+	Days              []string            `json:"days,omitempty"`  // e.g. ["fri", "sat"]; every day when empty
+	Price             *valueobject.Amount `json:"price,omitempty"` // 0 falls back to the room type base price
+	ClosedToArrival   *bool               `json:"closed_to_arrival,omitempty"`
+	ClosedToDeparture *bool               `json:"closed_to_departure,omitempty"`
+	MinStay           *int                `json:"min_stay,omitempty"` // 0 removes the minimum
+	MaxStay           *int                `json:"max_stay,omitempty"` // 0 removes the maximum
+}
+
+// CalendarDayResponse shows the rate and restrictions of a room type on one date.
+type CalendarDayResponse struct {
+	Date              Date               `json:"date"`
+	Price             valueobject.Amount `json:"price"`
+	PriceOverride     bool               `json:"price_override"` // false when price is the room type base price
+	ClosedToArrival   bool               `json:"closed_to_arrival"`
+	ClosedToDeparture bool               `json:"closed_to_departure"`
+	MinStay           int                `json:"min_stay,omitempty"`
+	MaxStay           int                `json:"max_stay,omitempty"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+
 // CreatedHotelResponse represents payload after creating a hotel.
 type CreatedHotelResponse struct {
 	ID          string `json:"id"`
@@ -11,13 +13,13 @@ type CreatedHotelResponse struct {
 
 // CreatedRoomTypeResponse represents payload after creating a room type.
 type CreatedRoomTypeResponse struct {
-	ID        string  `json:"id"`
-	HotelID   string  `json:"hotel_id"`
-	Name      string  `json:"name"`
-	Capacity  int     `json:"capacity"`
-	BasePrice float64 `json:"base_price"`
-	Amenities string  `json:"amenities"`
-	Message   string  `json:"message"`
+	ID        string             `json:"id"`
+	HotelID   string             `json:"hotel_id"`
+	Name      string             `json:"name"`
+	Capacity  int                `json:"capacity"`
+	BasePrice valueobject.Amount `json:"base_price"`
+	Amenities string             `json:"amenities"`
+	Message   string             `json:"message"`
 }
 
 // CreatedRoomResponse represents payload after creating a room.
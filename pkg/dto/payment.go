@@ -1,18 +1,26 @@
 package dto
 
+import "github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
+
 // PaymentRequest triggers payment provider.
 type PaymentRequest struct {
-	BookingID string  `json:"booking_id"`
-	Amount    float64 `json:"amount"`
-	Currency  string  `json:"currency"`
+	BookingID string             `json:"booking_id"`
+	UserID    string             `json:"user_id,omitempty"` // admins only: pay on behalf of this user
+	Amount    valueobject.Amount `json:"amount"`
+	Currency  string             `json:"currency"`
+	Kind      string             `json:"kind,omitempty"`  // booking (default) or supplement
+	Lines     []PriceLine        `json:"lines,omitempty"` // itemised amount, shown in the payment description
 }
 
 // PaymentResponse describes created payment.
 type PaymentResponse struct {
-	ID         string `json:"id"`
-	Status     string `json:"status"`
-	Provider   string `json:"provider"`
-	PaymentURL string `json:"payment_url"`
+	ID          string             `json:"id"`
+	Kind        string             `json:"kind,omitempty"`
+	Amount      valueobject.Amount `json:"amount"`
+	Status      string             `json:"status"`
+	Provider    string             `json:"provider"`
+	PaymentURL  string             `json:"payment_url"`
+	Description string             `json:"description,omitempty"`
 }
 
 // WebhookRequest is provider callback payload.
@@ -24,14 +32,18 @@ type WebhookRequest struct {
 
 // RefundRequest triggers refunds.
 type RefundRequest struct {
-	PaymentID string  `json:"payment_id"`
-	Amount    float64 `json:"amount"`
-	Reason    string  `json:"reason"`
+	PaymentID string             `json:"payment_id,omitempty"`
+	BookingID string             `json:"booking_id,omitempty"` // instead of payment_id: refund across the booking's payments
+	Amount    valueobject.Amount `json:"amount"`
+	Currency  string             `json:"currency,omitempty"` // currency of amount, the payment's when omitted
+	Reason    string             `json:"reason"`
 }
 
 // RefundResponse describes refund status.
 type RefundResponse struct {
-	ID        string `json:"id"`
-	Status    string `json:"status"`
-	Reference string `json:"reference"`
+	ID        string             `json:"id"`
+	Amount    valueobject.Amount `json:"amount"`
+	Status    string             `json:"status"`
+	Reference string             `json:"reference"`
+	Payments  []RefundResponse   `json:"payments,omitempty"` // per payment, when a booking refund spans several
 }
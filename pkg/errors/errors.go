@@ -40,6 +40,10 @@ func StatusCode(err APIError) int {
 		return http.StatusNotFound
 	case "conflict":
 		return http.StatusConflict
+	case "precondition_failed":
+		return http.StatusPreconditionFailed
+	case "unprocessable_entity":
+		return http.StatusUnprocessableEntity
 	case "bad_gateway", "upstream_error":
 		return http.StatusBadGateway
 	default:
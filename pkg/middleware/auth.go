@@ -10,6 +10,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+	"github.com/ftryyln/hotel-booking-microservices/pkg/valueobject"
 )
 
 type contextKey string
@@ -44,7 +45,8 @@ func JWT(secret string, roles ...string) func(http.Handler) http.Handler {
 			token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 				return []byte(secret), nil
 			})
-			if err != nil || !token.Valid {
+			// Bearer tokens carry no audience; tokens minted for something else, like quotes, do.
+			if err != nil || !token.Valid || len(claims.Audience) > 0 {
 				writeError(w, errors.New("unauthorized", "invalid token"))
 				return
 			}
@@ -63,6 +65,32 @@ func JWT(secret string, roles ...string) func(http.Handler) http.Handler {
 	}
 }
 
+// ClaimsFrom returns the claims JWT attached to ctx.
+func ClaimsFrom(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(AuthContextKey).(*Claims)
+	return claims, ok
+}
+
+// WithServiceToken attaches a short-lived service token to ctx so background jobs, which have
+// no inbound request to borrow a token from, can call other authenticated services. The token
+// carries the service role, which routes must allow explicitly.
+func WithServiceToken(ctx context.Context, secret, service string) (context.Context, error) {
+	now := time.Now()
+	claims := Claims{
+		Role: string(valueobject.RoleService),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   service,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, AuthTokenKey, token), nil
+}
+
 func extractToken(r *http.Request) string {
 	auth := r.Header.Get("Authorization")
 	parts := strings.Split(auth, " ")
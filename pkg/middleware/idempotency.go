@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// IdempotencyKeyHeader carries the client chosen key that makes a retried request safe.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const idempotencyKeyCtx contextKey = "idempotency_key"
+
+// IdempotencyRecord is a stored Idempotency-Key with the request it was first used for and its response.
+type IdempotencyRecord struct {
+	Key         string // scoped key: service, caller and the client's key
+	Fingerprint string // hash of method, path and body of the first request
+	Completed   bool   // false while the first request is still being handled
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+// IdempotencyStore persists idempotency keys.
+type IdempotencyStore interface {
+	// Reserve stores rec unless an unexpired record with the same key exists.
+	// It returns the stored record and whether rec was the one stored.
+	Reserve(ctx context.Context, rec IdempotencyRecord, now time.Time) (IdempotencyRecord, bool, error)
+	// Complete attaches the response to a reserved key.
+	Complete(ctx context.Context, rec IdempotencyRecord) error
+	// Release drops a reserved key so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// Idempotency replays the stored response when a request is retried with the same Idempotency-Key
+// and body. A key reused with a different body, or while the first request is still running, is
+// rejected. Server errors release the key so the client can retry; keys expire after ttl.
+// Reads and requests without the header pass through untouched. Mount it after JWT so keys are scoped per caller.
+func Idempotency(store IdempotencyStore, scope string, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || r.Method == http.MethodGet || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > 255 {
+				writeError(w, errors.New("bad_request", "Idempotency-Key is too long"))
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeError(w, errors.New("bad_request", "invalid payload"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			now := time.Now()
+			rec := IdempotencyRecord{
+				Key:         scope + ":" + caller(r) + ":" + key,
+				Fingerprint: fingerprint(r, body),
+				ExpiresAt:   now.Add(ttl),
+			}
+			stored, reserved, err := store.Reserve(r.Context(), rec, now)
+			if err != nil {
+				writeError(w, errors.New("internal_error", "idempotency store unavailable"))
+				return
+			}
+			if !reserved {
+				switch {
+				case stored.Fingerprint != rec.Fingerprint:
+					writeError(w, errors.New("unprocessable_entity", "Idempotency-Key was already used for a different request"))
+				case !stored.Completed:
+					writeError(w, errors.New("conflict", "a request with this Idempotency-Key is still in progress"))
+				default:
+					if stored.ContentType != "" {
+						w.Header().Set("Content-Type", stored.ContentType)
+					}
+					w.Header().Set("Idempotent-Replayed", "true")
+					w.WriteHeader(stored.StatusCode)
+					_, _ = w.Write(stored.Body)
+				}
+				return
+			}
+
+			rw := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rw, r)
+
+			// The response is already sent; a failed write below only costs the replay.
+			ctx := context.WithoutCancel(r.Context())
+			if rw.status >= http.StatusInternalServerError {
+				_ = store.Release(ctx, rec.Key)
+				return
+			}
+			rec.Completed = true
+			rec.StatusCode = rw.status
+			rec.ContentType = rw.Header().Get("Content-Type")
+			rec.Body = rw.body.Bytes()
+			_ = store.Complete(ctx, rec)
+		})
+	}
+}
+
+// WithIdempotencyKey attaches a key for outgoing service calls to send as Idempotency-Key.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx, key)
+}
+
+// IdempotencyKeyFrom returns the key attached with WithIdempotencyKey.
+func IdempotencyKeyFrom(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyCtx).(string)
+	return key, ok && key != ""
+}
+
+// caller identifies who sent the request so two callers never share a key.
+func caller(r *http.Request) string {
+	claims, ok := r.Context().Value(AuthContextKey).(*Claims)
+	switch {
+	case !ok:
+		return "anonymous"
+	case claims.UserID != "":
+		return claims.UserID
+	default:
+		return claims.Subject
+	}
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter copies the response so it can be stored for replays.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// MemoryIdempotencyStore keeps keys in process memory; suitable for tests and single instances.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: map[string]IdempotencyRecord{}}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(_ context.Context, rec IdempotencyRecord, now time.Time) (IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stored, ok := s.records[rec.Key]; ok && stored.ExpiresAt.After(now) {
+		return stored, false, nil
+	}
+	s.records[rec.Key] = rec
+	return rec, true, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, rec IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[rec.Key] = rec
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
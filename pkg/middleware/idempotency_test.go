@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/middleware"
+)
+
+func TestIdempotencyReplaysAndRejectsReuse(t *testing.T) {
+	calls := 0
+	failNext := false
+	handler := middleware.Idempotency(middleware.NewMemoryIdempotencyStore(), "booking", time.Hour)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if failNext {
+				failNext = false
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"` + time.Now().String() + `"}`))
+		}))
+
+	serve := func(key, userID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/bookings", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(middleware.IdempotencyKeyHeader, key)
+		}
+		claims := &middleware.Claims{UserID: userID}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.AuthContextKey, claims))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := serve("k1", "alice", `{"guests":2}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	replay := serve("k1", "alice", `{"guests":2}`)
+	require.Equal(t, http.StatusCreated, replay.Code)
+	require.Equal(t, first.Body.String(), replay.Body.String())
+	require.Equal(t, "true", replay.Header().Get("Idempotent-Replayed"))
+	require.Equal(t, 1, calls)
+
+	// Same key with another body, or from another caller.
+	require.Equal(t, http.StatusUnprocessableEntity, serve("k1", "alice", `{"guests":3}`).Code)
+	require.Equal(t, http.StatusCreated, serve("k1", "bob", `{"guests":3}`).Code)
+	require.Equal(t, 2, calls)
+
+	// Server errors are not stored, so the client may retry.
+	failNext = true
+	require.Equal(t, http.StatusBadGateway, serve("k2", "alice", `{}`).Code)
+	require.Equal(t, http.StatusCreated, serve("k2", "alice", `{}`).Code)
+	require.Equal(t, 4, calls)
+
+	// Without a key every request runs.
+	serve("", "alice", `{}`)
+	serve("", "alice", `{}`)
+	require.Equal(t, 6, calls)
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// Cursor marks the last item of a page: its sort value and id. Clients only see it as an opaque token.
+type Cursor struct {
+	Sort  Sort
+	Value any // typed like the sort field
+	ID    string
+}
+
+type cursorToken struct {
+	Field string `json:"f"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// EncodeCursor builds the token for the page following the item with the given sort value and id.
+func EncodeCursor(s Sort, value any, id string) string {
+	var v string
+	switch val := value.(type) {
+	case time.Time:
+		v = val.UTC().Format(time.RFC3339Nano)
+	default:
+		v = fmt.Sprint(val)
+	}
+	raw, _ := json.Marshal(cursorToken{Field: s.Field, Desc: s.Desc, Value: v, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor reads a token produced by EncodeCursor, checking its field against the schema.
+func DecodeCursor(token string, schema Schema) (Cursor, error) {
+	invalid := errors.New("bad_request", "invalid cursor")
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, invalid
+	}
+	var t cursorToken
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return Cursor{}, invalid
+	}
+	field, ok := schema.Fields[t.Field]
+	if !ok || !field.Sortable || t.ID == "" {
+		return Cursor{}, invalid
+	}
+	value, err := parseValue(field.Type, t.Value)
+	if err != nil {
+		return Cursor{}, invalid
+	}
+	return Cursor{Sort: Sort{Field: t.Field, Desc: t.Desc}, Value: value, ID: t.ID}, nil
+}
@@ -0,0 +1,138 @@
+package query
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// Op is the comparison a Filter applies.
+type Op string
+
+const (
+	OpEq       Op = "eq"
+	OpGte      Op = "gte"
+	OpLte      Op = "lte"
+	OpContains Op = "contains" // case-insensitive substring match
+)
+
+// FieldType tells Parse how to read a value.
+type FieldType int
+
+const (
+	String FieldType = iota
+	UUID
+	Time // RFC 3339 timestamp or YYYY-MM-DD date
+)
+
+// Field is a filterable or sortable attribute of a list.
+type Field struct {
+	Type     FieldType
+	Ops      []Op // filter operators allowed on the field; none makes it sort-only
+	Sortable bool
+}
+
+// Schema is the allow-list of fields a list endpoint accepts. Anything else is rejected
+// before it can reach a repository.
+type Schema struct {
+	Fields      map[string]Field
+	DefaultSort Sort
+}
+
+// Filter restricts a list to items whose Field compares to Value with Op.
+// Value holds the parsed type: string, uuid.UUID or time.Time.
+type Filter struct {
+	Field string
+	Op    Op
+	Value any
+}
+
+// Sort orders a list by one field; repositories break ties by id.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Parse reads limit, offset, sort, cursor and filters from query parameters.
+// Filters are written field=value for equality and field[op]=value otherwise, e.g.
+// status=confirmed&check_in[gte]=2025-12-01&sort=-created_at. Parameters naming no field of the
+// schema are left to the handler.
+func Parse(values url.Values, schema Schema) (Options, error) {
+	limit, _ := strconv.Atoi(values.Get("limit"))
+	offset, _ := strconv.Atoi(values.Get("offset"))
+	opts := Options{Limit: limit, Offset: offset, Sort: schema.DefaultSort}
+
+	if raw := values.Get("sort"); raw != "" {
+		s := Sort{Field: strings.TrimPrefix(raw, "-"), Desc: strings.HasPrefix(raw, "-")}
+		if f, ok := schema.Fields[s.Field]; !ok || !f.Sortable {
+			return Options{}, errors.New("bad_request", "cannot sort by "+s.Field)
+		}
+		opts.Sort = s
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name, op := key, OpEq
+		if i := strings.IndexByte(key, '['); i > 0 && strings.HasSuffix(key, "]") {
+			name, op = key[:i], Op(key[i+1:len(key)-1])
+		}
+		field, ok := schema.Fields[name]
+		if !ok {
+			continue
+		}
+		if !field.allows(op) {
+			return Options{}, errors.New("bad_request", "cannot filter "+name+" with "+string(op))
+		}
+		value, err := parseValue(field.Type, values.Get(key))
+		if err != nil {
+			return Options{}, errors.New("bad_request", "invalid value for "+name)
+		}
+		opts.Filters = append(opts.Filters, Filter{Field: name, Op: op, Value: value})
+	}
+
+	if token := values.Get("cursor"); token != "" {
+		cursor, err := DecodeCursor(token, schema)
+		if err != nil {
+			return Options{}, err
+		}
+		if cursor.Sort != opts.Sort {
+			return Options{}, errors.New("bad_request", "cursor does not match the requested sort")
+		}
+		opts.After = &cursor
+		opts.Offset = 0
+	}
+	return opts, nil
+}
+
+func (f Field) allows(op Op) bool {
+	for _, allowed := range f.Ops {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+func parseValue(typ FieldType, raw string) (any, error) {
+	switch typ {
+	case UUID:
+		return uuid.Parse(raw)
+	case Time:
+		if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+			return t, nil
+		}
+		return time.Parse("2006-01-02", raw)
+	default:
+		return raw, nil
+	}
+}
@@ -0,0 +1,68 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+var testSchema = Schema{
+	Fields: map[string]Field{
+		"status":     {Type: String, Ops: []Op{OpEq}},
+		"check_in":   {Type: Time, Ops: []Op{OpGte, OpLte}, Sortable: true},
+		"created_at": {Type: Time, Sortable: true},
+	},
+	DefaultSort: Sort{Field: "created_at", Desc: true},
+}
+
+func TestParseFiltersAndSort(t *testing.T) {
+	values, _ := url.ParseQuery("status=confirmed&check_in[gte]=2025-12-01&sort=check_in&limit=5&page=2")
+	opts, err := Parse(values, testSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.Limit != 5 || opts.Sort != (Sort{Field: "check_in"}) {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+	if len(opts.Filters) != 2 {
+		t.Fatalf("expected 2 filters, got %+v", opts.Filters)
+	}
+	in := opts.Filters[0]
+	if in.Field != "check_in" || in.Op != OpGte || !in.Value.(time.Time).Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected check_in filter %+v", in)
+	}
+}
+
+func TestParseRejectsFieldsOutsideSchema(t *testing.T) {
+	for _, raw := range []string{
+		"sort=password",
+		"sort=status",
+		"status[gte]=a",
+		"check_in[gte]=yesterday",
+		"cursor=not-a-cursor",
+	} {
+		values, _ := url.ParseQuery(raw)
+		if _, err := Parse(values, testSchema); err == nil {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2025, 12, 1, 10, 30, 0, 0, time.UTC)
+	token := EncodeCursor(Sort{Field: "check_in"}, at, "abc")
+
+	values := url.Values{"sort": {"check_in"}, "cursor": {token}, "offset": {"20"}}
+	opts, err := Parse(values, testSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.After == nil || opts.After.ID != "abc" || !opts.After.Value.(time.Time).Equal(at) || opts.Offset != 0 {
+		t.Fatalf("unexpected cursor %+v", opts)
+	}
+
+	values.Set("sort", "-check_in")
+	if _, err := Parse(values, testSchema); err == nil {
+		t.Fatal("expected cursor for another sort to be rejected")
+	}
+}
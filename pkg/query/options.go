@@ -1,9 +1,12 @@
 package query
 
-// Options defines basic pagination/filter inputs.
+// Options defines pagination, filter and sort inputs.
 type Options struct {
-	Limit  int
-	Offset int
+	Limit   int
+	Offset  int // ignored when After is set
+	Filters []Filter
+	Sort    Sort    // zero value means the list's default order
+	After   *Cursor // continue after this item instead of paging by offset
 }
 
 // Normalize applies sensible defaults and guards negatives.
@@ -19,3 +22,10 @@ func (o Options) Normalize(defaultLimit int) Options {
 	}
 	return o
 }
+
+// Page is one page of a list with the cursor of the next page and the number of matching items.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string // empty on the last page
+	Total      int64
+}
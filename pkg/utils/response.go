@@ -15,9 +15,11 @@ type Envelope struct {
 
 // Meta carries response metadata.
 type Meta struct {
-	Message   string `json:"message,omitempty"`
-	RequestID string `json:"requestId"`
-	Count     int    `json:"count,omitempty"`
+	Message    string `json:"message,omitempty"`
+	RequestID  string `json:"requestId"`
+	Count      int    `json:"count,omitempty"`
+	Total      int64  `json:"total,omitempty"`       // items matching the filters across all pages
+	NextCursor string `json:"next_cursor,omitempty"` // empty on the last page
 }
 
 // Resource represents a single resource item.
@@ -72,6 +74,23 @@ func RespondWithCount(w http.ResponseWriter, status int, message string, data an
 	_ = json.NewEncoder(w).Encode(env)
 }
 
+// RespondWithPage writes a list envelope with the total count and the cursor of the next page.
+func RespondWithPage(w http.ResponseWriter, status int, message string, data any, count int, total int64, nextCursor string) {
+	env := Envelope{
+		Data: data,
+		Meta: Meta{
+			Message:    message,
+			RequestID:  requestIDFrom(message),
+			Count:      count,
+			Total:      total,
+			NextCursor: nextCursor,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(env)
+}
+
 // requestIDFrom generates a UUID; message used only to vary seed (timestamp added).
 func requestIDFrom(_ string) string {
 	return uuid.New().String()
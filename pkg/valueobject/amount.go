@@ -0,0 +1,226 @@
+package valueobject
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// amountScale is the number of ten-thousandths in one unit of a currency. Four decimal places hold
+// the minor units of every currency plus the precision of a percentage taken before rounding.
+const (
+	amountDecimals = 4
+	amountScale    = 10000
+)
+
+// Amount is an exact decimal amount of money with four decimal places. Unlike float64 it adds up
+// exactly; multiplications and divisions round half away from zero, and Round brings a result to
+// the minor unit of its currency.
+type Amount struct {
+	units int64 // ten-thousandths
+}
+
+// AmountOf returns the amount nearest to f. Floats only enter at the edges, e.g. in tests.
+func AmountOf(f float64) Amount {
+	return Amount{units: int64(math.Round(f * amountScale))}
+}
+
+// ParseAmount parses a decimal number such as "1226500" or "76.66", rounding beyond four decimals.
+func ParseAmount(s string) (Amount, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Amount{}, pkgErrors.New("bad_request", "invalid amount")
+	}
+	units := roundRat(r.Mul(r, big.NewRat(amountScale, 1)))
+	if !units.IsInt64() {
+		return Amount{}, pkgErrors.New("bad_request", "amount out of range")
+	}
+	return Amount{units: units.Int64()}, nil
+}
+
+func (a Amount) Add(b Amount) Amount { return Amount{units: a.units + b.units} }
+func (a Amount) Sub(b Amount) Amount { return Amount{units: a.units - b.units} }
+func (a Amount) Neg() Amount         { return Amount{units: -a.units} }
+func (a Amount) Mul(n int) Amount    { return Amount{units: a.units * int64(n)} }
+
+// MulDiv returns a * num / den.
+func (a Amount) MulDiv(num, den int64) Amount {
+	return a.mulRat(big.NewRat(num, den))
+}
+
+// Scale returns a * num / den, e.g. the share of a percentage given as an Amount.
+func (a Amount) Scale(num, den Amount) Amount {
+	if den.IsZero() {
+		return Amount{}
+	}
+	return a.MulDiv(num.units, den.units)
+}
+
+// Percent returns p percent of a; p is taken to four decimals.
+func (a Amount) Percent(p float64) Amount {
+	return a.MulDiv(int64(math.Round(p*amountScale)), 100*amountScale)
+}
+
+// MulRate returns a multiplied by a factor such as an exchange rate.
+func (a Amount) MulRate(f float64) Amount {
+	r := new(big.Rat)
+	if r.SetFloat64(f) == nil {
+		return Amount{}
+	}
+	return a.mulRat(r)
+}
+
+func (a Amount) mulRat(r *big.Rat) Amount {
+	units := roundRat(new(big.Rat).Mul(new(big.Rat).SetInt64(a.units), r))
+	return Amount{units: units.Int64()}
+}
+
+// Round rounds a to the minor unit of currency, half away from zero.
+func (a Amount) Round(currency string) Amount {
+	step := int64(math.Pow10(amountDecimals - CurrencyDigits(currency)))
+	return Amount{units: roundRat(big.NewRat(a.units, step)).Int64() * step}
+}
+
+// Cmp compares a and b, returning -1, 0 or +1.
+func (a Amount) Cmp(b Amount) int {
+	switch {
+	case a.units < b.units:
+		return -1
+	case a.units > b.units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (a Amount) IsZero() bool     { return a.units == 0 }
+func (a Amount) IsNegative() bool { return a.units < 0 }
+func (a Amount) IsPositive() bool { return a.units > 0 }
+
+// MinAmount returns the smaller of a and b.
+func MinAmount(a, b Amount) Amount {
+	if a.units < b.units {
+		return a
+	}
+	return b
+}
+
+// Float64 returns the nearest float, for providers and logs that need one.
+func (a Amount) Float64() float64 {
+	return float64(a.units) / amountScale
+}
+
+// String formats a with as few decimals as it needs, e.g. "1226500" or "76.66".
+func (a Amount) String() string {
+	return strings.TrimSuffix(strings.TrimRight(a.StringFixed(amountDecimals), "0"), ".")
+}
+
+// StringFixed formats a with exactly digits decimals, rounding if a has more.
+func (a Amount) StringFixed(digits int) string {
+	units := a.units
+	if digits < amountDecimals {
+		step := int64(math.Pow10(amountDecimals - digits))
+		units = roundRat(big.NewRat(units, step)).Int64() * step
+	}
+	sign := ""
+	if units < 0 {
+		sign, units = "-", -units
+	}
+	whole := strconv.FormatInt(units/amountScale, 10)
+	if digits <= 0 {
+		return sign + whole
+	}
+	frac := fmt.Sprintf("%0*d", amountDecimals, units%amountScale)
+	for len(frac) < digits {
+		frac += "0"
+	}
+	return sign + whole + "." + frac[:digits]
+}
+
+// MarshalJSON writes a as a JSON number, so clients keep reading plain numbers.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number, or a number in a string, without going through float64.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		*a = Amount{}
+		return nil
+	}
+	parsed, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value stores a as an exact decimal string in numeric columns.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan reads a numeric column.
+func (a *Amount) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*a = Amount{}
+		return nil
+	case int64:
+		*a = Amount{units: v * amountScale}
+		return nil
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("cannot scan %T into Amount", src)
+	}
+	parsed, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// roundRat rounds r to an integer, half away from zero.
+func roundRat(r *big.Rat) *big.Int {
+	num, den := new(big.Int).Set(r.Num()), r.Denom()
+	neg := num.Sign() < 0
+	num.Abs(num)
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if neg {
+		q.Neg(q)
+	}
+	return q
+}
+
+// currencyDigits lists the currencies whose minor unit is not a hundredth. Rupiah are charged in
+// whole units, the way payment providers settle them.
+var currencyDigits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IDR": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyDigits returns the number of decimals amounts of currency are rounded to.
+func CurrencyDigits(currency string) int {
+	if digits, ok := currencyDigits[currency]; ok {
+		return digits
+	}
+	return 2
+}
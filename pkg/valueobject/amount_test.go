@@ -0,0 +1,102 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1226500", "1226500"},
+		{"76.66", "76.66"},
+		{" -0.5 ", "-0.5"},
+		{"1.23456", "1.2346"},
+		{"1e3", "1000"},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in)
+		if err != nil || got.String() != tt.want {
+			t.Fatalf("ParseAmount(%q): expected %s, got %v %v", tt.in, tt.want, got, err)
+		}
+	}
+	if _, err := ParseAmount("ten"); err == nil {
+		t.Fatalf("expected error for a non-number")
+	}
+}
+
+func TestAmountRound(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     string
+	}{
+		{1226500.5, "IDR", "1226501"},
+		{-2.5, "IDR", "-3"},
+		{76.665, "USD", "76.67"},
+		{76.664, "USD", "76.66"},
+		{1.2345, "KWD", "1.235"},
+		{100.5, "JPY", "101"},
+	}
+	for _, tt := range tests {
+		if got := AmountOf(tt.amount).Round(tt.currency); got.String() != tt.want {
+			t.Fatalf("%v %s: expected %s, got %s", tt.amount, tt.currency, tt.want, got)
+		}
+	}
+}
+
+func TestAmountMath(t *testing.T) {
+	if got := AmountOf(300).MulDiv(2, 3); got != AmountOf(200) {
+		t.Fatalf("expected 200, got %s", got)
+	}
+	if got := AmountOf(100).MulDiv(1, 3); got.String() != "33.3333" {
+		t.Fatalf("expected 33.3333, got %s", got)
+	}
+	if got := AmountOf(1000).Percent(-12.5); got != AmountOf(-125) {
+		t.Fatalf("expected -125, got %s", got)
+	}
+	if got := AmountOf(1100).Scale(AmountOf(10), AmountOf(110)); got != AmountOf(100) {
+		t.Fatalf("expected 100, got %s", got)
+	}
+	if got := AmountOf(76.6).StringFixed(2); got != "76.60" {
+		t.Fatalf("expected 76.60, got %s", got)
+	}
+	if got := AmountOf(76.666).StringFixed(0); got != "77" {
+		t.Fatalf("expected 77, got %s", got)
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	var v struct {
+		Number Amount `json:"number"`
+		Text   Amount `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(`{"number": 0.1, "text": "1226500.25"}`), &v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v.Number != AmountOf(0.1) || v.Text != AmountOf(1226500.25) {
+		t.Fatalf("unexpected amounts %s %s", v.Number, v.Text)
+	}
+	out, _ := json.Marshal(v)
+	if string(out) != `{"number":0.1,"text":1226500.25}` {
+		t.Fatalf("unexpected json %s", out)
+	}
+}
+
+func TestAmountScan(t *testing.T) {
+	for _, src := range []any{int64(12), float64(12), []byte("12.0000"), "12"} {
+		var a Amount
+		if err := a.Scan(src); err != nil || a != AmountOf(12) {
+			t.Fatalf("scan %T: expected 12, got %s %v", src, a, err)
+		}
+	}
+	var a Amount
+	if err := a.Scan(nil); err != nil || !a.IsZero() {
+		t.Fatalf("expected zero from NULL, got %s %v", a, err)
+	}
+	if v, _ := AmountOf(76.66).Value(); v != "76.66" {
+		t.Fatalf("expected exact decimal string, got %v", v)
+	}
+}
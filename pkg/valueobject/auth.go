@@ -21,9 +21,10 @@ type Role string
 const (
 	RoleCustomer Role = "customer"
 	RoleAdmin    Role = "admin"
+	RoleService  Role = "service" // service tokens only; users cannot register with it
 )
 
-// ParseRole validates and returns a normalized role.
+// ParseRole validates and returns a normalized user role.
 func ParseRole(raw string) (Role, error) {
 	role := strings.ToLower(strings.TrimSpace(raw))
 	if role == "" {
@@ -10,4 +10,7 @@ func TestValidateRole(t *testing.T) {
 	if _, err := ParseRole("bad"); err == nil {
 		t.Fatalf("expected error for invalid role")
 	}
+	if _, err := ParseRole("service"); err == nil {
+		t.Fatalf("expected error for service role")
+	}
 }
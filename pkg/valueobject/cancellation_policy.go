@@ -0,0 +1,60 @@
+package valueobject
+
+import (
+	"strings"
+	"time"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// Cancellation policy codes configurable on room types.
+const (
+	PolicyFreeCancellation = "free_cancellation"
+	PolicyFlexible         = "flexible"
+	PolicyNonRefundable    = "non_refundable"
+)
+
+// CancellationPolicy decides how much of a paid stay is refunded on cancellation.
+type CancellationPolicy struct {
+	Code          string
+	FreeUntil     time.Duration // free cancellation until this long before check-in
+	PenaltyNights int           // nights charged when cancelling inside the free window
+	NonRefundable bool
+}
+
+var cancellationPolicies = map[string]CancellationPolicy{
+	PolicyFreeCancellation: {Code: PolicyFreeCancellation},
+	PolicyFlexible:         {Code: PolicyFlexible, FreeUntil: 48 * time.Hour, PenaltyNights: 1},
+	PolicyNonRefundable:    {Code: PolicyNonRefundable, NonRefundable: true},
+}
+
+// DefaultCancellationPolicy applies to room types without an explicit policy.
+func DefaultCancellationPolicy() CancellationPolicy {
+	return cancellationPolicies[PolicyFlexible]
+}
+
+// ParseCancellationPolicy resolves a policy code, defaulting to flexible when empty.
+func ParseCancellationPolicy(code string) (CancellationPolicy, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCancellationPolicy(), nil
+	}
+	policy, ok := cancellationPolicies[code]
+	if !ok {
+		return CancellationPolicy{}, pkgErrors.New("bad_request", "invalid cancellation policy")
+	}
+	return policy, nil
+}
+
+// RefundFor returns the refundable part of paid for a stay of nights starting at checkIn, cancelled at now.
+// Nothing is refunded once the stay has started. The penalty is rounded to the currency of paid.
+func (p CancellationPolicy) RefundFor(paid Money, nights int, checkIn, now time.Time) Money {
+	if p.NonRefundable || !now.Before(checkIn) {
+		return Money{Currency: paid.Currency}
+	}
+	if now.Before(checkIn.Add(-p.FreeUntil)) || nights <= 0 {
+		return paid
+	}
+	penalty := paid.Amount.MulDiv(int64(min(p.PenaltyNights, nights)), int64(nights)).Round(paid.Currency)
+	return Money{Amount: paid.Amount.Sub(penalty), Currency: paid.Currency}
+}
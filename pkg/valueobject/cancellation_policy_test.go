@@ -0,0 +1,47 @@
+package valueobject
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCancellationPolicy(t *testing.T) {
+	p, err := ParseCancellationPolicy("")
+	if err != nil || p.Code != PolicyFlexible {
+		t.Fatalf("expected flexible default, got %v %v", p.Code, err)
+	}
+	if p, err := ParseCancellationPolicy(" Non_Refundable "); err != nil || !p.NonRefundable {
+		t.Fatalf("expected non refundable policy, got %v %v", p, err)
+	}
+	if _, err := ParseCancellationPolicy("whatever"); err == nil {
+		t.Fatalf("expected error for unknown policy")
+	}
+}
+
+func TestCancellationPolicyRefundFor(t *testing.T) {
+	checkIn := time.Date(2025, 12, 10, 14, 0, 0, 0, time.UTC)
+	flexible, _ := ParseCancellationPolicy(PolicyFlexible)
+	free, _ := ParseCancellationPolicy(PolicyFreeCancellation)
+	nonRefundable, _ := ParseCancellationPolicy(PolicyNonRefundable)
+
+	tests := []struct {
+		name   string
+		policy CancellationPolicy
+		now    time.Time
+		want   float64
+	}{
+		{"flexible before window", flexible, checkIn.Add(-72 * time.Hour), 300},
+		{"flexible inside window", flexible, checkIn.Add(-24 * time.Hour), 200},
+		{"free cancellation late", free, checkIn.Add(-time.Hour), 300},
+		{"non refundable", nonRefundable, checkIn.Add(-72 * time.Hour), 0},
+		{"after check-in", free, checkIn.Add(time.Hour), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.RefundFor(Money{Amount: AmountOf(300), Currency: "IDR"}, 3, checkIn, tt.now)
+			if got.Amount != AmountOf(tt.want) || got.Currency != "IDR" {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package valueobject
+
+import (
+	"strings"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// Charge rule kinds. Taxes are levied on the room price plus the fees charged on top of it.
+const (
+	ChargeTax = "tax"
+	ChargeFee = "fee"
+)
+
+// Charge rule bases.
+const (
+	ChargePercent = "percent" // Amount is a percentage of the price the charge is levied on
+	ChargeFlat    = "flat"    // Amount is charged per room, per night or per stay
+)
+
+// Flat charges are due per room for every night or once for the stay.
+const (
+	ChargePerNight = "night"
+	ChargePerStay  = "stay"
+)
+
+// ChargeRule is a tax or fee added to the price of every room of a hotel. An inclusive charge is
+// already part of the room price and is only itemised; an exclusive one is added on top.
+type ChargeRule struct {
+	Kind      string
+	Name      string
+	Basis     string
+	Amount    Amount // percentage, or the flat amount
+	Per       string // flat charges only
+	Inclusive bool
+}
+
+// NewChargeRule validates a rule and clears the fields its basis does not use.
+func NewChargeRule(r ChargeRule) (ChargeRule, error) {
+	rule := ChargeRule{
+		Kind:      strings.ToLower(strings.TrimSpace(r.Kind)),
+		Name:      strings.TrimSpace(r.Name),
+		Basis:     strings.ToLower(strings.TrimSpace(r.Basis)),
+		Amount:    r.Amount,
+		Inclusive: r.Inclusive,
+	}
+	if rule.Kind != ChargeTax && rule.Kind != ChargeFee {
+		return ChargeRule{}, pkgErrors.New("bad_request", "kind must be tax or fee")
+	}
+	if rule.Name == "" {
+		return ChargeRule{}, pkgErrors.New("bad_request", "charge rule name required")
+	}
+	if rule.Amount.Cmp(Amount{}) <= 0 {
+		return ChargeRule{}, pkgErrors.New("bad_request", "amount must be positive")
+	}
+	switch rule.Basis {
+	case ChargePercent:
+		if rule.Amount.Cmp(AmountOf(100)) > 0 {
+			return ChargeRule{}, pkgErrors.New("bad_request", "percent must be at most 100")
+		}
+	case ChargeFlat:
+		rule.Per = strings.ToLower(strings.TrimSpace(r.Per))
+		if rule.Per != ChargePerNight && rule.Per != ChargePerStay {
+			return ChargeRule{}, pkgErrors.New("bad_request", "flat charges need per night or stay")
+		}
+	default:
+		return ChargeRule{}, pkgErrors.New("bad_request", "basis must be percent or flat")
+	}
+	return rule, nil
+}
+
+// Charge returns the amount due for one room of nights levied on base. The share of an inclusive
+// percentage is worked out of base rather than added to it. Percentages are not rounded; the caller
+// rounds them to the currency of base.
+func (r ChargeRule) Charge(base Amount, nights int) Amount {
+	hundred := AmountOf(100)
+	switch {
+	case r.Basis == ChargeFlat && r.Per == ChargePerNight:
+		return r.Amount.Mul(nights)
+	case r.Basis == ChargeFlat:
+		return r.Amount
+	case r.Inclusive:
+		return base.Scale(r.Amount, hundred.Add(r.Amount))
+	default:
+		return base.Scale(r.Amount, hundred)
+	}
+}
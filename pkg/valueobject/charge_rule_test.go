@@ -0,0 +1,51 @@
+package valueobject
+
+import "testing"
+
+func TestNewChargeRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    ChargeRule
+		wantErr bool
+	}{
+		{"percent tax", ChargeRule{Kind: "Tax", Name: "PB1", Basis: "percent", Amount: AmountOf(10)}, false},
+		{"flat fee per night", ChargeRule{Kind: ChargeFee, Name: "city fee", Basis: ChargeFlat, Amount: AmountOf(15000), Per: "Night"}, false},
+		{"flat fee without per", ChargeRule{Kind: ChargeFee, Name: "city fee", Basis: ChargeFlat, Amount: AmountOf(15000)}, true},
+		{"percent above 100", ChargeRule{Kind: ChargeTax, Name: "x", Basis: ChargePercent, Amount: AmountOf(120)}, true},
+		{"zero amount", ChargeRule{Kind: ChargeTax, Name: "x", Basis: ChargePercent}, true},
+		{"unknown kind", ChargeRule{Kind: "levy", Name: "x", Basis: ChargePercent, Amount: AmountOf(5)}, true},
+		{"unknown basis", ChargeRule{Kind: ChargeFee, Name: "x", Basis: "tiered", Amount: AmountOf(5)}, true},
+		{"missing name", ChargeRule{Kind: ChargeFee, Basis: ChargePercent, Amount: AmountOf(5)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewChargeRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	rule, _ := NewChargeRule(ChargeRule{Kind: ChargeTax, Name: "VAT", Basis: ChargePercent, Amount: AmountOf(11), Per: ChargePerNight})
+	if rule.Per != "" {
+		t.Fatalf("expected per cleared for percent rules, got %+v", rule)
+	}
+}
+
+func TestChargeRuleCharge(t *testing.T) {
+	tests := []struct {
+		name string
+		rule ChargeRule
+		want float64
+	}{
+		{"exclusive percent", ChargeRule{Basis: ChargePercent, Amount: AmountOf(10)}, 100},
+		{"inclusive percent", ChargeRule{Basis: ChargePercent, Amount: AmountOf(10), Inclusive: true}, 90.9091},
+		{"flat per night", ChargeRule{Basis: ChargeFlat, Amount: AmountOf(15), Per: ChargePerNight}, 45},
+		{"flat per stay", ChargeRule{Basis: ChargeFlat, Amount: AmountOf(15), Per: ChargePerStay}, 15},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Charge(AmountOf(1000), 3); got != AmountOf(tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -39,8 +39,18 @@ func (d DateRange) Overlaps(other DateRange) bool {
 	return d.Start.Before(other.End) && other.Start.Before(d.End)
 }
 
+// NightDates returns the calendar date of every night in the range.
+func (d DateRange) NightDates() []time.Time {
+	start := endDateOnly(d.Start)
+	nights := d.Nights()
+	dates := make([]time.Time, 0, nights)
+	for i := 0; i < nights; i++ {
+		dates = append(dates, start.AddDate(0, 0, i))
+	}
+	return dates
+}
+
 func endDateOnly(t time.Time) time.Time {
 	y, m, d := t.Date()
 	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
 }
-
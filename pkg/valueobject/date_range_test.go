@@ -24,3 +24,17 @@ func TestNewDateRange(t *testing.T) {
 		t.Fatalf("expected error when start is zero")
 	}
 }
+
+func TestDateRangeNightDates(t *testing.T) {
+	start := time.Date(2025, 1, 30, 14, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 2, 12, 0, 0, 0, time.UTC)
+	dr, _ := NewDateRange(start, end)
+
+	dates := dr.NightDates()
+	if len(dates) != 3 {
+		t.Fatalf("expected 3 nights, got %d", len(dates))
+	}
+	if want := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC); !dates[2].Equal(want) {
+		t.Fatalf("expected last night %v, got %v", want, dates[2])
+	}
+}
@@ -0,0 +1,107 @@
+package valueobject
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// DefaultCurrency prices hotels that were created before hotels had a currency.
+const DefaultCurrency = "IDR"
+
+// ParseCurrency validates an ISO 4217 currency code and returns it upper-cased.
+func ParseCurrency(code string) (string, error) {
+	cur := strings.ToUpper(strings.TrimSpace(code))
+	if len(cur) != 3 || strings.Trim(cur, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", pkgErrors.New("bad_request", "currency must be a 3-letter ISO 4217 code")
+	}
+	return cur, nil
+}
+
+// ExchangeRate converts amounts from Base into Quote: one unit of Base is worth Rate units of Quote.
+type ExchangeRate struct {
+	Base   string
+	Quote  string
+	Rate   float64
+	AsOf   time.Time // when the rate was published
+	Source string    // e.g. "admin" or the provider the rate came from
+}
+
+// NewExchangeRate validates a rate between two currencies.
+func NewExchangeRate(base, quote string, rate float64, asOf time.Time, source string) (ExchangeRate, error) {
+	b, err := ParseCurrency(base)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+	q, err := ParseCurrency(quote)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+	if b == q {
+		return ExchangeRate{}, pkgErrors.New("bad_request", "exchange rate needs two different currencies")
+	}
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return ExchangeRate{}, pkgErrors.New("bad_request", "exchange rate must be positive")
+	}
+	return ExchangeRate{Base: b, Quote: q, Rate: rate, AsOf: asOf, Source: source}, nil
+}
+
+// IdentityRate converts a currency into itself.
+func IdentityRate(currency string) ExchangeRate {
+	return ExchangeRate{Base: currency, Quote: currency, Rate: 1}
+}
+
+// IsIdentity reports whether the rate leaves amounts unchanged.
+func (r ExchangeRate) IsIdentity() bool {
+	return r.Base == r.Quote
+}
+
+// Apply converts an amount of Base into Quote, rounded to the minor unit of Quote.
+func (r ExchangeRate) Apply(amount Amount) Amount {
+	if r.IsIdentity() {
+		return amount
+	}
+	return amount.MulRate(r.Rate).Round(r.Quote)
+}
+
+// Inverse converts the other way round.
+func (r ExchangeRate) Inverse() ExchangeRate {
+	return ExchangeRate{Base: r.Quote, Quote: r.Base, Rate: 1 / r.Rate, AsOf: r.AsOf, Source: r.Source}
+}
+
+// ExchangeRates is a table of the latest rates, as published by a provider or uploaded by an admin.
+type ExchangeRates []ExchangeRate
+
+// Find returns the rate from base into quote: a rate published for the pair, the inverse of one
+// published the other way round, or a cross rate over a currency both are quoted against. Cross rates
+// are as old as the older of the two rates they are derived from.
+func (t ExchangeRates) Find(base, quote string) (ExchangeRate, bool) {
+	if base == quote {
+		return IdentityRate(base), true
+	}
+	for _, r := range t {
+		switch {
+		case r.Base == base && r.Quote == quote:
+			return r, true
+		case r.Base == quote && r.Quote == base:
+			return r.Inverse(), true
+		}
+	}
+	for _, from := range t {
+		if from.Quote != base {
+			continue
+		}
+		for _, to := range t {
+			if to.Base == from.Base && to.Quote == quote {
+				cross := ExchangeRate{Base: base, Quote: quote, Rate: to.Rate / from.Rate, AsOf: to.AsOf, Source: to.Source}
+				if from.AsOf.Before(to.AsOf) {
+					cross.AsOf = from.AsOf
+				}
+				return cross, true
+			}
+		}
+	}
+	return ExchangeRate{}, false
+}
@@ -0,0 +1,72 @@
+package valueobject
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseCurrency(t *testing.T) {
+	if cur, err := ParseCurrency(" usd "); err != nil || cur != "USD" {
+		t.Fatalf("expected USD, got %q, %v", cur, err)
+	}
+	for _, code := range []string{"", "US", "USDT", "U5D"} {
+		if _, err := ParseCurrency(code); err == nil {
+			t.Fatalf("expected error for %q", code)
+		}
+	}
+}
+
+func TestNewExchangeRate(t *testing.T) {
+	if _, err := NewExchangeRate("usd", "idr", 16000, time.Now(), "admin"); err != nil {
+		t.Fatalf("expected valid rate, got %v", err)
+	}
+	if _, err := NewExchangeRate("USD", "USD", 1, time.Now(), "admin"); err == nil {
+		t.Fatalf("expected error for same currency")
+	}
+	if _, err := NewExchangeRate("USD", "IDR", 0, time.Now(), "admin"); err == nil {
+		t.Fatalf("expected error for zero rate")
+	}
+}
+
+func TestExchangeRatesFind(t *testing.T) {
+	older := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	rates := ExchangeRates{
+		{Base: "USD", Quote: "IDR", Rate: 16000, AsOf: newer},
+		{Base: "USD", Quote: "SGD", Rate: 1.35, AsOf: older},
+	}
+
+	tests := []struct {
+		base, quote string
+		want        float64
+	}{
+		{"USD", "IDR", 16000},
+		{"IDR", "USD", 1.0 / 16000},
+		{"IDR", "SGD", 1.35 / 16000},
+		{"SGD", "SGD", 1},
+	}
+	for _, tt := range tests {
+		rate, ok := rates.Find(tt.base, tt.quote)
+		if !ok || math.Abs(rate.Rate-tt.want) > 1e-12 || rate.Base != tt.base || rate.Quote != tt.quote {
+			t.Fatalf("%s/%s: expected %v, got %+v (found %v)", tt.base, tt.quote, tt.want, rate, ok)
+		}
+	}
+	if cross, _ := rates.Find("IDR", "SGD"); !cross.AsOf.Equal(older) {
+		t.Fatalf("expected cross rate as of the older rate, got %v", cross.AsOf)
+	}
+	if _, ok := rates.Find("IDR", "AUD"); ok {
+		t.Fatalf("expected no rate for AUD")
+	}
+}
+
+func TestMoneyConvert(t *testing.T) {
+	rate := ExchangeRate{Base: "IDR", Quote: "USD", Rate: 1.0 / 16000}
+	got, err := Money{Amount: AmountOf(1226500), Currency: "IDR"}.Convert(rate)
+	if err != nil || got.Currency != "USD" || got.Amount != AmountOf(76.66) {
+		t.Fatalf("expected 76.66 USD, got %v, %v", got, err)
+	}
+	if _, err := (Money{Amount: AmountOf(10), Currency: "SGD"}).Convert(rate); err == nil {
+		t.Fatalf("expected error converting from the wrong currency")
+	}
+}
@@ -0,0 +1,112 @@
+package valueobject
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+const (
+	maxGuestNameLength       = 100
+	maxSpecialRequestsLength = 500
+	maxStaffNoteLength       = 2000
+)
+
+var (
+	phonePattern       = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+	nationalityPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	phoneSeparators    = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+)
+
+// GuestDetails identifies the lead guest and the names of everyone travelling with them.
+type GuestDetails struct {
+	Name             string
+	Phone            string
+	Nationality      string // ISO 3166-1 alpha-2 country code, empty when not given
+	AdditionalGuests []string
+}
+
+// NewGuestDetails validates and normalizes the lead guest's details.
+// Name and phone are required; the phone keeps only digits and a leading plus.
+func NewGuestDetails(name, phone, nationality string, additional []string) (GuestDetails, error) {
+	n, err := normalizeGuestName(name)
+	if err != nil {
+		return GuestDetails{}, err
+	}
+	p := phoneSeparators.Replace(strings.TrimSpace(phone))
+	if !phonePattern.MatchString(p) {
+		return GuestDetails{}, pkgErrors.New("bad_request", "invalid guest phone")
+	}
+	c := strings.ToUpper(strings.TrimSpace(nationality))
+	if c != "" && !nationalityPattern.MatchString(c) {
+		return GuestDetails{}, pkgErrors.New("bad_request", "nationality must be a two-letter country code")
+	}
+	var others []string
+	for _, raw := range additional {
+		other, err := normalizeGuestName(raw)
+		if err != nil {
+			return GuestDetails{}, pkgErrors.New("bad_request", "invalid additional guest name")
+		}
+		others = append(others, other)
+	}
+	return GuestDetails{Name: n, Phone: p, Nationality: c, AdditionalGuests: others}, nil
+}
+
+// IsZero reports whether no guest details were given.
+func (g GuestDetails) IsZero() bool {
+	return g.Name == "" && g.Phone == "" && g.Nationality == "" && len(g.AdditionalGuests) == 0
+}
+
+// Headcount returns the lead guest plus everyone named with them.
+func (g GuestDetails) Headcount() int {
+	return 1 + len(g.AdditionalGuests)
+}
+
+func normalizeGuestName(raw string) (string, error) {
+	name := strings.Join(strings.Fields(raw), " ")
+	if name == "" {
+		return "", pkgErrors.New("bad_request", "guest name required")
+	}
+	if utf8.RuneCountInString(name) > maxGuestNameLength {
+		return "", pkgErrors.New("bad_request", "guest name too long")
+	}
+	return name, nil
+}
+
+// NormalizeArrivalTime validates an expected arrival time in 24-hour HH:MM form.
+// An empty value means the guest did not say.
+func NormalizeArrivalTime(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return "", nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return "", pkgErrors.New("bad_request", "arrival_time must be HH:MM")
+	}
+	return t.Format("15:04"), nil
+}
+
+// NormalizeSpecialRequests trims free-text guest requests such as an extra bed or a high floor.
+func NormalizeSpecialRequests(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if utf8.RuneCountInString(s) > maxSpecialRequestsLength {
+		return "", pkgErrors.New("bad_request", "special requests too long")
+	}
+	return s, nil
+}
+
+// NormalizeStaffNote validates an internal note written by hotel staff.
+func NormalizeStaffNote(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return "", pkgErrors.New("bad_request", "note required")
+	}
+	if utf8.RuneCountInString(s) > maxStaffNoteLength {
+		return "", pkgErrors.New("bad_request", "note too long")
+	}
+	return s, nil
+}
@@ -0,0 +1,68 @@
+package valueobject
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewGuestDetails(t *testing.T) {
+	g, err := NewGuestDetails("  Jane   Doe ", "+62 812-3456-7890", "id", []string{" John Doe "})
+	if err != nil {
+		t.Fatalf("expected valid guest, got %v", err)
+	}
+	if g.Name != "Jane Doe" || g.Phone != "+6281234567890" || g.Nationality != "ID" {
+		t.Fatalf("unexpected normalization: %+v", g)
+	}
+	if len(g.AdditionalGuests) != 1 || g.AdditionalGuests[0] != "John Doe" || g.Headcount() != 2 {
+		t.Fatalf("unexpected additional guests: %+v", g.AdditionalGuests)
+	}
+
+	invalid := []struct {
+		name        string
+		guest       string
+		phone       string
+		nationality string
+		additional  []string
+	}{
+		{"missing name", " ", "+6281234567", "", nil},
+		{"long name", strings.Repeat("a", 101), "+6281234567", "", nil},
+		{"bad phone", "Jane", "call me", "", nil},
+		{"short phone", "Jane", "12345", "", nil},
+		{"bad nationality", "Jane", "+6281234567", "IDN", nil},
+		{"blank additional guest", "Jane", "+6281234567", "", []string{""}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGuestDetails(tt.guest, tt.phone, tt.nationality, tt.additional); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestNormalizeArrivalTime(t *testing.T) {
+	if got, err := NormalizeArrivalTime(" 9:30 "); err != nil || got != "09:30" {
+		t.Fatalf("expected 09:30, got %q (%v)", got, err)
+	}
+	if got, err := NormalizeArrivalTime(""); err != nil || got != "" {
+		t.Fatalf("expected empty arrival time to be allowed")
+	}
+	if _, err := NormalizeArrivalTime("25:00"); err == nil {
+		t.Fatalf("expected error for invalid hour")
+	}
+}
+
+func TestNormalizeSpecialRequestsAndNotes(t *testing.T) {
+	if got, err := NormalizeSpecialRequests("  extra bed, high floor "); err != nil || got != "extra bed, high floor" {
+		t.Fatalf("unexpected special requests %q (%v)", got, err)
+	}
+	if _, err := NormalizeSpecialRequests(strings.Repeat("x", 501)); err == nil {
+		t.Fatalf("expected error for long special requests")
+	}
+	if _, err := NormalizeStaffNote("   "); err == nil {
+		t.Fatalf("expected error for empty note")
+	}
+	if got, err := NormalizeStaffNote(" VIP, upgrade if possible "); err != nil || got != "VIP, upgrade if possible" {
+		t.Fatalf("unexpected note %q (%v)", got, err)
+	}
+}
@@ -20,11 +20,11 @@ func ValidateHotel(name, address string) (string, string, error) {
 }
 
 // RoomTypeSpec validates capacity and base price.
-func RoomTypeSpec(capacity int, basePrice float64) error {
+func RoomTypeSpec(capacity int, basePrice Amount) error {
 	if capacity <= 0 {
 		return pkgErrors.New("bad_request", "capacity must be positive")
 	}
-	if basePrice <= 0 {
+	if basePrice.Cmp(Amount{}) <= 0 {
 		return pkgErrors.New("bad_request", "base price must be positive")
 	}
 	return nil
@@ -37,8 +37,16 @@ const (
 	RoomAvailable   RoomStatus = "available"
 	RoomUnavailable RoomStatus = "unavailable"
 	RoomMaintenance RoomStatus = "maintenance"
+	RoomOccupied    RoomStatus = "occupied"
+	RoomCleaning    RoomStatus = "cleaning"
 )
 
+// BookableRoomStatuses lists room states that count towards sellable inventory.
+// Occupied and cleaning rooms are only temporarily out of service between stays.
+func BookableRoomStatuses() []RoomStatus {
+	return []RoomStatus{RoomAvailable, RoomOccupied, RoomCleaning}
+}
+
 // NormalizeRoomStatus validates or defaults to available.
 func NormalizeRoomStatus(raw string) (RoomStatus, error) {
 	status := strings.ToLower(strings.TrimSpace(raw))
@@ -46,7 +54,7 @@ func NormalizeRoomStatus(raw string) (RoomStatus, error) {
 		return RoomAvailable, nil
 	}
 	switch RoomStatus(status) {
-	case RoomAvailable, RoomUnavailable, RoomMaintenance:
+	case RoomAvailable, RoomUnavailable, RoomMaintenance, RoomOccupied, RoomCleaning:
 		return RoomStatus(status), nil
 	default:
 		return "", pkgErrors.New("bad_request", "invalid room status")
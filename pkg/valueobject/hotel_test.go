@@ -16,13 +16,13 @@ func TestValidateHotel(t *testing.T) {
 }
 
 func TestRoomTypeSpec(t *testing.T) {
-	if err := RoomTypeSpec(2, 1000); err != nil {
+	if err := RoomTypeSpec(2, AmountOf(1000)); err != nil {
 		t.Fatalf("expected valid room type, got %v", err)
 	}
-	if err := RoomTypeSpec(0, 1000); err == nil {
+	if err := RoomTypeSpec(0, AmountOf(1000)); err == nil {
 		t.Fatalf("expected error for capacity 0")
 	}
-	if err := RoomTypeSpec(2, -1); err == nil {
+	if err := RoomTypeSpec(2, AmountOf(-1)); err == nil {
 		t.Fatalf("expected error for negative price")
 	}
 }
@@ -9,20 +9,24 @@ import (
 
 // Money captures amount and currency with validation.
 type Money struct {
-	Amount   float64
+	Amount   Amount
 	Currency string
 }
 
-// NewMoney validates amount and currency.
-func NewMoney(amount float64, currency string) (Money, error) {
-	if amount < 0 {
+// NewMoney validates amount and currency. The amount may not be more precise than the minor unit
+// of its currency.
+func NewMoney(amount Amount, currency string) (Money, error) {
+	if amount.IsNegative() {
 		return Money{}, pkgErrors.New("bad_request", "amount cannot be negative")
 	}
-	cur := strings.TrimSpace(currency)
+	cur := strings.ToUpper(strings.TrimSpace(currency))
 	if cur == "" {
 		return Money{}, pkgErrors.New("bad_request", "currency cannot be empty")
 	}
-	return Money{Amount: amount, Currency: strings.ToUpper(cur)}, nil
+	if amount.Round(cur) != amount {
+		return Money{}, pkgErrors.New("bad_request", fmt.Sprintf("%s amounts have at most %d decimals", cur, CurrencyDigits(cur)))
+	}
+	return Money{Amount: amount, Currency: cur}, nil
 }
 
 
@@ -31,7 +35,7 @@ func (m Money) Add(other Money) (Money, error) {
 	if m.Currency != other.Currency {
 		return Money{}, pkgErrors.New("bad_request", "cannot add money with different currencies")
 	}
-	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+	return Money{Amount: m.Amount.Add(other.Amount), Currency: m.Currency}, nil
 }
 
 // Subtract subtracts two Money values (must be same currency).
@@ -39,24 +43,24 @@ func (m Money) Subtract(other Money) (Money, error) {
 	if m.Currency != other.Currency {
 		return Money{}, pkgErrors.New("bad_request", "cannot subtract money with different currencies")
 	}
-	result := m.Amount - other.Amount
-	if result < 0 {
+	result := m.Amount.Sub(other.Amount)
+	if result.IsNegative() {
 		return Money{}, pkgErrors.New("bad_request", "result cannot be negative")
 	}
 	return Money{Amount: result, Currency: m.Currency}, nil
 }
 
-// Multiply multiplies the money by a factor.
+// Multiply multiplies the money by a factor, rounded to the currency.
 func (m Money) Multiply(factor float64) (Money, error) {
 	if factor < 0 {
 		return Money{}, pkgErrors.New("bad_request", "factor cannot be negative")
 	}
-	return Money{Amount: m.Amount * factor, Currency: m.Currency}, nil
+	return Money{Amount: m.Amount.MulRate(factor).Round(m.Currency), Currency: m.Currency}, nil
 }
 
 // IsZero checks if the amount is zero.
 func (m Money) IsZero() bool {
-	return m.Amount == 0
+	return m.Amount.IsZero()
 }
 
 // IsGreaterThan checks if this money is greater than another.
@@ -64,10 +68,18 @@ func (m Money) IsGreaterThan(other Money) bool {
 	if m.Currency != other.Currency {
 		return false
 	}
-	return m.Amount > other.Amount
+	return m.Amount.Cmp(other.Amount) > 0
 }
 
 // String returns a string representation of the money.
 func (m Money) String() string {
-	return fmt.Sprintf("%.2f %s", m.Amount, m.Currency)
+	return fmt.Sprintf("%s %s", m.Amount.StringFixed(CurrencyDigits(m.Currency)), m.Currency)
+}
+
+// Convert converts the money into another currency at rate, rounded to the new currency.
+func (m Money) Convert(rate ExchangeRate) (Money, error) {
+	if m.Currency != rate.Base {
+		return Money{}, pkgErrors.New("bad_request", "exchange rate does not convert from "+m.Currency)
+	}
+	return Money{Amount: rate.Apply(m.Amount), Currency: rate.Quote}, nil
 }
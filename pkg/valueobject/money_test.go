@@ -3,14 +3,34 @@ package valueobject
 import "testing"
 
 func TestNewMoney(t *testing.T) {
-	_, err := NewMoney(1000, "IDR")
+	_, err := NewMoney(AmountOf(1000), "IDR")
 	if err != nil {
 		t.Fatalf("expected valid money, got %v", err)
 	}
-	if _, err := NewMoney(-1, "IDR"); err == nil {
+	if _, err := NewMoney(AmountOf(-1), "IDR"); err == nil {
 		t.Fatalf("expected error for negative amount")
 	}
-	if _, err := NewMoney(1000, ""); err == nil {
+	if _, err := NewMoney(AmountOf(1000), ""); err == nil {
 		t.Fatalf("expected error for empty currency")
 	}
+	if _, err := NewMoney(AmountOf(10.5), "IDR"); err == nil {
+		t.Fatalf("expected error for rupiah cents")
+	}
+	if _, err := NewMoney(AmountOf(10.55), "USD"); err != nil {
+		t.Fatalf("expected valid dollars and cents, got %v", err)
+	}
+}
+
+func TestMoneyArithmeticIsExact(t *testing.T) {
+	dime := Money{Amount: AmountOf(0.1), Currency: "USD"}
+	total := dime
+	for i := 0; i < 9; i++ {
+		total, _ = total.Add(dime)
+	}
+	if total.Amount != AmountOf(1) || total.String() != "1.00 USD" {
+		t.Fatalf("expected exactly 1.00 USD, got %v", total)
+	}
+	if got, _ := (Money{Amount: AmountOf(1000), Currency: "IDR"}).Multiply(0.333); got.Amount != AmountOf(333) {
+		t.Fatalf("expected 333 IDR, got %v", got)
+	}
 }
@@ -0,0 +1,21 @@
+package valueobject
+
+// NoShowPolicy decides what a guest who never arrived is charged.
+// ChargeNights <= 0 forfeits the whole payment; otherwise that many nights are kept
+// and the rest of the stay is refunded.
+type NoShowPolicy struct {
+	ChargeNights int
+}
+
+// Forfeits reports whether the whole payment is kept.
+func (p NoShowPolicy) Forfeits() bool {
+	return p.ChargeNights <= 0
+}
+
+// Charge returns the amount kept out of paid for a stay of nights, rounded to the currency of paid.
+func (p NoShowPolicy) Charge(paid Money, nights int) Money {
+	if p.Forfeits() || nights <= 0 || p.ChargeNights >= nights {
+		return paid
+	}
+	return Money{Amount: paid.Amount.MulDiv(int64(p.ChargeNights), int64(nights)).Round(paid.Currency), Currency: paid.Currency}
+}
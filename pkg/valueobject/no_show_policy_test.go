@@ -0,0 +1,23 @@
+package valueobject
+
+import "testing"
+
+func TestNoShowPolicyCharge(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy NoShowPolicy
+		want   float64
+	}{
+		{"forfeit", NoShowPolicy{}, 300},
+		{"one night", NoShowPolicy{ChargeNights: 1}, 100},
+		{"more nights than stay", NoShowPolicy{ChargeNights: 5}, 300},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Charge(Money{Amount: AmountOf(300), Currency: "IDR"}, 3)
+			if got.Amount != AmountOf(tt.want) || got.Currency != "IDR" {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package valueobject
+
+import (
+	"strings"
+	"time"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// Pricing rule kinds. Each kind adjusts a different part of the nightly price.
+const (
+	PricingSeason       = "season"         // nightly rate, or a percentage of it, for nights in a date range
+	PricingDayOfWeek    = "day_of_week"    // percentage of the nightly rate on given weekdays
+	PricingOccupancy    = "occupancy"      // percentage of the nightly rate per guest above those included
+	PricingLengthOfStay = "length_of_stay" // percentage off every night of long enough stays
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// PricingRule adjusts nightly prices. Percent is signed: 20 adds 20% and -10 takes 10% off.
+type PricingRule struct {
+	Kind      string
+	Name      string
+	StartDate time.Time      // season: first night covered
+	EndDate   time.Time      // season: first night no longer covered
+	Weekdays  []time.Weekday // day_of_week
+	Rate      Amount         // season: replaces the nightly rate when positive, Percent is then ignored
+	Percent   float64
+	Guests    int // occupancy: guests included in the rate
+	MinNights int // length_of_stay
+}
+
+// NewPricingRule validates a rule and clears the fields its kind does not use.
+func NewPricingRule(r PricingRule) (PricingRule, error) {
+	rule := PricingRule{Kind: strings.ToLower(strings.TrimSpace(r.Kind)), Name: strings.TrimSpace(r.Name), Percent: r.Percent}
+	if rule.Name == "" {
+		return PricingRule{}, pkgErrors.New("bad_request", "pricing rule name required")
+	}
+	if r.Percent <= -100 {
+		return PricingRule{}, pkgErrors.New("bad_request", "percent must be above -100")
+	}
+	switch rule.Kind {
+	case PricingSeason:
+		if r.StartDate.IsZero() || r.EndDate.IsZero() || !r.StartDate.Before(r.EndDate) {
+			return PricingRule{}, pkgErrors.New("bad_request", "season needs a start_date before its end_date")
+		}
+		if r.Rate.IsNegative() || (r.Rate.IsZero() && r.Percent == 0) {
+			return PricingRule{}, pkgErrors.New("bad_request", "season needs a positive rate or a percent")
+		}
+		rule.StartDate, rule.EndDate, rule.Rate = endDateOnly(r.StartDate), endDateOnly(r.EndDate), r.Rate
+		if !rule.Rate.IsZero() {
+			rule.Percent = 0
+		}
+	case PricingDayOfWeek:
+		if len(r.Weekdays) == 0 {
+			return PricingRule{}, pkgErrors.New("bad_request", "day_of_week needs at least one day")
+		}
+		rule.Weekdays = r.Weekdays
+	case PricingOccupancy:
+		if r.Guests < 1 {
+			return PricingRule{}, pkgErrors.New("bad_request", "occupancy needs the number of guests included")
+		}
+		rule.Guests = r.Guests
+	case PricingLengthOfStay:
+		if r.MinNights < 1 {
+			return PricingRule{}, pkgErrors.New("bad_request", "length_of_stay needs min_nights")
+		}
+		rule.MinNights = r.MinNights
+	default:
+		return PricingRule{}, pkgErrors.New("bad_request", "invalid pricing rule kind")
+	}
+	return rule, nil
+}
+
+// DefaultPricingRules apply for every kind a hotel has not configured: guests above two pay 20%
+// extra each, and stays of three and seven nights get 5% and 10% off.
+func DefaultPricingRules() []PricingRule {
+	return []PricingRule{
+		{Kind: PricingOccupancy, Name: "extra guest", Guests: 2, Percent: 20},
+		{Kind: PricingLengthOfStay, Name: "long stay", MinNights: 7, Percent: -10},
+		{Kind: PricingLengthOfStay, Name: "long stay", MinNights: 3, Percent: -5},
+	}
+}
+
+// CoversNight reports whether a season or day_of_week rule applies to the night starting on date.
+func (r PricingRule) CoversNight(date time.Time) bool {
+	date = endDateOnly(date)
+	switch r.Kind {
+	case PricingSeason:
+		return !date.Before(r.StartDate) && date.Before(r.EndDate)
+	case PricingDayOfWeek:
+		for _, d := range r.Weekdays {
+			if d == date.Weekday() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseWeekdays reads weekday names such as "sat" or "Saturday".
+func ParseWeekdays(names []string) ([]time.Weekday, error) {
+	days := make([]time.Weekday, 0, len(names))
+	for _, name := range names {
+		n := strings.ToLower(strings.TrimSpace(name))
+		if len(n) < 3 {
+			return nil, pkgErrors.New("bad_request", "invalid weekday "+name)
+		}
+		d, ok := weekdays[n[:3]]
+		if !ok || !strings.HasPrefix(strings.ToLower(d.String()), n) {
+			return nil, pkgErrors.New("bad_request", "invalid weekday "+name)
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+// WeekdayNames returns the short names ParseWeekdays reads back.
+func WeekdayNames(days []time.Weekday) []string {
+	names := make([]string, 0, len(days))
+	for _, d := range days {
+		names = append(names, strings.ToLower(d.String()[:3]))
+	}
+	return names
+}
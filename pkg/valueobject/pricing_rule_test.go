@@ -0,0 +1,68 @@
+package valueobject
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPricingRule(t *testing.T) {
+	start := time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		rule    PricingRule
+		wantErr bool
+	}{
+		{"season rate", PricingRule{Kind: "Season", Name: "peak", StartDate: start, EndDate: start.AddDate(0, 0, 10), Rate: AmountOf(900)}, false},
+		{"season without dates", PricingRule{Kind: PricingSeason, Name: "peak", Rate: AmountOf(900)}, true},
+		{"season without price", PricingRule{Kind: PricingSeason, Name: "peak", StartDate: start, EndDate: start.AddDate(0, 0, 1)}, true},
+		{"weekend", PricingRule{Kind: PricingDayOfWeek, Name: "weekend", Weekdays: []time.Weekday{time.Saturday}, Percent: 15}, false},
+		{"weekend without days", PricingRule{Kind: PricingDayOfWeek, Name: "weekend", Percent: 15}, true},
+		{"occupancy", PricingRule{Kind: PricingOccupancy, Name: "extra guest", Guests: 1, Percent: 10}, false},
+		{"length of stay", PricingRule{Kind: PricingLengthOfStay, Name: "week", Percent: -100}, true},
+		{"unknown kind", PricingRule{Kind: "promo", Name: "x", Percent: 5}, true},
+		{"missing name", PricingRule{Kind: PricingOccupancy, Guests: 1, Percent: 10}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPricingRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	rule, _ := NewPricingRule(PricingRule{Kind: PricingSeason, Name: "peak", StartDate: start, EndDate: start.AddDate(0, 0, 10), Rate: AmountOf(900), Percent: 20, Guests: 3})
+	if rule.Kind != PricingSeason || rule.Percent != 0 || rule.Guests != 0 {
+		t.Fatalf("expected unused fields cleared, got %+v", rule)
+	}
+}
+
+func TestPricingRuleCoversNight(t *testing.T) {
+	start := time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC)
+	season := PricingRule{Kind: PricingSeason, StartDate: start, EndDate: start.AddDate(0, 0, 2)}
+	if !season.CoversNight(start.Add(14*time.Hour)) || !season.CoversNight(start.AddDate(0, 0, 1)) {
+		t.Fatalf("expected season to cover its nights")
+	}
+	if season.CoversNight(start.AddDate(0, 0, 2)) || season.CoversNight(start.AddDate(0, 0, -1)) {
+		t.Fatalf("expected season to end before its end date")
+	}
+	weekend := PricingRule{Kind: PricingDayOfWeek, Weekdays: []time.Weekday{time.Friday, time.Saturday}}
+	if !weekend.CoversNight(time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC)) || weekend.CoversNight(time.Date(2025, 12, 21, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected weekend rule to cover friday and saturday only")
+	}
+}
+
+func TestParseWeekdays(t *testing.T) {
+	days, err := ParseWeekdays([]string{"Sat", "sunday", " fri "})
+	if err != nil || len(days) != 3 || days[0] != time.Saturday || days[1] != time.Sunday || days[2] != time.Friday {
+		t.Fatalf("unexpected weekdays %v %v", days, err)
+	}
+	if names := WeekdayNames(days); names[0] != "sat" || names[1] != "sun" || names[2] != "fri" {
+		t.Fatalf("unexpected names %v", names)
+	}
+	for _, bad := range []string{"sa", "saturnday", "funday"} {
+		if _, err := ParseWeekdays([]string{bad}); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package valueobject
+
+import (
+	"strings"
+
+	pkgErrors "github.com/ftryyln/hotel-booking-microservices/pkg/errors"
+)
+
+// Payment timings of a rate plan.
+const (
+	PayNow     = "pay_now"      // charged when booking, like a plain room type
+	PayAtHotel = "pay_at_hotel" // nothing is charged up front; the hotel collects on arrival
+)
+
+// maxInclusions bounds the inclusions listed on a rate plan.
+const maxInclusions = 10
+
+// RatePlan is the commercial terms a room type is sold under, e.g. a non-refundable rate or one
+// with breakfast. Percent adjusts the nightly rate first, then PerNight is added to it, so a
+// breakfast plan may add a flat amount and a non-refundable one take a percentage off.
+type RatePlan struct {
+	Name               string
+	Percent            float64  // signed: -10 takes 10% off the nightly rate
+	PerNight           Amount   // added to the nightly rate, e.g. the price of breakfast
+	Inclusions         []string // e.g. breakfast, lower case
+	CancellationPolicy string   // policy code replacing the room type's
+	PaymentTiming      string
+}
+
+// NewRatePlan validates a plan, normalizing its inclusions and defaulting to pay now.
+func NewRatePlan(p RatePlan) (RatePlan, error) {
+	plan := RatePlan{
+		Name:          strings.TrimSpace(p.Name),
+		Percent:       p.Percent,
+		PerNight:      p.PerNight,
+		PaymentTiming: strings.ToLower(strings.TrimSpace(p.PaymentTiming)),
+	}
+	if plan.Name == "" {
+		return RatePlan{}, pkgErrors.New("bad_request", "rate plan name required")
+	}
+	if plan.Percent <= -100 {
+		return RatePlan{}, pkgErrors.New("bad_request", "percent must be above -100")
+	}
+	if plan.PerNight.IsNegative() {
+		return RatePlan{}, pkgErrors.New("bad_request", "per_night must not be negative")
+	}
+	if plan.PaymentTiming == "" {
+		plan.PaymentTiming = PayNow
+	}
+	if plan.PaymentTiming != PayNow && plan.PaymentTiming != PayAtHotel {
+		return RatePlan{}, pkgErrors.New("bad_request", "payment timing must be pay_now or pay_at_hotel")
+	}
+	policy, err := ParseCancellationPolicy(p.CancellationPolicy)
+	if err != nil {
+		return RatePlan{}, err
+	}
+	plan.CancellationPolicy = policy.Code
+
+	seen := make(map[string]bool, len(p.Inclusions))
+	for _, inc := range p.Inclusions {
+		inc = strings.ToLower(strings.TrimSpace(inc))
+		if inc == "" || seen[inc] {
+			continue
+		}
+		if strings.Contains(inc, ",") {
+			return RatePlan{}, pkgErrors.New("bad_request", "inclusions cannot contain commas")
+		}
+		seen[inc] = true
+		plan.Inclusions = append(plan.Inclusions, inc)
+	}
+	if len(plan.Inclusions) > maxInclusions {
+		return RatePlan{}, pkgErrors.New("bad_request", "too many inclusions")
+	}
+	return plan, nil
+}
+
+// Modifies reports whether the plan changes the nightly rate.
+func (p RatePlan) Modifies() bool {
+	return p.Percent != 0 || !p.PerNight.IsZero()
+}
+
+// Adjust applies the plan to a nightly rate. The result is not rounded; the caller rounds it to
+// the currency of rate.
+func (p RatePlan) Adjust(rate Amount) Amount {
+	return rate.Add(rate.Percent(p.Percent)).Add(p.PerNight)
+}
+
+// PaysAtHotel reports whether guests on the plan pay the hotel on arrival.
+func (p RatePlan) PaysAtHotel() bool {
+	return p.PaymentTiming == PayAtHotel
+}
@@ -0,0 +1,60 @@
+package valueobject
+
+import "testing"
+
+func TestNewRatePlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		plan    RatePlan
+		wantErr bool
+	}{
+		{"flexible", RatePlan{Name: "Flexible"}, false},
+		{"non-refundable", RatePlan{Name: "Saver", Percent: -15, CancellationPolicy: "Non_Refundable"}, false},
+		{"breakfast", RatePlan{Name: "Bed and breakfast", PerNight: AmountOf(150000), Inclusions: []string{"Breakfast"}}, false},
+		{"pay at hotel", RatePlan{Name: "Pay later", PaymentTiming: "PAY_AT_HOTEL"}, false},
+		{"missing name", RatePlan{Percent: -10}, true},
+		{"free", RatePlan{Name: "Free", Percent: -100}, true},
+		{"negative per night", RatePlan{Name: "x", PerNight: AmountOf(-1)}, true},
+		{"unknown timing", RatePlan{Name: "x", PaymentTiming: "pay_later"}, true},
+		{"unknown policy", RatePlan{Name: "x", CancellationPolicy: "strict"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRatePlan(tt.plan)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	plan, _ := NewRatePlan(RatePlan{Name: "Breakfast", Inclusions: []string{" Breakfast", "breakfast", "", "Parking"}})
+	if len(plan.Inclusions) != 2 || plan.Inclusions[0] != "breakfast" || plan.Inclusions[1] != "parking" {
+		t.Fatalf("expected normalized inclusions, got %v", plan.Inclusions)
+	}
+	if plan.PaymentTiming != PayNow || plan.CancellationPolicy != PolicyFlexible {
+		t.Fatalf("expected pay now and the flexible policy by default, got %+v", plan)
+	}
+}
+
+func TestRatePlanAdjust(t *testing.T) {
+	tests := []struct {
+		name string
+		plan RatePlan
+		want Amount
+	}{
+		{"unchanged", RatePlan{Name: "Flexible"}, AmountOf(1000000)},
+		{"percent off", RatePlan{Percent: -10}, AmountOf(900000)},
+		{"breakfast", RatePlan{PerNight: AmountOf(150000)}, AmountOf(1150000)},
+		{"both", RatePlan{Percent: -10, PerNight: AmountOf(150000)}, AmountOf(1050000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.plan.Adjust(AmountOf(1000000)); got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+	if (RatePlan{Name: "Flexible"}).Modifies() {
+		t.Fatalf("expected a plan without modifiers to leave the rate alone")
+	}
+}
@@ -0,0 +1,28 @@
+package valueobject
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{30, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.Backoff(tt.attempts); got != tt.want {
+			t.Fatalf("attempt %d: expected %v, got %v", tt.attempts, tt.want, got)
+		}
+	}
+	if p.Exhausted(4) || !p.Exhausted(5) {
+		t.Fatalf("expected policy to be exhausted after 5 attempts only")
+	}
+}
@@ -11,12 +11,13 @@ const (
 	StatusCancelled      BookingStatus = "cancelled"
 	StatusCheckedIn      BookingStatus = "checked_in"
 	StatusCompleted      BookingStatus = "completed"
+	StatusNoShow         BookingStatus = "no_show"
 )
 
 // ValidateBookingStatus ensures status is known.
 func ValidateBookingStatus(status string) (BookingStatus, error) {
 	switch BookingStatus(status) {
-	case StatusPendingPayment, StatusConfirmed, StatusCancelled, StatusCheckedIn, StatusCompleted:
+	case StatusPendingPayment, StatusConfirmed, StatusCancelled, StatusCheckedIn, StatusCompleted, StatusNoShow:
 		return BookingStatus(status), nil
 	default:
 		return "", pkgErrors.New("bad_request", "invalid booking status")
@@ -31,7 +32,7 @@ func (s BookingStatus) CanTransition(target BookingStatus) error {
 			return nil
 		}
 	case StatusConfirmed:
-		if target == StatusCheckedIn || target == StatusCancelled {
+		if target == StatusCheckedIn || target == StatusCancelled || target == StatusNoShow {
 			return nil
 		}
 	case StatusCheckedIn:
@@ -24,6 +24,17 @@ func TestBookingStatusTransition(t *testing.T) {
 	if err := next.CanTransition(completed); err == nil {
 		t.Fatalf("expected invalid transition to fail")
 	}
+
+	noShow, err := ValidateBookingStatus("no_show")
+	if err != nil {
+		t.Fatalf("expected no_show to be valid, got %v", err)
+	}
+	if err := next.CanTransition(noShow); err != nil {
+		t.Fatalf("expected confirmed -> no_show ok, got %v", err)
+	}
+	if err := curr.CanTransition(noShow); err == nil {
+		t.Fatalf("expected pending_payment -> no_show to fail")
+	}
 }
 
 func TestValidatePaymentStatus(t *testing.T) {
@@ -42,6 +53,12 @@ func TestNormalizeRoomStatus(t *testing.T) {
 	if _, err := NormalizeRoomStatus("available"); err != nil {
 		t.Fatalf("available should be valid")
 	}
+	if _, err := NormalizeRoomStatus("occupied"); err != nil {
+		t.Fatalf("occupied should be valid")
+	}
+	if _, err := NormalizeRoomStatus("cleaning"); err != nil {
+		t.Fatalf("cleaning should be valid")
+	}
 	if _, err := NormalizeRoomStatus("bad"); err == nil {
 		t.Fatalf("expected error for invalid room status")
 	}